@@ -0,0 +1,180 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// AuditAction is the kind of change recorded by an audit event.
+type AuditAction string
+
+// Audit actions of mutating API requests.
+const (
+	AuditCreateAction AuditAction = "create"
+	AuditUpdateAction AuditAction = "update"
+	AuditDeleteAction AuditAction = "delete"
+)
+
+// AuditEvent is an append-only record of a mutating API request.
+type AuditEvent struct {
+	ID   ID        `json:"id"`
+	Time time.Time `json:"time"`
+
+	// OrgID is the organization of the changed resource, if any.
+	OrgID ID `json:"orgID,omitempty"`
+	// UserID and AuthorizationID identify the actor of the request, if it was authenticated.
+	UserID          ID `json:"userID,omitempty"`
+	AuthorizationID ID `json:"authorizationID,omitempty"`
+
+	ResourceType ResourceType `json:"resourceType"`
+	ResourceID   ID           `json:"resourceID,omitempty"`
+	Action       AuditAction  `json:"action"`
+
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	SourceIP string `json:"sourceIP,omitempty"`
+	Status   int    `json:"status"`
+
+	// Diff maps the changed fields of the resource to their values before and after the request.
+	Diff map[string]AuditChange `json:"diff,omitempty"`
+}
+
+// AuditChange is the value of a field of a resource before and after a change.
+type AuditChange struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditEventFilter represents a set of filters that restrict the returned audit events.
+type AuditEventFilter struct {
+	OrgID  *ID
+	UserID *ID
+	// Since and Until bound the time of the returned events, inclusively.
+	Since *time.Time
+	Until *time.Time
+}
+
+// AuditLogService records and retrieves audit events.
+type AuditLogService interface {
+	// AppendAuditEvent appends an event to the audit log, setting its ID and, if unset, its time.
+	AppendAuditEvent(ctx context.Context, e *AuditEvent) error
+
+	// FindAuditEvents returns the audit events matching the filter in time order.
+	FindAuditEvents(ctx context.Context, filter AuditEventFilter, opt ...FindOptions) ([]*AuditEvent, int, error)
+}
+
+// AuditRecording collects the stored states of the resources changed by the services
+// while a request is served, so that its audit event records their diff.
+type AuditRecording struct {
+	mu      sync.Mutex
+	states  map[ID]*auditStates
+	secrets map[ID]map[string]AuditChange
+}
+
+type auditStates struct {
+	before, after []byte
+}
+
+// NewAuditRecording returns an empty AuditRecording.
+func NewAuditRecording() *AuditRecording {
+	return &AuditRecording{
+		states:  make(map[ID]*auditStates),
+		secrets: make(map[ID]map[string]AuditChange),
+	}
+}
+
+// RecordState records the stored states of the resource with the ID before and after a change.
+// A nil state means the resource is not stored. The state before its first change is kept.
+func (r *AuditRecording) RecordState(id ID, before, after []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.states[id]; ok {
+		s.after = after
+		return
+	}
+	r.states[id] = &auditStates{before: before, after: after}
+}
+
+// State returns the stored states of the resource with the ID before its first change
+// and after its last change. It returns false if the resource has not changed.
+func (r *AuditRecording) State(id ID) (before, after []byte, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.states[id]
+	if !ok {
+		return nil, nil, false
+	}
+	return s.before, s.after, true
+}
+
+// Created returns the IDs of the resources that were stored by the changes but not before them.
+func (r *AuditRecording) Created() []ID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []ID
+	for id, s := range r.states {
+		if s.before == nil && s.after != nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// RecordSecret records a change of the secret with the key of the organization.
+// The value of the secret is never recorded, only whether it existed before and after the change.
+func (r *AuditRecording) RecordSecret(orgID ID, key string, existed, exists bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	secrets, ok := r.secrets[orgID]
+	if !ok {
+		secrets = make(map[string]AuditChange)
+		r.secrets[orgID] = secrets
+	}
+
+	c, ok := secrets[key]
+	if !ok && existed {
+		c.Before = AuditRedacted
+	}
+	c.After = nil
+	if exists {
+		c.After = AuditRedacted
+	}
+	secrets[key] = c
+}
+
+// Secrets returns the changes of the secrets of the organization by key, with their values redacted.
+func (r *AuditRecording) Secrets(orgID ID) map[string]AuditChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.secrets[orgID]) == 0 {
+		return nil
+	}
+	secrets := make(map[string]AuditChange, len(r.secrets[orgID]))
+	for k, c := range r.secrets[orgID] {
+		secrets[k] = c
+	}
+	return secrets
+}
+
+// AuditRedacted is the value recorded in place of a secret value.
+var AuditRedacted = json.RawMessage(`"[REDACTED]"`)
+
+type auditRecordingContextKey struct{}
+
+// ContextWithAuditRecording returns a context on which the changes of the stored resources are recorded into r.
+func ContextWithAuditRecording(ctx context.Context, r *AuditRecording) context.Context {
+	return context.WithValue(ctx, auditRecordingContextKey{}, r)
+}
+
+// AuditRecordingFromContext returns the AuditRecording of the context, or nil if the changes are not recorded.
+func AuditRecordingFromContext(ctx context.Context) *AuditRecording {
+	r, _ := ctx.Value(auditRecordingContextKey{}).(*AuditRecording)
+	return r
+}
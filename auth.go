@@ -3,6 +3,7 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // AuthorizationKind is returned by (*Authorization).Kind().
@@ -14,6 +15,12 @@ var (
 		Msg:  "unable to create token",
 		Code: EInvalid,
 	}
+
+	// ErrAuthorizationExpired is returned when an authorization is used after its expiration.
+	ErrAuthorizationExpired = &Error{
+		Msg:  "authorization expired",
+		Code: EUnauthorized,
+	}
 )
 
 // Authorization is an authorization. 🎉
@@ -25,12 +32,16 @@ type Authorization struct {
 	OrgID       ID           `json:"orgID"`
 	UserID      ID           `json:"userID,omitempty"`
 	Permissions []Permission `json:"permissions"`
+	// ExpiresAt is the time after which the authorization is no longer active.
+	// An authorization without expiration is valid until it is deactivated or deleted.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
 // AuthorizationUpdate is the authorization update request.
 type AuthorizationUpdate struct {
-	Status      *Status `json:"status,omitempty"`
-	Description *string `json:"description,omitempty"`
+	Status      *Status    `json:"status,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
 }
 
 // Valid ensures that the authorization is valid.
@@ -62,9 +73,18 @@ func IsActive(a *Authorization) bool {
 	return a.IsActive()
 }
 
-// IsActive returns true if the authorization active.
+// IsActive returns true if the authorization active and unexpired at the current time.
 func (a *Authorization) IsActive() bool {
-	return a.Status == Active
+	return a.Status == Active && a.Expired(time.Now()) == nil
+}
+
+// Expired returns an error if the authorization is expired at now.
+func (a *Authorization) Expired(now time.Time) error {
+	if a.ExpiresAt != nil && now.After(*a.ExpiresAt) {
+		return ErrAuthorizationExpired
+	}
+
+	return nil
 }
 
 // GetUserID returns the user id.
@@ -103,13 +123,21 @@ type AuthorizationService interface {
 	// Creates a new authorization and sets a.Token and a.UserID with the new identifier.
 	CreateAuthorization(ctx context.Context, a *Authorization) error
 
-	// UpdateAuthorization updates the status and description if available.
+	// UpdateAuthorization updates the status, description and expiration if available.
 	UpdateAuthorization(ctx context.Context, id ID, udp *AuthorizationUpdate) (*Authorization, error)
 
 	// Removes a authorization by token.
 	DeleteAuthorization(ctx context.Context, id ID) error
 }
 
+// AuthorizationRotationService replaces the token of authorizations.
+type AuthorizationRotationService interface {
+	// RotateAuthorization creates a new authorization, with a new token and the same permissions
+	// as the authorization id, and makes the authorization id expire after gracePeriod.
+	// The expiration of the authorization id is left unchanged if it would expire earlier.
+	RotateAuthorization(ctx context.Context, id ID, gracePeriod time.Duration) (*Authorization, error)
+}
+
 // AuthorizationFilter represents a set of filter that restrict the returned results.
 type AuthorizationFilter struct {
 	Token *string
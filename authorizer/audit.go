@@ -0,0 +1,91 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.AuditLogService = (*AuditLogService)(nil)
+
+// AuditLogService wraps a influxdb.AuditLogService and authorizes actions
+// against it appropriately.
+type AuditLogService struct {
+	s influxdb.AuditLogService
+}
+
+// NewAuditLogService constructs an instance of an authorizing audit log service.
+func NewAuditLogService(s influxdb.AuditLogService) *AuditLogService {
+	return &AuditLogService{
+		s: s,
+	}
+}
+
+// newAuditPermission returns the permission to the audit events of the organization,
+// or to the events of no organization if orgID is invalid.
+func newAuditPermission(a influxdb.Action, orgID influxdb.ID) (*influxdb.Permission, error) {
+	if !orgID.Valid() {
+		return influxdb.NewGlobalPermission(a, influxdb.AuditResourceType)
+	}
+	return influxdb.NewPermission(a, influxdb.AuditResourceType, orgID)
+}
+
+func authorizeAudit(ctx context.Context, a influxdb.Action, orgID influxdb.ID) error {
+	p, err := newAuditPermission(a, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AppendAuditEvent checks to see if the authorizer on context has write access to the audit log
+// of the organization of the event.
+func (s *AuditLogService) AppendAuditEvent(ctx context.Context, e *influxdb.AuditEvent) error {
+	if err := authorizeAudit(ctx, influxdb.WriteAction, e.OrgID); err != nil {
+		return err
+	}
+
+	return s.s.AppendAuditEvent(ctx, e)
+}
+
+// FindAuditEvents retrieves all audit events that match the provided filter and then filters the list down
+// to the events of the organizations whose audit log is authorized.
+func (s *AuditLogService) FindAuditEvents(ctx context.Context, filter influxdb.AuditEventFilter, opt ...influxdb.FindOptions) ([]*influxdb.AuditEvent, int, error) {
+	if filter.OrgID != nil {
+		if err := authorizeAudit(ctx, influxdb.ReadAction, *filter.OrgID); err != nil {
+			return nil, 0, err
+		}
+
+		return s.s.FindAuditEvents(ctx, filter, opt...)
+	}
+
+	// TODO: we'll likely want to push this operation into the database eventually since fetching the whole list of data
+	// will likely be expensive.
+	es, _, err := s.s.FindAuditEvents(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	events := es[:0]
+	for _, e := range es {
+		err := authorizeAudit(ctx, influxdb.ReadAction, e.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		events = append(events, e)
+	}
+
+	return events, len(events), nil
+}
@@ -0,0 +1,94 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestAuditLogService_FindAuditEvents(t *testing.T) {
+	orgOne := influxdbtesting.MustIDBase16(orgOneID)
+	orgTwo := influxdbtesting.MustIDBase16("020f755c3c083001")
+	events := func() []*influxdb.AuditEvent {
+		return []*influxdb.AuditEvent{
+			{ID: 1, OrgID: orgOne},
+			{ID: 2, OrgID: orgTwo},
+			{ID: 3},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		filter      influxdb.AuditEventFilter
+		wantIDs     []influxdb.ID
+		wantErr     bool
+	}{
+		{
+			name: "global permission reads all events",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.AuditResourceType}},
+			},
+			wantIDs: []influxdb.ID{1, 2, 3},
+		},
+		{
+			name: "org permission reads the events of the org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.AuditResourceType, OrgID: &orgOne}},
+			},
+			wantIDs: []influxdb.ID{1},
+		},
+		{
+			name: "org filter requires org permission",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.AuditResourceType, OrgID: &orgOne}},
+			},
+			filter:  influxdb.AuditEventFilter{OrgID: &orgTwo},
+			wantErr: true,
+		},
+		{
+			name: "other permissions read no events",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}},
+			},
+			wantIDs: []influxdb.ID{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewAuditLogService()
+			m.FindAuditEventsFn = func(ctx context.Context, filter influxdb.AuditEventFilter, opt ...influxdb.FindOptions) ([]*influxdb.AuditEvent, int, error) {
+				es := events()
+				return es, len(es), nil
+			}
+			s := authorizer.NewAuditLogService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+			es, _, err := s.FindAuditEvents(ctx, tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tt.wantErr {
+				if influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+					t.Errorf("expected unauthorized, got %v", err)
+				}
+				return
+			}
+
+			ids := []influxdb.ID{}
+			for _, e := range es {
+				ids = append(ids, e.ID)
+			}
+			if diff := cmp.Diff(ids, tt.wantIDs); diff != "" {
+				t.Errorf("unexpected events -got/+want\n%s", diff)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package authorizer
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/influxdb"
+	influxdbcontext "github.com/influxdata/influxdb/context"
 )
 
 var _ influxdb.AuthorizationService = (*AuthorizationService)(nil)
@@ -129,6 +131,7 @@ func (s *AuthorizationService) CreateAuthorization(ctx context.Context, a *influ
 }
 
 // VerifyPermission ensures that an authorization is allowed all of the appropriate permissions.
+// A permission restricted to a predicate on context may only be granted with the same predicate.
 func VerifyPermissions(ctx context.Context, ps []influxdb.Permission) error {
 	for _, p := range ps {
 		if err := IsAllowed(ctx, p); err != nil {
@@ -138,11 +141,46 @@ func VerifyPermissions(ctx context.Context, ps []influxdb.Permission) error {
 				Code: influxdb.EForbidden,
 			}
 		}
+
+		if err := verifyPredicate(ctx, p); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// verifyPredicate ensures that the predicates of the permissions on context matching p are not lifted by p.
+func verifyPredicate(ctx context.Context, p influxdb.Permission) error {
+	a, err := influxdbcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	var ps []influxdb.Permission
+	switch a := a.(type) {
+	case *influxdb.Authorization:
+		ps = a.Permissions
+	case *influxdb.Session:
+		ps = a.Permissions
+	}
+
+	predicates := influxdb.PermissionPredicates(p, ps)
+	if len(predicates) == 0 {
+		return nil
+	}
+	for _, predicate := range predicates {
+		if predicate == p.Predicate {
+			return nil
+		}
+	}
+
+	return &influxdb.Error{
+		Msg:  fmt.Sprintf("permission %s is not allowed", p),
+		Code: influxdb.EForbidden,
+	}
+}
+
 // UpdateAuthorization checks to see if the authorizer on context has write access to the authorization provided.
 func (s *AuthorizationService) UpdateAuthorization(ctx context.Context, id influxdb.ID, upd *influxdb.AuthorizationUpdate) (*influxdb.Authorization, error) {
 	a, err := s.s.FindAuthorizationByID(ctx, id)
@@ -170,3 +208,40 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id influ
 
 	return s.s.DeleteAuthorization(ctx, id)
 }
+
+var _ influxdb.AuthorizationRotationService = (*AuthorizationRotationService)(nil)
+
+// AuthorizationRotationService wraps a influxdb.AuthorizationRotationService and authorizes actions
+// against it appropriately.
+type AuthorizationRotationService struct {
+	auths influxdb.AuthorizationService
+	s     influxdb.AuthorizationRotationService
+}
+
+// NewAuthorizationRotationService constructs an instance of an authorizing authorization rotation service.
+// The authorization service is used to look up the authorizations to rotate.
+func NewAuthorizationRotationService(auths influxdb.AuthorizationService, s influxdb.AuthorizationRotationService) *AuthorizationRotationService {
+	return &AuthorizationRotationService{
+		auths: auths,
+		s:     s,
+	}
+}
+
+// RotateAuthorization checks to see if the authorizer on context has write access to the authorization provided,
+// and is allowed all of its permissions, as it would need to create it.
+func (s *AuthorizationRotationService) RotateAuthorization(ctx context.Context, id influxdb.ID, gracePeriod time.Duration) (*influxdb.Authorization, error) {
+	a, err := s.auths.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteAuthorization(ctx, a.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := VerifyPermissions(ctx, a.Permissions); err != nil {
+		return nil, err
+	}
+
+	return s.s.RotateAuthorization(ctx, id, gracePeriod)
+}
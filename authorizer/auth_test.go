@@ -270,3 +270,77 @@ func TestAuthorizationService_WriteAuthorization(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorizationService_CreateAuthorizationWithPredicate(t *testing.T) {
+	acme := influxdb.Permission{
+		Action: influxdb.ReadAction,
+		Resource: influxdb.Resource{
+			Type:  influxdb.BucketsResourceType,
+			OrgID: influxdbtesting.IDPtr(1),
+			ID:    influxdbtesting.IDPtr(2),
+		},
+		Predicate: `tenant="acme"`,
+	}
+	unrestricted := acme
+	unrestricted.Predicate = ""
+	other := acme
+	other.Predicate = `tenant="other"`
+
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		wants      error
+	}{
+		{
+			name:       "same predicate",
+			permission: acme,
+		},
+		{
+			name:       "without predicate",
+			permission: unrestricted,
+			wants: &influxdb.Error{
+				Msg:  "permission read:orgs/0000000000000001/buckets/0000000000000002 is not allowed",
+				Code: influxdb.EForbidden,
+			},
+		},
+		{
+			name:       "other predicate",
+			permission: other,
+			wants: &influxdb.Error{
+				Msg:  `permission read:orgs/0000000000000001/buckets/0000000000000002[tenant="other"] is not allowed`,
+				Code: influxdb.EForbidden,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mock.AuthorizationService{}
+			m.CreateAuthorizationFn = func(ctx context.Context, a *influxdb.Authorization) error {
+				return nil
+			}
+			s := authorizer.NewAuthorizationService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &influxdb.Authorization{
+				UserID: 1,
+				Status: influxdb.Active,
+				Permissions: []influxdb.Permission{
+					acme,
+					{
+						Action: influxdb.WriteAction,
+						Resource: influxdb.Resource{
+							Type: influxdb.UsersResourceType,
+							ID:   influxdbtesting.IDPtr(1),
+						},
+					},
+				},
+			})
+
+			err := s.CreateAuthorization(ctx, &influxdb.Authorization{
+				UserID:      1,
+				Permissions: []influxdb.Permission{tt.permission},
+			})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants)
+		})
+	}
+}
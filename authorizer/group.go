@@ -0,0 +1,136 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.GroupService = (*GroupService)(nil)
+
+// GroupService wraps a influxdb.GroupService and authorizes actions
+// against it appropriately.
+type GroupService struct {
+	s influxdb.GroupService
+}
+
+// NewGroupService constructs an instance of an authorizing group service.
+func NewGroupService(s influxdb.GroupService) *GroupService {
+	return &GroupService{
+		s: s,
+	}
+}
+
+func newGroupPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.GroupsResourceType, orgID)
+}
+
+func authorizeReadGroup(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newGroupPermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteGroup(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newGroupPermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindGroupByID checks to see if the authorizer on context has read access to the group id provided.
+func (s *GroupService) FindGroupByID(ctx context.Context, id influxdb.ID) (*influxdb.Group, error) {
+	g, err := s.s.FindGroupByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadGroup(ctx, g.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// FindGroups retrieves all groups that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *GroupService) FindGroups(ctx context.Context, filter influxdb.GroupFilter, opt ...influxdb.FindOptions) ([]*influxdb.Group, int, error) {
+	// TODO: we'll likely want to push this operation into the database eventually since fetching the whole list of data
+	// will likely be expensive.
+	gs, _, err := s.s.FindGroups(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	groups := gs[:0]
+	for _, g := range gs {
+		err := authorizeReadGroup(ctx, g.OrgID, g.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		groups = append(groups, g)
+	}
+
+	return groups, len(groups), nil
+}
+
+// CreateGroup checks to see if the authorizer on context has write access to the groups of the organization.
+func (s *GroupService) CreateGroup(ctx context.Context, g *influxdb.Group) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.GroupsResourceType, g.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateGroup(ctx, g)
+}
+
+// UpdateGroup checks to see if the authorizer on context has write access to the group provided.
+func (s *GroupService) UpdateGroup(ctx context.Context, id influxdb.ID, upd influxdb.GroupUpdate) (*influxdb.Group, error) {
+	g, err := s.s.FindGroupByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteGroup(ctx, g.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateGroup(ctx, id, upd)
+}
+
+// DeleteGroup checks to see if the authorizer on context has write access to the group provided.
+func (s *GroupService) DeleteGroup(ctx context.Context, id influxdb.ID) error {
+	g, err := s.s.FindGroupByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteGroup(ctx, g.OrgID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteGroup(ctx, id)
+}
@@ -0,0 +1,112 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestGroupService_FindGroups(t *testing.T) {
+	orgID := influxdbtesting.MustIDBase16(orgOneID)
+	groups := []*influxdb.Group{
+		{ID: 1, OrgID: orgID, Name: "engineers"},
+		{ID: 2, OrgID: orgID, Name: "sre"},
+		{ID: 3, OrgID: influxdbtesting.MustIDBase16("020f755c3c083001"), Name: "support"},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wants       []*influxdb.Group
+	}{
+		{
+			name: "authorized to read the groups of an organization",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.GroupsResourceType, OrgID: &orgID}},
+			},
+			wants: groups[:2],
+		},
+		{
+			name: "authorized to read a single group",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.GroupsResourceType, ID: influxdbtesting.IDPtr(2)}},
+			},
+			wants: groups[1:2],
+		},
+		{
+			name:  "unauthorized to read groups",
+			wants: []*influxdb.Group{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewGroupService()
+			m.FindGroupsFn = func(context.Context, influxdb.GroupFilter, ...influxdb.FindOptions) ([]*influxdb.Group, int, error) {
+				gs := append([]*influxdb.Group{}, groups...)
+				return gs, len(gs), nil
+			}
+			s := authorizer.NewGroupService(m)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.permissions})
+
+			gs, _, err := s.FindGroups(ctx, influxdb.GroupFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(gs, tt.wants); diff != "" {
+				t.Errorf("groups are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestGroupService_DeleteGroup(t *testing.T) {
+	orgID := influxdbtesting.MustIDBase16(orgOneID)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wants       error
+	}{
+		{
+			name: "authorized to delete the group",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.GroupsResourceType, ID: influxdbtesting.IDPtr(1)}},
+			},
+		},
+		{
+			name: "unauthorized to delete the group",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.GroupsResourceType, OrgID: &orgID}},
+			},
+			wants: &influxdb.Error{
+				Msg:  "write:orgs/020f755c3c083000/groups/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewGroupService()
+			m.FindGroupByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Group, error) {
+				return &influxdb.Group{ID: id, OrgID: orgID, Name: "engineers"}, nil
+			}
+			s := authorizer.NewGroupService(m)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.permissions})
+
+			err := s.DeleteGroup(ctx, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RoleService = (*RoleService)(nil)
+
+// RoleService wraps a influxdb.RoleService and authorizes actions
+// against it appropriately.
+type RoleService struct {
+	s influxdb.RoleService
+}
+
+// NewRoleService constructs an instance of an authorizing role service.
+func NewRoleService(s influxdb.RoleService) *RoleService {
+	return &RoleService{
+		s: s,
+	}
+}
+
+func newRolePermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.RolesResourceType, orgID)
+}
+
+func authorizeReadRole(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newRolePermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteRole(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newRolePermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindRoleByID checks to see if the authorizer on context has read access to the role id provided.
+func (s *RoleService) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	r, err := s.s.FindRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadRole(ctx, r.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FindRoles retrieves all roles that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *RoleService) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	// TODO: we'll likely want to push this operation into the database eventually since fetching the whole list of data
+	// will likely be expensive.
+	rs, _, err := s.s.FindRoles(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	roles := rs[:0]
+	for _, r := range rs {
+		err := authorizeReadRole(ctx, r.OrgID, r.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		roles = append(roles, r)
+	}
+
+	return roles, len(roles), nil
+}
+
+// CreateRole checks to see if the authorizer on context has write access to the roles of the organization,
+// and holds the permissions of the new role.
+func (s *RoleService) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.RolesResourceType, r.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	if err := VerifyPermissions(ctx, r.Permissions); err != nil {
+		return err
+	}
+
+	return s.s.CreateRole(ctx, r)
+}
+
+// UpdateRole checks to see if the authorizer on context has write access to the role,
+// and holds the permissions it is updated with.
+func (s *RoleService) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	r, err := s.s.FindRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteRole(ctx, r.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	if upd.Permissions != nil {
+		if err := VerifyPermissions(ctx, *upd.Permissions); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.s.UpdateRole(ctx, id, upd)
+}
+
+// DeleteRole checks to see if the authorizer on context has write access to the role provided.
+func (s *RoleService) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	r, err := s.s.FindRoleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteRole(ctx, r.OrgID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteRole(ctx, id)
+}
+
+// FindRoleBindings retrieves all role bindings that match the provided filter
+// and then filters the list down to the bindings of the roles that are authorized.
+func (s *RoleService) FindRoleBindings(ctx context.Context, filter influxdb.RoleBindingFilter) ([]*influxdb.RoleBinding, error) {
+	bs, err := s.s.FindRoleBindings(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := bs[:0]
+	for _, b := range bs {
+		r, err := s.s.FindRoleByID(ctx, b.RoleID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = authorizeReadRole(ctx, r.OrgID, r.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		bindings = append(bindings, b)
+	}
+
+	return bindings, nil
+}
+
+// CreateRoleBinding checks to see if the authorizer on context has write access to the role,
+// and holds the permissions granted by binding it.
+func (s *RoleService) CreateRoleBinding(ctx context.Context, b *influxdb.RoleBinding) error {
+	r, err := s.s.FindRoleByID(ctx, b.RoleID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteRole(ctx, r.OrgID, r.ID); err != nil {
+		return err
+	}
+
+	if err := VerifyPermissions(ctx, r.Permissions); err != nil {
+		return err
+	}
+
+	return s.s.CreateRoleBinding(ctx, b)
+}
+
+// DeleteRoleBinding checks to see if the authorizer on context has write access to the role.
+func (s *RoleService) DeleteRoleBinding(ctx context.Context, roleID, userID influxdb.ID) error {
+	r, err := s.s.FindRoleByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteRole(ctx, r.OrgID, r.ID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteRoleBinding(ctx, roleID, userID)
+}
@@ -0,0 +1,152 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestRoleService_CreateRole(t *testing.T) {
+	orgID := influxdbtesting.MustIDBase16(orgOneID)
+	readBuckets := influxdb.Permission{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+	}
+	writeRoles := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.RolesResourceType, OrgID: &orgID},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wants       error
+	}{
+		{
+			name:        "authorized to create role with held permissions",
+			permissions: []influxdb.Permission{writeRoles, readBuckets},
+		},
+		{
+			name:        "unauthorized to create roles",
+			permissions: []influxdb.Permission{readBuckets},
+			wants: &influxdb.Error{
+				Msg:  "write:orgs/020f755c3c083000/roles is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name:        "forbidden to grant permissions not held",
+			permissions: []influxdb.Permission{writeRoles},
+			wants: &influxdb.Error{
+				Msg:  "permission read:orgs/020f755c3c083000/buckets is not allowed",
+				Code: influxdb.EForbidden,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewRoleService(mock.NewRoleService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.permissions})
+
+			err := s.CreateRole(ctx, &influxdb.Role{
+				OrgID:       orgID,
+				Name:        "bucket readers",
+				Permissions: []influxdb.Permission{readBuckets},
+			})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants)
+		})
+	}
+}
+
+func TestRoleService_CreateRoleBinding(t *testing.T) {
+	orgID := influxdbtesting.MustIDBase16(orgOneID)
+	readBuckets := influxdb.Permission{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+	}
+	writeRole := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.RolesResourceType, ID: influxdbtesting.IDPtr(1)},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wants       error
+	}{
+		{
+			name:        "authorized to bind role",
+			permissions: []influxdb.Permission{writeRole, readBuckets},
+		},
+		{
+			name:        "unauthorized to write role",
+			permissions: []influxdb.Permission{readBuckets},
+			wants: &influxdb.Error{
+				Msg:  "write:orgs/020f755c3c083000/roles/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name:        "forbidden to bind role with permissions not held",
+			permissions: []influxdb.Permission{writeRole},
+			wants: &influxdb.Error{
+				Msg:  "permission read:orgs/020f755c3c083000/buckets is not allowed",
+				Code: influxdb.EForbidden,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles := mock.NewRoleService()
+			roles.FindRoleByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+				return &influxdb.Role{
+					ID:          id,
+					OrgID:       orgID,
+					Name:        "bucket readers",
+					Permissions: []influxdb.Permission{readBuckets},
+				}, nil
+			}
+			s := authorizer.NewRoleService(roles)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.permissions})
+
+			err := s.CreateRoleBinding(ctx, &influxdb.RoleBinding{RoleID: 1, UserID: 2})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants)
+		})
+	}
+}
+
+func TestRoleService_FindRoles(t *testing.T) {
+	orgID := influxdbtesting.MustIDBase16(orgOneID)
+	roles := mock.NewRoleService()
+	roles.FindRolesFn = func(context.Context, influxdb.RoleFilter, ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+		return []*influxdb.Role{
+			{ID: 1, OrgID: orgID, Name: "a"},
+			{ID: 2, OrgID: orgID, Name: "b"},
+		}, 2, nil
+	}
+	s := authorizer.NewRoleService(roles)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.RolesResourceType, ID: influxdbtesting.IDPtr(2)},
+	}}})
+
+	rs, n, err := s.FindRoles(ctx, influxdb.RoleFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || len(rs) != 1 || rs[0].ID != 2 {
+		t.Fatalf("unexpected roles %+v", rs)
+	}
+}
@@ -0,0 +1,108 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+)
+
+var _ influxdb.RunningQueryService = (*RunningQueryService)(nil)
+
+// RunningQueryService wraps a influxdb.RunningQueryService and authorizes actions
+// against it appropriately. Users may always read and kill their own queries.
+type RunningQueryService struct {
+	s influxdb.RunningQueryService
+}
+
+// NewRunningQueryService constructs an instance of an authorizing running query service.
+func NewRunningQueryService(s influxdb.RunningQueryService) *RunningQueryService {
+	return &RunningQueryService{
+		s: s,
+	}
+}
+
+// newQueriesPermission returns the permission to the queries of the organization,
+// or to the queries of no organization if orgID is invalid.
+func newQueriesPermission(a influxdb.Action, orgID influxdb.ID) (*influxdb.Permission, error) {
+	if !orgID.Valid() {
+		return influxdb.NewGlobalPermission(a, influxdb.QueriesResourceType)
+	}
+	return influxdb.NewPermission(a, influxdb.QueriesResourceType, orgID)
+}
+
+func authorizeRunningQuery(ctx context.Context, a influxdb.Action, q *influxdb.RunningQuery) error {
+	auth, err := influxdbcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+	if userID := auth.GetUserID(); userID.Valid() && userID == q.UserID {
+		return nil
+	}
+
+	p, err := newQueriesPermission(a, q.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindRunningQueries retrieves all running queries that match the provided filter and then filters the list down
+// to the queries that are authorized.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+	qs, err := s.s.FindRunningQueries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	queries := qs[:0]
+	for _, q := range qs {
+		err := authorizeRunningQuery(ctx, influxdb.ReadAction, q)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		queries = append(queries, q)
+	}
+
+	return queries, nil
+}
+
+// FindRunningQueryByID checks to see if the authorizer on context has read access to the query.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+	q, err := s.s.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRunningQuery(ctx, influxdb.ReadAction, q); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// KillRunningQuery checks to see if the authorizer on context has write access to the query.
+func (s *RunningQueryService) KillRunningQuery(ctx context.Context, id influxdb.ID) error {
+	q, err := s.s.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeRunningQuery(ctx, influxdb.WriteAction, q); err != nil {
+		return err
+	}
+
+	return s.s.KillRunningQuery(ctx, id)
+}
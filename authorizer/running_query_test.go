@@ -0,0 +1,140 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestRunningQueryService_FindRunningQueries(t *testing.T) {
+	orgOne := influxdbtesting.MustIDBase16(orgOneID)
+	orgTwo := influxdbtesting.MustIDBase16("020f755c3c083001")
+	queries := func() []*influxdb.RunningQuery {
+		return []*influxdb.RunningQuery{
+			{ID: 1, OrgID: orgOne, UserID: 2},
+			{ID: 2, OrgID: orgOne, UserID: 5},
+			{ID: 3, OrgID: orgTwo, UserID: 5},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantIDs     []influxdb.ID
+	}{
+		{
+			name: "global permission reads all queries",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.QueriesResourceType}},
+			},
+			wantIDs: []influxdb.ID{1, 2, 3},
+		},
+		{
+			name: "org permission reads the queries of the org",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.QueriesResourceType, OrgID: &orgTwo}},
+			},
+			wantIDs: []influxdb.ID{1, 3},
+		},
+		{
+			name: "users read their own queries",
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}},
+			},
+			wantIDs: []influxdb.ID{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewRunningQueryService()
+			m.FindRunningQueriesFn = func(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+				return queries(), nil
+			}
+			s := authorizer.NewRunningQueryService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+			qs, err := s.FindRunningQueries(ctx, influxdb.RunningQueryFilter{})
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			ids := []influxdb.ID{}
+			for _, q := range qs {
+				ids = append(ids, q.ID)
+			}
+			if diff := cmp.Diff(ids, tt.wantIDs); diff != "" {
+				t.Errorf("unexpected queries -got/+want\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunningQueryService_KillRunningQuery(t *testing.T) {
+	orgOne := influxdbtesting.MustIDBase16(orgOneID)
+
+	tests := []struct {
+		name        string
+		userID      influxdb.ID
+		permissions []influxdb.Permission
+		wantErr     bool
+	}{
+		{
+			name:   "users kill their own queries",
+			userID: 2,
+		},
+		{
+			name:    "killing the queries of other users requires permission",
+			userID:  5,
+			wantErr: true,
+		},
+		{
+			name:   "write permission kills the queries of the org",
+			userID: 5,
+			permissions: []influxdb.Permission{
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.QueriesResourceType, OrgID: &orgOne}},
+			},
+		},
+		{
+			name:   "read permission does not kill queries",
+			userID: 5,
+			permissions: []influxdb.Permission{
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.QueriesResourceType}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var killed bool
+			m := mock.NewRunningQueryService()
+			m.FindRunningQueryByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+				return &influxdb.RunningQuery{ID: id, OrgID: orgOne, UserID: tt.userID}, nil
+			}
+			m.KillRunningQueryFn = func(ctx context.Context, id influxdb.ID) error {
+				killed = true
+				return nil
+			}
+			s := authorizer.NewRunningQueryService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+			err := s.KillRunningQuery(ctx, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tt.wantErr && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("expected unauthorized, got %v", err)
+			}
+			if killed == tt.wantErr {
+				t.Errorf("expected killed to be %v", !tt.wantErr)
+			}
+		})
+	}
+}
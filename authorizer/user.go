@@ -125,10 +125,20 @@ func (s *UserService) CreateUser(ctx context.Context, o *influxdb.User) error {
 }
 
 // UpdateUser checks to see if the authorizer on context has write access to the user provided.
+// Linking the user to an OAuth ID requires write access to all users.
 func (s *UserService) UpdateUser(ctx context.Context, id influxdb.ID, upd influxdb.UserUpdate) (*influxdb.User, error) {
 	if err := authorizeWriteUser(ctx, id); err != nil {
 		return nil, err
 	}
+	if upd.OAuthID != nil {
+		p, err := influxdb.NewGlobalPermission(influxdb.WriteAction, influxdb.UsersResourceType)
+		if err != nil {
+			return nil, err
+		}
+		if err := IsAllowed(ctx, *p); err != nil {
+			return nil, err
+		}
+	}
 
 	return s.s.UpdateUser(ctx, id, upd)
 }
@@ -311,12 +311,15 @@ func TestUserService_FindUsers(t *testing.T) {
 }
 
 func TestUserService_UpdateUser(t *testing.T) {
+	oauthID := "oidc:jane"
+
 	type fields struct {
 		UserService influxdb.UserService
 	}
 	type args struct {
 		id         influxdb.ID
 		permission influxdb.Permission
+		update     influxdb.UserUpdate
 	}
 	type wants struct {
 		err error
@@ -381,6 +384,60 @@ func TestUserService_UpdateUser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "authorized to link user to an oauth id",
+			fields: fields{
+				UserService: &mock.UserService{
+					UpdateUserFn: func(ctx context.Context, id influxdb.ID, upd influxdb.UserUpdate) (*influxdb.User, error) {
+						return &influxdb.User{
+							ID: 1,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id: 1,
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.UsersResourceType,
+					},
+				},
+				update: influxdb.UserUpdate{OAuthID: &oauthID},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to link own user to an oauth id",
+			fields: fields{
+				UserService: &mock.UserService{
+					UpdateUserFn: func(ctx context.Context, id influxdb.ID, upd influxdb.UserUpdate) (*influxdb.User, error) {
+						return &influxdb.User{
+							ID: 1,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id: 1,
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.UsersResourceType,
+						ID:   influxdbtesting.IDPtr(1),
+					},
+				},
+				update: influxdb.UserUpdate{OAuthID: &oauthID},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:users is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -390,7 +447,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
 
-			_, err := s.UpdateUser(ctx, tt.args.id, influxdb.UserUpdate{})
+			_, err := s.UpdateUser(ctx, tt.args.id, tt.args.update)
 			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
 		})
 	}
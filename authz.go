@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+
+	"github.com/influxdata/influxql"
 )
 
 var (
@@ -129,6 +131,14 @@ const (
 	NotificationEndpointResourceType = ResourceType("notificationEndpoints") // 15
 	// ChecksResourceType gives permission to one or more Checks.
 	ChecksResourceType = ResourceType("checks") // 16
+	// RolesResourceType gives permission to one or more roles.
+	RolesResourceType = ResourceType("roles") // 17
+	// AuditResourceType gives permission to the audit log.
+	AuditResourceType = ResourceType("audit") // 18
+	// GroupsResourceType gives permission to one or more groups.
+	GroupsResourceType = ResourceType("groups") // 19
+	// QueriesResourceType gives permission to the running queries.
+	QueriesResourceType = ResourceType("queries") // 20
 )
 
 // AllResourceTypes is the list of all known resource types.
@@ -150,6 +160,10 @@ var AllResourceTypes = []ResourceType{
 	NotificationRuleResourceType,     // 14
 	NotificationEndpointResourceType, // 15
 	ChecksResourceType,               // 16
+	RolesResourceType,                // 17
+	AuditResourceType,                // 18
+	GroupsResourceType,               // 19
+	QueriesResourceType,              // 20
 	// NOTE: when modifying this list, please update the swagger for components.schemas.Permission resource enum.
 }
 
@@ -167,6 +181,10 @@ var OrgResourceTypes = []ResourceType{
 	NotificationRuleResourceType,     // 14
 	NotificationEndpointResourceType, // 15
 	ChecksResourceType,               // 16
+	RolesResourceType,                // 17
+	AuditResourceType,                // 18
+	GroupsResourceType,               // 19
+	QueriesResourceType,              // 20
 }
 
 // Valid checks if the resource type is a member of the ResourceType enum.
@@ -194,6 +212,10 @@ func (t ResourceType) Valid() (err error) {
 	case NotificationRuleResourceType: // 14
 	case NotificationEndpointResourceType: // 15
 	case ChecksResourceType: // 16
+	case RolesResourceType: // 17
+	case AuditResourceType: // 18
+	case GroupsResourceType: // 19
+	case QueriesResourceType: // 20
 	default:
 		err = ErrInvalidResourceType
 	}
@@ -205,9 +227,15 @@ func (t ResourceType) Valid() (err error) {
 type Permission struct {
 	Action   Action   `json:"action"`
 	Resource Resource `json:"resource"`
+
+	// Predicate restricts a bucket read permission to the series matching it,
+	// such as _measurement="cpu" AND tenant="acme".
+	Predicate string `json:"predicate,omitempty"`
 }
 
 // Matches returns whether or not one permission matches the other.
+// The predicate of p is not considered: it restricts the series read from the resource,
+// not the access to the resource.
 func (p Permission) Matches(perm Permission) bool {
 	if p.Action != perm.Action {
 		return false
@@ -245,6 +273,9 @@ func (p Permission) Matches(perm Permission) bool {
 }
 
 func (p Permission) String() string {
+	if p.Predicate != "" {
+		return fmt.Sprintf("%s:%s[%s]", p.Action, p.Resource, p.Predicate)
+	}
 	return fmt.Sprintf("%s:%s", p.Action, p.Resource)
 }
 
@@ -282,9 +313,98 @@ func (p *Permission) Valid() error {
 		}
 	}
 
+	if p.Predicate != "" {
+		if p.Action != ReadAction || p.Resource.Type != BucketsResourceType {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "predicates are only supported by bucket read permissions",
+			}
+		}
+
+		if _, err := p.PredicateExpr(); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Err:  err,
+				Msg:  "invalid predicate for permission",
+			}
+		}
+	}
+
 	return nil
 }
 
+// PredicateExpr parses the predicate of the permission.
+// A predicate compares tag keys, _measurement and _field to strings or regular expressions
+// with =, !=, =~ and !~, combined with AND, OR and parentheses.
+// Both 'cpu' and "cpu" are strings in a predicate.
+func (p Permission) PredicateExpr() (influxql.Expr, error) {
+	expr, err := influxql.ParseExpr(p.Predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePredicateExpr(expr); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+func validatePredicateExpr(expr influxql.Expr) error {
+	switch expr := expr.(type) {
+	case *influxql.ParenExpr:
+		return validatePredicateExpr(expr.Expr)
+	case *influxql.BinaryExpr:
+		switch expr.Op {
+		case influxql.AND, influxql.OR:
+			if err := validatePredicateExpr(expr.LHS); err != nil {
+				return err
+			}
+			return validatePredicateExpr(expr.RHS)
+		case influxql.EQ, influxql.NEQ:
+			switch expr.RHS.(type) {
+			case *influxql.StringLiteral, *influxql.VarRef:
+			default:
+				return fmt.Errorf("expected a string to compare to, got %s", expr.RHS)
+			}
+		case influxql.EQREGEX, influxql.NEQREGEX:
+			if _, ok := expr.RHS.(*influxql.RegexLiteral); !ok {
+				return fmt.Errorf("expected a regular expression to match, got %s", expr.RHS)
+			}
+		default:
+			return fmt.Errorf("unsupported operator %s", expr.Op)
+		}
+
+		ref, ok := expr.LHS.(*influxql.VarRef)
+		if !ok {
+			return fmt.Errorf("expected a tag key, got %s", expr.LHS)
+		}
+		if ref.Val == "_value" || ref.Val == "_time" {
+			return fmt.Errorf("unsupported key %s", ref.Val)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported expression %s", expr)
+	}
+}
+
+// PermissionPredicates returns the predicates of the permissions of ps that match perm.
+// It returns nil if no permission matches perm or one of the matching permissions has no predicate,
+// in which case perm is not restricted to a predicate.
+func PermissionPredicates(perm Permission, ps []Permission) []string {
+	var predicates []string
+	for _, p := range ps {
+		if !p.Matches(perm) {
+			continue
+		}
+		if p.Predicate == "" {
+			return nil
+		}
+		predicates = append(predicates, p.Predicate)
+	}
+	return predicates
+}
+
 // NewPermission returns a permission with provided arguments.
 func NewPermission(a Action, rt ResourceType, orgID ID) (*Permission, error) {
 	p := &Permission{
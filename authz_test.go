@@ -1,6 +1,7 @@
 package influxdb_test
 
 import (
+	"reflect"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
@@ -243,8 +244,9 @@ func TestAuthorizer_PermissionAllowed(t *testing.T) {
 
 func TestPermission_Valid(t *testing.T) {
 	type fields struct {
-		Action   platform.Action
-		Resource platform.Resource
+		Action    platform.Action
+		Resource  platform.Resource
+		Predicate string
 	}
 	tests := []struct {
 		name    string
@@ -302,12 +304,74 @@ func TestPermission_Valid(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid bucket read permission with a predicate",
+			fields: fields{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					ID:    validID(),
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Predicate: `_measurement="cpu" AND (tenant="acme" OR host =~ /^acme-/)`,
+			},
+		},
+		{
+			name: "invalid bucket write permission with a predicate",
+			fields: fields{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					ID:    validID(),
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Predicate: `tenant="acme"`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid dashboard read permission with a predicate",
+			fields: fields{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.DashboardsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Predicate: `tenant="acme"`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid bucket read permission with a field value predicate",
+			fields: fields{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Predicate: `_value > 10`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid bucket read permission with a malformed predicate",
+			fields: fields{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Predicate: `tenant="acme" AND`,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &platform.Permission{
-				Action:   tt.fields.Action,
-				Resource: tt.fields.Resource,
+				Action:    tt.fields.Action,
+				Resource:  tt.fields.Resource,
+				Predicate: tt.fields.Predicate,
 			}
 			if err := p.Valid(); (err != nil) != tt.wantErr {
 				t.Errorf("Permission.Valid() error = %v, wantErr %v", err, tt.wantErr)
@@ -435,3 +499,67 @@ func validID() *platform.ID {
 	id := platform.ID(100)
 	return &id
 }
+
+func TestPermissionPredicates(t *testing.T) {
+	bucket := platform.Permission{
+		Action: platform.ReadAction,
+		Resource: platform.Resource{
+			Type:  platform.BucketsResourceType,
+			OrgID: influxdbtesting.IDPtr(1),
+			ID:    influxdbtesting.IDPtr(2),
+		},
+	}
+	acme := platform.Permission{
+		Action: platform.ReadAction,
+		Resource: platform.Resource{
+			Type:  platform.BucketsResourceType,
+			OrgID: influxdbtesting.IDPtr(1),
+			ID:    influxdbtesting.IDPtr(2),
+		},
+		Predicate: `tenant="acme"`,
+	}
+	orgBuckets := platform.Permission{
+		Action: platform.ReadAction,
+		Resource: platform.Resource{
+			Type:  platform.BucketsResourceType,
+			OrgID: influxdbtesting.IDPtr(1),
+		},
+	}
+	otherBucket := platform.Permission{
+		Action: platform.ReadAction,
+		Resource: platform.Resource{
+			Type:  platform.BucketsResourceType,
+			OrgID: influxdbtesting.IDPtr(1),
+			ID:    influxdbtesting.IDPtr(3),
+		},
+		Predicate: `tenant="other"`,
+	}
+
+	tests := []struct {
+		name        string
+		permissions []platform.Permission
+		want        []string
+	}{
+		{
+			name:        "predicate",
+			permissions: []platform.Permission{acme, otherBucket},
+			want:        []string{`tenant="acme"`},
+		},
+		{
+			name:        "unrestricted permission",
+			permissions: []platform.Permission{acme, orgBuckets},
+		},
+		{
+			name:        "no matching permission",
+			permissions: []platform.Permission{otherBucket},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := platform.PermissionPredicates(bucket, tt.permissions)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PermissionPredicates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,7 +25,7 @@ func (c *Client) initializeAuthorizations(ctx context.Context, tx *bolt.Tx) erro
 	return nil
 }
 
-// FindAuthorizationByID retrieves a authorization by id.
+// FindAuthorizationByID retrieves a authorization by id, without its token.
 func (c *Client) FindAuthorizationByID(ctx context.Context, id platform.ID) (*platform.Authorization, error) {
 	var a *platform.Authorization
 	var err error
@@ -38,8 +38,12 @@ func (c *Client) FindAuthorizationByID(ctx context.Context, id platform.ID) (*pl
 		}
 		return err
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return a, err
+	a.Token = ""
+	return a, nil
 }
 
 func (c *Client) findAuthorizationByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.Authorization, *platform.Error) {
@@ -143,6 +147,7 @@ func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platfor
 // FindAuthorizations retrives all authorizations that match an arbitrary authorization filter.
 // Filters using ID, or Token should be efficient.
 // Other filters will do a linear scan across all authorizations searching for a match.
+// The tokens of the authorizations are omitted unless they are filtered by token.
 func (c *Client) FindAuthorizations(ctx context.Context, filter platform.AuthorizationFilter, opt ...platform.FindOptions) ([]*platform.Authorization, int, error) {
 	if filter.ID != nil {
 		a, err := c.FindAuthorizationByID(ctx, *filter.ID)
@@ -185,6 +190,9 @@ func (c *Client) FindAuthorizations(ctx context.Context, filter platform.Authori
 		}
 	}
 
+	for _, a := range as {
+		a.Token = ""
+	}
 	return as, len(as), nil
 }
 
@@ -402,7 +410,8 @@ func (c *Client) deleteAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 	return nil
 }
 
-// UpdateAuthorization updates the status and description if available.
+// UpdateAuthorization updates the status, description and expiration if available.
+// The updated authorization is returned without its token.
 func (c *Client) UpdateAuthorization(ctx context.Context, id platform.ID, upd *platform.AuthorizationUpdate) (*platform.Authorization, error) {
 	var a *platform.Authorization
 	err := c.db.Update(func(tx *bolt.Tx) error {
@@ -416,7 +425,12 @@ func (c *Client) UpdateAuthorization(ctx context.Context, id platform.ID, upd *p
 		}
 		return nil
 	})
-	return a, err
+	if err != nil {
+		return nil, err
+	}
+
+	a.Token = ""
+	return a, nil
 }
 
 func (c *Client) updateAuthorization(ctx context.Context, tx *bolt.Tx, id platform.ID, upd *platform.AuthorizationUpdate) (*platform.Authorization, *platform.Error) {
@@ -431,6 +445,9 @@ func (c *Client) updateAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 	if upd.Description != nil {
 		a.Description = *upd.Description
 	}
+	if upd.ExpiresAt != nil {
+		a.ExpiresAt = upd.ExpiresAt
+	}
 
 	b, err := encodeAuthorization(a)
 	if err != nil {
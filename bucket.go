@@ -13,8 +13,35 @@ type BucketType int
 const (
 	// BucketTypeLogs defines the bucket ID of the system logs.
 	BucketTypeLogs = BucketType(iota + 10)
+	// BucketTypeAudit defines the bucket ID of the mirrored audit events.
+	BucketTypeAudit
+	// BucketTypeQueryLog defines the bucket ID of the logged slow queries.
+	BucketTypeQueryLog
 )
 
+// SystemBucketReadPermission returns the permission required to read the system bucket
+// with the ID within the organization. The data of the audit and query log buckets is
+// protected by the resource it records rather than by a bucket permission.
+// It returns false if the ID is not one of these system buckets.
+func SystemBucketReadPermission(orgID, id ID) (*Permission, bool) {
+	var rt ResourceType
+	switch BucketType(id) {
+	case BucketTypeAudit:
+		rt = AuditResourceType
+	case BucketTypeQueryLog:
+		rt = QueriesResourceType
+	default:
+		return nil, false
+	}
+	return &Permission{
+		Action: ReadAction,
+		Resource: Resource{
+			Type:  rt,
+			OrgID: &orgID,
+		},
+	}, true
+}
+
 // InfiniteRetention is default infinite retention period.
 const InfiniteRetention = 0
 
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"os"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
@@ -615,3 +616,80 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// AuthorizationRotateFlags are command line args used when rotating an authorization
+type AuthorizationRotateFlags struct {
+	id          string
+	gracePeriod time.Duration
+}
+
+var authorizationRotateFlags AuthorizationRotateFlags
+
+func init() {
+	authorizationRotateCmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Replace the token of an authorization",
+		Long: `Create an authorization with a new token and the permissions of an authorization,
+and make the token of that authorization expire after a grace period.`,
+		RunE: wrapCheckSetup(authorizationRotateF),
+	}
+
+	authorizationRotateCmd.Flags().StringVarP(&authorizationRotateFlags.id, "id", "i", "", "The authorization ID (required)")
+	authorizationRotateCmd.MarkFlagRequired("id")
+	authorizationRotateCmd.Flags().DurationVarP(&authorizationRotateFlags.gracePeriod, "grace-period", "", 0, "How long the rotated token remains valid")
+
+	authorizationCmd.AddCommand(authorizationRotateCmd)
+}
+
+func newAuthorizationRotationService(f Flags) (platform.AuthorizationRotationService, error) {
+	if flags.local {
+		return newLocalKVService()
+	}
+	return &http.AuthorizationService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+func authorizationRotateF(cmd *cobra.Command, args []string) error {
+	s, err := newAuthorizationRotationService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(authorizationRotateFlags.id); err != nil {
+		return err
+	}
+
+	a, err := s.RotateAuthorization(context.Background(), id, authorizationRotateFlags.gracePeriod)
+	if err != nil {
+		return err
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Token",
+		"Status",
+		"UserID",
+		"Permissions",
+	)
+
+	ps := []string{}
+	for _, p := range a.Permissions {
+		ps = append(ps, p.String())
+	}
+
+	w.Write(map[string]interface{}{
+		"ID":          a.ID.String(),
+		"Token":       a.Token,
+		"Status":      a.Status,
+		"UserID":      a.UserID.String(),
+		"Permissions": ps,
+	})
+
+	w.Flush()
+
+	return nil
+}
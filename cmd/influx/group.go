@@ -0,0 +1,539 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Group Command
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Group management commands",
+	Run:   groupF,
+}
+
+func groupF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func newGroupService(f Flags) (platform.GroupService, error) {
+	if flags.local {
+		return newLocalKVService()
+	}
+	return &http.GroupService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+// newGroupMappingService returns a service mapping users or groups to the resources of the resource type.
+func newGroupMappingService(f Flags, rt platform.ResourceType) (platform.UserResourceMappingService, error) {
+	if flags.local {
+		return newLocalKVService()
+	}
+	return &http.UserResourceMappingService{
+		Addr:     flags.host,
+		Token:    flags.token,
+		BasePath: path.Join("/api/v2", string(rt)),
+	}, nil
+}
+
+func writeGroups(gs ...*platform.Group) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"OrgID",
+		"Description",
+	)
+	for _, g := range gs {
+		w.Write(map[string]interface{}{
+			"ID":          g.ID.String(),
+			"Name":        g.Name,
+			"OrgID":       g.OrgID.String(),
+			"Description": g.Description,
+		})
+	}
+	w.Flush()
+}
+
+// Create Command
+type GroupCreateFlags struct {
+	name        string
+	description string
+	orgID       string
+}
+
+var groupCreateFlags GroupCreateFlags
+
+func init() {
+	groupCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create group",
+		RunE:  wrapCheckSetup(groupCreateF),
+	}
+
+	groupCreateCmd.Flags().StringVarP(&groupCreateFlags.name, "name", "n", "", "The name of the group that will be created")
+	groupCreateCmd.Flags().StringVarP(&groupCreateFlags.description, "description", "d", "", "The description of the group")
+	groupCreateCmd.Flags().StringVarP(&groupCreateFlags.orgID, "org-id", "", "", "The ID of the organization of the group")
+	groupCreateCmd.MarkFlagRequired("name")
+	groupCreateCmd.MarkFlagRequired("org-id")
+
+	groupCmd.AddCommand(groupCreateCmd)
+}
+
+func groupCreateF(cmd *cobra.Command, args []string) error {
+	s, err := newGroupService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize group service client: %v", err)
+	}
+
+	g := &platform.Group{
+		Name:        groupCreateFlags.name,
+		Description: groupCreateFlags.description,
+	}
+	if err := g.OrgID.DecodeFromString(groupCreateFlags.orgID); err != nil {
+		return fmt.Errorf("failed to decode org id %s: %v", groupCreateFlags.orgID, err)
+	}
+
+	if err := s.CreateGroup(context.Background(), g); err != nil {
+		return fmt.Errorf("failed to create group: %v", err)
+	}
+
+	writeGroups(g)
+	return nil
+}
+
+// Find Command
+type GroupFindFlags struct {
+	id    string
+	name  string
+	orgID string
+}
+
+var groupFindFlags GroupFindFlags
+
+func init() {
+	groupFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find groups",
+		RunE:  wrapCheckSetup(groupFindF),
+	}
+
+	groupFindCmd.Flags().StringVarP(&groupFindFlags.id, "id", "i", "", "The group ID")
+	groupFindCmd.Flags().StringVarP(&groupFindFlags.name, "name", "n", "", "The group name")
+	groupFindCmd.Flags().StringVarP(&groupFindFlags.orgID, "org-id", "", "", "The group organization ID")
+
+	groupCmd.AddCommand(groupFindCmd)
+}
+
+func groupFindF(cmd *cobra.Command, args []string) error {
+	s, err := newGroupService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize group service client: %v", err)
+	}
+
+	filter := platform.GroupFilter{}
+	if groupFindFlags.name != "" {
+		filter.Name = &groupFindFlags.name
+	}
+
+	if groupFindFlags.id != "" {
+		id, err := platform.IDFromString(groupFindFlags.id)
+		if err != nil {
+			return fmt.Errorf("failed to decode group id %s: %v", groupFindFlags.id, err)
+		}
+		filter.ID = id
+	}
+
+	if groupFindFlags.orgID != "" {
+		orgID, err := platform.IDFromString(groupFindFlags.orgID)
+		if err != nil {
+			return fmt.Errorf("failed to decode org id %s: %v", groupFindFlags.orgID, err)
+		}
+		filter.OrgID = orgID
+	}
+
+	gs, _, err := s.FindGroups(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to find groups: %v", err)
+	}
+
+	writeGroups(gs...)
+	return nil
+}
+
+// Update Command
+type GroupUpdateFlags struct {
+	id          string
+	name        string
+	description string
+}
+
+var groupUpdateFlags GroupUpdateFlags
+
+func init() {
+	groupUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update group",
+		RunE:  wrapCheckSetup(groupUpdateF),
+	}
+
+	groupUpdateCmd.Flags().StringVarP(&groupUpdateFlags.id, "id", "i", "", "The group ID (required)")
+	groupUpdateCmd.Flags().StringVarP(&groupUpdateFlags.name, "name", "n", "", "New group name")
+	groupUpdateCmd.Flags().StringVarP(&groupUpdateFlags.description, "description", "d", "", "New group description")
+	groupUpdateCmd.MarkFlagRequired("id")
+
+	groupCmd.AddCommand(groupUpdateCmd)
+}
+
+func groupUpdateF(cmd *cobra.Command, args []string) error {
+	s, err := newGroupService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize group service client: %v", err)
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(groupUpdateFlags.id); err != nil {
+		return fmt.Errorf("failed to decode group id %s: %v", groupUpdateFlags.id, err)
+	}
+
+	update := platform.GroupUpdate{}
+	if groupUpdateFlags.name != "" {
+		update.Name = &groupUpdateFlags.name
+	}
+	if groupUpdateFlags.description != "" {
+		update.Description = &groupUpdateFlags.description
+	}
+
+	g, err := s.UpdateGroup(context.Background(), id, update)
+	if err != nil {
+		return fmt.Errorf("failed to update group: %v", err)
+	}
+
+	writeGroups(g)
+	return nil
+}
+
+// GroupDeleteFlags contains the flag of the group delete command
+type GroupDeleteFlags struct {
+	id string
+}
+
+var groupDeleteFlags GroupDeleteFlags
+
+func init() {
+	groupDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete group",
+		RunE:  wrapCheckSetup(groupDeleteF),
+	}
+
+	groupDeleteCmd.Flags().StringVarP(&groupDeleteFlags.id, "id", "i", "", "The group ID (required)")
+	groupDeleteCmd.MarkFlagRequired("id")
+
+	groupCmd.AddCommand(groupDeleteCmd)
+}
+
+func groupDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newGroupService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize group service client: %v", err)
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(groupDeleteFlags.id); err != nil {
+		return fmt.Errorf("failed to decode group id %s: %v", groupDeleteFlags.id, err)
+	}
+
+	ctx := context.TODO()
+	g, err := s.FindGroupByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find group with id %q: %v", id, err)
+	}
+
+	if err := s.DeleteGroup(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete group with id %q: %v", id, err)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Deleted",
+	)
+	w.Write(map[string]interface{}{
+		"ID":      g.ID.String(),
+		"Name":    g.Name,
+		"Deleted": true,
+	})
+	w.Flush()
+
+	return nil
+}
+
+// Member management
+var groupMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "Group membership commands",
+	Run:   groupF,
+}
+
+func init() {
+	groupCmd.AddCommand(groupMembersCmd)
+}
+
+// GroupMembersFlags contains the flags of the group members commands
+type GroupMembersFlags struct {
+	id       string
+	memberID string
+	owner    bool
+}
+
+var groupMembersFlags GroupMembersFlags
+
+func (f GroupMembersFlags) userType() platform.UserType {
+	if f.owner {
+		return platform.Owner
+	}
+	return platform.Member
+}
+
+func groupMembersListF(cmd *cobra.Command, args []string) error {
+	s, err := newGroupMappingService(flags, platform.GroupsResourceType)
+	if err != nil {
+		return fmt.Errorf("failed to initialize members service client: %v", err)
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(groupMembersFlags.id); err != nil {
+		return fmt.Errorf("failed to decode group id %s: %v", groupMembersFlags.id, err)
+	}
+
+	filter := platform.UserResourceMappingFilter{
+		ResourceID:   id,
+		ResourceType: platform.GroupsResourceType,
+		UserType:     groupMembersFlags.userType(),
+	}
+	mappings, _, err := s.FindUserResourceMappings(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to find members: %v", err)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Role",
+	)
+	for _, m := range mappings {
+		w.Write(map[string]interface{}{
+			"ID":   m.UserID.String(),
+			"Role": m.UserType,
+		})
+	}
+	w.Flush()
+	return nil
+}
+
+func groupMembersAddF(cmd *cobra.Command, args []string) error {
+	s, err := newGroupMappingService(flags, platform.GroupsResourceType)
+	if err != nil {
+		return fmt.Errorf("failed to initialize members service client: %v", err)
+	}
+
+	var id, memberID platform.ID
+	if err := id.DecodeFromString(groupMembersFlags.id); err != nil {
+		return fmt.Errorf("failed to decode group id %s: %v", groupMembersFlags.id, err)
+	}
+	if err := memberID.DecodeFromString(groupMembersFlags.memberID); err != nil {
+		return fmt.Errorf("failed to decode member id %s: %v", groupMembersFlags.memberID, err)
+	}
+
+	mapping := &platform.UserResourceMapping{
+		ResourceID:   id,
+		ResourceType: platform.GroupsResourceType,
+		UserID:       memberID,
+		UserType:     groupMembersFlags.userType(),
+	}
+	if err := s.CreateUserResourceMapping(context.Background(), mapping); err != nil {
+		return fmt.Errorf("failed to add member: %v", err)
+	}
+
+	return nil
+}
+
+func groupMembersRemoveF(cmd *cobra.Command, args []string) error {
+	s, err := newGroupMappingService(flags, platform.GroupsResourceType)
+	if err != nil {
+		return fmt.Errorf("failed to initialize members service client: %v", err)
+	}
+
+	var id, memberID platform.ID
+	if err := id.DecodeFromString(groupMembersFlags.id); err != nil {
+		return fmt.Errorf("failed to decode group id %s: %v", groupMembersFlags.id, err)
+	}
+	if err := memberID.DecodeFromString(groupMembersFlags.memberID); err != nil {
+		return fmt.Errorf("failed to decode member id %s: %v", groupMembersFlags.memberID, err)
+	}
+
+	if err := s.DeleteUserResourceMapping(context.Background(), id, memberID); err != nil {
+		return fmt.Errorf("failed to remove member: %v", err)
+	}
+
+	return nil
+}
+
+func init() {
+	groupMembersListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List group members",
+		RunE:  wrapCheckSetup(groupMembersListF),
+	}
+	groupMembersListCmd.Flags().StringVarP(&groupMembersFlags.id, "id", "i", "", "The group ID (required)")
+	groupMembersListCmd.Flags().BoolVarP(&groupMembersFlags.owner, "owners", "", false, "List the owners rather than the members of the group")
+	groupMembersListCmd.MarkFlagRequired("id")
+
+	groupMembersAddCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add group member",
+		RunE:  wrapCheckSetup(groupMembersAddF),
+	}
+	groupMembersAddCmd.Flags().StringVarP(&groupMembersFlags.id, "id", "i", "", "The group ID (required)")
+	groupMembersAddCmd.Flags().StringVarP(&groupMembersFlags.memberID, "member", "m", "", "The user ID of the member (required)")
+	groupMembersAddCmd.Flags().BoolVarP(&groupMembersFlags.owner, "owner", "", false, "Add the user as an owner of the group")
+	groupMembersAddCmd.MarkFlagRequired("id")
+	groupMembersAddCmd.MarkFlagRequired("member")
+
+	groupMembersRemoveCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove group member",
+		RunE:  wrapCheckSetup(groupMembersRemoveF),
+	}
+	groupMembersRemoveCmd.Flags().StringVarP(&groupMembersFlags.id, "id", "i", "", "The group ID (required)")
+	groupMembersRemoveCmd.Flags().StringVarP(&groupMembersFlags.memberID, "member", "m", "", "The user ID of the member (required)")
+	groupMembersRemoveCmd.MarkFlagRequired("id")
+	groupMembersRemoveCmd.MarkFlagRequired("member")
+
+	groupMembersCmd.AddCommand(groupMembersListCmd, groupMembersAddCmd, groupMembersRemoveCmd)
+}
+
+// Resource management
+var groupResourcesCmd = &cobra.Command{
+	Use:   "resources",
+	Short: "Commands mapping the group to resources",
+	Run:   groupF,
+}
+
+func init() {
+	groupCmd.AddCommand(groupResourcesCmd)
+}
+
+// GroupResourcesFlags contains the flags of the group resources commands
+type GroupResourcesFlags struct {
+	id           string
+	resourceType string
+	resourceID   string
+	owner        bool
+}
+
+var groupResourcesFlags GroupResourcesFlags
+
+func (f GroupResourcesFlags) decode() (platform.ResourceType, platform.ID, platform.ID, error) {
+	rt := platform.ResourceType(f.resourceType)
+	if err := rt.Valid(); err != nil {
+		return rt, 0, 0, fmt.Errorf("invalid resource type %s: %v", f.resourceType, err)
+	}
+
+	var id, resourceID platform.ID
+	if err := id.DecodeFromString(f.id); err != nil {
+		return rt, 0, 0, fmt.Errorf("failed to decode group id %s: %v", f.id, err)
+	}
+	if err := resourceID.DecodeFromString(f.resourceID); err != nil {
+		return rt, 0, 0, fmt.Errorf("failed to decode resource id %s: %v", f.resourceID, err)
+	}
+	return rt, id, resourceID, nil
+}
+
+func groupResourcesAddF(cmd *cobra.Command, args []string) error {
+	rt, id, resourceID, err := groupResourcesFlags.decode()
+	if err != nil {
+		return err
+	}
+
+	s, err := newGroupMappingService(flags, rt)
+	if err != nil {
+		return fmt.Errorf("failed to initialize members service client: %v", err)
+	}
+
+	userType := platform.Member
+	if groupResourcesFlags.owner {
+		userType = platform.Owner
+	}
+
+	mapping := &platform.UserResourceMapping{
+		ResourceID:   resourceID,
+		ResourceType: rt,
+		UserID:       id,
+		UserType:     userType,
+		MappingType:  platform.GroupMappingType,
+	}
+	if err := s.CreateUserResourceMapping(context.Background(), mapping); err != nil {
+		return fmt.Errorf("failed to add group to resource: %v", err)
+	}
+
+	return nil
+}
+
+func groupResourcesRemoveF(cmd *cobra.Command, args []string) error {
+	rt, id, resourceID, err := groupResourcesFlags.decode()
+	if err != nil {
+		return err
+	}
+
+	s, err := newGroupMappingService(flags, rt)
+	if err != nil {
+		return fmt.Errorf("failed to initialize members service client: %v", err)
+	}
+
+	if err := s.DeleteUserResourceMapping(context.Background(), resourceID, id); err != nil {
+		return fmt.Errorf("failed to remove group from resource: %v", err)
+	}
+
+	return nil
+}
+
+func init() {
+	groupResourcesAddCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add the group to the members of a resource",
+		RunE:  wrapCheckSetup(groupResourcesAddF),
+	}
+	groupResourcesAddCmd.Flags().StringVarP(&groupResourcesFlags.id, "id", "i", "", "The group ID (required)")
+	groupResourcesAddCmd.Flags().StringVarP(&groupResourcesFlags.resourceType, "resource-type", "", "", "The type of the resource, such as buckets, dashboards, orgs or tasks (required)")
+	groupResourcesAddCmd.Flags().StringVarP(&groupResourcesFlags.resourceID, "resource-id", "r", "", "The resource ID (required)")
+	groupResourcesAddCmd.Flags().BoolVarP(&groupResourcesFlags.owner, "owner", "", false, "Add the group as an owner of the resource")
+	groupResourcesAddCmd.MarkFlagRequired("id")
+	groupResourcesAddCmd.MarkFlagRequired("resource-type")
+	groupResourcesAddCmd.MarkFlagRequired("resource-id")
+
+	groupResourcesRemoveCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove the group from the members of a resource",
+		RunE:  wrapCheckSetup(groupResourcesRemoveF),
+	}
+	groupResourcesRemoveCmd.Flags().StringVarP(&groupResourcesFlags.id, "id", "i", "", "The group ID (required)")
+	groupResourcesRemoveCmd.Flags().StringVarP(&groupResourcesFlags.resourceType, "resource-type", "", "", "The type of the resource, such as buckets, dashboards, orgs or tasks (required)")
+	groupResourcesRemoveCmd.Flags().StringVarP(&groupResourcesFlags.resourceID, "resource-id", "r", "", "The resource ID (required)")
+	groupResourcesRemoveCmd.MarkFlagRequired("id")
+	groupResourcesRemoveCmd.MarkFlagRequired("resource-type")
+	groupResourcesRemoveCmd.MarkFlagRequired("resource-id")
+
+	groupResourcesCmd.AddCommand(groupResourcesAddCmd, groupResourcesRemoveCmd)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -22,14 +23,16 @@ func main() {
 }
 
 var influxCmd = &cobra.Command{
-	Use:   "influx",
-	Short: "Influx Client",
-	Run:   influxF,
+	Use:               "influx",
+	Short:             "Influx Client",
+	Run:               influxF,
+	PersistentPreRunE: setClientTLSConfig,
 }
 
 func init() {
 	influxCmd.AddCommand(authorizationCmd)
 	influxCmd.AddCommand(bucketCmd)
+	influxCmd.AddCommand(groupCmd)
 	influxCmd.AddCommand(organizationCmd)
 	influxCmd.AddCommand(queryCmd)
 	influxCmd.AddCommand(replCmd)
@@ -42,9 +45,11 @@ func init() {
 
 // Flags contains all the CLI flag values for influx.
 type Flags struct {
-	token string
-	host  string
-	local bool
+	token      string
+	host       string
+	local      bool
+	skipVerify bool
+	caCert     string
 }
 
 var flags Flags
@@ -95,12 +100,39 @@ func init() {
 
 	influxCmd.PersistentFlags().BoolVar(&flags.local, "local", false, "Run commands locally against the filesystem")
 
+	influxCmd.PersistentFlags().BoolVar(&flags.skipVerify, "skip-verify", false, "Skip the verification of the TLS certificate of Influx")
+	influxCmd.PersistentFlags().StringVar(&flags.caCert, "ca-cert", "", "Path to the PEM encoded certificate authorities that verify the TLS certificate of Influx")
+	viper.BindEnv("CA_CERT")
+	if h := viper.GetString("CA_CERT"); h != "" {
+		flags.caCert = h
+	}
+
 	// Override help on all the commands tree
 	walk(influxCmd, func(c *cobra.Command) {
 		c.Flags().BoolP("help", "h", false, fmt.Sprintf("Help for the %s command ", c.Name()))
 	})
 }
 
+// setClientTLSConfig configures the TLS connections to Influx from the flags.
+func setClientTLSConfig(cmd *cobra.Command, args []string) error {
+	if !flags.skipVerify && flags.caCert == "" {
+		return nil
+	}
+
+	cfg := &tls.Config{
+		InsecureSkipVerify: flags.skipVerify,
+	}
+	if flags.caCert != "" {
+		pool, err := http.LoadCertPool(flags.caCert)
+		if err != nil {
+			return fmt.Errorf("failed to load certificate authorities: %v", err)
+		}
+		cfg.RootCAs = pool
+	}
+	http.SetClientTLSConfig(cfg)
+	return nil
+}
+
 func checkSetup(host string) error {
 	s := &http.SetupService{
 		Addr: flags.host,
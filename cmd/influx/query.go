@@ -3,10 +3,21 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/repl"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/arrow"
 	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/ndjson"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -15,14 +26,28 @@ var queryCmd = &cobra.Command{
 	Use:   "query [query literal or @/path/to/query.flux]",
 	Short: "Execute a Flux query",
 	Long: `Execute a literal Flux query provided as a string,
-or execute a literal Flux query contained in a file by specifying the file prefixed with an @ sign.`,
+or execute a literal Flux query contained in a file by specifying the file prefixed with an @ sign.
+
+The results are printed as tables unless another format is given with --format.
+The arrow format writes an Apache Arrow IPC stream for each table.
+
+The running queries are listed with the ls command and stopped with the kill command.`,
 	Args: cobra.ExactArgs(1),
 	RunE: wrapCheckSetup(fluxQueryF),
 }
 
 var queryFlags struct {
-	OrgID string
-	Org   string
+	OrgID  string
+	Org    string
+	Format string
+}
+
+// queryDialects are the dialects of the formats the query results may be written in,
+// besides the tables of the REPL.
+var queryDialects = map[string]func() flux.Dialect{
+	http.CSVFormat:   func() flux.Dialect { return csv.DefaultDialect() },
+	http.ArrowFormat: func() flux.Dialect { return new(arrow.Dialect) },
+	http.JSONFormat:  func() flux.Dialect { return new(ndjson.Dialect) },
 }
 
 func init() {
@@ -37,6 +62,8 @@ func init() {
 	if h := viper.GetString("ORG"); h != "" {
 		queryFlags.Org = h
 	}
+
+	queryCmd.Flags().StringVar(&queryFlags.Format, "format", "table", "The format of the results: table, csv, arrow or json")
 }
 
 func fluxQueryF(cmd *cobra.Command, args []string) error {
@@ -48,6 +75,11 @@ func fluxQueryF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("must specify exactly one of org or org-id")
 	}
 
+	newDialect, ok := queryDialects[queryFlags.Format]
+	if !ok && queryFlags.Format != "table" {
+		return fmt.Errorf("unknown format %q, expected table, csv, arrow or json", queryFlags.Format)
+	}
+
 	q, err := repl.LoadQuery(args[0])
 	if err != nil {
 		return fmt.Errorf("failed to load query: %v", err)
@@ -76,6 +108,10 @@ func fluxQueryF(cmd *cobra.Command, args []string) error {
 		orgID = o.ID
 	}
 
+	if newDialect != nil {
+		return writeQueryResults(orgID, q, newDialect())
+	}
+
 	r, err := getFluxREPL(flags.host, flags.token, orgID)
 	if err != nil {
 		return fmt.Errorf("failed to get the flux REPL: %v", err)
@@ -87,3 +123,173 @@ func fluxQueryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// writeQueryResults writes the results of the query encoded by the dialect to stdout.
+func writeQueryResults(orgID platform.ID, q string, d flux.Dialect) error {
+	s := &http.FluxService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			OrganizationID: orgID,
+			Compiler: lang.FluxCompiler{
+				Now:   time.Now(),
+				Query: q,
+			},
+		},
+		Dialect: d,
+	}
+	if _, err := s.Query(context.Background(), os.Stdout, req); err != nil {
+		return fmt.Errorf("failed to execute query: %v", err)
+	}
+	return nil
+}
+
+func newRunningQueryService(f Flags) (platform.RunningQueryService, error) {
+	if flags.local {
+		return nil, fmt.Errorf("local flag not supported for query command")
+	}
+	return &http.RunningQueryService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+func writeRunningQueries(qs ...*platform.RunningQuery) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"OrgID",
+		"UserID",
+		"State",
+		"Queued",
+		"Executing",
+		"MemoryBytes",
+		"Query",
+	)
+	for _, q := range qs {
+		w.Write(map[string]interface{}{
+			"ID":          q.ID.String(),
+			"OrgID":       q.OrgID.String(),
+			"UserID":      q.UserID.String(),
+			"State":       q.State,
+			"Queued":      q.QueueDuration.String(),
+			"Executing":   q.ExecuteDuration.String(),
+			"MemoryBytes": q.MemoryBytes,
+			"Query":       strings.Join(strings.Fields(q.Query), " "),
+		})
+	}
+	w.Flush()
+}
+
+// List Command
+type QueryListFlags struct {
+	userID string
+}
+
+var queryListFlags QueryListFlags
+
+func init() {
+	queryListCmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List the running queries",
+		Args:    cobra.NoArgs,
+		RunE:    wrapCheckSetup(queryListF),
+	}
+
+	queryListCmd.Flags().StringVarP(&queryListFlags.userID, "user-id", "", "", "Only list the queries of the user")
+
+	queryCmd.AddCommand(queryListCmd)
+}
+
+func queryListF(cmd *cobra.Command, args []string) error {
+	if queryFlags.OrgID != "" && queryFlags.Org != "" {
+		return fmt.Errorf("must specify at most one of org or org-id")
+	}
+
+	s, err := newRunningQueryService(flags)
+	if err != nil {
+		return err
+	}
+
+	filter := platform.RunningQueryFilter{}
+	if queryFlags.OrgID != "" {
+		orgID, err := platform.IDFromString(queryFlags.OrgID)
+		if err != nil {
+			return fmt.Errorf("failed to decode org-id: %v", err)
+		}
+		filter.OrgID = orgID
+	}
+
+	if queryFlags.Org != "" {
+		orgSvc, err := newOrganizationService(flags)
+		if err != nil {
+			return fmt.Errorf("failed to initialized organization service client: %v", err)
+		}
+
+		o, err := orgSvc.FindOrganization(context.Background(), platform.OrganizationFilter{Name: &queryFlags.Org})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve organization %q: %v", queryFlags.Org, err)
+		}
+		filter.OrgID = &o.ID
+	}
+
+	if queryListFlags.userID != "" {
+		userID, err := platform.IDFromString(queryListFlags.userID)
+		if err != nil {
+			return fmt.Errorf("failed to decode user id %s: %v", queryListFlags.userID, err)
+		}
+		filter.UserID = userID
+	}
+
+	qs, err := s.FindRunningQueries(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to list queries: %v", err)
+	}
+
+	writeRunningQueries(qs...)
+	return nil
+}
+
+// Kill Command
+func init() {
+	queryKillCmd := &cobra.Command{
+		Use:   "kill [query ID]",
+		Short: "Kill a running query",
+		Args:  cobra.ExactArgs(1),
+		RunE:  wrapCheckSetup(queryKillF),
+	}
+
+	queryCmd.AddCommand(queryKillCmd)
+}
+
+func queryKillF(cmd *cobra.Command, args []string) error {
+	s, err := newRunningQueryService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(args[0]); err != nil {
+		return fmt.Errorf("failed to decode query id %s: %v", args[0], err)
+	}
+
+	if err := s.KillRunningQuery(context.Background(), id); err != nil {
+		return fmt.Errorf("failed to kill query with id %q: %v", id, err)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Killed",
+	)
+	w.Write(map[string]interface{}{
+		"ID":     id.String(),
+		"Killed": true,
+	})
+	w.Flush()
+
+	return nil
+}
@@ -134,6 +134,63 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// TaskDryRunFlags define the DryRun Command
+type TaskDryRunFlags struct {
+	org          string
+	orgID        string
+	scheduledFor string
+}
+
+var taskDryRunFlags TaskDryRunFlags
+
+func init() {
+	taskDryRunCmd := &cobra.Command{
+		Use:   "dryrun [query literal or @/path/to/query.flux]",
+		Short: "Execute a task script without writing its data",
+		Long: `Execute a task script as a run scheduled for the given time would,
+and print the data its to() calls would have written, as annotated CSV.`,
+		Args: cobra.ExactArgs(1),
+		RunE: wrapCheckSetup(taskDryRunF),
+	}
+
+	taskDryRunCmd.Flags().StringVarP(&taskDryRunFlags.org, "org", "", "", "organization name")
+	taskDryRunCmd.Flags().StringVarP(&taskDryRunFlags.orgID, "org-id", "", "", "id of the organization to run the script in")
+	taskDryRunCmd.Flags().StringVarP(&taskDryRunFlags.scheduledFor, "scheduled-for", "", "", "simulated time of the run, in RFC3339 (defaults to now)")
+
+	taskCmd.AddCommand(taskDryRunCmd)
+}
+
+func taskDryRunF(cmd *cobra.Command, args []string) error {
+	if (taskDryRunFlags.org == "") == (taskDryRunFlags.orgID == "") {
+		return fmt.Errorf("must specify exactly one of org or org-id")
+	}
+
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	flux, err := repl.LoadQuery(args[0])
+	if err != nil {
+		return fmt.Errorf("error parsing flux script: %s", err)
+	}
+
+	req := http.TaskDryRunRequest{
+		Flux:         flux,
+		Organization: taskDryRunFlags.org,
+		ScheduledFor: taskDryRunFlags.scheduledFor,
+	}
+	if taskDryRunFlags.orgID != "" {
+		oid, err := platform.IDFromString(taskDryRunFlags.orgID)
+		if err != nil {
+			return fmt.Errorf("error parsing organization ID: %s", err)
+		}
+		req.OrganizationID = *oid
+	}
+
+	return s.DryRun(context.Background(), req, os.Stdout)
+}
+
 // taskFindFlags define the Find Command
 type TaskFindFlags struct {
 	user  string
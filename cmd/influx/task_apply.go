@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/task/options"
+	"github.com/spf13/cobra"
+)
+
+// TaskApplyFlags define the Apply command
+type TaskApplyFlags struct {
+	org    string
+	orgID  string
+	dryRun bool
+}
+
+var taskApplyFlags TaskApplyFlags
+
+func init() {
+	taskApplyCmd := &cobra.Command{
+		Use:   "apply [directory]",
+		Short: "Create, update or deactivate the tasks of an organization to match the .flux files of a directory",
+		Long: `Create, update or deactivate the tasks of an organization to match the .flux files of a directory.
+
+Tasks are matched to files by the name set in their task option.
+Tasks of the organization that are not defined in the directory are deactivated.`,
+		Args: cobra.ExactArgs(1),
+		RunE: wrapCheckSetup(taskApplyF),
+	}
+
+	taskApplyCmd.Flags().StringVarP(&taskApplyFlags.org, "org", "", "", "organization name")
+	taskApplyCmd.Flags().StringVarP(&taskApplyFlags.orgID, "org-id", "", "", "id of the organization that owns the tasks")
+	taskApplyCmd.Flags().BoolVarP(&taskApplyFlags.dryRun, "dry-run", "", false, "print the changes without applying them")
+
+	taskCmd.AddCommand(taskApplyCmd)
+}
+
+// Actions of a task change.
+const (
+	taskCreateAction     = "create"
+	taskUpdateAction     = "update"
+	taskDeactivateAction = "deactivate"
+	taskUnchangedAction  = "unchanged"
+)
+
+// taskDefinition is a task script read from a file.
+type taskDefinition struct {
+	name string
+	file string
+	flux string
+}
+
+// taskChange is a change needed to make a task match its definition.
+type taskChange struct {
+	action string
+	name   string
+	// task is the existing task, if any.
+	task *platform.Task
+	// def is the definition of the task, if any.
+	def *taskDefinition
+}
+
+func taskApplyF(cmd *cobra.Command, args []string) error {
+	if (taskApplyFlags.org == "") == (taskApplyFlags.orgID == "") {
+		return fmt.Errorf("must specify exactly one of org or org-id")
+	}
+
+	defs, err := readTaskDefinitions(args[0])
+	if err != nil {
+		return err
+	}
+
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	filter := platform.TaskFilter{Organization: taskApplyFlags.org}
+	var orgID platform.ID
+	if taskApplyFlags.orgID != "" {
+		if err := orgID.DecodeFromString(taskApplyFlags.orgID); err != nil {
+			return fmt.Errorf("error parsing organization ID: %s", err)
+		}
+		filter.OrganizationID = &orgID
+	}
+
+	ctx := context.Background()
+	tasks, err := findAllTasks(ctx, s, filter)
+	if err != nil {
+		return err
+	}
+
+	changes, err := planTaskChanges(tasks, defs)
+	if err != nil {
+		return err
+	}
+
+	if !taskApplyFlags.dryRun {
+		for _, c := range changes {
+			t, err := applyTaskChange(ctx, s, c, platform.TaskCreate{
+				Organization:   taskApplyFlags.org,
+				OrganizationID: orgID,
+				Token:          flags.token,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to %s task %q: %v", c.action, c.name, err)
+			}
+			c.task = t
+		}
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Action",
+		"ID",
+		"Name",
+		"File",
+	)
+	for _, c := range changes {
+		id, file := "", ""
+		if c.task != nil {
+			id = c.task.ID.String()
+		}
+		if c.def != nil {
+			file = c.def.file
+		}
+		w.Write(map[string]interface{}{
+			"Action": c.action,
+			"ID":     id,
+			"Name":   c.name,
+			"File":   file,
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+// readTaskDefinitions reads the .flux files of dir.
+// It fails if a script does not have a valid task option, or if two scripts define a task with the same name.
+func readTaskDefinitions(dir string) ([]*taskDefinition, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.flux"))
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []*taskDefinition
+	byName := make(map[string]*taskDefinition)
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		flux := string(b)
+		opt, err := options.FromScript(flux)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing task options of %s: %v", file, err)
+		}
+
+		if other, ok := byName[opt.Name]; ok {
+			return nil, fmt.Errorf("task %q is defined in both %s and %s", opt.Name, other.file, file)
+		}
+
+		def := &taskDefinition{name: opt.Name, file: file, flux: flux}
+		byName[def.name] = def
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// findAllTasks pages through all the tasks matching filter.
+func findAllTasks(ctx context.Context, s platform.TaskService, filter platform.TaskFilter) ([]*platform.Task, error) {
+	filter.Limit = platform.TaskMaxPageSize
+
+	var tasks []*platform.Task
+	for {
+		page, _, err := s.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page...)
+		if len(page) < filter.Limit {
+			return tasks, nil
+		}
+		filter.After = &page[len(page)-1].ID
+	}
+}
+
+// planTaskChanges returns the changes needed for tasks to match defs, sorted by task name.
+// Tasks are matched to definitions by name: unmatched definitions are created,
+// definitions whose script differs or whose task is inactive are updated,
+// and active tasks without a definition are deactivated.
+func planTaskChanges(tasks []*platform.Task, defs []*taskDefinition) ([]*taskChange, error) {
+	byName := make(map[string]*platform.Task, len(tasks))
+	for _, t := range tasks {
+		if _, ok := byName[t.Name]; ok {
+			return nil, fmt.Errorf("several tasks are named %q, rename or delete them before applying", t.Name)
+		}
+		byName[t.Name] = t
+	}
+
+	var changes []*taskChange
+	defined := make(map[string]bool, len(defs))
+	for _, def := range defs {
+		defined[def.name] = true
+
+		c := &taskChange{name: def.name, def: def}
+		t, ok := byName[def.name]
+		switch {
+		case !ok:
+			c.action = taskCreateAction
+		case strings.TrimSpace(t.Flux) != strings.TrimSpace(def.flux) || t.Status != platform.TaskStatusActive:
+			c.action = taskUpdateAction
+			c.task = t
+		default:
+			c.action = taskUnchangedAction
+			c.task = t
+		}
+		changes = append(changes, c)
+	}
+
+	for _, t := range tasks {
+		if defined[t.Name] || t.Status == platform.TaskStatusInactive {
+			continue
+		}
+		changes = append(changes, &taskChange{action: taskDeactivateAction, name: t.Name, task: t})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].name < changes[j].name
+	})
+	return changes, nil
+}
+
+// applyTaskChange makes the change, and returns the resulting task.
+// Tasks are created with the organization and token of tc.
+func applyTaskChange(ctx context.Context, s platform.TaskService, c *taskChange, tc platform.TaskCreate) (*platform.Task, error) {
+	switch c.action {
+	case taskCreateAction:
+		tc.Flux = c.def.flux
+		return s.CreateTask(ctx, tc)
+	case taskUpdateAction:
+		status := platform.TaskStatusActive
+		return s.UpdateTask(ctx, c.task.ID, platform.TaskUpdate{
+			Flux:   &c.def.flux,
+			Status: &status,
+		})
+	case taskDeactivateAction:
+		status := platform.TaskStatusInactive
+		return s.UpdateTask(ctx, c.task.ID, platform.TaskUpdate{
+			Status: &status,
+		})
+	default:
+		return c.task, nil
+	}
+}
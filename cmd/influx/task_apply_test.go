@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func taskScript(name string) string {
+	return fmt.Sprintf(`option task = {name: %q, every: 1h}
+
+from(bucket: "b") |> range(start: -1h)
+`, name)
+}
+
+func TestReadTaskDefinitions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influx-task-apply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(file, content string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.flux", taskScript("a"))
+	write("b.flux", taskScript("b"))
+	write("README.md", "not a task")
+
+	defs, err := readTaskDefinitions(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defs) != 2 || defs[0].name != "a" || defs[1].name != "b" {
+		t.Fatalf("unexpected definitions %+v", defs)
+	}
+
+	write("c.flux", taskScript("a"))
+	if _, err := readTaskDefinitions(dir); err == nil {
+		t.Fatal("expected an error for a task defined twice")
+	}
+
+	write("c.flux", `from(bucket: "b") |> range(start: -1h)`)
+	if _, err := readTaskDefinitions(dir); err == nil {
+		t.Fatal("expected an error for a script without task option")
+	}
+}
+
+func TestPlanTaskChanges(t *testing.T) {
+	tasks := []*platform.Task{
+		{ID: 1, Name: "unchanged", Flux: taskScript("unchanged"), Status: platform.TaskStatusActive},
+		{ID: 2, Name: "modified", Flux: taskScript("old"), Status: platform.TaskStatusActive},
+		{ID: 3, Name: "disabled", Flux: taskScript("disabled"), Status: platform.TaskStatusInactive},
+		{ID: 4, Name: "removed", Flux: taskScript("removed"), Status: platform.TaskStatusActive},
+		{ID: 5, Name: "removed-inactive", Flux: taskScript("removed-inactive"), Status: platform.TaskStatusInactive},
+	}
+	defs := []*taskDefinition{
+		{name: "unchanged", flux: taskScript("unchanged") + "\n"},
+		{name: "modified", flux: taskScript("modified")},
+		{name: "disabled", flux: taskScript("disabled")},
+		{name: "added", flux: taskScript("added")},
+	}
+
+	changes, err := planTaskChanges(tasks, defs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []struct {
+		action string
+		name   string
+		id     platform.ID
+	}{
+		{action: taskCreateAction, name: "added"},
+		{action: taskUpdateAction, name: "disabled", id: 3},
+		{action: taskUpdateAction, name: "modified", id: 2},
+		{action: taskDeactivateAction, name: "removed", id: 4},
+		{action: taskUnchangedAction, name: "unchanged", id: 1},
+	}
+	if len(changes) != len(exp) {
+		t.Fatalf("expected %d changes, got %d", len(exp), len(changes))
+	}
+	for i, e := range exp {
+		c := changes[i]
+		var id platform.ID
+		if c.task != nil {
+			id = c.task.ID
+		}
+		if c.action != e.action || c.name != e.name || id != e.id {
+			t.Errorf("unexpected change %d: want %s %s (%d), got %s %s (%d)", i, e.action, e.name, e.id, c.action, c.name, id)
+		}
+	}
+
+	// Tasks cannot be matched by name when several have the same name.
+	tasks = append(tasks, &platform.Task{ID: 6, Name: "modified"})
+	if _, err := planTaskChanges(tasks, defs); err == nil {
+		t.Fatal("expected an error for duplicate task names")
+	}
+}
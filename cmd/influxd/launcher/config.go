@@ -0,0 +1,115 @@
+package launcher
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/influxdata/influxdb/kit/cli"
+	"github.com/influxdata/influxdb/storage"
+	itoml "github.com/influxdata/influxdb/toml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// storageConfigKey is the table of the configuration file holding the storage engine settings.
+const storageConfigKey = "storage"
+
+// storageEnvPrefix prefixes the environment variables overriding the storage engine settings,
+// such as INFLUXD_STORAGE_ENGINE_CACHE_MAX_MEMORY_SIZE.
+const storageEnvPrefix = "INFLUXD_STORAGE"
+
+// NewPrintConfigCommand returns the command printing the effective configuration of influxd,
+// resolved from its flags, environment variables and configuration file, as a configuration file.
+func NewPrintConfigCommand() *cobra.Command {
+	l := NewLauncher()
+	cmd := &cobra.Command{
+		Use:   "print-config",
+		Short: "Print the effective configuration of the influxd server",
+		Args:  cobra.NoArgs,
+	}
+
+	opts := buildLauncherCommand(l, cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return l.printConfig(cmd.OutOrStdout(), opts)
+	}
+
+	return cmd
+}
+
+// loadConfig resolves the options and the storage engine settings from the configuration file,
+// then applies the environment overrides of the storage engine settings.
+func (m *Launcher) loadConfig(cmd *cobra.Command, opts []cli.Opt) error {
+	if m.configPath != "" {
+		b, err := ioutil.ReadFile(m.configPath)
+		if err != nil {
+			return fmt.Errorf("failed to read configuration file: %v", err)
+		}
+		if err := decodeConfig(b, opts, &m.StorageConfig); err != nil {
+			return fmt.Errorf("invalid configuration file %s: %v", m.configPath, err)
+		}
+
+		viper.SetConfigType("toml")
+		if err := viper.ReadConfig(bytes.NewReader(b)); err != nil {
+			return fmt.Errorf("invalid configuration file %s: %v", m.configPath, err)
+		}
+		cli.ResolveOptions(cmd, opts)
+	}
+
+	return itoml.ApplyEnvOverrides(os.Getenv, storageEnvPrefix, &m.StorageConfig)
+}
+
+// decodeConfig decodes the storage engine settings of the configuration file b into sc,
+// and verifies that the other keys of b are options.
+func decodeConfig(b []byte, opts []cli.Opt, sc *storage.Config) error {
+	file := struct {
+		Storage *storage.Config `toml:"storage"`
+	}{
+		Storage: sc,
+	}
+	md, err := toml.Decode(string(b), &file)
+	if err != nil {
+		return err
+	}
+
+	flags := make(map[string]bool, len(opts))
+	for _, o := range opts {
+		flags[o.Flag] = true
+	}
+	for _, k := range md.Undecoded() {
+		if len(k) > 1 || !flags[k[0]] || k[0] == "config" {
+			return fmt.Errorf("unknown configuration key %q", strings.Join(k, "."))
+		}
+	}
+	return nil
+}
+
+// printConfig writes the effective configuration to w, in the format of the configuration file.
+func (m *Launcher) printConfig(w io.Writer, opts []cli.Opt) error {
+	config := make(map[string]interface{}, len(opts)+1)
+	for _, o := range opts {
+		if o.Flag == "config" {
+			continue
+		}
+		switch destP := o.DestP.(type) {
+		case *string:
+			config[o.Flag] = *destP
+		case *int:
+			config[o.Flag] = *destP
+		case *bool:
+			config[o.Flag] = *destP
+		case *time.Duration:
+			config[o.Flag] = destP.String()
+		case *[]string:
+			config[o.Flag] = *destP
+		}
+	}
+	config[storageConfigKey] = m.StorageConfig
+
+	return toml.NewEncoder(w).Encode(config)
+}
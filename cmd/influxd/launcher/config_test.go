@@ -0,0 +1,78 @@
+package launcher_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+	"github.com/spf13/viper"
+)
+
+func TestPrintConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// The configuration file is read into the global viper, that the other launchers of the tests use.
+	defer viper.Reset()
+
+	path := filepath.Join(dir, "influxd.toml")
+	config := `http-bind-address = ":8888"
+query-concurrency = 4
+task-max-catchup = "2h"
+
+[storage.engine.cache]
+max-memory-size = "2g"
+
+[storage.wal]
+fsync-delay = "100ms"
+`
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	cmd := launcher.NewPrintConfigCommand()
+	cmd.SetOutput(&buf)
+	cmd.SetArgs([]string{"--config", path, "--query-concurrency", "6"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, exp := range []string{
+		`http-bind-address = ":8888"`,
+		`query-concurrency = 6`,
+		`task-max-catchup = "2h0m0s"`,
+		`max-memory-size = 2147483648`,
+		`fsync-delay = "100ms"`,
+	} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Errorf("expected %s in configuration:\n%s", exp, buf.String())
+		}
+	}
+}
+
+func TestPrintConfig_UnknownKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "influxd.toml")
+	if err := ioutil.WriteFile(path, []byte("no-such-flag = true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := launcher.NewPrintConfigCommand()
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--config", path})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error for an unknown configuration key")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/authorizer"
@@ -32,9 +33,11 @@ import (
 	infprom "github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/control"
+	"github.com/influxdata/influxdb/query/influxql"
 	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/source"
 	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
 	"github.com/influxdata/influxdb/storage/readservice"
 	taskbackend "github.com/influxdata/influxdb/task/backend"
 	"github.com/influxdata/influxdb/task/backend/coordinator"
@@ -108,13 +111,20 @@ func NewCommand() *cobra.Command {
 	return cmd
 }
 
-func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
+// buildLauncherCommand binds the options of the launcher to cmd and returns them.
+// The options are resolved from the configuration file before cmd runs.
+func buildLauncherCommand(l *Launcher, cmd *cobra.Command) []cli.Opt {
 	dir, err := fs.InfluxDir()
 	if err != nil {
 		panic(fmt.Errorf("failed to determine influx directory: %v", err))
 	}
 
 	opts := []cli.Opt{
+		{
+			DestP: &l.configPath,
+			Flag:  "config",
+			Desc:  "path to a TOML configuration file; its keys are the names of the flags, and its storage table holds the storage engine settings",
+		},
 		{
 			DestP:   &l.logLevel,
 			Flag:    "log-level",
@@ -133,6 +143,32 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: ":9999",
 			Desc:    "bind address for the REST HTTP API",
 		},
+		{
+			DestP: &l.tlsCert,
+			Flag:  "tls-cert",
+			Desc:  "TLS certificate for HTTPs; the REST HTTP API is served over HTTPs when set along with tls-key, and the certificate is reloaded on SIGHUP",
+		},
+		{
+			DestP: &l.tlsKey,
+			Flag:  "tls-key",
+			Desc:  "TLS private key for HTTPs",
+		},
+		{
+			DestP:   &l.tlsMinVersion,
+			Flag:    "tls-min-version",
+			Default: "1.2",
+			Desc:    "minimum TLS version accepted for HTTPs (1.0, 1.1, 1.2 or 1.3)",
+		},
+		{
+			DestP: &l.tlsCipherSuites,
+			Flag:  "tls-cipher-suites",
+			Desc:  "TLS cipher suites accepted for HTTPs, named as in the crypto/tls package; defaults to the Go defaults; cipher suites with known security issues are rejected",
+		},
+		{
+			DestP: &l.tlsClientCA,
+			Flag:  "tls-client-ca",
+			Desc:  "certificate authorities of the client certificates accepted for authentication; a client certificate authenticates with the authorization whose ID is its common name",
+		},
 		{
 			DestP:   &l.boltPath,
 			Flag:    "bolt-path",
@@ -186,9 +222,227 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP:   &l.passwordPolicy.MinLength,
+			Flag:    "password-min-length",
+			Default: kv.MinPasswordLength,
+			Desc:    "minimum number of characters of passwords",
+		},
+		{
+			DestP:   &l.passwordPolicy.RequireUpper,
+			Flag:    "password-require-upper",
+			Default: false,
+			Desc:    "require passwords to contain an upper case letter",
+		},
+		{
+			DestP:   &l.passwordPolicy.RequireLower,
+			Flag:    "password-require-lower",
+			Default: false,
+			Desc:    "require passwords to contain a lower case letter",
+		},
+		{
+			DestP:   &l.passwordPolicy.RequireDigit,
+			Flag:    "password-require-digit",
+			Default: false,
+			Desc:    "require passwords to contain a digit",
+		},
+		{
+			DestP:   &l.passwordPolicy.RequireSymbol,
+			Flag:    "password-require-symbol",
+			Default: false,
+			Desc:    "require passwords to contain a symbol",
+		},
+		{
+			DestP:   &l.signinLockout.MaxAttempts,
+			Flag:    "signin-lockout-attempts",
+			Default: 0,
+			Desc:    "number of consecutive failed sign-ins locking out a user or an address; 0 disables lockouts",
+		},
+		{
+			DestP:   &l.signinLockout.Duration,
+			Flag:    "signin-lockout-duration",
+			Default: time.Minute,
+			Desc:    "duration of the first lockout, doubling with each further failed sign-in",
+		},
+		{
+			DestP:   &l.signinLockout.MaxDuration,
+			Flag:    "signin-lockout-max-duration",
+			Default: time.Hour,
+			Desc:    "maximum duration of lockouts; failed sign-ins older than it are forgotten",
+		},
+		{
+			DestP:   &l.auditMirror,
+			Flag:    "audit-mirror",
+			Default: false,
+			Desc:    "mirror the audit events of organizations into their audit system bucket (000000000000000b), readable with the audit read permission",
+		},
+		{
+			DestP: &l.oauth2.Provider,
+			Flag:  "oauth2-provider",
+			Desc:  "OAuth2 provider users may sign in with: github, google, heroku or oidc; empty disables OAuth2 sign-in",
+		},
+		{
+			DestP: &l.oauth2.ClientID,
+			Flag:  "oauth2-client-id",
+			Desc:  "client ID registered with the OAuth2 provider",
+		},
+		{
+			DestP: &l.oauth2.ClientSecret,
+			Flag:  "oauth2-client-secret",
+			Desc:  "client secret registered with the OAuth2 provider",
+		},
+		{
+			DestP: &l.oauth2.RedirectURL,
+			Flag:  "oauth2-redirect-url",
+			Desc:  "URL of the OAuth2 callback, such as https://influxdb.example.com/api/v2/signin/oauth2/oidc/callback",
+		},
+		{
+			DestP: &l.oauth2.Scopes,
+			Flag:  "oauth2-scopes",
+			Desc:  "scopes requested from the oidc provider; defaults to openid, email and profile",
+		},
+		{
+			DestP: &l.oauth2.AuthURL,
+			Flag:  "oauth2-auth-url",
+			Desc:  "authorization endpoint of the oidc provider",
+		},
+		{
+			DestP: &l.oauth2.TokenURL,
+			Flag:  "oauth2-token-url",
+			Desc:  "token endpoint of the oidc provider",
+		},
+		{
+			DestP: &l.oauth2.UserInfoURL,
+			Flag:  "oauth2-userinfo-url",
+			Desc:  "userinfo endpoint of the oidc provider",
+		},
+		{
+			DestP: &l.oauth2.JWKSURL,
+			Flag:  "oauth2-jwks-url",
+			Desc:  "key set of the oidc provider; when set, users are read from the claims of the id_token instead of the userinfo endpoint",
+		},
+		{
+			DestP: &l.oauth2.Issuer,
+			Flag:  "oauth2-issuer",
+			Desc:  "required issuer of the id_token of the oidc provider",
+		},
+		{
+			DestP:   &l.oauth2.UsernameClaim,
+			Flag:    "oauth2-username-claim",
+			Default: "email",
+			Desc:    "claim of the oidc provider mapped to the name of the user",
+		},
+		{
+			DestP:   &l.oauth2.GroupsClaim,
+			Flag:    "oauth2-groups-claim",
+			Default: "groups",
+			Desc:    "claim of the oidc provider listing the groups of the user",
+		},
+		{
+			DestP: &l.oauth2.Organizations,
+			Flag:  "oauth2-organizations",
+			Desc:  "organizations (github, heroku) or email domains (google, oidc) users must belong to in order to sign in",
+		},
+		{
+			DestP:   &l.oauth2.AutoProvision,
+			Flag:    "oauth2-auto-provision",
+			Default: false,
+			Desc:    "creates the users signing in with OAuth2 for the first time",
+		},
+		{
+			DestP: &l.oauth2GroupOrgs,
+			Flag:  "oauth2-group-orgs",
+			Desc:  "group=org pairs adding auto-provisioned users of a group as members of an organization",
+		},
+		{
+			DestP: &l.oauth2.Secret,
+			Flag:  "oauth2-secret",
+			Desc:  "secret signing the state of OAuth2 sign-ins; must be shared by influxd processes behind a load balancer",
+		},
+		{
+			DestP:   &l.taskMaxCatchup,
+			Flag:    "task-max-catchup",
+			Default: time.Duration(0),
+			Desc:    "maximum age of missed task runs to catch up on when a task is claimed; 0 means no limit",
+		},
+		{
+			DestP:   &l.taskLeaseTTL,
+			Flag:    "task-lease-ttl",
+			Default: time.Duration(0),
+			Desc:    "share tasks with the other influxd processes using the same metadata store, holding leases on them for this long; 0 disables task leases",
+		},
+		{
+			DestP:   &l.taskLeaseOwner,
+			Flag:    "task-lease-owner",
+			Default: "",
+			Desc:    "name identifying this process when leasing tasks; defaults to a random ID",
+		},
+		{
+			DestP:   &l.queryConcurrency,
+			Flag:    "query-concurrency",
+			Default: 10,
+			Desc:    "number of queries allowed to execute concurrently",
+		},
+		{
+			DestP:   &l.queryQueueSize,
+			Flag:    "query-queue-size",
+			Default: 10,
+			Desc:    "number of queries allowed to wait for execution",
+		},
+		{
+			DestP:   &l.queryConcurrencyPerOrg,
+			Flag:    "query-concurrency-per-org",
+			Default: 0,
+			Desc:    "number of queries of an organization allowed to execute concurrently; 0 means no limit besides query-concurrency",
+		},
+		{
+			DestP:   &l.queryQueueSizePerOrg,
+			Flag:    "query-queue-size-per-org",
+			Default: 0,
+			Desc:    "number of queries of an organization allowed to wait for execution; 0 means no limit besides query-queue-size",
+		},
+		{
+			DestP: &l.queryOrgWeights,
+			Flag:  "query-org-weights",
+			Desc:  "orgID=weight pairs giving organizations a larger share of query executions; organizations have a weight of 1 by default",
+		},
+		{
+			DestP:   &l.queryMemoryBytes,
+			Flag:    "query-memory-bytes",
+			Default: 0,
+			Desc:    "maximum number of bytes a query is allowed to use; 0 means no limit",
+		},
+		{
+			DestP:   &l.queryMaxMemoryBytes,
+			Flag:    "query-max-memory-bytes",
+			Default: 0,
+			Desc:    "maximum number of bytes all of the executing queries are allowed to use together; queries borrow from it up to query-memory-bytes and wait in the queue for up to query-memory-wait when it is exhausted; 0 means no limit",
+		},
+		{
+			DestP:   &l.queryInitialMemoryBytes,
+			Flag:    "query-initial-memory-bytes",
+			Default: 0,
+			Desc:    "number of bytes of query-max-memory-bytes that must be free for a query to start executing; 0 means an equal share for each of the query-concurrency queries",
+		},
+		{
+			DestP:   &l.queryMemoryWait,
+			Flag:    "query-memory-wait",
+			Default: time.Duration(0),
+			Desc:    "how long a query waits for query-initial-memory-bytes to be free before it is rejected; 0 rejects it at once",
+		},
+		{
+			DestP:   &l.queryLogThreshold,
+			Flag:    "query-log-threshold",
+			Default: time.Duration(0),
+			Desc:    "log the queries taking at least this long and store them into the query log system bucket (000000000000000c) of their organization, readable with the queries read permission; 0 disables the query log",
+		},
 	}
 
 	cli.BindOptions(cmd, opts)
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return l.loadConfig(cmd, opts)
+	}
+	return opts
 }
 
 // Launcher represents the main program execution.
@@ -202,12 +456,39 @@ type Launcher struct {
 	testing              bool
 	sessionLength        int // in minutes
 	sessionRenewDisabled bool
+	passwordPolicy       platform.PasswordPolicy
+	signinLockout        platform.SigninLockoutPolicy
+	auditMirror          bool
+	taskMaxCatchup       time.Duration
+	taskLeaseTTL         time.Duration
+	taskLeaseOwner       string
+
+	oauth2          http.OAuth2Config
+	oauth2GroupOrgs []string
+
+	configPath              string
+	queryConcurrency        int
+	queryQueueSize          int
+	queryMemoryBytes        int
+	queryMaxMemoryBytes     int
+	queryInitialMemoryBytes int
+	queryMemoryWait         time.Duration
+	queryConcurrencyPerOrg  int
+	queryQueueSizePerOrg    int
+	queryOrgWeights         []string
+	queryLogThreshold       time.Duration
 
 	logLevel          string
 	tracingType       string
 	reportingDisabled bool
 
 	httpBindAddress string
+	tlsEnabled      bool
+	tlsCert         string
+	tlsKey          string
+	tlsMinVersion   string
+	tlsCipherSuites []string
+	tlsClientCA     string
 	boltPath        string
 	enginePath      string
 	secretStore     string
@@ -225,6 +506,7 @@ type Launcher struct {
 	natsServer *nats.Server
 
 	scheduler          *taskbackend.TickScheduler
+	taskCoordinator    *coordinator.Coordinator
 	taskControlService taskbackend.TaskControlService
 
 	jaegerTracerCloser io.Closer
@@ -269,6 +551,9 @@ func (m *Launcher) Logger() *zap.Logger {
 
 // URL returns the URL to connect to the HTTP server.
 func (m *Launcher) URL() string {
+	if m.tlsEnabled {
+		return fmt.Sprintf("https://127.0.0.1:%d", m.httpPort)
+	}
 	return fmt.Sprintf("http://127.0.0.1:%d", m.httpPort)
 }
 
@@ -283,6 +568,7 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 	m.httpServer.Shutdown(ctx)
 
 	m.logger.Info("Stopping", zap.String("service", "task"))
+	m.taskCoordinator.Stop()
 	m.scheduler.Stop()
 
 	m.logger.Info("Stopping", zap.String("service", "nats"))
@@ -396,7 +682,9 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	serviceConfig := kv.ServiceConfig{
-		SessionLength: time.Duration(m.sessionLength) * time.Minute,
+		SessionLength:       time.Duration(m.sessionLength) * time.Minute,
+		PasswordPolicy:      m.passwordPolicy,
+		SigninLockoutPolicy: m.signinLockout,
 	}
 
 	var flusher http.Flusher
@@ -499,18 +787,29 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 		// TODO(cwolff): Figure out a good default per-query memory limit:
 		//   https://github.com/influxdata/influxdb/issues/13642
-		const (
-			concurrencyQuota         = 10
-			memoryBytesQuotaPerQuery = math.MaxInt64
-			QueueSize                = 10
-		)
+		memoryBytesQuotaPerQuery := int64(math.MaxInt64)
+		if m.queryMemoryBytes > 0 {
+			memoryBytesQuotaPerQuery = int64(m.queryMemoryBytes)
+		}
+
+		orgWeights, err := m.queryOrgWeightsConfig()
+		if err != nil {
+			m.logger.Error("Failed to configure query organization weights", zap.Error(err))
+			return err
+		}
 
 		cc := control.Config{
-			ExecutorDependencies:     make(execute.Dependencies),
-			ConcurrencyQuota:         concurrencyQuota,
-			MemoryBytesQuotaPerQuery: int64(memoryBytesQuotaPerQuery),
-			QueueSize:                QueueSize,
-			Logger:                   m.logger.With(zap.String("service", "storage-reads")),
+			ExecutorDependencies:            make(execute.Dependencies),
+			ConcurrencyQuota:                m.queryConcurrency,
+			MemoryBytesQuotaPerQuery:        memoryBytesQuotaPerQuery,
+			MemoryBytesQuota:                int64(m.queryMaxMemoryBytes),
+			InitialMemoryBytesQuotaPerQuery: int64(m.queryInitialMemoryBytes),
+			MemoryWaitTimeout:               m.queryMemoryWait,
+			QueueSize:                       m.queryQueueSize,
+			ConcurrencyQuotaPerOrg:          m.queryConcurrencyPerOrg,
+			QueueSizePerOrg:                 m.queryQueueSizePerOrg,
+			OrgWeights:                      orgWeights,
+			Logger:                          m.logger.With(zap.String("service", "storage-reads")),
 		}
 
 		authBucketSvc := authorizer.NewBucketService(bucketSvc)
@@ -532,6 +831,34 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	var storageQueryService = readservice.NewProxyQueryService(m.queryController)
+	if m.queryLogThreshold > 0 {
+		logger := m.logger.With(zap.String("service", "query-log"))
+		storageQueryService = &query.LoggingProxyQueryService{
+			ProxyQueryService: storageQueryService,
+			QueryLogger:       storage.NewQueryLogger(pointsWriter, logger),
+			Logger:            logger,
+			Threshold:         m.queryLogThreshold,
+		}
+	}
+
+	// The InfluxQL queries of self sources are transpiled with the buckets mapped
+	// to databases and the wildcards expanded from the series of the storage engine.
+	compilerMappings := make(flux.CompilerMappings)
+	if err := influxql.AddCompilerMappings(
+		compilerMappings,
+		readservice.NewDBRPMappingService(authorizer.NewBucketService(bucketSvc)),
+		reads.NewSchemaMapper(readservice.NewStore(m.engine)),
+	); err != nil {
+		m.logger.Error("Failed to add InfluxQL compiler mappings", zap.Error(err))
+		return err
+	}
+	// Self sources are queried through the local query service.
+	newSourceQueryService := func(s *platform.Source) (query.ProxyQueryService, error) {
+		if s.Type == platform.SelfSourceType {
+			return storageQueryService, nil
+		}
+		return source.NewQueryService(s)
+	}
 	var taskSvc platform.TaskService
 	{
 
@@ -543,11 +870,21 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		executor := taskexecutor.NewAsyncQueryServiceExecutor(m.logger.With(zap.String("service", "task-executor")), m.queryController, authSvc, combinedTaskService)
 
 		// create the scheduler
-		m.scheduler = taskbackend.NewScheduler(combinedTaskService, executor, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger))
+		m.scheduler = taskbackend.NewScheduler(combinedTaskService, executor, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger), taskbackend.WithMaxCatchup(m.taskMaxCatchup))
 		m.scheduler.Start(ctx)
 		m.reg.MustRegister(m.scheduler.PrometheusCollectors()...)
 
-		taskSvc = coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, combinedTaskService)
+		var coordinatorOpts []coordinator.Option
+		if m.taskLeaseTTL > 0 {
+			owner := m.taskLeaseOwner
+			if owner == "" {
+				owner = snowflake.NewIDGenerator().ID().String()
+			}
+			m.logger.Info("Sharing tasks using leases", zap.String("owner", owner), zap.Duration("ttl", m.taskLeaseTTL))
+			coordinatorOpts = append(coordinatorOpts, coordinator.WithLeases(m.kvService, owner, m.taskLeaseTTL))
+		}
+		m.taskCoordinator = coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, combinedTaskService, coordinatorOpts...)
+		taskSvc = m.taskCoordinator
 		taskSvc = authorizer.NewTaskService(m.logger.With(zap.String("service", "task-authz-validator")), taskSvc, bucketSvc)
 		m.taskControlService = combinedTaskService
 	}
@@ -598,14 +935,32 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		Addr: m.httpBindAddress,
 	}
 
+	var oauth2Configs []*http.OAuth2Config
+	if m.oauth2.Provider != "" {
+		c, err := m.oauth2Config()
+		if err != nil {
+			m.logger.Error("Failed to configure oauth2", zap.Error(err))
+			return err
+		}
+		oauth2Configs = append(oauth2Configs, c)
+	}
+
+	var auditLogSvc platform.AuditLogService = m.kvService
+	if m.auditMirror {
+		auditLogSvc = storage.NewAuditLogService(auditLogSvc, pointsWriter, m.logger.With(zap.String("service", "audit-mirror")))
+	}
+
 	m.apibackend = &http.APIBackend{
 		AssetsPath:           m.assetsPath,
 		HTTPErrorHandler:     http.ErrorHandler(0),
 		Logger:               m.logger,
 		SessionRenewDisabled: m.sessionRenewDisabled,
+		OAuth2Configs:        oauth2Configs,
 		NewBucketService:     source.NewBucketService,
-		NewQueryService:      source.NewQueryService,
+		NewQueryService:      newSourceQueryService,
+		CompilerMappings:     compilerMappings,
 		PointsWriter:         pointsWriter,
+		ReadStore:            readservice.NewStore(m.engine),
 		AuthorizationService: authSvc,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
 		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
@@ -614,6 +969,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		OrganizationService:             orgSvc,
 		UserResourceMappingService:      userResourceSvc,
 		LabelService:                    labelSvc,
+		RoleService:                     m.kvService,
+		GroupService:                    m.kvService,
+		GroupPermissionService:          m.kvService,
+		AuditLogService:                 auditLogSvc,
 		DashboardService:                dashboardSvc,
 		DashboardOperationLogService:    dashboardLogSvc,
 		BucketOperationLogService:       bucketLogSvc,
@@ -622,9 +981,12 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		SourceService:                   sourceSvc,
 		VariableService:                 variableSvc,
 		PasswordsService:                passwdsSvc,
+		SigninLockoutService:            m.kvService,
 		OnboardingService:               onboardingSvc,
 		InfluxQLService:                 nil, // No InfluxQL support
 		FluxService:                     storageQueryService,
+		QueryService:                    query.QueryServiceBridge{AsyncQueryService: m.queryController},
+		RunningQueryService:             m.queryController,
 		TaskService:                     taskSvc,
 		TelegrafService:                 telegrafSvc,
 		NotificationRuleStore:           notificationRuleSvc,
@@ -635,6 +997,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		LookupService:                   lookupSvc,
 		DocumentService:                 m.kvService,
 		OrgLookupService:                m.kvService,
+		AuthorizationRotationService:    m.kvService,
 		WriteEventRecorder:              infprom.NewEventRecorder("write"),
 		QueryEventRecorder:              infprom.NewEventRecorder("query"),
 	}
@@ -656,6 +1019,18 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		m.httpServer.Handler = http.DebugFlush(ctx, h, flusher)
 	}
 
+	transport := "http"
+	if m.tlsCert != "" || m.tlsKey != "" {
+		tlsConfig, err := m.newTLSConfig(ctx, httpLogger)
+		if err != nil {
+			httpLogger.Error("failed to configure TLS", zap.Error(err))
+			return err
+		}
+		m.httpServer.TLSConfig = tlsConfig
+		m.tlsEnabled = true
+		transport = "https"
+	}
+
 	ln, err := net.Listen("tcp", m.httpBindAddress)
 	if err != nil {
 		httpLogger.Error("failed http listener", zap.Error(err))
@@ -670,9 +1045,14 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.wg.Add(1)
 	go func(logger *zap.Logger) {
 		defer m.wg.Done()
-		logger.Info("Listening", zap.String("transport", "http"), zap.String("addr", m.httpBindAddress), zap.Int("port", m.httpPort))
+		logger.Info("Listening", zap.String("transport", transport), zap.String("addr", m.httpBindAddress), zap.Int("port", m.httpPort))
 
-		if err := m.httpServer.Serve(ln); err != nethttp.ErrServerClosed {
+		serve := m.httpServer.Serve
+		if m.tlsEnabled {
+			// The certificate is provided by the TLS configuration.
+			serve = func(ln net.Listener) error { return m.httpServer.ServeTLS(ln, "", "") }
+		}
+		if err := serve(ln); err != nethttp.ErrServerClosed {
 			logger.Error("failed http service", zap.Error(err))
 		}
 		logger.Info("Stopping")
@@ -2,15 +2,19 @@ package launcher_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"io/ioutil"
 	nethttp "net/http"
+	"strings"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influxd/launcher"
 	"github.com/influxdata/influxdb/http"
 	_ "github.com/influxdata/influxdb/query/builtin"
+	platformtesting "github.com/influxdata/influxdb/testing"
 )
 
 // Default context.
@@ -139,3 +143,34 @@ func TestLauncher_SetupWithUsers(t *testing.T) {
 		t.Fatalf("unexpected 2 users: %#+v", exp)
 	}
 }
+
+func TestLauncher_TLS(t *testing.T) {
+	l := launcher.NewTestLauncher()
+	cert, certPath, keyPath := platformtesting.WriteCertificate(t, l.Path, "127.0.0.1")
+	if err := l.Run(ctx, "--tls-cert", certPath, "--tls-key", keyPath); err != nil {
+		t.Fatal(err)
+	}
+	defer l.ShutdownOrFail(t, ctx)
+
+	if !strings.HasPrefix(l.URL(), "https://") {
+		t.Fatalf("expected an https URL, got %s", l.URL())
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	client := &nethttp.Client{
+		Transport: &nethttp.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+	}
+	resp, err := client.Get(l.URL() + "/api/v2/setup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.TLS == nil {
+		t.Fatal("expected the response to be served over TLS")
+	}
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+}
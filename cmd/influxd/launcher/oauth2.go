@@ -0,0 +1,26 @@
+package launcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/influxdb/http"
+)
+
+// oauth2Config returns the configuration of the OAuth2 provider users may sign in with.
+func (m *Launcher) oauth2Config() (*http.OAuth2Config, error) {
+	c := m.oauth2
+	c.GroupOrgs = make(map[string]string, len(m.oauth2GroupOrgs))
+	for _, pair := range m.oauth2GroupOrgs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("invalid oauth2 group organization %q, expected group=org", pair)
+		}
+		c.GroupOrgs[kv[0]] = kv[1]
+	}
+
+	if _, err := http.NewOAuth2Provider(&c, nil); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
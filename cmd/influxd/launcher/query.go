@@ -0,0 +1,30 @@
+package launcher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// queryOrgWeightsConfig returns the weights of the organizations when scheduling queries.
+func (m *Launcher) queryOrgWeightsConfig() (map[platform.ID]int, error) {
+	weights := make(map[platform.ID]int, len(m.queryOrgWeights))
+	for _, pair := range m.queryOrgWeights {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid query organization weight %q, expected orgID=weight", pair)
+		}
+		var orgID platform.ID
+		if err := orgID.DecodeFromString(kv[0]); err != nil {
+			return nil, fmt.Errorf("invalid organization ID in query organization weight %q: %v", pair, err)
+		}
+		w, err := strconv.Atoi(kv[1])
+		if err != nil || w <= 0 {
+			return nil, fmt.Errorf("invalid weight in query organization weight %q, expected a positive integer", pair)
+		}
+		weights[orgID] = w
+	}
+	return weights, nil
+}
@@ -8,10 +8,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influxd/launcher"
 	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/toml"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
 )
@@ -247,3 +251,102 @@ func TestStorage_CacheSnapshot_Age(t *testing.T) {
 		t.Fatalf("got %d series in TSM files, expected %d", got, exp)
 	}
 }
+
+func TestStorage_PrometheusRemoteReadPredicate(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, "up,tenant=acme value=1 1500000000000000000\nup,tenant=other value=2 1500000000000000000")
+
+	// The token may only read the series of the acme tenant.
+	auth := &influxdb.Authorization{
+		OrgID:  l.Org.ID,
+		UserID: l.User.ID,
+		Permissions: []influxdb.Permission{{
+			Action: influxdb.ReadAction,
+			Resource: influxdb.Resource{
+				Type:  influxdb.BucketsResourceType,
+				ID:    &l.Bucket.ID,
+				OrgID: &l.Org.ID,
+			},
+			Predicate: `tenant="acme"`,
+		}},
+	}
+	if err := l.KeyValueService().CreateAuthorization(ctx, auth); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := proto.Marshal(&prometheus.ReadRequest{
+		Queries: []*prometheus.Query{{
+			StartTimestampMs: 1500000000000,
+			EndTimestampMs:   1500000060000,
+			Matchers:         []*prometheus.LabelMatcher{{Type: prometheus.MatchEqual, Name: "__name__", Value: "up"}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := l.NewHTTPRequestOrFail(t, "POST", fmt.Sprintf("/api/v2/prometheus/read?orgID=%s&bucket=%s", l.Org.ID, l.Bucket.ID), auth.Token, string(snappy.Encode(nil, data)))
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("unexpected status code: %d, body: %s", resp.StatusCode, body)
+	}
+	if data, err = snappy.Decode(nil, body); err != nil {
+		t.Fatal(err)
+	}
+	var rr prometheus.ReadResponse
+	if err := proto.Unmarshal(data, &rr); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*prometheus.TimeSeries{{
+		Labels:  []*prometheus.Label{{Name: "__name__", Value: "up"}, {Name: "tenant", Value: "acme"}},
+		Samples: []*prometheus.Sample{{Value: 1, Timestamp: 1500000000000}},
+	}}
+	if len(rr.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(rr.Results))
+	}
+	if diff := cmp.Diff(want, rr.Results[0].Timeseries); diff != "" {
+		t.Fatalf("unexpected series -want/+got:\n%s", diff)
+	}
+}
+
+func TestStorage_SourceInfluxQLQuery(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	l.WritePointsOrFail(t, `m,k=v f=100i,g="a" 946684800000000000`)
+
+	body := fmt.Sprintf(`{"type":"influxql","query":"SELECT * FROM m WHERE time >= '2000-01-01T00:00:00Z' AND time < '2000-01-02T00:00:00Z'","db":%q,"organizationID":%q}`, l.Bucket.Name, l.Org.ID)
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", fmt.Sprintf("/api/v2/sources/%s/query", kv.DefaultSourceID), body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("unexpected status code: %d, body: %s", resp.StatusCode, buf)
+	}
+
+	// The wildcard is expanded to the fields and tags of the measurement.
+	exp := `,result,table,_start,_measurement,_time,f,g,k` + "\r\n" +
+		`,0,0,2000-01-01T00:00:00Z,m,2000-01-01T00:00:00Z,100,a,v` + "\r\n\r\n"
+	if diff := cmp.Diff(string(buf), exp); diff != "" {
+		t.Fatal(diff)
+	}
+}
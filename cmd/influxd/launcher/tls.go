@@ -0,0 +1,77 @@
+package launcher
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/influxdata/influxdb/http"
+	"go.uber.org/zap"
+)
+
+// newTLSConfig returns the TLS configuration of the HTTP server.
+// The certificate is reloaded on SIGHUP until ctx is done.
+func (m *Launcher) newTLSConfig(ctx context.Context, logger *zap.Logger) (*tls.Config, error) {
+	if m.tlsCert == "" || m.tlsKey == "" {
+		return nil, errors.New("both tls-cert and tls-key must be set")
+	}
+
+	certs, err := http.NewCertificateReloader(m.tlsCert, m.tlsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	minVersion, err := http.ParseTLSVersion(m.tlsMinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		GetCertificate:           certs.GetCertificate,
+		MinVersion:               minVersion,
+		PreferServerCipherSuites: true,
+	}
+
+	if len(m.tlsCipherSuites) > 0 {
+		suites, err := http.ParseCipherSuites(m.tlsCipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		cfg.CipherSuites = suites
+	}
+
+	if m.tlsClientCA != "" {
+		pool, err := http.LoadCertPool(m.tlsClientCA)
+		if err != nil {
+			return nil, err
+		}
+		// Clients without certificate authenticate with a token or a session.
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer signal.Stop(sighup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sighup:
+				if err := certs.Reload(); err != nil {
+					logger.Error("Failed to reload TLS certificate", zap.Error(err))
+					continue
+				}
+				logger.Info("Reloaded TLS certificate", zap.String("cert", m.tlsCert))
+			}
+		}
+	}()
+
+	return cfg, nil
+}
@@ -35,6 +35,7 @@ func init() {
 	rootCmd.InitDefaultHelpCmd()
 
 	rootCmd.AddCommand(launcher.NewCommand())
+	rootCmd.AddCommand(launcher.NewPrintConfigCommand())
 	rootCmd.AddCommand(generate.Command)
 	rootCmd.AddCommand(inspect.NewCommand())
 }
@@ -22,6 +22,7 @@ const (
 	EUnavailable         = "unavailable"
 	EForbidden           = "forbidden"
 	ETooManyRequests     = "too many requests"
+	ETooLarge            = "request too large"
 	EUnauthorized        = "unauthorized"
 	EMethodNotAllowed    = "method not allowed"
 )
@@ -0,0 +1,97 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// ErrGroupNotFound is the error for a missing Group.
+const ErrGroupNotFound = "group not found"
+
+const (
+	OpFindGroupByID = "FindGroupByID"
+	OpFindGroups    = "FindGroups"
+	OpCreateGroup   = "CreateGroup"
+	OpUpdateGroup   = "UpdateGroup"
+	OpDeleteGroup   = "DeleteGroup"
+
+	OpFindGroupPermissions = "FindGroupPermissions"
+)
+
+// GroupService represents a service for managing groups.
+//
+// The users of a group are mapped to it with a UserResourceMapping of the groups resource type.
+// A group is itself mapped to resources with a UserResourceMapping of the GroupMappingType,
+// granting its users the permissions to the resource.
+type GroupService interface {
+	// FindGroupByID returns a single group by ID.
+	FindGroupByID(ctx context.Context, id ID) (*Group, error)
+
+	// FindGroups returns a list of groups that match filter and the total count of matching groups.
+	FindGroups(ctx context.Context, filter GroupFilter, opt ...FindOptions) ([]*Group, int, error)
+
+	// CreateGroup creates a new group and sets g.ID with the new identifier.
+	CreateGroup(ctx context.Context, g *Group) error
+
+	// UpdateGroup updates a group with a changeset.
+	UpdateGroup(ctx context.Context, id ID, upd GroupUpdate) (*Group, error)
+
+	// DeleteGroup deletes a group along with its users and the resources it is mapped to.
+	DeleteGroup(ctx context.Context, id ID) error
+}
+
+// GroupPermissionService resolves the permissions a user is granted by the groups they belong to.
+type GroupPermissionService interface {
+	// FindGroupPermissions returns the permissions granted to a user by the groups of an org.
+	FindGroupPermissions(ctx context.Context, userID, orgID ID) ([]Permission, error)
+}
+
+// Group is a named set of users within an organization.
+type Group struct {
+	ID          ID     `json:"id,omitempty"`
+	OrgID       ID     `json:"orgID,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Validate returns an error if the group is invalid.
+func (g *Group) Validate() error {
+	if g.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "group name is required",
+		}
+	}
+
+	if !g.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+
+	return nil
+}
+
+// GroupUpdate represents a changeset for a group.
+// Only the properties specified are updated.
+type GroupUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// Apply applies the changeset to g.
+func (u GroupUpdate) Apply(g *Group) {
+	if u.Name != nil {
+		g.Name = *u.Name
+	}
+	if u.Description != nil {
+		g.Description = *u.Description
+	}
+}
+
+// GroupFilter represents a set of filters that restrict the returned results.
+type GroupFilter struct {
+	ID    *ID
+	Name  *string
+	OrgID *ID
+}
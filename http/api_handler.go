@@ -4,6 +4,7 @@ import (
 	http "net/http"
 	"strings"
 
+	"github.com/influxdata/flux"
 	influxdb "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/authorizer"
 	"github.com/influxdata/influxdb/chronograf/server"
@@ -11,6 +12,7 @@ import (
 	"github.com/influxdata/influxdb/kit/prom"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
@@ -24,6 +26,10 @@ type APIHandler struct {
 	AuthorizationHandler    *AuthorizationHandler
 	DashboardHandler        *DashboardHandler
 	LabelHandler            *LabelHandler
+	RoleHandler             *RoleHandler
+	GroupHandler            *GroupHandler
+	AuditHandler            *AuditHandler
+	RunningQueryHandler     *RunningQueryHandler
 	AssetHandler            *AssetHandler
 	ChronografHandler       *ChronografHandler
 	ScraperHandler          *ScraperHandler
@@ -33,6 +39,8 @@ type APIHandler struct {
 	CheckHandler            *CheckHandler
 	TelegrafHandler         *TelegrafHandler
 	QueryHandler            *FluxHandler
+	PromQLHandler           *PromQLHandler
+	PrometheusRemoteHandler *PrometheusRemoteHandler
 	WriteHandler            *WriteHandler
 	DocumentHandler         *DocumentHandler
 	SetupHandler            *SetupHandler
@@ -48,21 +56,31 @@ type APIBackend struct {
 	Logger     *zap.Logger
 	influxdb.HTTPErrorHandler
 	SessionRenewDisabled bool
+	OAuth2Configs        []*OAuth2Config
 
 	NewBucketService func(*influxdb.Source) (influxdb.BucketService, error)
 	NewQueryService  func(*influxdb.Source) (query.ProxyQueryService, error)
 
+	// CompilerMappings creates the compilers of the queries of self sources.
+	CompilerMappings flux.CompilerMappings
+
 	WriteEventRecorder metric.EventRecorder
 	QueryEventRecorder metric.EventRecorder
 
 	PointsWriter                    storage.PointsWriter
+	ReadStore                       reads.Store
 	AuthorizationService            influxdb.AuthorizationService
+	AuthorizationRotationService    influxdb.AuthorizationRotationService
 	BucketService                   influxdb.BucketService
 	SessionService                  influxdb.SessionService
 	UserService                     influxdb.UserService
 	OrganizationService             influxdb.OrganizationService
 	UserResourceMappingService      influxdb.UserResourceMappingService
 	LabelService                    influxdb.LabelService
+	RoleService                     influxdb.RoleService
+	GroupService                    influxdb.GroupService
+	GroupPermissionService          influxdb.GroupPermissionService
+	AuditLogService                 influxdb.AuditLogService
 	DashboardService                influxdb.DashboardService
 	DashboardOperationLogService    influxdb.DashboardOperationLogService
 	BucketOperationLogService       influxdb.BucketOperationLogService
@@ -71,9 +89,12 @@ type APIBackend struct {
 	SourceService                   influxdb.SourceService
 	VariableService                 influxdb.VariableService
 	PasswordsService                influxdb.PasswordsService
+	SigninLockoutService            influxdb.SigninLockoutService
 	OnboardingService               influxdb.OnboardingService
 	InfluxQLService                 query.ProxyQueryService
 	FluxService                     query.ProxyQueryService
+	QueryService                    query.QueryService
+	RunningQueryService             influxdb.RunningQueryService
 	TaskService                     influxdb.TaskService
 	CheckService                    influxdb.CheckService
 	TelegrafService                 influxdb.TelegrafConfigStore
@@ -114,6 +135,7 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.DocumentHandler = NewDocumentHandler(documentBackend)
 
 	sessionBackend := NewSessionBackend(b)
+	sessionBackend.UserResourceMappingService = internalURM
 	h.SessionHandler = NewSessionHandler(sessionBackend)
 
 	bucketBackend := NewBucketBackend(b)
@@ -138,6 +160,9 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 
 	authorizationBackend := NewAuthorizationBackend(b)
 	authorizationBackend.AuthorizationService = authorizer.NewAuthorizationService(b.AuthorizationService)
+	if b.AuthorizationRotationService != nil {
+		authorizationBackend.AuthorizationRotationService = authorizer.NewAuthorizationRotationService(b.AuthorizationService, b.AuthorizationRotationService)
+	}
 	h.AuthorizationHandler = NewAuthorizationHandler(authorizationBackend)
 
 	scraperBackend := NewScraperBackend(b)
@@ -178,9 +203,22 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	fluxBackend := NewFluxBackend(b)
 	h.QueryHandler = NewFluxHandler(fluxBackend)
 
+	promQLBackend := NewPromQLBackend(b)
+	h.PromQLHandler = NewPromQLHandler(promQLBackend)
+
+	prometheusRemoteBackend := NewPrometheusRemoteBackend(b)
+	h.PrometheusRemoteHandler = NewPrometheusRemoteHandler(prometheusRemoteBackend)
+
 	h.ChronografHandler = NewChronografHandler(b.ChronografService, b.HTTPErrorHandler)
 	h.SwaggerHandler = newSwaggerLoader(b.Logger.With(zap.String("service", "swagger-loader")), b.HTTPErrorHandler)
 	h.LabelHandler = NewLabelHandler(authorizer.NewLabelService(b.LabelService), b.HTTPErrorHandler)
+	h.RoleHandler = NewRoleHandler(authorizer.NewRoleService(b.RoleService), b.HTTPErrorHandler)
+	groupBackend := NewGroupBackend(b)
+	groupBackend.GroupService = authorizer.NewGroupService(b.GroupService)
+	h.GroupHandler = NewGroupHandler(groupBackend)
+
+	h.AuditHandler = NewAuditHandler(authorizer.NewAuditLogService(b.AuditLogService), b.HTTPErrorHandler)
+	h.RunningQueryHandler = NewRunningQueryHandler(authorizer.NewRunningQueryService(b.RunningQueryService), b.HTTPErrorHandler)
 
 	return h
 }
@@ -188,17 +226,21 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
+	"audit":          "/api/v2/audit",
 	"authorizations": "/api/v2/authorizations",
 	"buckets":        "/api/v2/buckets",
 	"dashboards":     "/api/v2/dashboards",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
+	"groups":            "/api/v2/groups",
 	"labels":            "/api/v2/labels",
 	"variables":         "/api/v2/variables",
 	"me":                "/api/v2/me",
 	"notificationRules": "/api/v2/notificationRules",
 	"orgs":              "/api/v2/orgs",
+	"roles":             "/api/v2/roles",
+	"queries":           "/api/v2/queries",
 	"query": map[string]string{
 		"self":        "/api/v2/query",
 		"ast":         "/api/v2/query/ast",
@@ -207,6 +249,7 @@ var apiLinks = map[string]interface{}{
 	},
 	"setup":    "/api/v2/setup",
 	"signin":   "/api/v2/signin",
+	"oauth2":   "/api/v2/signin/oauth2",
 	"signout":  "/api/v2/signout",
 	"sources":  "/api/v2/sources",
 	"scrapers": "/api/v2/scrapers",
@@ -243,7 +286,7 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if r.URL.Path == "/api/v2/signin" || r.URL.Path == "/api/v2/signout" {
+	if r.URL.Path == "/api/v2/signin" || r.URL.Path == "/api/v2/signout" || strings.HasPrefix(r.URL.Path, "/api/v2/signin/") {
 		h.SessionHandler.ServeHTTP(w, r)
 		return
 	}
@@ -258,11 +301,26 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/queries") {
+		h.RunningQueryHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/query") {
 		h.QueryHandler.ServeHTTP(w, r)
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v1/query") {
+		h.PromQLHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/prometheus") {
+		h.PrometheusRemoteHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/buckets") {
 		h.BucketHandler.ServeHTTP(w, r)
 		return
@@ -273,6 +331,21 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/roles") {
+		h.RoleHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/groups") {
+		h.GroupHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/audit") {
+		h.AuditHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/users") {
 		h.UserHandler.ServeHTTP(w, r)
 		return
@@ -0,0 +1,456 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	platcontext "github.com/influxdata/influxdb/context"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	auditPath = "/api/v2/audit"
+
+	// maxAuditResponseBytes is the largest response read for the ID of a created resource.
+	maxAuditResponseBytes = 1 << 16
+)
+
+// AuditHandler represents an HTTP API handler for the audit log.
+type AuditHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	AuditLogService influxdb.AuditLogService
+}
+
+// NewAuditHandler returns a new instance of AuditHandler.
+func NewAuditHandler(s influxdb.AuditLogService, he influxdb.HTTPErrorHandler) *AuditHandler {
+	h := &AuditHandler{
+		Router:           NewRouter(he),
+		HTTPErrorHandler: he,
+		Logger:           zap.NewNop(),
+		AuditLogService:  s,
+	}
+
+	h.HandlerFunc("GET", auditPath, h.handleGetAuditEvents)
+	return h
+}
+
+type auditEventsResponse struct {
+	Links  map[string]string      `json:"links"`
+	Events []*influxdb.AuditEvent `json:"events"`
+}
+
+// handleGetAuditEvents is the HTTP handler for the GET /api/v2/audit route.
+func (h *AuditHandler) handleGetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeAuditEventFilter(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	es, _, err := h.AuditLogService.FindAuditEvents(ctx, filter, *opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := &auditEventsResponse{
+		Links: map[string]string{
+			"self": auditPath,
+		},
+		Events: es,
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeAuditEventFilter(r *http.Request) (influxdb.AuditEventFilter, error) {
+	qp := r.URL.Query()
+	var filter influxdb.AuditEventFilter
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = id
+	}
+
+	if userID := qp.Get("userID"); userID != "" {
+		id, err := influxdb.IDFromString(userID)
+		if err != nil {
+			return filter, err
+		}
+		filter.UserID = id
+	}
+
+	since, err := decodeAuditTime(qp.Get("since"), "since")
+	if err != nil {
+		return filter, err
+	}
+	filter.Since = since
+
+	until, err := decodeAuditTime(qp.Get("until"), "until")
+	if err != nil {
+		return filter, err
+	}
+	filter.Until = until
+
+	return filter, nil
+}
+
+func decodeAuditTime(v, name string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  name + " must be an RFC3339 time",
+			Err:  err,
+		}
+	}
+	return &t, nil
+}
+
+// AuditRecorder records the mutating API requests served by its handler into the audit log.
+// The handler must serve authenticated requests, so that the actor of the request can be recorded.
+type AuditRecorder struct {
+	Handler         http.Handler
+	AuditLogService influxdb.AuditLogService
+	Logger          *zap.Logger
+}
+
+// NewAuditRecorder returns a new instance of AuditRecorder.
+func NewAuditRecorder(h http.Handler, s influxdb.AuditLogService, log *zap.Logger) *AuditRecorder {
+	return &AuditRecorder{
+		Handler:         h,
+		AuditLogService: s,
+		Logger:          log,
+	}
+}
+
+// auditResource is the resource changed by a request.
+type auditResource struct {
+	typ   influxdb.ResourceType
+	id    influxdb.ID
+	orgID influxdb.ID
+	// path is the route of the resource, if it exists.
+	path string
+}
+
+// ServeHTTP serves the request and records it into the audit log if it is mutating.
+// The services record the stored states of the resources they change on the context
+// of the request, from which the diff of the changed resource is computed.
+func (h *AuditRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !auditedRequest(r) {
+		h.Handler.ServeHTTP(w, r)
+		return
+	}
+
+	ctx := r.Context()
+	e := &influxdb.AuditEvent{
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		SourceIP: remoteHost(r),
+	}
+	if a, err := platcontext.GetAuthorizer(ctx); err == nil {
+		e.UserID = a.GetUserID()
+		if a.Kind() == influxdb.AuthorizationKind {
+			e.AuthorizationID = a.Identifier()
+		}
+	}
+
+	res := parseAuditResource(r.URL.Path, e.UserID)
+	e.ResourceType, e.ResourceID = res.typ, res.id
+
+	rec := influxdb.NewAuditRecording()
+	sw := newAuditResponseWriter(w)
+	h.Handler.ServeHTTP(sw, r.WithContext(influxdb.ContextWithAuditRecording(ctx, rec)))
+	e.Status = sw.code()
+
+	switch {
+	case r.Method == "DELETE" && res.path == r.URL.Path:
+		e.Action = influxdb.AuditDeleteAction
+	case r.Method == "POST" && res.path == "":
+		e.Action = influxdb.AuditCreateAction
+	default:
+		e.Action = influxdb.AuditUpdateAction
+	}
+
+	var before, after map[string]json.RawMessage
+	if e.Status < 300 {
+		if e.Action == influxdb.AuditCreateAction && !e.ResourceID.Valid() {
+			e.ResourceID = createdAuditResource(rec, sw)
+		}
+		if res.typ == influxdb.SecretsResourceType {
+			e.Diff = rec.Secrets(res.orgID)
+		} else if b, a, ok := rec.State(e.ResourceID); ok {
+			before, after = decodeAuditState(b), decodeAuditState(a)
+			e.Diff = diffAuditStates(before, after)
+		}
+	}
+
+	e.OrgID = res.orgID
+	for _, s := range []map[string]json.RawMessage{after, before} {
+		if !e.OrgID.Valid() {
+			e.OrgID = auditStateID(s, "orgID")
+		}
+	}
+	if !e.OrgID.Valid() {
+		if id, err := influxdb.IDFromString(r.URL.Query().Get("orgID")); err == nil {
+			e.OrgID = *id
+		}
+	}
+
+	if err := h.AuditLogService.AppendAuditEvent(ctx, e); err != nil {
+		h.Logger.Error("Failed to append audit event", zap.String("method", e.Method), zap.String("path", e.Path), zap.Error(err))
+	}
+}
+
+// createdAuditResource returns the ID of the resource created by a request.
+// It is the ID of the resource in the response, or else of the only resource created.
+func createdAuditResource(rec *influxdb.AuditRecording, sw *auditResponseWriter) influxdb.ID {
+	if !sw.truncated {
+		var res struct {
+			ID influxdb.ID `json:"id"`
+		}
+		if err := json.Unmarshal(sw.body.Bytes(), &res); err == nil && res.ID.Valid() {
+			return res.ID
+		}
+	}
+	if ids := rec.Created(); len(ids) == 1 {
+		return ids[0]
+	}
+	return 0
+}
+
+// unauditedPaths are the path prefixes of the requests writing or querying data,
+// which are not audited.
+var unauditedPaths = []string{
+	"/api/v2/write",
+	"/api/v2/query",
+	prometheusWritePath,
+	prometheusReadPath,
+	promQLInstantPath, // includes promQLRangePath
+}
+
+// auditedRequest returns true if the request may change resources.
+// Writes and queries are not audited.
+func auditedRequest(r *http.Request) bool {
+	switch r.Method {
+	case "POST", "PUT", "PATCH", "DELETE":
+	default:
+		return false
+	}
+
+	for _, p := range unauditedPaths {
+		if strings.HasPrefix(r.URL.Path, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAuditResource returns the resource changed by a request on path, made by the user userID.
+// The resource is named by the first path segment following /api/v2, except for the secrets
+// of organizations.
+func parseAuditResource(path string, userID influxdb.ID) auditResource {
+	segs := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/v2"), "/"), "/")
+	res := auditResource{typ: influxdb.ResourceType(segs[0])}
+
+	if segs[0] == "me" {
+		res.typ = influxdb.UsersResourceType
+		res.id = userID
+		res.path = "/api/v2/me"
+		return res
+	}
+
+	if len(segs) < 2 {
+		return res
+	}
+	var id influxdb.ID
+	if err := id.DecodeFromString(segs[1]); err != nil {
+		return res
+	}
+
+	if res.typ == influxdb.OrgsResourceType {
+		res.orgID = id
+		if len(segs) > 2 && segs[2] == "secrets" {
+			res.typ = influxdb.SecretsResourceType
+			res.path = "/api/v2/orgs/" + segs[1] + "/secrets"
+			return res
+		}
+	}
+
+	res.id = id
+	res.path = "/api/v2/" + segs[0] + "/" + segs[1]
+	return res
+}
+
+// auditRedactedField returns true if the values of the field of a resource are not recorded.
+// These are the fields holding tokens, passwords or secrets, whatever their exact name.
+func auditRedactedField(k string) bool {
+	k = strings.ToLower(k)
+	for _, s := range []string{"token", "password", "secret"} {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeAuditState decodes the stored state of a resource from a JSON object,
+// redacting its secret fields.
+func decodeAuditState(b []byte) map[string]json.RawMessage {
+	var s map[string]json.RawMessage
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil
+	}
+
+	for k, v := range s {
+		if auditRedactedField(k) {
+			s[k] = influxdb.AuditRedacted
+			continue
+		}
+		s[k] = redactAuditValue(v)
+	}
+	return s
+}
+
+// redactAuditValue redacts the secret fields of the objects nested in v.
+func redactAuditValue(v json.RawMessage) json.RawMessage {
+	var i interface{}
+	if err := json.Unmarshal(v, &i); err != nil {
+		return v
+	}
+
+	var redacted bool
+	var redact func(i interface{})
+	redact = func(i interface{}) {
+		switch i := i.(type) {
+		case map[string]interface{}:
+			for k, v := range i {
+				if auditRedactedField(k) {
+					i[k] = "[REDACTED]"
+					redacted = true
+					continue
+				}
+				redact(v)
+			}
+		case []interface{}:
+			for _, v := range i {
+				redact(v)
+			}
+		}
+	}
+	redact(i)
+
+	if !redacted {
+		return v
+	}
+	b, err := json.Marshal(i)
+	if err != nil {
+		return influxdb.AuditRedacted
+	}
+	return b
+}
+
+// auditStateID returns the ID in the field of the state, if any.
+func auditStateID(s map[string]json.RawMessage, field string) influxdb.ID {
+	var v string
+	if err := json.Unmarshal(s[field], &v); err != nil {
+		return 0
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(v); err != nil {
+		return 0
+	}
+	return id
+}
+
+// diffAuditStates returns the fields that differ between the states.
+func diffAuditStates(before, after map[string]json.RawMessage) map[string]influxdb.AuditChange {
+	diff := make(map[string]influxdb.AuditChange)
+	for k, v := range before {
+		if !auditValuesEqual(v, after[k]) {
+			diff[k] = influxdb.AuditChange{Before: v, After: after[k]}
+		}
+	}
+	for k, v := range after {
+		if _, ok := before[k]; !ok {
+			diff[k] = influxdb.AuditChange{After: v}
+		}
+	}
+
+	if len(diff) == 0 {
+		return nil
+	}
+	return diff
+}
+
+func auditValuesEqual(a, b json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// auditResponseWriter captures the status and, up to maxAuditResponseBytes, the body of a response.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+	truncated  bool
+}
+
+func newAuditResponseWriter(w http.ResponseWriter) *auditResponseWriter {
+	return &auditResponseWriter{ResponseWriter: w}
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.body.Len()+len(b) > maxAuditResponseBytes {
+		w.truncated = true
+	} else if !w.truncated {
+		w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteHeader writes the header and captures the status code.
+func (w *auditResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *auditResponseWriter) code() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+	return w.statusCode
+}
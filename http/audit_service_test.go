@@ -0,0 +1,280 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	platcontext "github.com/influxdata/influxdb/context"
+	platformhttp "github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+// stubAuditedAPI changes the resources of a service like the API handlers do.
+type stubAuditedAPI struct {
+	svc      *kv.Service
+	orgID    platform.ID
+	userID   platform.ID
+	bucketID platform.ID
+}
+
+func (a *stubAuditedAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	encode := func(code int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(v)
+	}
+
+	switch {
+	case r.URL.Path == "/api/v2/buckets/"+a.bucketID.String() && r.Method == "PATCH":
+		var upd platform.BucketUpdate
+		json.NewDecoder(r.Body).Decode(&upd)
+		b, err := a.svc.UpdateBucket(ctx, a.bucketID, upd)
+		if err != nil {
+			encode(http.StatusInternalServerError, err)
+			return
+		}
+		encode(http.StatusOK, b)
+	case r.URL.Path == "/api/v2/buckets/"+a.bucketID.String() && r.Method == "DELETE":
+		if err := a.svc.DeleteBucket(ctx, a.bucketID); err != nil {
+			encode(http.StatusInternalServerError, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.URL.Path == "/api/v2/authorizations" && r.Method == "POST":
+		auth := &platform.Authorization{OrgID: a.orgID, UserID: a.userID}
+		if err := a.svc.CreateAuthorization(ctx, auth); err != nil {
+			encode(http.StatusInternalServerError, err)
+			return
+		}
+		encode(http.StatusCreated, auth)
+	case r.URL.Path == "/api/v2/orgs/"+a.orgID.String()+"/secrets" && r.Method == "PATCH":
+		var upd map[string]string
+		json.NewDecoder(r.Body).Decode(&upd)
+		if err := a.svc.PatchSecrets(ctx, a.orgID, upd); err != nil {
+			encode(http.StatusInternalServerError, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusForbidden)
+	}
+}
+
+func TestAuditRecorder(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	id := platform.ID(0x020f755c3c082000)
+	svc.IDGenerator = mock.IDGenerator{IDFn: func() platform.ID {
+		id++
+		return id
+	}}
+	svc.TokenGenerator = mock.NewTokenGenerator("secret-token", nil)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)}
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &platform.Organization{Name: "o"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &platform.User{Name: "u"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &platform.Bucket{OrgID: org.ID, Name: "telemetry"}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+	api := &stubAuditedAPI{
+		svc:      svc,
+		orgID:    org.ID,
+		userID:   user.ID,
+		bucketID: bucket.ID,
+	}
+	h := platformhttp.NewAuditRecorder(api, svc, zap.NewNop())
+
+	auth := &platform.Authorization{ID: 3, UserID: 4, Status: platform.Active}
+	serve := func(method, path, body string) int {
+		r := httptest.NewRequest(method, "http://localhost:9999"+path, strings.NewReader(body))
+		r.RemoteAddr = "10.1.2.3:51234"
+		r = r.WithContext(platcontext.SetAuthorizer(r.Context(), auth))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	serve("GET", "/api/v2/buckets/"+bucket.ID.String(), "")
+	serve("PATCH", "/api/v2/buckets/"+bucket.ID.String(), `{"name":"metrics"}`)
+	serve("POST", "/api/v2/authorizations", `{}`)
+	serve("PATCH", "/api/v2/orgs/"+org.ID.String()+"/secrets", `{"apikey":"s3cr3t"}`)
+	serve("PATCH", "/api/v2/orgs/"+org.ID.String()+"/secrets", `{"apikey":"rotated"}`)
+	serve("DELETE", "/api/v2/buckets/"+bucket.ID.String(), "")
+	serve("POST", "/api/v2/write", "m f=1")
+	serve("POST", "/api/v2/query", `{"query":"buckets()"}`)
+	serve("POST", "/api/v2/prometheus/write", "")
+	serve("POST", "/api/v2/prometheus/read", "")
+	serve("POST", "/api/v1/query", "query=up")
+	serve("POST", "/api/v1/query_range", "query=up")
+	serve("DELETE", "/api/v2/dashboards/020f755c3c082fff", "")
+
+	es, _, err := svc.FindAuditEvents(ctx, platform.AuditEventFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type event struct {
+		Action       platform.AuditAction
+		ResourceType platform.ResourceType
+		ResourceID   string
+		OrgID        string
+		Status       int
+		Diff         map[string][2]string
+	}
+	var got []event
+	for _, e := range es {
+		if e.UserID != 4 || e.AuthorizationID != 3 || e.SourceIP != "10.1.2.3" {
+			t.Errorf("unexpected actor of event %+v", e)
+		}
+		ev := event{
+			Action:       e.Action,
+			ResourceType: e.ResourceType,
+			Status:       e.Status,
+		}
+		if e.ResourceID.Valid() {
+			ev.ResourceID = e.ResourceID.String()
+		}
+		if e.OrgID.Valid() {
+			ev.OrgID = e.OrgID.String()
+		}
+		for k, c := range e.Diff {
+			if ev.Diff == nil {
+				ev.Diff = make(map[string][2]string)
+			}
+			ev.Diff[k] = [2]string{string(c.Before), string(c.After)}
+		}
+		got = append(got, ev)
+	}
+
+	want := []event{
+		{
+			Action:       platform.AuditUpdateAction,
+			ResourceType: platform.BucketsResourceType,
+			ResourceID:   bucket.ID.String(),
+			OrgID:        org.ID.String(),
+			Status:       http.StatusOK,
+			Diff:         map[string][2]string{"name": {`"telemetry"`, `"metrics"`}},
+		},
+		{
+			Action:       platform.AuditCreateAction,
+			ResourceType: platform.AuthorizationsResourceType,
+			ResourceID:   "020f755c3c082005",
+			OrgID:        org.ID.String(),
+			Status:       http.StatusCreated,
+			Diff: map[string][2]string{
+				"description": {"", `""`},
+				"hashedToken": {"", `"[REDACTED]"`},
+				"id":          {"", `"020f755c3c082005"`},
+				"orgID":       {"", `"020f755c3c082001"`},
+				"permissions": {"", "null"},
+				"status":      {"", `"active"`},
+				"token":       {"", `"[REDACTED]"`},
+				"userID":      {"", `"020f755c3c082002"`},
+			},
+		},
+		{
+			Action:       platform.AuditUpdateAction,
+			ResourceType: platform.SecretsResourceType,
+			OrgID:        org.ID.String(),
+			Status:       http.StatusNoContent,
+			Diff:         map[string][2]string{"apikey": {"", `"[REDACTED]"`}},
+		},
+		{
+			Action:       platform.AuditUpdateAction,
+			ResourceType: platform.SecretsResourceType,
+			OrgID:        org.ID.String(),
+			Status:       http.StatusNoContent,
+			Diff:         map[string][2]string{"apikey": {`"[REDACTED]"`, `"[REDACTED]"`}},
+		},
+		{
+			Action:       platform.AuditDeleteAction,
+			ResourceType: platform.BucketsResourceType,
+			ResourceID:   bucket.ID.String(),
+			OrgID:        org.ID.String(),
+			Status:       http.StatusNoContent,
+			Diff: map[string][2]string{
+				"createdAt":       {`"2019-06-01T00:00:00Z"`, ""},
+				"description":     {`""`, ""},
+				"id":              {`"020f755c3c082003"`, ""},
+				"name":            {`"metrics"`, ""},
+				"orgID":           {`"020f755c3c082001"`, ""},
+				"retentionPeriod": {"0", ""},
+				"updatedAt":       {`"2019-06-01T00:00:00Z"`, ""},
+			},
+		},
+		{
+			Action:       platform.AuditDeleteAction,
+			ResourceType: platform.DashboardsResourceType,
+			ResourceID:   "020f755c3c082fff",
+			Status:       http.StatusForbidden,
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("unexpected events -got/+want\n%s", diff)
+	}
+}
+
+func TestAuditHandler_handleGetAuditEvents(t *testing.T) {
+	var gotFilter platform.AuditEventFilter
+	var gotOpts platform.FindOptions
+	svc := mock.NewAuditLogService()
+	svc.FindAuditEventsFn = func(ctx context.Context, filter platform.AuditEventFilter, opt ...platform.FindOptions) ([]*platform.AuditEvent, int, error) {
+		gotFilter, gotOpts = filter, opt[0]
+		return []*platform.AuditEvent{{ID: 1, Action: platform.AuditCreateAction}}, 1, nil
+	}
+	h := platformhttp.NewAuditHandler(svc, platformhttp.ErrorHandler(0))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/audit?orgID=020f755c3c083000&since=2019-06-01T00:00:00Z&limit=10&descending=true", nil)
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+
+	if gotFilter.OrgID == nil || gotFilter.OrgID.String() != "020f755c3c083000" {
+		t.Errorf("unexpected org filter %v", gotFilter.OrgID)
+	}
+	if gotFilter.Since == nil || gotFilter.Since.Format("2006-01-02") != "2019-06-01" || gotFilter.Until != nil {
+		t.Errorf("unexpected time filter %v %v", gotFilter.Since, gotFilter.Until)
+	}
+	if gotOpts.Limit != 10 || !gotOpts.Descending {
+		t.Errorf("unexpected find options %+v", gotOpts)
+	}
+
+	var res struct {
+		Events []*platform.AuditEvent `json:"events"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Events) != 1 || res.Events[0].Action != platform.AuditCreateAction {
+		t.Errorf("unexpected events %+v", res.Events)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost:9999/api/v2/audit?until=yesterday", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unexpected status %d for invalid time", w.Code)
+	}
+}
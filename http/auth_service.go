@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"path"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -22,10 +24,11 @@ type AuthorizationBackend struct {
 	platform.HTTPErrorHandler
 	Logger *zap.Logger
 
-	AuthorizationService platform.AuthorizationService
-	OrganizationService  platform.OrganizationService
-	UserService          platform.UserService
-	LookupService        platform.LookupService
+	AuthorizationService         platform.AuthorizationService
+	AuthorizationRotationService platform.AuthorizationRotationService
+	OrganizationService          platform.OrganizationService
+	UserService                  platform.UserService
+	LookupService                platform.LookupService
 }
 
 // NewAuthorizationBackend returns a new instance of AuthorizationBackend.
@@ -34,10 +37,11 @@ func NewAuthorizationBackend(b *APIBackend) *AuthorizationBackend {
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		Logger:           b.Logger.With(zap.String("handler", "authorization")),
 
-		AuthorizationService: b.AuthorizationService,
-		OrganizationService:  b.OrganizationService,
-		UserService:          b.UserService,
-		LookupService:        b.LookupService,
+		AuthorizationService:         b.AuthorizationService,
+		AuthorizationRotationService: b.AuthorizationRotationService,
+		OrganizationService:          b.OrganizationService,
+		UserService:                  b.UserService,
+		LookupService:                b.LookupService,
 	}
 }
 
@@ -47,10 +51,11 @@ type AuthorizationHandler struct {
 	platform.HTTPErrorHandler
 	Logger *zap.Logger
 
-	OrganizationService  platform.OrganizationService
-	UserService          platform.UserService
-	AuthorizationService platform.AuthorizationService
-	LookupService        platform.LookupService
+	OrganizationService          platform.OrganizationService
+	UserService                  platform.UserService
+	AuthorizationService         platform.AuthorizationService
+	AuthorizationRotationService platform.AuthorizationRotationService
+	LookupService                platform.LookupService
 }
 
 // NewAuthorizationHandler returns a new instance of AuthorizationHandler.
@@ -60,10 +65,11 @@ func NewAuthorizationHandler(b *AuthorizationBackend) *AuthorizationHandler {
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		Logger:           b.Logger,
 
-		AuthorizationService: b.AuthorizationService,
-		OrganizationService:  b.OrganizationService,
-		UserService:          b.UserService,
-		LookupService:        b.LookupService,
+		AuthorizationService:         b.AuthorizationService,
+		AuthorizationRotationService: b.AuthorizationRotationService,
+		OrganizationService:          b.OrganizationService,
+		UserService:                  b.UserService,
+		LookupService:                b.LookupService,
 	}
 
 	h.HandlerFunc("POST", "/api/v2/authorizations", h.handlePostAuthorization)
@@ -71,6 +77,7 @@ func NewAuthorizationHandler(b *AuthorizationBackend) *AuthorizationHandler {
 	h.HandlerFunc("GET", "/api/v2/authorizations/:id", h.handleGetAuthorization)
 	h.HandlerFunc("PATCH", "/api/v2/authorizations/:id", h.handleUpdateAuthorization)
 	h.HandlerFunc("DELETE", "/api/v2/authorizations/:id", h.handleDeleteAuthorization)
+	h.HandlerFunc("POST", "/api/v2/authorizations/:id/rotate", h.handleRotateAuthorization)
 	return h
 }
 
@@ -84,6 +91,7 @@ type authResponse struct {
 	UserID      platform.ID          `json:"userID"`
 	User        string               `json:"user"`
 	Permissions []permissionResponse `json:"permissions"`
+	ExpiresAt   *time.Time           `json:"expiresAt,omitempty"`
 	Links       map[string]string    `json:"links"`
 }
 
@@ -98,6 +106,7 @@ func newAuthResponse(a *platform.Authorization, org *platform.Organization, user
 		User:        user.Name,
 		Org:         org.Name,
 		Permissions: ps,
+		ExpiresAt:   a.ExpiresAt,
 		Links: map[string]string{
 			"self": fmt.Sprintf("/api/v2/authorizations/%s", a.ID),
 			"user": fmt.Sprintf("/api/v2/users/%s", a.UserID),
@@ -114,16 +123,18 @@ func (a *authResponse) toPlatform() *platform.Authorization {
 		Description: a.Description,
 		OrgID:       a.OrgID,
 		UserID:      a.UserID,
+		ExpiresAt:   a.ExpiresAt,
 	}
 	for _, p := range a.Permissions {
-		res.Permissions = append(res.Permissions, platform.Permission{Action: p.Action, Resource: p.Resource.Resource})
+		res.Permissions = append(res.Permissions, platform.Permission{Action: p.Action, Resource: p.Resource.Resource, Predicate: p.Predicate})
 	}
 	return res
 }
 
 type permissionResponse struct {
-	Action   platform.Action  `json:"action"`
-	Resource resourceResponse `json:"resource"`
+	Action    platform.Action  `json:"action"`
+	Resource  resourceResponse `json:"resource"`
+	Predicate string           `json:"predicate,omitempty"`
 }
 
 type resourceResponse struct {
@@ -140,6 +151,7 @@ func newPermissionsResponse(ctx context.Context, ps []platform.Permission, svc p
 			Resource: resourceResponse{
 				Resource: p.Resource,
 			},
+			Predicate: p.Predicate,
 		}
 
 		if p.Resource.ID != nil {
@@ -238,6 +250,7 @@ type postAuthorizationRequest struct {
 	UserID      *platform.ID          `json:"userID,omitempty"`
 	Description string                `json:"description"`
 	Permissions []platform.Permission `json:"permissions"`
+	ExpiresAt   *time.Time            `json:"expiresAt,omitempty"`
 }
 
 func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Authorization {
@@ -247,6 +260,7 @@ func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Auth
 		Description: p.Description,
 		Permissions: p.Permissions,
 		UserID:      userID,
+		ExpiresAt:   p.ExpiresAt,
 	}
 }
 
@@ -256,6 +270,7 @@ func newPostAuthorizationRequest(a *platform.Authorization) (*postAuthorizationR
 		Description: a.Description,
 		Permissions: a.Permissions,
 		Status:      a.Status,
+		ExpiresAt:   a.ExpiresAt,
 	}
 
 	if a.UserID.Valid() {
@@ -572,6 +587,114 @@ func decodeUpdateAuthorizationRequest(ctx context.Context, r *http.Request) (*up
 	}, nil
 }
 
+// handleRotateAuthorization is the HTTP handler for the POST /api/v2/authorizations/:id/rotate route.
+func (h *AuthorizationHandler) handleRotateAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	h.Logger.Debug("rotate auth request", zap.String("r", fmt.Sprint(r)))
+	req, err := decodeRotateAuthorizationRequest(ctx, r)
+	if err != nil {
+		h.Logger.Info("failed to decode request", zap.String("handler", "rotateAuthorization"), zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.AuthorizationRotationService == nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  "authorization rotation is not supported",
+		}, w)
+		return
+	}
+
+	a, err := h.AuthorizationRotationService.RotateAuthorization(ctx, req.ID, req.GracePeriod)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	o, err := h.OrganizationService.FindOrganizationByID(ctx, a.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	u, err := h.UserService.FindUserByID(ctx, a.UserID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ps, err := newPermissionsResponse(ctx, a.Permissions, h.LookupService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("auth rotated", zap.String("auth", fmt.Sprint(a)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newAuthResponse(a, o, u, ps)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// postRotateAuthorizationRequest is the body of a POST /api/v2/authorizations/:id/rotate request.
+type postRotateAuthorizationRequest struct {
+	// GracePeriod is how long the rotated token remains valid, as a duration such as "24h".
+	// The rotated token expires immediately if it is empty.
+	GracePeriod string `json:"gracePeriod,omitempty"`
+}
+
+type rotateAuthorizationRequest struct {
+	ID          platform.ID
+	GracePeriod time.Duration
+}
+
+func decodeRotateAuthorizationRequest(ctx context.Context, r *http.Request) (*rotateAuthorizationRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i platform.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	req := &postRotateAuthorizationRequest{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil && err != io.EOF {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "invalid json structure",
+				Err:  err,
+			}
+		}
+	}
+
+	var grace time.Duration
+	if req.GracePeriod != "" {
+		d, err := time.ParseDuration(req.GracePeriod)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "invalid grace period",
+				Err:  err,
+			}
+		}
+		grace = d
+	}
+
+	return &rotateAuthorizationRequest{
+		ID:          i,
+		GracePeriod: grace,
+	}, nil
+}
+
 // handleDeleteAuthorization is the HTTP handler for the DELETE /api/v2/authorizations/:id route.
 func (h *AuthorizationHandler) handleDeleteAuthorization(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -638,7 +761,10 @@ type AuthorizationService struct {
 	InsecureSkipVerify bool
 }
 
-var _ platform.AuthorizationService = (*AuthorizationService)(nil)
+var (
+	_ platform.AuthorizationService         = (*AuthorizationService)(nil)
+	_ platform.AuthorizationRotationService = (*AuthorizationService)(nil)
+)
 
 // FindAuthorizationByID finds the authorization against a remote influx server.
 func (s *AuthorizationService) FindAuthorizationByID(ctx context.Context, id platform.ID) (*platform.Authorization, error) {
@@ -853,6 +979,47 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platf
 	return CheckError(resp)
 }
 
+// RotateAuthorization creates a new authorization with the permissions of the authorization id,
+// and makes the authorization id expire after gracePeriod.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, error) {
+	u, err := NewURL(s.Addr, path.Join(authorizationIDPath(id), "rotate"))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(postRotateAuthorizationRequest{GracePeriod: gracePeriod.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var res authResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+
+	return res.toPlatform(), nil
+}
+
 func authorizationIDPath(id platform.ID) string {
 	return path.Join(authorizationPath, id.String())
 }
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"time"
@@ -21,6 +22,13 @@ type AuthenticationHandler struct {
 	SessionService       platform.SessionService
 	SessionRenewDisabled bool
 
+	// GroupPermissionService, when set, grants token and certificate authorizations
+	// the permissions of the groups their user belongs to in their org.
+	GroupPermissionService platform.GroupPermissionService
+
+	// TimeGenerator is the time against which the expiration of authorizations is checked.
+	platform.TimeGenerator
+
 	// This is only really used for it's lookup method the specific http
 	// handler used to register routes does not matter.
 	noAuthRouter *httprouter.Router
@@ -33,6 +41,7 @@ func NewAuthenticationHandler(h platform.HTTPErrorHandler) *AuthenticationHandle
 	return &AuthenticationHandler{
 		Logger:           zap.NewNop(),
 		HTTPErrorHandler: h,
+		TimeGenerator:    platform.RealTimeGenerator{},
 		Handler:          http.DefaultServeMux,
 		noAuthRouter:     httprouter.New(),
 	}
@@ -45,16 +54,21 @@ func (h *AuthenticationHandler) RegisterNoAuthRoute(method, path string) {
 }
 
 const (
-	tokenAuthScheme   = "token"
-	sessionAuthScheme = "session"
+	tokenAuthScheme       = "token"
+	sessionAuthScheme     = "session"
+	certificateAuthScheme = "certificate"
 )
 
-// ProbeAuthScheme probes the http request for the requests for token or cookie session.
+// ProbeAuthScheme probes the http request for the requests for token or cookie session,
+// or else for a verified client certificate.
 func ProbeAuthScheme(r *http.Request) (string, error) {
 	_, tokenErr := GetToken(r)
 	_, sessErr := decodeCookieSession(r.Context(), r)
 
 	if tokenErr != nil && sessErr != nil {
+		if _, err := verifiedClientCertificate(r); err == nil {
+			return certificateAuthScheme, nil
+		}
 		return "", fmt.Errorf("token required")
 	}
 
@@ -96,6 +110,14 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		r = r.WithContext(ctx)
 		h.Handler.ServeHTTP(w, r)
 		return
+	case certificateAuthScheme:
+		ctx, err = h.extractCertificateAuthorization(ctx, r)
+		if err != nil {
+			break
+		}
+		r = r.WithContext(ctx)
+		h.Handler.ServeHTTP(w, r)
+		return
 	}
 
 	UnauthorizedError(ctx, h, w)
@@ -112,6 +134,15 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 		return ctx, err
 	}
 
+	if err := a.Expired(h.Now()); err != nil {
+		return ctx, err
+	}
+
+	a, err = h.withGroupPermissions(ctx, a)
+	if err != nil {
+		return ctx, err
+	}
+
 	return platcontext.SetAuthorizer(ctx, a), nil
 }
 
@@ -136,3 +167,64 @@ func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Requ
 
 	return platcontext.SetAuthorizer(ctx, s), nil
 }
+
+// extractCertificateAuthorization authenticates requests with a verified client certificate.
+// The common name of the certificate is the ID of the authorization it is mapped to.
+func (h *AuthenticationHandler) extractCertificateAuthorization(ctx context.Context, r *http.Request) (context.Context, error) {
+	cert, err := verifiedClientCertificate(r)
+	if err != nil {
+		return ctx, err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(cert.Subject.CommonName); err != nil {
+		return ctx, err
+	}
+
+	a, err := h.AuthorizationService.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return ctx, err
+	}
+
+	if err := a.Expired(h.Now()); err != nil {
+		return ctx, err
+	}
+
+	a, err = h.withGroupPermissions(ctx, a)
+	if err != nil {
+		return ctx, err
+	}
+
+	return platcontext.SetAuthorizer(ctx, a), nil
+}
+
+// withGroupPermissions returns a copy of the authorization that is also granted
+// the permissions of the groups its user belongs to in the org of the authorization.
+func (h *AuthenticationHandler) withGroupPermissions(ctx context.Context, a *platform.Authorization) (*platform.Authorization, error) {
+	if h.GroupPermissionService == nil || !a.UserID.Valid() {
+		return a, nil
+	}
+
+	ps, err := h.GroupPermissionService.FindGroupPermissions(ctx, a.UserID, a.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ps) == 0 {
+		return a, nil
+	}
+
+	ga := *a
+	ga.Permissions = make([]platform.Permission, 0, len(a.Permissions)+len(ps))
+	ga.Permissions = append(ga.Permissions, a.Permissions...)
+	ga.Permissions = append(ga.Permissions, ps...)
+	return &ga, nil
+}
+
+// verifiedClientCertificate returns the client certificate of the request, if it was verified.
+// Client certificates are only verified when the server is configured with client certificate authorities.
+func verifiedClientCertificate(r *http.Request) (*x509.Certificate, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, fmt.Errorf("no verified client certificate")
+	}
+	return r.TLS.VerifiedChains[0][0], nil
+}
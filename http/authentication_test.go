@@ -2,6 +2,9 @@ package http_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +12,8 @@ import (
 	"time"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	platcontext "github.com/influxdata/influxdb/context"
 	platformhttp "github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/mock"
 )
@@ -295,3 +300,129 @@ func TestAuthenticationHandler_NoAuthRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthenticationHandler_GroupPermissions(t *testing.T) {
+	userID := platform.ID(1)
+	orgID := platform.ID(2)
+	bucketID := platform.ID(3)
+	readBucket := platform.Permission{
+		Action:   platform.ReadAction,
+		Resource: platform.Resource{Type: platform.BucketsResourceType, ID: &bucketID},
+	}
+
+	var allowed bool
+	h := platformhttp.NewAuthenticationHandler(platformhttp.ErrorHandler(0))
+	h.AuthorizationService = &mock.AuthorizationService{
+		FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+			return &platform.Authorization{UserID: userID, OrgID: orgID, Status: platform.Active}, nil
+		},
+	}
+	h.SessionService = mock.NewSessionService()
+	h.GroupPermissionService = &mock.GroupPermissionService{
+		FindGroupPermissionsFn: func(ctx context.Context, uid, oid platform.ID) ([]platform.Permission, error) {
+			if uid != userID || oid != orgID {
+				return nil, nil
+			}
+			return []platform.Permission{readBucket}, nil
+		},
+	}
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed = authorizer.IsAllowed(r.Context(), readBucket) == nil
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://any.url", nil)
+	platformhttp.SetToken("abc123", r)
+	h.ServeHTTP(w, r)
+
+	if !allowed {
+		t.Errorf("token should be allowed %s through the groups of its user", readBucket)
+	}
+}
+
+func TestAuthenticationHandler_Certificate(t *testing.T) {
+	authID := platform.ID(1)
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Second)
+	verified := func(commonName string) *tls.ConnectionState {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		tls       *tls.ConnectionState
+		expiresAt *time.Time
+		wantCode  int
+	}{
+		{
+			name:     "verified certificate of an authorization",
+			tls:      verified(authID.String()),
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "unverified certificate",
+			tls: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: authID.String()}}},
+			},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "common name is not an authorization ID",
+			tls:      verified("127.0.0.1"),
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "unknown authorization",
+			tls:      verified(platform.ID(2).String()),
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:      "authorization expiring now",
+			tls:       verified(authID.String()),
+			expiresAt: &now,
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "expired authorization",
+			tls:       verified(authID.String()),
+			expiresAt: &expired,
+			wantCode:  http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var auth platform.Authorizer
+			h := platformhttp.NewAuthenticationHandler(platformhttp.ErrorHandler(0))
+			h.AuthorizationService = &mock.AuthorizationService{
+				FindAuthorizationByIDFn: func(ctx context.Context, id platform.ID) (*platform.Authorization, error) {
+					if id != authID {
+						return nil, &platform.Error{Code: platform.ENotFound, Msg: "authorization not found"}
+					}
+					return &platform.Authorization{ID: authID, Status: platform.Active, ExpiresAt: tt.expiresAt}, nil
+				},
+			}
+			h.SessionService = mock.NewSessionService()
+			h.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+			h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth, _ = platcontext.GetAuthorizer(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "https://any.url", nil)
+			r.TLS = tt.tls
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("unexpected status code: got %d, want %d", w.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK && (auth == nil || auth.Identifier() != authID) {
+				t.Errorf("expected the request to be authorized by %s, got %v", authID, auth)
+			}
+		})
+	}
+}
@@ -27,6 +27,7 @@ type BucketBackend struct {
 	UserResourceMappingService influxdb.UserResourceMappingService
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
+	GroupService               influxdb.GroupService
 	OrganizationService        influxdb.OrganizationService
 }
 
@@ -41,6 +42,7 @@ func NewBucketBackend(b *APIBackend) *BucketBackend {
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
+		GroupService:               b.GroupService,
 		OrganizationService:        b.OrganizationService,
 	}
 }
@@ -100,6 +102,7 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 		UserType:                   influxdb.Member,
 		UserResourceMappingService: b.UserResourceMappingService,
 		UserService:                b.UserService,
+		GroupService:               b.GroupService,
 	}
 	h.HandlerFunc("POST", bucketsIDMembersPath, newPostMemberHandler(memberBackend))
 	h.HandlerFunc("GET", bucketsIDMembersPath, newGetMembersHandler(memberBackend))
@@ -112,6 +115,7 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 		UserType:                   influxdb.Owner,
 		UserResourceMappingService: b.UserResourceMappingService,
 		UserService:                b.UserService,
+		GroupService:               b.GroupService,
 	}
 	h.HandlerFunc("POST", bucketsIDOwnersPath, newPostMemberHandler(ownerBackend))
 	h.HandlerFunc("GET", bucketsIDOwnersPath, newGetMembersHandler(ownerBackend))
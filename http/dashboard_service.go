@@ -24,6 +24,7 @@ type DashboardBackend struct {
 	UserResourceMappingService   platform.UserResourceMappingService
 	LabelService                 platform.LabelService
 	UserService                  platform.UserService
+	GroupService                 platform.GroupService
 }
 
 // NewDashboardBackend creates a backend used by the dashboard handler.
@@ -37,6 +38,7 @@ func NewDashboardBackend(b *APIBackend) *DashboardBackend {
 		UserResourceMappingService:   b.UserResourceMappingService,
 		LabelService:                 b.LabelService,
 		UserService:                  b.UserService,
+		GroupService:                 b.GroupService,
 	}
 }
 
@@ -105,6 +107,7 @@ func NewDashboardHandler(b *DashboardBackend) *DashboardHandler {
 		UserType:                   platform.Member,
 		UserResourceMappingService: b.UserResourceMappingService,
 		UserService:                b.UserService,
+		GroupService:               b.GroupService,
 	}
 	h.HandlerFunc("POST", dashboardsIDMembersPath, newPostMemberHandler(memberBackend))
 	h.HandlerFunc("GET", dashboardsIDMembersPath, newGetMembersHandler(memberBackend))
@@ -117,6 +120,7 @@ func NewDashboardHandler(b *DashboardBackend) *DashboardHandler {
 		UserType:                   platform.Owner,
 		UserResourceMappingService: b.UserResourceMappingService,
 		UserService:                b.UserService,
+		GroupService:               b.GroupService,
 	}
 	h.HandlerFunc("POST", dashboardsIDOwnersPath, newPostMemberHandler(ownerBackend))
 	h.HandlerFunc("GET", dashboardsIDOwnersPath, newGetMembersHandler(ownerBackend))
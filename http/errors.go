@@ -151,6 +151,7 @@ var statusCodePlatformError = map[string]int{
 	platform.EUnavailable:         http.StatusServiceUnavailable,
 	platform.EForbidden:           http.StatusForbidden,
 	platform.ETooManyRequests:     http.StatusTooManyRequests,
+	platform.ETooLarge:            http.StatusRequestEntityTooLarge,
 	platform.EUnauthorized:        http.StatusUnauthorized,
 	platform.EMethodNotAllowed:    http.StatusMethodNotAllowed,
 }
@@ -0,0 +1,487 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// GroupBackend is all services and associated parameters required to construct
+// the GroupHandler.
+type GroupBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	GroupService               influxdb.GroupService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	UserService                influxdb.UserService
+}
+
+// NewGroupBackend returns a new instance of GroupBackend.
+func NewGroupBackend(b *APIBackend) *GroupBackend {
+	return &GroupBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "group")),
+
+		GroupService:               b.GroupService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+}
+
+// GroupHandler represents an HTTP API handler for groups and their users.
+type GroupHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	GroupService influxdb.GroupService
+}
+
+const (
+	groupsPath            = "/api/v2/groups"
+	groupsIDPath          = "/api/v2/groups/:id"
+	groupsIDMembersPath   = "/api/v2/groups/:id/members"
+	groupsIDMembersIDPath = "/api/v2/groups/:id/members/:userID"
+	groupsIDOwnersPath    = "/api/v2/groups/:id/owners"
+	groupsIDOwnersIDPath  = "/api/v2/groups/:id/owners/:userID"
+)
+
+// NewGroupHandler returns a new instance of GroupHandler.
+func NewGroupHandler(b *GroupBackend) *GroupHandler {
+	h := &GroupHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		GroupService: b.GroupService,
+	}
+
+	h.HandlerFunc("POST", groupsPath, h.handlePostGroup)
+	h.HandlerFunc("GET", groupsPath, h.handleGetGroups)
+	h.HandlerFunc("GET", groupsIDPath, h.handleGetGroup)
+	h.HandlerFunc("PATCH", groupsIDPath, h.handlePatchGroup)
+	h.HandlerFunc("DELETE", groupsIDPath, h.handleDeleteGroup)
+
+	memberBackend := MemberBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		Logger:                     b.Logger.With(zap.String("handler", "member")),
+		ResourceType:               influxdb.GroupsResourceType,
+		UserType:                   influxdb.Member,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+	h.HandlerFunc("POST", groupsIDMembersPath, newPostMemberHandler(memberBackend))
+	h.HandlerFunc("GET", groupsIDMembersPath, newGetMembersHandler(memberBackend))
+	h.HandlerFunc("DELETE", groupsIDMembersIDPath, newDeleteMemberHandler(memberBackend))
+
+	ownerBackend := MemberBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		Logger:                     b.Logger.With(zap.String("handler", "member")),
+		ResourceType:               influxdb.GroupsResourceType,
+		UserType:                   influxdb.Owner,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+	h.HandlerFunc("POST", groupsIDOwnersPath, newPostMemberHandler(ownerBackend))
+	h.HandlerFunc("GET", groupsIDOwnersPath, newGetMembersHandler(ownerBackend))
+	h.HandlerFunc("DELETE", groupsIDOwnersIDPath, newDeleteMemberHandler(ownerBackend))
+
+	return h
+}
+
+type groupResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Group
+}
+
+func newGroupResponse(g *influxdb.Group) *groupResponse {
+	return &groupResponse{
+		Links: map[string]string{
+			"self":    fmt.Sprintf("/api/v2/groups/%s", g.ID),
+			"members": fmt.Sprintf("/api/v2/groups/%s/members", g.ID),
+			"owners":  fmt.Sprintf("/api/v2/groups/%s/owners", g.ID),
+			"org":     fmt.Sprintf("/api/v2/orgs/%s", g.OrgID),
+		},
+		Group: *g,
+	}
+}
+
+type groupsResponse struct {
+	Links  map[string]string `json:"links"`
+	Groups []*groupResponse  `json:"groups"`
+}
+
+func newGroupsResponse(gs []*influxdb.Group) *groupsResponse {
+	res := &groupsResponse{
+		Links: map[string]string{
+			"self": groupsPath,
+		},
+		Groups: make([]*groupResponse, 0, len(gs)),
+	}
+	for _, g := range gs {
+		res.Groups = append(res.Groups, newGroupResponse(g))
+	}
+	return res
+}
+
+// handlePostGroup is the HTTP handler for the POST /api/v2/groups route.
+func (h *GroupHandler) handlePostGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	group := &influxdb.Group{}
+	if err := json.NewDecoder(r.Body).Decode(group); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode group request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := group.Validate(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.GroupService.CreateGroup(ctx, group); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("group created", zap.String("group", fmt.Sprint(group)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newGroupResponse(group)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetGroups is the HTTP handler for the GET /api/v2/groups route.
+func (h *GroupHandler) handleGetGroups(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeGroupFilter(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	gs, _, err := h.GroupService.FindGroups(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGroupsResponse(gs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeGroupFilter(r *http.Request) (influxdb.GroupFilter, error) {
+	qp := r.URL.Query()
+	var filter influxdb.GroupFilter
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = id
+	}
+
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	return filter, nil
+}
+
+// decodeGroupID returns the ID of the group in the path of the request.
+func decodeGroupID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+// handleGetGroup is the HTTP handler for the GET /api/v2/groups/:id route.
+func (h *GroupHandler) handleGetGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeGroupID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	group, err := h.GroupService.FindGroupByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGroupResponse(group)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchGroup is the HTTP handler for the PATCH /api/v2/groups/:id route.
+func (h *GroupHandler) handlePatchGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeGroupID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.GroupUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode group update",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	group, err := h.GroupService.UpdateGroup(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("group updated", zap.String("group", fmt.Sprint(group)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGroupResponse(group)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteGroup is the HTTP handler for the DELETE /api/v2/groups/:id route.
+func (h *GroupHandler) handleDeleteGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeGroupID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.GroupService.DeleteGroup(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("group deleted", zap.String("groupID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GroupService connects to Influx via HTTP using tokens to manage groups.
+type GroupService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.GroupService = (*GroupService)(nil)
+
+func groupIDPath(id influxdb.ID) string {
+	return path.Join(groupsPath, id.String())
+}
+
+// FindGroupByID returns a single group by ID.
+func (s *GroupService) FindGroupByID(ctx context.Context, id influxdb.ID) (*influxdb.Group, error) {
+	u, err := NewURL(s.Addr, groupIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var gr groupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
+		return nil, err
+	}
+	return &gr.Group, nil
+}
+
+// FindGroups returns a list of groups that match filter and the total count of matching groups.
+func (s *GroupService) FindGroups(ctx context.Context, filter influxdb.GroupFilter, opt ...influxdb.FindOptions) ([]*influxdb.Group, int, error) {
+	u, err := NewURL(s.Addr, groupsPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := u.Query()
+	if filter.OrgID != nil {
+		query.Add("orgID", filter.OrgID.String())
+	}
+	if filter.Name != nil {
+		query.Add("name", *filter.Name)
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, 0, err
+	}
+
+	var gr groupsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
+		return nil, 0, err
+	}
+
+	gs := make([]*influxdb.Group, 0, len(gr.Groups))
+	for _, g := range gr.Groups {
+		if filter.ID != nil && *filter.ID != g.ID {
+			continue
+		}
+		gs = append(gs, &g.Group)
+	}
+	return gs, len(gs), nil
+}
+
+// CreateGroup creates a new group and sets g.ID with the new identifier.
+func (s *GroupService) CreateGroup(ctx context.Context, g *influxdb.Group) error {
+	u, err := NewURL(s.Addr, groupsPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	var gr groupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
+		return err
+	}
+	*g = gr.Group
+	return nil
+}
+
+// UpdateGroup updates a group with a changeset.
+func (s *GroupService) UpdateGroup(ctx context.Context, id influxdb.ID, upd influxdb.GroupUpdate) (*influxdb.Group, error) {
+	u, err := NewURL(s.Addr, groupIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(upd)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var gr groupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
+		return nil, err
+	}
+	return &gr.Group, nil
+}
+
+// DeleteGroup deletes a group along with its users and the resources it is mapped to.
+func (s *GroupService) DeleteGroup(ctx context.Context, id influxdb.ID) error {
+	u, err := NewURL(s.Addr, groupIDPath(id))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap"
+)
+
+func TestGroupService(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &influxdb.Bucket{OrgID: org.ID, Name: "bucket"}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	groupServer := httptest.NewServer(NewGroupHandler(&GroupBackend{
+		HTTPErrorHandler:           ErrorHandler(0),
+		Logger:                     zap.NewNop(),
+		GroupService:               svc,
+		UserResourceMappingService: svc,
+		UserService:                svc,
+	}))
+	defer groupServer.Close()
+	client := &GroupService{Addr: groupServer.URL}
+
+	group := &influxdb.Group{OrgID: org.ID, Name: "engineers"}
+	if err := client.CreateGroup(ctx, group); err != nil {
+		t.Fatal(err)
+	}
+	if !group.ID.Valid() {
+		t.Fatal("expected the group to have an ID")
+	}
+
+	desc := "all of engineering"
+	if _, err := client.UpdateGroup(ctx, group.ID, influxdb.GroupUpdate{Description: &desc}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.FindGroupByID(ctx, group.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Description != desc || got.OrgID != org.ID {
+		t.Fatalf("unexpected group %+v", got)
+	}
+
+	gs, n, err := client.FindGroups(ctx, influxdb.GroupFilter{OrgID: &org.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || gs[0].ID != group.ID {
+		t.Fatalf("unexpected groups %+v", gs)
+	}
+
+	// Users are added to a group as its members.
+	members := &UserResourceMappingService{Addr: groupServer.URL, BasePath: groupsPath}
+	if err := members.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		UserID:       user.ID,
+		UserType:     influxdb.Member,
+		ResourceType: influxdb.GroupsResourceType,
+		ResourceID:   group.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ms, _, err := members.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{ResourceID: group.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 1 || ms[0].UserID != user.ID || ms[0].UserType != influxdb.Member {
+		t.Fatalf("unexpected members %+v", ms)
+	}
+
+	// Groups cannot be members of groups.
+	err = members.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		UserID:       group.ID,
+		UserType:     influxdb.Member,
+		MappingType:  influxdb.GroupMappingType,
+		ResourceType: influxdb.GroupsResourceType,
+		ResourceID:   group.ID,
+	})
+	if influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected an invalid mapping, got %v", err)
+	}
+
+	// Groups are added to the members of resources.
+	bucketBackend := NewMockBucketBackend()
+	bucketBackend.HTTPErrorHandler = ErrorHandler(0)
+	bucketBackend.BucketService = svc
+	bucketBackend.UserResourceMappingService = svc
+	bucketBackend.UserService = svc
+	bucketBackend.GroupService = svc
+	bucketServer := httptest.NewServer(NewBucketHandler(bucketBackend))
+	defer bucketServer.Close()
+
+	bucketMembers := &UserResourceMappingService{Addr: bucketServer.URL, BasePath: bucketsPath}
+	if err := bucketMembers.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		UserID:       group.ID,
+		UserType:     influxdb.Owner,
+		MappingType:  influxdb.GroupMappingType,
+		ResourceType: influxdb.BucketsResourceType,
+		ResourceID:   bucket.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ms, _, err = bucketMembers.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{ResourceID: bucket.ID, UserType: influxdb.Owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 1 || ms[0].UserID != group.ID || ms[0].MappingType != influxdb.GroupMappingType {
+		t.Fatalf("unexpected owners %+v", ms)
+	}
+
+	if err := members.DeleteUserResourceMapping(ctx, group.ID, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteGroup(ctx, group.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.FindGroupByID(ctx, group.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected the group to be deleted, got %v", err)
+	}
+}
@@ -27,6 +27,7 @@ type OrgBackend struct {
 	SecretService                   influxdb.SecretService
 	LabelService                    influxdb.LabelService
 	UserService                     influxdb.UserService
+	GroupService                    influxdb.GroupService
 }
 
 // NewOrgBackend is a datasource used by the org handler.
@@ -41,6 +42,7 @@ func NewOrgBackend(b *APIBackend) *OrgBackend {
 		SecretService:                   b.SecretService,
 		LabelService:                    b.LabelService,
 		UserService:                     b.UserService,
+		GroupService:                    b.GroupService,
 	}
 }
 
@@ -102,6 +104,7 @@ func NewOrgHandler(b *OrgBackend) *OrgHandler {
 		UserType:                   influxdb.Member,
 		UserResourceMappingService: b.UserResourceMappingService,
 		UserService:                b.UserService,
+		GroupService:               b.GroupService,
 	}
 	h.HandlerFunc("POST", organizationsIDMembersPath, newPostMemberHandler(memberBackend))
 	h.HandlerFunc("GET", organizationsIDMembersPath, newGetMembersHandler(memberBackend))
@@ -114,6 +117,7 @@ func NewOrgHandler(b *OrgBackend) *OrgHandler {
 		UserType:                   influxdb.Owner,
 		UserResourceMappingService: b.UserResourceMappingService,
 		UserService:                b.UserService,
+		GroupService:               b.GroupService,
 	}
 	h.HandlerFunc("POST", organizationsIDOwnersPath, newPostMemberHandler(ownerBackend))
 	h.HandlerFunc("GET", organizationsIDOwnersPath, newGetMembersHandler(ownerBackend))
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 )
 
 // PlatformHandler is a collection of all the service handlers.
@@ -26,13 +27,20 @@ func setCORSResponseHeaders(w http.ResponseWriter, r *http.Request) {
 func NewPlatformHandler(b *APIBackend) *PlatformHandler {
 	h := NewAuthenticationHandler(b.HTTPErrorHandler)
 	h.Handler = NewAPIHandler(b)
+	if b.AuditLogService != nil {
+		h.Handler = NewAuditRecorder(h.Handler, b.AuditLogService, b.Logger.With(zap.String("service", "audit")))
+	}
 	h.AuthorizationService = b.AuthorizationService
 	h.SessionService = b.SessionService
+	h.GroupPermissionService = b.GroupPermissionService
 	h.SessionRenewDisabled = b.SessionRenewDisabled
 
 	h.RegisterNoAuthRoute("GET", "/api/v2")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signin")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signout")
+	h.RegisterNoAuthRoute("GET", oauth2SigninPath)
+	h.RegisterNoAuthRoute("GET", oauth2ProviderPath)
+	h.RegisterNoAuthRoute("GET", oauth2CallbackPath)
 	h.RegisterNoAuthRoute("POST", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/swagger.json")
@@ -62,6 +70,7 @@ func (h *PlatformHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Serve the chronograf assets for any basepath that does not start with addressable parts
 	// of the platform API.
 	if !strings.HasPrefix(r.URL.Path, "/v1") &&
+		!strings.HasPrefix(r.URL.Path, "/api/v1") &&
 		!strings.HasPrefix(r.URL.Path, "/api/v2") &&
 		!strings.HasPrefix(r.URL.Path, "/chronograf/") {
 		h.AssetHandler.ServeHTTP(w, r)
@@ -0,0 +1,324 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/prometheus"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	prometheusWritePath = "/api/v2/prometheus/write"
+	prometheusReadPath  = "/api/v2/prometheus/read"
+
+	// maxPrometheusRemoteRequestBytes is the maximum size of both the compressed
+	// and the decoded body of a remote write or remote read request.
+	maxPrometheusRemoteRequestBytes = 32 << 20
+)
+
+// PrometheusRemoteBackend is all services and associated parameters required to construct
+// the PrometheusRemoteHandler.
+type PrometheusRemoteBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	PointsWriter        storage.PointsWriter
+	ReadStore           reads.Store
+	BucketService       platform.BucketService
+	OrganizationService platform.OrganizationService
+}
+
+// NewPrometheusRemoteBackend returns a new instance of PrometheusRemoteBackend.
+func NewPrometheusRemoteBackend(b *APIBackend) *PrometheusRemoteBackend {
+	return &PrometheusRemoteBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "prometheus_remote")),
+
+		PointsWriter:        b.PointsWriter,
+		ReadStore:           b.ReadStore,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// PrometheusRemoteHandler implements the remote write and remote read protocols of Prometheus
+// against the bucket named by the bucket parameter of the organization named by the org or
+// orgID parameters.
+type PrometheusRemoteHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	PointsWriter        storage.PointsWriter
+	ReadStore           reads.Store
+	BucketService       platform.BucketService
+	OrganizationService platform.OrganizationService
+}
+
+// NewPrometheusRemoteHandler returns a new handler at /api/v2/prometheus for Prometheus remote storage.
+func NewPrometheusRemoteHandler(b *PrometheusRemoteBackend) *PrometheusRemoteHandler {
+	h := &PrometheusRemoteHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		PointsWriter:        b.PointsWriter,
+		ReadStore:           b.ReadStore,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("POST", prometheusWritePath, h.handleRemoteWrite)
+	h.HandlerFunc("POST", prometheusReadPath, h.handleRemoteRead)
+	return h
+}
+
+func (h *PrometheusRemoteHandler) handleRemoteWrite(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PrometheusRemoteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	org, bucket, err := h.authorizeBucket(ctx, r, platform.WriteAction)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req := &prometheus.WriteRequest{}
+	if err := decodeSnappyProto(w, r, req); err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: decodeErrorCode(err),
+			Op:   "http/handleRemoteWrite",
+			Msg:  "unable to decode remote write request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	data, err := prometheus.EncodeRemoteWrite(req)
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handleRemoteWrite",
+			Msg:  fmt.Sprintf("unable to convert series: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	encoded := tsdb.EncodeName(org.ID, bucket.ID)
+	mm := models.EscapeMeasurement(encoded[:])
+	points, err := models.ParsePointsWithPrecision(data, mm, time.Now(), "ns")
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handleRemoteWrite",
+			Msg:  fmt.Sprintf("unable to parse points: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+		h.Logger.Error("Error writing points", zap.Error(err))
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Op:   "http/handleRemoteWrite",
+			Msg:  fmt.Sprintf("unable to write points to database: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PrometheusRemoteHandler) handleRemoteRead(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PrometheusRemoteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	org, bucket, err := h.authorizeBucket(ctx, r, platform.ReadAction)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req := &prometheus.ReadRequest{}
+	if err := decodeSnappyProto(w, r, req); err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: decodeErrorCode(err),
+			Op:   "http/handleRemoteRead",
+			Msg:  "unable to decode remote read request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	// The series are restricted to the predicates of the permissions of the authorizer.
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	auth, err := queryAuthorization(a, org.ID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	ctx = query.ContextWithRequest(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: org.ID,
+	})
+
+	reader := &prometheus.RemoteReader{Store: h.ReadStore}
+	resp, err := reader.Read(ctx, uint64(org.ID), uint64(bucket.ID), req)
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Op:   "http/handleRemoteRead",
+			Msg:  fmt.Sprintf("unable to read series: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Op:   "http/handleRemoteRead",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Header().Set("Content-Encoding", "snappy")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(snappy.Encode(nil, data)); err != nil {
+		logEncodingError(h.Logger, r, err)
+	}
+}
+
+// authorizeBucket finds the bucket of the request and checks that the authorizer
+// of the request is allowed the action on it.
+func (h *PrometheusRemoteHandler) authorizeBucket(ctx context.Context, r *http.Request, action platform.Action) (*platform.Organization, *platform.Bucket, error) {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	org, err := queryOrganization(ctx, r, h.OrganizationService)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bucket, err := findBucketByIDOrName(ctx, h.BucketService, org.ID, r.URL.Query().Get("bucket"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p, err := platform.NewPermissionAtID(bucket.ID, action, platform.BucketsResourceType, org.ID)
+	if err != nil {
+		return nil, nil, &platform.Error{
+			Code: platform.EInternal,
+			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+			Err:  err,
+		}
+	}
+
+	if !a.Allowed(*p) {
+		return nil, nil, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  fmt.Sprintf("insufficient permissions for %s", action),
+		}
+	}
+	return org, bucket, nil
+}
+
+// findBucketByIDOrName finds the bucket of the organization identified by s,
+// which is either the ID or the name of the bucket.
+func findBucketByIDOrName(ctx context.Context, svc platform.BucketService, orgID platform.ID, s string) (*platform.Bucket, error) {
+	if id, err := platform.IDFromString(s); err == nil {
+		b, err := svc.FindBucket(ctx, platform.BucketFilter{
+			OrganizationID: &orgID,
+			ID:             id,
+		})
+		if err == nil {
+			return b, nil
+		} else if platform.ErrorCode(err) != platform.ENotFound {
+			return nil, err
+		}
+	}
+
+	return svc.FindBucket(ctx, platform.BucketFilter{
+		OrganizationID: &orgID,
+		Name:           &s,
+	})
+}
+
+// errPrometheusRemoteRequestTooLarge is returned when the body of a remote request
+// exceeds maxPrometheusRemoteRequestBytes, either compressed or decoded.
+var errPrometheusRemoteRequestTooLarge = &platform.Error{
+	Code: platform.ETooLarge,
+	Msg:  fmt.Sprintf("request exceeds the maximum size of %d bytes", maxPrometheusRemoteRequestBytes),
+}
+
+// decodeSnappyProto decodes the snappy compressed protocol buffer body of r into pb.
+func decodeSnappyProto(w http.ResponseWriter, r *http.Request, pb proto.Message) error {
+	if r.ContentLength > maxPrometheusRemoteRequestBytes {
+		return errPrometheusRemoteRequestTooLarge
+	}
+
+	compressed, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPrometheusRemoteRequestBytes))
+	if err != nil {
+		// The reader fails once the limit is read, so a body that reached it was too large.
+		if len(compressed) >= maxPrometheusRemoteRequestBytes {
+			return errPrometheusRemoteRequestTooLarge
+		}
+		return err
+	}
+
+	n, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return err
+	}
+	if n > maxPrometheusRemoteRequestBytes {
+		return errPrometheusRemoteRequestTooLarge
+	}
+
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, pb)
+}
+
+// decodeErrorCode returns the error code of a request that failed to decode.
+// Requests are invalid unless they are too large.
+func decodeErrorCode(err error) string {
+	if platform.ErrorCode(err) == platform.ETooLarge {
+		return platform.ETooLarge
+	}
+	return platform.EInvalid
+}
@@ -0,0 +1,233 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	gogoproto "github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	platform "github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/prometheus"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"go.uber.org/zap/zaptest"
+)
+
+type remoteReadStore struct {
+	reads.Store
+	req *datatypes.ReadFilterRequest
+}
+
+func (s *remoteReadStore) ReadFilter(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+	s.req = req
+	return nil, nil
+}
+
+func (s *remoteReadStore) GetSource(orgID, bucketID uint64) gogoproto.Message {
+	return &types.StringValue{}
+}
+
+func TestPrometheusRemoteHandler(t *testing.T) {
+	i := inmem.NewService()
+	ctx := context.Background()
+	org := &platform.Organization{Name: "org"}
+	if err := i.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &platform.Bucket{Name: "prometheus", OrgID: org.ID}
+	if err := i.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	pw := &mock.PointsWriter{}
+	store := &remoteReadStore{}
+	h := NewPrometheusRemoteHandler(&PrometheusRemoteBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zaptest.NewLogger(t),
+		PointsWriter:        pw,
+		ReadStore:           store,
+		BucketService:       i,
+		OrganizationService: i,
+	})
+
+	permission := func(action platform.Action) []platform.Permission {
+		p, err := platform.NewPermissionAtID(bucket.ID, action, platform.BucketsResourceType, org.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []platform.Permission{*p}
+	}
+	encode := func(pb proto.Message) []byte {
+		data, err := proto.Marshal(pb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return snappy.Encode(nil, data)
+	}
+
+	write := encode(&prometheus.WriteRequest{
+		Timeseries: []*prometheus.TimeSeries{
+			{
+				Labels:  []*prometheus.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "node"}},
+				Samples: []*prometheus.Sample{{Value: 1, Timestamp: 1500000000000}},
+			},
+		},
+	})
+	read := encode(&prometheus.ReadRequest{
+		Queries: []*prometheus.Query{
+			{
+				StartTimestampMs: 1500000000000,
+				EndTimestampMs:   1500000060000,
+				Matchers:         []*prometheus.LabelMatcher{{Type: prometheus.MatchEqual, Name: "__name__", Value: "up"}},
+			},
+		},
+	})
+
+	// The snappy block format starts with the decoded length of the block.
+	inflated := make([]byte, binary.MaxVarintLen64)
+	inflated = inflated[:binary.PutUvarint(inflated, maxPrometheusRemoteRequestBytes+1)]
+
+	tests := []struct {
+		name        string
+		path        string
+		params      url.Values
+		body        []byte
+		permissions []platform.Permission
+		wantCode    int
+	}{
+		{
+			name:        "remote write",
+			path:        prometheusWritePath,
+			params:      url.Values{"org": {"org"}, "bucket": {"prometheus"}},
+			body:        write,
+			permissions: permission(platform.WriteAction),
+			wantCode:    http.StatusNoContent,
+		},
+		{
+			name:        "remote write to a bucket by id",
+			path:        prometheusWritePath,
+			params:      url.Values{"orgID": {org.ID.String()}, "bucket": {bucket.ID.String()}},
+			body:        write,
+			permissions: permission(platform.WriteAction),
+			wantCode:    http.StatusNoContent,
+		},
+		{
+			name:        "remote write requires write permission",
+			path:        prometheusWritePath,
+			params:      url.Values{"org": {"org"}, "bucket": {"prometheus"}},
+			body:        write,
+			permissions: permission(platform.ReadAction),
+			wantCode:    http.StatusForbidden,
+		},
+		{
+			name:        "remote write of an uncompressed body",
+			path:        prometheusWritePath,
+			params:      url.Values{"org": {"org"}, "bucket": {"prometheus"}},
+			body:        []byte("up 1"),
+			permissions: permission(platform.WriteAction),
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "remote write of a body larger than the limit",
+			path:        prometheusWritePath,
+			params:      url.Values{"org": {"org"}, "bucket": {"prometheus"}},
+			body:        make([]byte, maxPrometheusRemoteRequestBytes+1),
+			permissions: permission(platform.WriteAction),
+			wantCode:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "remote write of a body decoding larger than the limit",
+			path:        prometheusWritePath,
+			params:      url.Values{"org": {"org"}, "bucket": {"prometheus"}},
+			body:        inflated,
+			permissions: permission(platform.WriteAction),
+			wantCode:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "remote write to an unknown bucket",
+			path:        prometheusWritePath,
+			params:      url.Values{"org": {"org"}, "bucket": {"nope"}},
+			body:        write,
+			permissions: permission(platform.WriteAction),
+			wantCode:    http.StatusNotFound,
+		},
+		{
+			name:        "remote read",
+			path:        prometheusReadPath,
+			params:      url.Values{"org": {"org"}, "bucket": {"prometheus"}},
+			body:        read,
+			permissions: permission(platform.ReadAction),
+			wantCode:    http.StatusOK,
+		},
+		{
+			name:        "remote read requires read permission",
+			path:        prometheusReadPath,
+			params:      url.Values{"org": {"org"}, "bucket": {"prometheus"}},
+			body:        read,
+			permissions: permission(platform.WriteAction),
+			wantCode:    http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw.Points = nil
+			store.req = nil
+
+			r := httptest.NewRequest("POST", tt.path+"?"+tt.params.Encode(), bytes.NewReader(tt.body))
+			r = r.WithContext(icontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("unexpected status code %d: %s", w.Code, w.Body.String())
+			}
+			if w.Code >= 300 {
+				return
+			}
+
+			switch tt.path {
+			case prometheusWritePath:
+				if len(pw.Points) != 1 {
+					t.Fatalf("expected 1 point to be written, got %d", len(pw.Points))
+				}
+			case prometheusReadPath:
+				if store.req == nil || store.req.Range.Start != 1500000000000000000 || store.req.Range.End != 1500000060000000001 {
+					t.Fatalf("unexpected read request %v", store.req)
+				}
+				if got := w.Header().Get("Content-Encoding"); got != "snappy" {
+					t.Fatalf("unexpected content encoding %q", got)
+				}
+				compressed, err := ioutil.ReadAll(w.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				data, err := snappy.Decode(nil, compressed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var resp prometheus.ReadResponse
+				if err := proto.Unmarshal(data, &resp); err != nil {
+					t.Fatal(err)
+				}
+				if len(resp.Results) != 1 {
+					t.Fatalf("expected 1 result, got %d", len(resp.Results))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/promql"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	promQLInstantPath = "/api/v1/query"
+	promQLRangePath   = "/api/v1/query_range"
+)
+
+// PromQLBackend is all services and associated parameters required to construct
+// the PromQLHandler.
+type PromQLBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	OrganizationService influxdb.OrganizationService
+	ProxyQueryService   query.ProxyQueryService
+}
+
+// NewPromQLBackend returns a new instance of PromQLBackend.
+func NewPromQLBackend(b *APIBackend) *PromQLBackend {
+	return &PromQLBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "promql")),
+
+		OrganizationService: b.OrganizationService,
+		ProxyQueryService:   b.FluxService,
+	}
+}
+
+// PromQLHandler implements the query endpoints of the Prometheus HTTP API.
+// The metrics are read from the bucket named by the bucket parameter of the
+// organization named by the org or orgID parameters.
+type PromQLHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	Now                 func() time.Time
+	OrganizationService influxdb.OrganizationService
+	ProxyQueryService   query.ProxyQueryService
+}
+
+// NewPromQLHandler returns a new handler at /api/v1/query and /api/v1/query_range for promql queries.
+func NewPromQLHandler(b *PromQLBackend) *PromQLHandler {
+	h := &PromQLHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+		Now:              time.Now,
+
+		OrganizationService: b.OrganizationService,
+		ProxyQueryService:   b.ProxyQueryService,
+	}
+
+	h.HandlerFunc("GET", promQLInstantPath, h.handleInstantQuery)
+	h.HandlerFunc("POST", promQLInstantPath, h.handleInstantQuery)
+	h.HandlerFunc("GET", promQLRangePath, h.handleRangeQuery)
+	h.HandlerFunc("POST", promQLRangePath, h.handleRangeQuery)
+	return h
+}
+
+func (h *PromQLHandler) handleInstantQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PromQLHandler")
+	defer span.Finish()
+
+	if err := r.ParseForm(); err != nil {
+		h.encodeError(w, r, promql.ErrorBadData, http.StatusBadRequest, err)
+		return
+	}
+
+	end := h.Now()
+	if v := r.Form.Get("time"); v != "" {
+		t, err := parsePromTime(v)
+		if err != nil {
+			h.encodeError(w, r, promql.ErrorBadData, http.StatusBadRequest, fmt.Errorf("invalid parameter time: %v", err))
+			return
+		}
+		end = t
+	}
+
+	h.query(w, r, &QueryRequest{End: &end}, true)
+}
+
+func (h *PromQLHandler) handleRangeQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PromQLHandler")
+	defer span.Finish()
+
+	if err := r.ParseForm(); err != nil {
+		h.encodeError(w, r, promql.ErrorBadData, http.StatusBadRequest, err)
+		return
+	}
+
+	req := &QueryRequest{
+		Step: r.Form.Get("step"),
+	}
+	for _, p := range []struct {
+		name string
+		t    **time.Time
+	}{{"start", &req.Start}, {"end", &req.End}} {
+		t, err := parsePromTime(r.Form.Get(p.name))
+		if err != nil {
+			h.encodeError(w, r, promql.ErrorBadData, http.StatusBadRequest, fmt.Errorf("invalid parameter %s: %v", p.name, err))
+			return
+		}
+		*p.t = &t
+	}
+	if req.Step == "" {
+		h.encodeError(w, r, promql.ErrorBadData, http.StatusBadRequest, errors.New("invalid parameter step: step is required"))
+		return
+	}
+
+	h.query(w, r, req, false)
+}
+
+// query runs a promql query parameterized by the request form and req.
+func (h *PromQLHandler) query(w http.ResponseWriter, r *http.Request, req *QueryRequest, instant bool) {
+	ctx := r.Context()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.encodeError(w, r, promql.ErrorBadData, http.StatusUnauthorized, err)
+		return
+	}
+
+	req.Type = "promql"
+	req.Query = r.Form.Get("query")
+	req.Bucket = r.Form.Get("bucket")
+	*req = req.WithDefaults()
+	if err := req.Validate(); err != nil {
+		h.encodeError(w, r, promql.ErrorBadData, http.StatusBadRequest, err)
+		return
+	}
+
+	req.Org, err = queryOrganization(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.encodeError(w, r, promql.ErrorBadData, http.StatusBadRequest, err)
+		return
+	}
+
+	pr, err := req.proxyRequest(h.Now)
+	if err != nil {
+		h.encodeError(w, r, promql.ErrorBadData, http.StatusBadRequest, err)
+		return
+	}
+	pr.Dialect = &promql.Dialect{Instant: instant}
+
+	pr.Request.Authorization, err = queryAuthorization(a, req.Org.ID)
+	if err != nil {
+		h.encodeError(w, r, promql.ErrorBadData, http.StatusUnauthorized, err)
+		return
+	}
+	ctx = pcontext.SetAuthorizer(ctx, pr.Request.Authorization)
+	ctx = query.ContextWithPriority(ctx, query.HighPriority)
+
+	cw := iocounter.Writer{Writer: w}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, pr); err != nil {
+		if cw.Count() == 0 {
+			h.encodeError(w, r, promql.ErrorExecution, http.StatusUnprocessableEntity, err)
+			return
+		}
+		h.Logger.Info("Error writing response to client",
+			zap.String("handler", "promql"),
+			zap.Error(err),
+		)
+	}
+}
+
+// encodeError writes err in the format of the Prometheus HTTP API.
+// The status code of platform errors takes precedence over code.
+func (h *PromQLHandler) encodeError(w http.ResponseWriter, r *http.Request, errorType string, code int, err error) {
+	if _, ok := err.(*influxdb.Error); ok {
+		if c, ok := statusCodePlatformError[influxdb.ErrorCode(err)]; ok {
+			code = c
+		}
+		if code == http.StatusInternalServerError {
+			errorType = promql.ErrorInternal
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(promql.NewErrorResponse(errorType, err)); err != nil {
+		logEncodingError(h.Logger, r, err)
+	}
+}
+
+// parsePromTime parses a time as either an RFC3339 timestamp or a number of seconds since the epoch.
+func parsePromTime(s string) (time.Time, error) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		whole, frac := math.Modf(secs)
+		return time.Unix(int64(whole), int64(frac*1e9)).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
+
+// parsePromDuration parses a duration as either a Go duration or a number of seconds.
+func parsePromDuration(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		d := secs * float64(time.Second)
+		if d > math.MaxInt64 || d < math.MinInt64 {
+			return 0, fmt.Errorf("cannot parse %q to a valid duration. It overflows int64", s)
+		}
+		return time.Duration(d), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/repl"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/mock"
+	"github.com/influxdata/influxdb/query/promql"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPromQLHandler_Query(t *testing.T) {
+	i := inmem.NewService()
+	org := &influxdb.Organization{Name: "org"}
+	if err := i.CreateOrganization(context.Background(), org); err != nil {
+		t.Fatal(err)
+	}
+
+	var got *query.ProxyRequest
+	b := &PromQLBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zaptest.NewLogger(t),
+		OrganizationService: i,
+		ProxyQueryService: &mock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				got = req
+				if _, ok := req.Dialect.(*promql.Dialect); !ok {
+					t.Errorf("expected a promql dialect, got %T", req.Dialect)
+				}
+				if _, ok := req.Request.Compiler.(repl.Compiler); !ok {
+					t.Errorf("expected a spec compiler, got %T", req.Request.Compiler)
+				}
+				return flux.Statistics{}, json.NewEncoder(w).Encode(&promql.Response{Status: "success"})
+			},
+		},
+	}
+	h := NewPromQLHandler(b)
+	h.Now = func() time.Time { return time.Unix(1500000000, 0) }
+
+	tests := []struct {
+		name     string
+		path     string
+		params   url.Values
+		wantCode int
+		wantType string
+		instant  bool
+	}{
+		{
+			name:     "instant query",
+			path:     promQLInstantPath,
+			params:   url.Values{"query": {"up"}, "bucket": {"prometheus"}, "org": {"org"}},
+			wantCode: http.StatusOK,
+			instant:  true,
+		},
+		{
+			name:     "instant query at a time",
+			path:     promQLInstantPath,
+			params:   url.Values{"query": {"sum(up) by (job)"}, "bucket": {"prometheus"}, "orgID": {org.ID.String()}, "time": {"2017-07-14T02:40:00Z"}},
+			wantCode: http.StatusOK,
+			instant:  true,
+		},
+		{
+			name:     "range query",
+			path:     promQLRangePath,
+			params:   url.Values{"query": {"up"}, "bucket": {"prometheus"}, "org": {"org"}, "start": {"1499999000"}, "end": {"1500000000"}, "step": {"15s"}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "range query requires a step",
+			path:     promQLRangePath,
+			params:   url.Values{"query": {"up"}, "bucket": {"prometheus"}, "org": {"org"}, "start": {"1499999000"}, "end": {"1500000000"}},
+			wantCode: http.StatusBadRequest,
+			wantType: promql.ErrorBadData,
+		},
+		{
+			name:     "invalid query",
+			path:     promQLInstantPath,
+			params:   url.Values{"query": {"up{"}, "bucket": {"prometheus"}, "org": {"org"}},
+			wantCode: http.StatusBadRequest,
+			wantType: promql.ErrorBadData,
+		},
+		{
+			name:     "query requires a bucket",
+			path:     promQLInstantPath,
+			params:   url.Values{"query": {"up"}, "org": {"org"}},
+			wantCode: http.StatusBadRequest,
+			wantType: promql.ErrorBadData,
+		},
+		{
+			name:     "unknown organization",
+			path:     promQLInstantPath,
+			params:   url.Values{"query": {"up"}, "bucket": {"prometheus"}, "org": {"nope"}},
+			wantCode: http.StatusNotFound,
+			wantType: promql.ErrorBadData,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			r := httptest.NewRequest("GET", tt.path+"?"+tt.params.Encode(), nil)
+			r = r.WithContext(icontext.SetAuthorizer(r.Context(), &influxdb.Authorization{}))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("unexpected status code %d: %s", w.Code, w.Body.String())
+			}
+
+			var resp promql.Response
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.ErrorType != tt.wantType {
+				t.Fatalf("unexpected error type %q: %s", resp.ErrorType, resp.Error)
+			}
+			if tt.wantType != "" {
+				return
+			}
+
+			if got.Request.OrganizationID != org.ID {
+				t.Errorf("unexpected organization %s", got.Request.OrganizationID)
+			}
+			if d := got.Dialect.(*promql.Dialect); d.Instant != tt.instant {
+				t.Errorf("unexpected instant dialect %v", d.Instant)
+			}
+		})
+	}
+}
+
+func TestParsePromTime(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "1500000000", want: time.Unix(1500000000, 0)},
+		{in: "1500000000.5", want: time.Unix(1500000000, 5e8)},
+		{in: "2017-07-14T02:40:00Z", want: time.Unix(1500000000, 0)},
+		{in: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parsePromTime(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePromTime(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parsePromTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 
@@ -22,6 +23,9 @@ import (
 	"github.com/influxdata/flux/repl"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/arrow"
+	"github.com/influxdata/influxdb/query/ndjson"
+	"github.com/influxdata/influxdb/query/promql"
 	"github.com/influxdata/influxql"
 )
 
@@ -34,6 +38,13 @@ type QueryRequest struct {
 	Type    string       `json:"type"`
 	Dialect QueryDialect `json:"dialect"`
 
+	// Bucket, Start, End and Step are the evaluation parameters of promql queries.
+	// A promql query without a step is an instant query evaluated at End, which defaults to now.
+	Bucket string     `json:"bucket,omitempty"`
+	Start  *time.Time `json:"start,omitempty"`
+	End    *time.Time `json:"end,omitempty"`
+	Step   string     `json:"step,omitempty"`
+
 	Org *influxdb.Organization `json:"-"`
 }
 
@@ -44,6 +55,100 @@ type QueryDialect struct {
 	CommentPrefix  string   `json:"commentPrefix"`
 	DateTimeFormat string   `json:"dateTimeFormat"`
 	Annotations    []string `json:"annotations"`
+	// Format is the encoding of the query response. The other options only apply to CSV.
+	// An empty format is negotiated with the Accept header of the request, defaulting to CSV.
+	Format string `json:"format,omitempty"`
+}
+
+// Formats of the query response.
+const (
+	CSVFormat   = "csv"
+	ArrowFormat = "arrow"
+	JSONFormat  = "json"
+
+	// PromQLVectorFormat and PromQLMatrixFormat are the responses of the Prometheus HTTP API,
+	// with the steps of the query encoded as a vector or as a matrix. They are not negotiated
+	// with the Accept header.
+	PromQLVectorFormat = "promql-vector"
+	PromQLMatrixFormat = "promql-matrix"
+)
+
+// formatContentTypes maps the formats of the query response to their media types.
+var formatContentTypes = map[string]string{
+	CSVFormat:   "text/csv",
+	ArrowFormat: arrow.ContentType,
+	JSONFormat:  ndjson.ContentType,
+}
+
+// contentType returns the media type of the query response in the format of the dialect.
+func (d QueryDialect) contentType() string {
+	switch d.Format {
+	case PromQLVectorFormat, PromQLMatrixFormat:
+		return "application/json"
+	}
+	if ct, ok := formatContentTypes[d.Format]; ok {
+		return ct
+	}
+	return formatContentTypes[CSVFormat]
+}
+
+// acceptFormats are the formats negotiated with the Accept header, by order of preference.
+var acceptFormats = []string{CSVFormat, ArrowFormat, JSONFormat}
+
+// formatFromAccept returns the format of the query response negotiated with the Accept
+// header of a request. It returns the format of the media type with the highest quality,
+// or an empty format if none of them are acceptable.
+func formatFromAccept(accept string) string {
+	var (
+		format string
+		best   float64
+	)
+	for _, f := range acceptFormats {
+		if q := acceptQuality(accept, formatContentTypes[f]); q > best {
+			format, best = f, q
+		}
+	}
+	return format
+}
+
+// acceptQuality returns the quality the Accept header gives to the media type, as the q
+// parameter of the most specific media range that matches it. A media type that matches
+// none of the media ranges has a quality of zero.
+func acceptQuality(accept, mediaType string) float64 {
+	var (
+		quality     float64
+		specificity = -1
+	)
+	for _, v := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(v)
+		if err != nil {
+			continue
+		}
+
+		var s int
+		switch {
+		case mt == mediaType:
+			s = 2
+		case strings.HasSuffix(mt, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mt, "*")):
+			s = 1
+		case mt == "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+
+		q := 1.0
+		if qv, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qv, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		quality, specificity = q, s
+	}
+	return quality
 }
 
 // WithDefaults adds default values to the request.
@@ -79,7 +184,13 @@ func (r QueryRequest) Validate() error {
 		}
 	}
 
-	if r.Type != "flux" {
+	switch r.Type {
+	case "flux":
+	case "promql":
+		if err := r.validatePromQL(); err != nil {
+			return err
+		}
+	default:
 		return fmt.Errorf(`unknown query type: %s`, r.Type)
 	}
 
@@ -110,9 +221,56 @@ func (r QueryRequest) Validate() error {
 		return fmt.Errorf(`unknown dialect date time format: %s`, r.Dialect.DateTimeFormat)
 	}
 
+	switch r.Dialect.Format {
+	case "", CSVFormat, ArrowFormat, JSONFormat, PromQLVectorFormat, PromQLMatrixFormat:
+	default:
+		return fmt.Errorf(`unknown dialect format: %s`, r.Dialect.Format)
+	}
+
 	return nil
 }
 
+func (r QueryRequest) validatePromQL() error {
+	if r.Query == "" || r.Spec != nil || r.AST != nil || r.Extern != nil {
+		return errors.New(`promql queries require a query and cannot specify a spec, AST or external declarations`)
+	}
+
+	if r.Bucket == "" {
+		return errors.New(`promql queries require a bucket`)
+	}
+
+	if _, err := parsePromDuration(r.Step); r.Step != "" && err != nil {
+		return fmt.Errorf("invalid promql step: %v", err)
+	}
+
+	return nil
+}
+
+// promQLRange returns the evaluation range of a promql query.
+func (r QueryRequest) promQLRange(now func() time.Time) (promql.Range, error) {
+	var rng promql.Range
+	if r.End != nil {
+		rng.End = *r.End
+	} else {
+		rng.End = now()
+	}
+
+	rng.Start = rng.End
+	if r.Start != nil {
+		rng.Start = *r.Start
+	}
+
+	if r.Step != "" {
+		step, err := parsePromDuration(r.Step)
+		if err != nil {
+			return rng, err
+		}
+		rng.Step = step
+	}
+
+	return rng, rng.Validate()
+}
+
 // QueryAnalysis is a structured response of errors.
 type QueryAnalysis struct {
 	Errors []queryParseError `json:"errors"`
@@ -133,6 +291,8 @@ func (r QueryRequest) Analyze() (*QueryAnalysis, error) {
 		return r.analyzeFluxQuery()
 	case "influxql":
 		return r.analyzeInfluxQLQuery()
+	case "promql":
+		return r.analyzePromQLQuery()
 	}
 
 	return nil, fmt.Errorf("unknown query request type %s", r.Type)
@@ -197,6 +357,18 @@ func (r QueryRequest) analyzeInfluxQLQuery() (*QueryAnalysis, error) {
 	return a, nil
 }
 
+func (r QueryRequest) analyzePromQLQuery() (*QueryAnalysis, error) {
+	a := &QueryAnalysis{
+		Errors: []queryParseError{},
+	}
+	if _, err := promql.ParsePromQL(r.Query); err != nil {
+		a.Errors = append(a.Errors, queryParseError{
+			Message: err.Error(),
+		})
+	}
+	return a, nil
+}
+
 func columnFromCharacter(q string, char int) int {
 	col := 0
 	for i, c := range q {
@@ -226,7 +398,20 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 	}
 	// Query is preferred over AST
 	var compiler flux.Compiler
-	if r.Query != "" {
+	if r.Type == "promql" {
+		rng, err := r.promQLRange(now)
+		if err != nil {
+			return nil, err
+		}
+		spec, err := promql.BuildRange(r.Query, r.Bucket, rng)
+		if err != nil {
+			return nil, err
+		}
+		spec.Now = now()
+		compiler = repl.Compiler{
+			Spec: spec,
+		}
+	} else if r.Query != "" {
 		compiler = lang.FluxCompiler{
 			Now:    now(),
 			Extern: r.Extern,
@@ -247,6 +432,26 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 		}
 	}
 
+	return &query.ProxyRequest{
+		Request: query.Request{
+			OrganizationID: r.Org.ID,
+			Compiler:       compiler,
+		},
+		Dialect: r.dialect(),
+	}, nil
+}
+
+// dialect returns the dialect encoding the query response in the format of the request.
+func (r QueryRequest) dialect() flux.Dialect {
+	switch r.Dialect.Format {
+	case ArrowFormat:
+		return new(arrow.Dialect)
+	case JSONFormat:
+		return new(ndjson.Dialect)
+	case PromQLVectorFormat, PromQLMatrixFormat:
+		return &promql.Dialect{Instant: r.Dialect.Format == PromQLVectorFormat}
+	}
+
 	delimiter, _ := utf8.DecodeRuneInString(r.Dialect.Delimiter)
 
 	noHeader := false
@@ -256,19 +461,13 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 
 	// TODO(nathanielc): Use commentPrefix and dateTimeFormat
 	// once they are supported.
-	return &query.ProxyRequest{
-		Request: query.Request{
-			OrganizationID: r.Org.ID,
-			Compiler:       compiler,
-		},
-		Dialect: &csv.Dialect{
-			ResultEncoderConfig: csv.ResultEncoderConfig{
-				NoHeader:    noHeader,
-				Delimiter:   delimiter,
-				Annotations: r.Dialect.Annotations,
-			},
+	return &csv.Dialect{
+		ResultEncoderConfig: csv.ResultEncoderConfig{
+			NoHeader:    noHeader,
+			Delimiter:   delimiter,
+			Annotations: r.Dialect.Annotations,
 		},
-	}, nil
+	}
 }
 
 // QueryRequestFromProxyRequest converts a query.ProxyRequest into a QueryRequest.
@@ -297,6 +496,15 @@ func QueryRequestFromProxyRequest(req *query.ProxyRequest) (*QueryRequest, error
 		qr.Dialect.CommentPrefix = "#"
 		qr.Dialect.DateTimeFormat = "RFC3339"
 		qr.Dialect.Annotations = d.ResultEncoderConfig.Annotations
+	case *arrow.Dialect:
+		qr.Dialect.Format = ArrowFormat
+	case *ndjson.Dialect:
+		qr.Dialect.Format = JSONFormat
+	case *promql.Dialect:
+		qr.Dialect.Format = PromQLMatrixFormat
+		if d.Instant {
+			qr.Dialect.Format = PromQLVectorFormat
+		}
 	default:
 		return nil, fmt.Errorf("unsupported dialect %T", d)
 	}
@@ -331,6 +539,9 @@ func decodeQueryRequest(ctx context.Context, r *http.Request, svc influxdb.Organ
 		}
 	}
 
+	if req.Dialect.Format == "" {
+		req.Dialect.Format = formatFromAccept(r.Header.Get("Accept"))
+	}
 	req = req.WithDefaults()
 	if err := req.Validate(); err != nil {
 		return nil, body.bytesRead, err
@@ -362,16 +573,23 @@ func decodeProxyQueryRequest(ctx context.Context, r *http.Request, auth influxdb
 		return nil, n, err
 	}
 
-	var token *influxdb.Authorization
+	token, err := queryAuthorization(auth, req.Org.ID)
+	if err != nil {
+		return pr, n, err
+	}
+
+	pr.Request.Authorization = token
+	return pr, n, nil
+}
+
+// queryAuthorization returns the authorization to run a query of an organization as auth.
+func queryAuthorization(auth influxdb.Authorizer, orgID influxdb.ID) (*influxdb.Authorization, error) {
 	switch a := auth.(type) {
 	case *influxdb.Authorization:
-		token = a
+		return a, nil
 	case *influxdb.Session:
-		token = a.EphemeralAuth(req.Org.ID)
+		return a.EphemeralAuth(orgID), nil
 	default:
-		return pr, n, influxdb.ErrAuthorizerNotSupported
+		return nil, influxdb.ErrAuthorizerNotSupported
 	}
-
-	pr.Request.Authorization = token
-	return pr, n, nil
 }
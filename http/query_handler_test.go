@@ -434,6 +434,45 @@ func TestFluxHandler_PostQuery_Errors(t *testing.T) {
 	})
 }
 
+func TestFluxHandler_PostQuery_Priority(t *testing.T) {
+	i := inmem.NewService()
+	org := influxdb.Organization{Name: t.Name()}
+	if err := i.CreateOrganization(context.Background(), &org); err != nil {
+		t.Fatal(err)
+	}
+
+	var priority query.Priority
+	h := NewFluxHandler(&FluxBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zaptest.NewLogger(t),
+		QueryEventRecorder:  noopEventRecorder{},
+		OrganizationService: i,
+		ProxyQueryService: &mock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				priority = query.PriorityFromContext(ctx)
+				return flux.Statistics{}, nil
+			},
+		},
+	})
+
+	req, err := http.NewRequest("POST", "/api/v2/query?orgID="+org.ID.String(), bytes.NewReader([]byte("buckets()")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = req.WithContext(icontext.SetAuthorizer(req.Context(), &influxdb.Authorization{}))
+	req.Header.Set("Content-Type", "application/vnd.flux")
+
+	w := httptest.NewRecorder()
+	h.handleQuery(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", w.Code, w.Body.String())
+	}
+	if priority != query.HighPriority {
+		t.Errorf("unexpected priority of an interactive query: got %s want %s", priority, query.HighPriority)
+	}
+}
+
 func TestFluxService_Query_gzip(t *testing.T) {
 	// orgService is just to mock out orgs by returning
 	// the same org every time.
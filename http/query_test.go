@@ -3,6 +3,7 @@ package http
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -20,7 +21,9 @@ import (
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/arrow"
 	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/promql"
 )
 
 var cmpOptions = cmp.Options{
@@ -82,6 +85,8 @@ func TestQueryRequest_Validate(t *testing.T) {
 		Query   string
 		Type    string
 		Dialect QueryDialect
+		Bucket  string
+		Step    string
 		org     *platform.Organization
 	}
 	tests := []struct {
@@ -174,6 +179,72 @@ func TestQueryRequest_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "promql requires a bucket",
+			fields: fields{
+				Query: "up",
+				Type:  "promql",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "promql cannot have a spec",
+			fields: fields{
+				Query:  "up",
+				Spec:   &flux.Spec{},
+				Type:   "promql",
+				Bucket: "prometheus",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "promql step must be a duration",
+			fields: fields{
+				Query:  "up",
+				Type:   "promql",
+				Bucket: "prometheus",
+				Step:   "often",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid promql query",
+			fields: fields{
+				Query:  "sum(up) by (job)",
+				Type:   "promql",
+				Bucket: "prometheus",
+				Step:   "15",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+				},
+			},
+		},
+		{
+			name: "unknown format",
+			fields: fields{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					Format:         "xml",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "valid query",
 			fields: fields{
@@ -195,6 +266,8 @@ func TestQueryRequest_Validate(t *testing.T) {
 				Query:   tt.fields.Query,
 				Type:    tt.fields.Type,
 				Dialect: tt.fields.Dialect,
+				Bucket:  tt.fields.Bucket,
+				Step:    tt.fields.Step,
 				Org:     tt.fields.org,
 			}
 			if err := r.Validate(); (err != nil) != tt.wantErr {
@@ -361,6 +434,52 @@ func TestQueryRequest_proxyRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid arrow format",
+			fields: fields{
+				Type:  "flux",
+				Query: "howdy",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					Format:         ArrowFormat,
+				},
+				org: &platform.Organization{},
+			},
+			now: func() time.Time { return time.Unix(1, 1) },
+			want: &query.ProxyRequest{
+				Request: query.Request{
+					Compiler: lang.FluxCompiler{
+						Now:   time.Unix(1, 1),
+						Query: "howdy",
+					},
+				},
+				Dialect: &arrow.Dialect{},
+			},
+		},
+		{
+			name: "valid promql vector format",
+			fields: fields{
+				Type:  "flux",
+				Query: "howdy",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					Format:         PromQLVectorFormat,
+				},
+				org: &platform.Organization{},
+			},
+			now: func() time.Time { return time.Unix(1, 1) },
+			want: &query.ProxyRequest{
+				Request: query.Request{
+					Compiler: lang.FluxCompiler{
+						Now:   time.Unix(1, 1),
+						Query: "howdy",
+					},
+				},
+				Dialect: &promql.Dialect{Instant: true},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -385,6 +504,61 @@ func TestQueryRequest_proxyRequest(t *testing.T) {
 	}
 }
 
+func TestQueryRequestFromProxyRequest(t *testing.T) {
+	for _, d := range []flux.Dialect{
+		&arrow.Dialect{},
+		&promql.Dialect{},
+		&promql.Dialect{Instant: true},
+	} {
+		t.Run(fmt.Sprintf("%T", d), func(t *testing.T) {
+			want := &query.ProxyRequest{
+				Request: query.Request{
+					Compiler: lang.FluxCompiler{Query: "howdy"},
+				},
+				Dialect: d,
+			}
+			qr, err := QueryRequestFromProxyRequest(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := qr.WithDefaults()
+			r.Org = &platform.Organization{}
+			got, err := r.proxyRequest(func() time.Time { return time.Time{} })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(got, want, cmpOptions...) {
+				t.Errorf("QueryRequestFromProxyRequest() -want/+got\n%s", cmp.Diff(want, got, cmpOptions...))
+			}
+		})
+	}
+}
+
+func Test_formatFromAccept(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{accept: "", want: ""},
+		{accept: "text/html", want: ""},
+		{accept: "application/x-ndjson", want: JSONFormat},
+		{accept: "text/html, application/x-ndjson;q=0.9", want: JSONFormat},
+		{accept: "text/csv;q=0.5, application/x-ndjson", want: JSONFormat},
+		{accept: "text/csv;q=0.5, application/x-ndjson;q=0.4", want: CSVFormat},
+		{accept: "application/x-ndjson;q=0, */*;q=0.1", want: CSVFormat},
+		{accept: "*/*", want: CSVFormat},
+		{accept: "text/*", want: CSVFormat},
+		{accept: "application/*;q=0.8, application/x-ndjson", want: JSONFormat},
+		{accept: "application/x-ndjson;q=0", want: ""},
+		{accept: "application/x-ndjson;q=invalid, text/csv;q=0.1", want: CSVFormat},
+	}
+	for _, tt := range tests {
+		if got := formatFromAccept(tt.accept); got != tt.want {
+			t.Errorf("formatFromAccept(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
 func Test_decodeQueryRequest(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -451,6 +625,66 @@ func Test_decodeQueryRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "format negotiated with the accept header",
+			args: args{
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"query": "from()"}`))
+					r.Header.Set("Accept", "text/html, application/x-ndjson;q=0.9")
+					return r
+				}(),
+				svc: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{
+							ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+						}, nil
+					},
+				},
+			},
+			want: &QueryRequest{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					Header:         func(x bool) *bool { return &x }(true),
+					Format:         JSONFormat,
+				},
+				Org: &platform.Organization{
+					ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+				},
+			},
+		},
+		{
+			name: "dialect format preferred over the accept header",
+			args: args{
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"query": "from()", "dialect": {"format": "arrow"}}`))
+					r.Header.Set("Accept", "application/x-ndjson")
+					return r
+				}(),
+				svc: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{
+							ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+						}, nil
+					},
+				},
+			},
+			want: &QueryRequest{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					Header:         func(x bool) *bool { return &x }(true),
+					Format:         ArrowFormat,
+				},
+				Org: &platform.Organization{
+					ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+				},
+			},
+		},
 		{
 			name: "error decoding json",
 			args: args{
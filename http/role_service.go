@@ -0,0 +1,644 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// RoleHandler represents an HTTP API handler for roles and their bindings.
+type RoleHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RoleService influxdb.RoleService
+}
+
+const (
+	rolesPath              = "/api/v2/roles"
+	rolesIDPath            = "/api/v2/roles/:id"
+	rolesIDBindingsPath    = "/api/v2/roles/:id/bindings"
+	rolesIDBindingsIDPath  = "/api/v2/roles/:id/bindings/:userID"
+	roleBindingsPathFormat = "/api/v2/roles/%s/bindings"
+)
+
+// NewRoleHandler returns a new instance of RoleHandler.
+func NewRoleHandler(s influxdb.RoleService, he influxdb.HTTPErrorHandler) *RoleHandler {
+	h := &RoleHandler{
+		Router:           NewRouter(he),
+		HTTPErrorHandler: he,
+		Logger:           zap.NewNop(),
+		RoleService:      s,
+	}
+
+	h.HandlerFunc("POST", rolesPath, h.handlePostRole)
+	h.HandlerFunc("GET", rolesPath, h.handleGetRoles)
+
+	h.HandlerFunc("GET", rolesIDPath, h.handleGetRole)
+	h.HandlerFunc("PATCH", rolesIDPath, h.handlePatchRole)
+	h.HandlerFunc("DELETE", rolesIDPath, h.handleDeleteRole)
+
+	h.HandlerFunc("GET", rolesIDBindingsPath, h.handleGetRoleBindings)
+	h.HandlerFunc("POST", rolesIDBindingsPath, h.handlePostRoleBinding)
+	h.HandlerFunc("DELETE", rolesIDBindingsIDPath, h.handleDeleteRoleBinding)
+
+	return h
+}
+
+type roleResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Role
+}
+
+func newRoleResponse(r *influxdb.Role) *roleResponse {
+	return &roleResponse{
+		Links: map[string]string{
+			"self":     fmt.Sprintf("/api/v2/roles/%s", r.ID),
+			"bindings": fmt.Sprintf(roleBindingsPathFormat, r.ID),
+		},
+		Role: *r,
+	}
+}
+
+type rolesResponse struct {
+	Links map[string]string `json:"links"`
+	Roles []*roleResponse   `json:"roles"`
+}
+
+func newRolesResponse(rs []*influxdb.Role) *rolesResponse {
+	res := &rolesResponse{
+		Links: map[string]string{
+			"self": rolesPath,
+		},
+		Roles: make([]*roleResponse, 0, len(rs)),
+	}
+	for _, r := range rs {
+		res.Roles = append(res.Roles, newRoleResponse(r))
+	}
+	return res
+}
+
+type roleBindingsResponse struct {
+	Links    map[string]string       `json:"links"`
+	Bindings []*influxdb.RoleBinding `json:"bindings"`
+}
+
+func newRoleBindingsResponse(roleID influxdb.ID, bs []*influxdb.RoleBinding) *roleBindingsResponse {
+	return &roleBindingsResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf(roleBindingsPathFormat, roleID),
+			"role": fmt.Sprintf("/api/v2/roles/%s", roleID),
+		},
+		Bindings: bs,
+	}
+}
+
+// handlePostRole is the HTTP handler for the POST /api/v2/roles route.
+func (h *RoleHandler) handlePostRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role := &influxdb.Role{}
+	if err := json.NewDecoder(r.Body).Decode(role); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode role request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := role.Validate(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.CreateRole(ctx, role); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("role created", zap.String("role", fmt.Sprint(role)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newRoleResponse(role)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetRoles is the HTTP handler for the GET /api/v2/roles route.
+func (h *RoleHandler) handleGetRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeRoleFilter(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rs, _, err := h.RoleService.FindRoles(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRolesResponse(rs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeRoleFilter(r *http.Request) (influxdb.RoleFilter, error) {
+	qp := r.URL.Query()
+	var filter influxdb.RoleFilter
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = id
+	}
+
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	return filter, nil
+}
+
+// decodeRoleID returns the ID of the role in the path of the request.
+func decodeRoleID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+// handleGetRole is the HTTP handler for the GET /api/v2/roles/:id route.
+func (h *RoleHandler) handleGetRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	role, err := h.RoleService.FindRoleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchRole is the HTTP handler for the PATCH /api/v2/roles/:id route.
+func (h *RoleHandler) handlePatchRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.RoleUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode role update",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	role, err := h.RoleService.UpdateRole(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("role updated", zap.String("role", fmt.Sprint(role)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteRole is the HTTP handler for the DELETE /api/v2/roles/:id route.
+func (h *RoleHandler) handleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.DeleteRole(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("role deleted", zap.String("roleID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetRoleBindings is the HTTP handler for the GET /api/v2/roles/:id/bindings route.
+func (h *RoleHandler) handleGetRoleBindings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	bs, err := h.RoleService.FindRoleBindings(ctx, influxdb.RoleBindingFilter{RoleID: &id})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleBindingsResponse(id, bs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostRoleBinding is the HTTP handler for the POST /api/v2/roles/:id/bindings route.
+func (h *RoleHandler) handlePostRoleBinding(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b := &influxdb.RoleBinding{}
+	if err := json.NewDecoder(r.Body).Decode(b); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode role binding request",
+			Err:  err,
+		}, w)
+		return
+	}
+	b.RoleID = id
+
+	if err := b.Validate(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.CreateRoleBinding(ctx, b); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("role binding created", zap.String("binding", fmt.Sprint(b)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, b); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteRoleBinding is the HTTP handler for the DELETE /api/v2/roles/:id/bindings/:userID route.
+func (h *RoleHandler) handleDeleteRoleBinding(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var userID influxdb.ID
+	if err := userID.DecodeFromString(httprouter.ParamsFromContext(ctx).ByName("userID")); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.DeleteRoleBinding(ctx, id, userID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("role binding deleted", zap.String("roleID", id.String()), zap.String("userID", userID.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RoleService connects to Influx via HTTP using tokens to manage roles.
+type RoleService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.RoleService = (*RoleService)(nil)
+
+func roleIDPath(id influxdb.ID) string {
+	return path.Join(rolesPath, id.String())
+}
+
+// FindRoleByID returns a single role by ID.
+func (s *RoleService) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	u, err := NewURL(s.Addr, roleIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var rr roleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rr); err != nil {
+		return nil, err
+	}
+	return &rr.Role, nil
+}
+
+// FindRoles returns a list of roles that match filter and the total count of matching roles.
+func (s *RoleService) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	u, err := NewURL(s.Addr, rolesPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := u.Query()
+	if filter.OrgID != nil {
+		query.Add("orgID", filter.OrgID.String())
+	}
+	if filter.Name != nil {
+		query.Add("name", *filter.Name)
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, 0, err
+	}
+
+	var rr rolesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rr); err != nil {
+		return nil, 0, err
+	}
+
+	rs := make([]*influxdb.Role, 0, len(rr.Roles))
+	for _, r := range rr.Roles {
+		if filter.ID != nil && *filter.ID != r.ID {
+			continue
+		}
+		rs = append(rs, &r.Role)
+	}
+	return rs, len(rs), nil
+}
+
+// CreateRole creates a new role and sets r.ID with the new identifier.
+func (s *RoleService) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	u, err := NewURL(s.Addr, rolesPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	var rr roleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rr); err != nil {
+		return err
+	}
+	*r = rr.Role
+	return nil
+}
+
+// UpdateRole updates a role with a changeset.
+func (s *RoleService) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	u, err := NewURL(s.Addr, roleIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(upd)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var rr roleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rr); err != nil {
+		return nil, err
+	}
+	return &rr.Role, nil
+}
+
+// DeleteRole deletes a role and its bindings.
+func (s *RoleService) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	u, err := NewURL(s.Addr, roleIDPath(id))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
+
+// FindRoleBindings returns the bindings of the role of filter.
+// Bindings can only be listed by role over HTTP.
+func (s *RoleService) FindRoleBindings(ctx context.Context, filter influxdb.RoleBindingFilter) ([]*influxdb.RoleBinding, error) {
+	if filter.RoleID == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "role id is required",
+		}
+	}
+
+	u, err := NewURL(s.Addr, fmt.Sprintf(roleBindingsPathFormat, *filter.RoleID))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var br roleBindingsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&br); err != nil {
+		return nil, err
+	}
+
+	bs := br.Bindings[:0]
+	for _, b := range br.Bindings {
+		if filter.UserID != nil && *filter.UserID != b.UserID {
+			continue
+		}
+		bs = append(bs, b)
+	}
+	return bs, nil
+}
+
+// CreateRoleBinding binds a user to a role.
+func (s *RoleService) CreateRoleBinding(ctx context.Context, b *influxdb.RoleBinding) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	u, err := NewURL(s.Addr, fmt.Sprintf(roleBindingsPathFormat, b.RoleID))
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
+
+// DeleteRoleBinding unbinds a user from a role.
+func (s *RoleService) DeleteRoleBinding(ctx context.Context, roleID, userID influxdb.ID) error {
+	u, err := NewURL(s.Addr, path.Join(fmt.Sprintf(roleBindingsPathFormat, roleID), userID.String()))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
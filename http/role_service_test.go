@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestRoleService(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		UserID:       user.ID,
+		UserType:     influxdb.Member,
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   org.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewRoleHandler(svc, ErrorHandler(0)))
+	defer server.Close()
+	client := &RoleService{Addr: server.URL}
+
+	readBuckets := influxdb.Permission{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &org.ID},
+	}
+	role := &influxdb.Role{
+		OrgID:       org.ID,
+		Name:        "bucket readers",
+		Permissions: []influxdb.Permission{readBuckets},
+	}
+	if err := client.CreateRole(ctx, role); err != nil {
+		t.Fatal(err)
+	}
+	if !role.ID.Valid() {
+		t.Fatal("expected the role to have an ID")
+	}
+
+	desc := "reads every bucket"
+	if _, err := client.UpdateRole(ctx, role.ID, influxdb.RoleUpdate{Description: &desc}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.FindRoleByID(ctx, role.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Description != desc || len(got.Permissions) != 1 || !got.Permissions[0].Matches(readBuckets) {
+		t.Fatalf("unexpected role %+v", got)
+	}
+
+	rs, n, err := client.FindRoles(ctx, influxdb.RoleFilter{OrgID: &org.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || rs[0].ID != role.ID {
+		t.Fatalf("unexpected roles %+v", rs)
+	}
+
+	if err := client.CreateRoleBinding(ctx, &influxdb.RoleBinding{RoleID: role.ID, UserID: user.ID}); err != nil {
+		t.Fatal(err)
+	}
+	bs, err := client.FindRoleBindings(ctx, influxdb.RoleBindingFilter{RoleID: &role.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bs) != 1 || bs[0].UserID != user.ID {
+		t.Fatalf("unexpected role bindings %+v", bs)
+	}
+
+	if err := client.DeleteRoleBinding(ctx, role.ID, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteRoleBinding(ctx, role.ID, user.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+
+	if err := client.DeleteRole(ctx, role.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.FindRoleByID(ctx, role.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,291 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	queriesPath   = "/api/v2/queries"
+	queriesIDPath = "/api/v2/queries/:id"
+)
+
+// RunningQueryHandler represents an HTTP API handler for the running queries.
+type RunningQueryHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RunningQueryService influxdb.RunningQueryService
+}
+
+// NewRunningQueryHandler returns a new instance of RunningQueryHandler.
+func NewRunningQueryHandler(s influxdb.RunningQueryService, he influxdb.HTTPErrorHandler) *RunningQueryHandler {
+	h := &RunningQueryHandler{
+		Router:              NewRouter(he),
+		HTTPErrorHandler:    he,
+		Logger:              zap.NewNop(),
+		RunningQueryService: s,
+	}
+
+	h.HandlerFunc("GET", queriesPath, h.handleGetQueries)
+	h.HandlerFunc("GET", queriesIDPath, h.handleGetQuery)
+	h.HandlerFunc("DELETE", queriesIDPath, h.handleDeleteQuery)
+	return h
+}
+
+type runningQueryResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.RunningQuery
+}
+
+func newRunningQueryResponse(q *influxdb.RunningQuery) *runningQueryResponse {
+	return &runningQueryResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/queries/%s", q.ID),
+			"org":  fmt.Sprintf("/api/v2/orgs/%s", q.OrgID),
+		},
+		RunningQuery: *q,
+	}
+}
+
+type runningQueriesResponse struct {
+	Links   map[string]string       `json:"links"`
+	Queries []*runningQueryResponse `json:"queries"`
+}
+
+func newRunningQueriesResponse(qs []*influxdb.RunningQuery) *runningQueriesResponse {
+	res := &runningQueriesResponse{
+		Links: map[string]string{
+			"self": queriesPath,
+		},
+		Queries: make([]*runningQueryResponse, 0, len(qs)),
+	}
+	for _, q := range qs {
+		res.Queries = append(res.Queries, newRunningQueryResponse(q))
+	}
+	return res
+}
+
+// handleGetQueries is the HTTP handler for the GET /api/v2/queries route.
+func (h *RunningQueryHandler) handleGetQueries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeRunningQueryFilter(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	qs, err := h.RunningQueryService.FindRunningQueries(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRunningQueriesResponse(qs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeRunningQueryFilter(r *http.Request) (influxdb.RunningQueryFilter, error) {
+	qp := r.URL.Query()
+	var filter influxdb.RunningQueryFilter
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = id
+	}
+
+	if userID := qp.Get("userID"); userID != "" {
+		id, err := influxdb.IDFromString(userID)
+		if err != nil {
+			return filter, err
+		}
+		filter.UserID = id
+	}
+
+	return filter, nil
+}
+
+// decodeRunningQueryID returns the ID of the query in the path of the request.
+func decodeRunningQueryID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+// handleGetQuery is the HTTP handler for the GET /api/v2/queries/:id route.
+func (h *RunningQueryHandler) handleGetQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRunningQueryID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	q, err := h.RunningQueryService.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRunningQueryResponse(q)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteQuery is the HTTP handler for the DELETE /api/v2/queries/:id route.
+func (h *RunningQueryHandler) handleDeleteQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeRunningQueryID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RunningQueryService.KillRunningQuery(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("query killed", zap.String("queryID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunningQueryService connects to Influx via HTTP using tokens to manage the running queries.
+type RunningQueryService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.RunningQueryService = (*RunningQueryService)(nil)
+
+func runningQueryIDPath(id influxdb.ID) string {
+	return path.Join(queriesPath, id.String())
+}
+
+// FindRunningQueries returns the running queries matching the filter.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+	u, err := NewURL(s.Addr, queriesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	if filter.OrgID != nil {
+		query.Add("orgID", filter.OrgID.String())
+	}
+	if filter.UserID != nil {
+		query.Add("userID", filter.UserID.String())
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var qr runningQueriesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&qr); err != nil {
+		return nil, err
+	}
+
+	qs := make([]*influxdb.RunningQuery, 0, len(qr.Queries))
+	for _, q := range qr.Queries {
+		qs = append(qs, &q.RunningQuery)
+	}
+	return qs, nil
+}
+
+// FindRunningQueryByID returns a single running query by ID.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+	u, err := NewURL(s.Addr, runningQueryIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var qr runningQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&qr); err != nil {
+		return nil, err
+	}
+	return &qr.RunningQuery, nil
+}
+
+// KillRunningQuery cancels a running query.
+func (s *RunningQueryService) KillRunningQuery(ctx context.Context, id influxdb.ID) error {
+	u, err := NewURL(s.Addr, runningQueryIDPath(id))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
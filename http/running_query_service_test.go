@@ -0,0 +1,91 @@
+package http_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	platformhttp "github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestRunningQueryService(t *testing.T) {
+	createdAt := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	queries := []*platform.RunningQuery{
+		{
+			ID:              1,
+			OrgID:           2,
+			UserID:          3,
+			AuthorizationID: 4,
+			CompilerType:    "flux",
+			Query:           `from(bucket: "telegraf") |> range(start: -1h)`,
+			State:           "executing",
+			CreatedAt:       createdAt,
+			QueueDuration:   time.Millisecond,
+			ExecuteDuration: time.Minute,
+			MemoryBytes:     1024,
+			MaxMemoryBytes:  2048,
+		},
+	}
+
+	var (
+		filter platform.RunningQueryFilter
+		killed platform.ID
+	)
+	m := mock.NewRunningQueryService()
+	m.FindRunningQueriesFn = func(ctx context.Context, f platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+		filter = f
+		return queries, nil
+	}
+	m.FindRunningQueryByIDFn = func(ctx context.Context, id platform.ID) (*platform.RunningQuery, error) {
+		if id != queries[0].ID {
+			return nil, platform.ErrRunningQueryNotFound
+		}
+		return queries[0], nil
+	}
+	m.KillRunningQueryFn = func(ctx context.Context, id platform.ID) error {
+		if id != queries[0].ID {
+			return platform.ErrRunningQueryNotFound
+		}
+		killed = id
+		return nil
+	}
+
+	server := httptest.NewServer(platformhttp.NewRunningQueryHandler(m, platformhttp.ErrorHandler(0)))
+	defer server.Close()
+	s := &platformhttp.RunningQueryService{Addr: server.URL}
+	ctx := context.Background()
+
+	orgID := platform.ID(2)
+	qs, err := s.FindRunningQueries(ctx, platform.RunningQueryFilter{OrgID: &orgID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(qs, queries); diff != "" {
+		t.Errorf("unexpected queries -got/+want\n%s", diff)
+	}
+	if filter.OrgID == nil || *filter.OrgID != orgID || filter.UserID != nil {
+		t.Errorf("unexpected filter %+v", filter)
+	}
+
+	q, err := s.FindRunningQueryByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(q, queries[0]); diff != "" {
+		t.Errorf("unexpected query -got/+want\n%s", diff)
+	}
+
+	if err := s.KillRunningQuery(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if killed != 1 {
+		t.Errorf("expected query 1 to be killed, got %s", killed)
+	}
+
+	if err := s.KillRunningQuery(ctx, 5); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
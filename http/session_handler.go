@@ -2,9 +2,11 @@ package http
 
 import (
 	"context"
+	"net"
 	"net/http"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -17,6 +19,14 @@ type SessionBackend struct {
 
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
+	// SigninLockoutService locks out users and addresses failing to sign in; nil disables lockouts.
+	SigninLockoutService platform.SigninLockoutService
+
+	// OAuth2Configs configure the OAuth2 providers users may sign in with.
+	OAuth2Configs              []*OAuth2Config
+	UserService                platform.UserService
+	OrganizationService        platform.OrganizationService
+	UserResourceMappingService platform.UserResourceMappingService
 }
 
 // NewSessionBackend creates a new SessionBackend with associated logger.
@@ -25,8 +35,14 @@ func NewSessionBackend(b *APIBackend) *SessionBackend {
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		Logger:           b.Logger.With(zap.String("handler", "session")),
 
-		PasswordsService: b.PasswordsService,
-		SessionService:   b.SessionService,
+		PasswordsService:     b.PasswordsService,
+		SessionService:       b.SessionService,
+		SigninLockoutService: b.SigninLockoutService,
+
+		OAuth2Configs:              b.OAuth2Configs,
+		UserService:                b.UserService,
+		OrganizationService:        b.OrganizationService,
+		UserResourceMappingService: b.UserResourceMappingService,
 	}
 }
 
@@ -36,23 +52,51 @@ type SessionHandler struct {
 	platform.HTTPErrorHandler
 	Logger *zap.Logger
 
-	PasswordsService platform.PasswordsService
-	SessionService   platform.SessionService
+	PasswordsService     platform.PasswordsService
+	SessionService       platform.SessionService
+	SigninLockoutService platform.SigninLockoutService
+
+	oauth2Muxes map[string]*oauth2.AuthMux
 }
 
 // NewSessionHandler returns a new instance of SessionHandler.
+// OAuth2 providers that are misconfigured are logged and skipped.
 func NewSessionHandler(b *SessionBackend) *SessionHandler {
 	h := &SessionHandler{
 		Router:           NewRouter(b.HTTPErrorHandler),
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		Logger:           b.Logger,
 
-		PasswordsService: b.PasswordsService,
-		SessionService:   b.SessionService,
+		PasswordsService:     b.PasswordsService,
+		SessionService:       b.SessionService,
+		SigninLockoutService: b.SigninLockoutService,
+
+		oauth2Muxes: make(map[string]*oauth2.AuthMux, len(b.OAuth2Configs)),
+	}
+
+	for _, c := range b.OAuth2Configs {
+		a := &sessionAuthenticator{
+			config: c,
+			log:    b.Logger,
+
+			UserService:                b.UserService,
+			OrganizationService:        b.OrganizationService,
+			UserResourceMappingService: b.UserResourceMappingService,
+			SessionService:             b.SessionService,
+		}
+		mux, err := newOAuth2Mux(c, a, b.Logger)
+		if err != nil {
+			b.Logger.Error("Failed to configure oauth2 provider", zap.String("provider", c.ProviderName()), zap.Error(err))
+			continue
+		}
+		h.oauth2Muxes[c.ProviderName()] = mux
 	}
 
 	h.HandlerFunc("POST", "/api/v2/signin", h.handleSignin)
 	h.HandlerFunc("POST", "/api/v2/signout", h.handleSignout)
+	h.HandlerFunc("GET", oauth2SigninPath, h.handleOAuth2Providers)
+	h.HandlerFunc("GET", oauth2ProviderPath, h.handleOAuth2Signin)
+	h.HandlerFunc("GET", oauth2CallbackPath, h.handleOAuth2Callback)
 	return h
 }
 
@@ -66,6 +110,15 @@ func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	addr := remoteHost(r)
+	if h.SigninLockoutService != nil {
+		// The attempt counts as failed until the sign-in succeeds.
+		if err := h.SigninLockoutService.RecordSigninAttempt(ctx, req.Username, addr); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
 	if err := h.PasswordsService.ComparePassword(ctx, req.Username, req.Password); err != nil {
 		// Don't log here, it should already be handled by the service
 		UnauthorizedError(ctx, h, w)
@@ -78,10 +131,25 @@ func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.SigninLockoutService != nil {
+		if err := h.SigninLockoutService.RecordSigninSuccess(ctx, req.Username, addr); err != nil {
+			h.Logger.Error("Failed to record sign-in", zap.Error(err))
+		}
+	}
+
 	encodeCookieSession(w, s)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// remoteHost returns the host of the remote address of r.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 type signinRequest struct {
 	Username string
 	Password string
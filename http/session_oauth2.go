@@ -0,0 +1,529 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	gojwt "github.com/dgrijalva/jwt-go"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	oauth2SigninPath   = "/api/v2/signin/oauth2"
+	oauth2ProviderPath = "/api/v2/signin/oauth2/:provider"
+	oauth2CallbackPath = "/api/v2/signin/oauth2/:provider/callback"
+)
+
+// OAuth2 providers supported by OAuth2Config.
+const (
+	OAuth2ProviderGithub = "github"
+	OAuth2ProviderGoogle = "google"
+	OAuth2ProviderHeroku = "heroku"
+	OAuth2ProviderOIDC   = "oidc"
+)
+
+// OAuth2Config configures the sign-in of users with an OAuth2 or OpenID Connect provider.
+type OAuth2Config struct {
+	// Provider is the kind of the provider: github, google, heroku or oidc.
+	Provider string
+	// Name is the name of the provider in the sign-in routes. It defaults to Provider.
+	Name string
+
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the URL of the callback route of the provider, such as
+	// https://influxdb.example.com/api/v2/signin/oauth2/oidc/callback.
+	RedirectURL string
+	// Scopes are the scopes requested from an oidc provider, openid, email and profile by default.
+	Scopes []string
+
+	// AuthURL, TokenURL and UserInfoURL are the endpoints of an oidc provider.
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+	// JWKSURL verifies the id_token of an oidc provider, whose claims are then used instead of the UserInfoURL.
+	// Issuer, when set, is the required issuer of the id_token.
+	JWKSURL string
+	Issuer  string
+
+	// UsernameClaim is the claim of an oidc provider mapped to the name of the user, email by default.
+	UsernameClaim string
+	// GroupsClaim is the claim of an oidc provider listing the groups of the user, groups by default.
+	GroupsClaim string
+	// Organizations restricts the sign-in to the members of the organizations (github, heroku)
+	// or the email domains (google, oidc) of the provider.
+	Organizations []string
+
+	// Users sign in as the user linked to their identity at the provider.
+	// AutoProvision creates and links the users signing in for the first time, and adds the users
+	// as members of the organizations mapped to their groups by GroupOrgs.
+	AutoProvision bool
+	GroupOrgs     map[string]string
+
+	// Secret signs the state of the sign-in flows. A random secret is generated if it is empty,
+	// in which case a sign-in flow must complete on the instance it started on.
+	Secret string
+}
+
+// ProviderName returns the name of the provider in the sign-in routes.
+func (c *OAuth2Config) ProviderName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Provider
+}
+
+// NewOAuth2Provider returns the chronograf OAuth2 provider configured by c.
+func NewOAuth2Provider(c *OAuth2Config, log chronograf.Logger) (oauth2.Provider, error) {
+	switch c.Provider {
+	case OAuth2ProviderGithub:
+		return &oauth2.Github{
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			Orgs:         c.Organizations,
+			Logger:       log,
+		}, nil
+	case OAuth2ProviderGoogle:
+		return &oauth2.Google{
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			RedirectURL:  c.RedirectURL,
+			Domains:      c.Organizations,
+			Logger:       log,
+		}, nil
+	case OAuth2ProviderHeroku:
+		return &oauth2.Heroku{
+			ClientID:      c.ClientID,
+			ClientSecret:  c.ClientSecret,
+			Organizations: c.Organizations,
+			Logger:        log,
+		}, nil
+	case OAuth2ProviderOIDC:
+		if c.AuthURL == "" || c.TokenURL == "" {
+			return nil, fmt.Errorf("the auth and token urls of the oidc provider are required")
+		}
+		if c.UserInfoURL == "" && c.JWKSURL == "" {
+			return nil, fmt.Errorf("the userinfo or jwks url of the oidc provider is required")
+		}
+
+		scopes := c.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "email", "profile"}
+		}
+		usernameClaim := c.UsernameClaim
+		if usernameClaim == "" {
+			usernameClaim = "email"
+		}
+		groupsClaim := c.GroupsClaim
+		if groupsClaim == "" {
+			groupsClaim = "groups"
+		}
+
+		return &oidcProvider{
+			Generic: oauth2.Generic{
+				PageName:       c.ProviderName(),
+				ClientID:       c.ClientID,
+				ClientSecret:   c.ClientSecret,
+				RequiredScopes: scopes,
+				Domains:        c.Organizations,
+				RedirectURL:    c.RedirectURL,
+				AuthURL:        c.AuthURL,
+				TokenURL:       c.TokenURL,
+				APIURL:         c.UserInfoURL,
+				APIKey:         usernameClaim,
+				Logger:         log,
+			},
+			issuer:      c.Issuer,
+			groupsClaim: groupsClaim,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown oauth2 provider %q", c.Provider)
+	}
+}
+
+// oidcProvider is a generic OAuth2 provider reading the groups of the users from a claim,
+// and verifying the audience and issuer of the id_token.
+type oidcProvider struct {
+	oauth2.Generic
+	issuer      string
+	groupsClaim string
+}
+
+// Group returns the comma delimited groups of the user from the userinfo endpoint.
+func (p *oidcProvider) Group(provider *http.Client) (string, error) {
+	r, err := provider.Get(p.APIURL)
+	if err != nil {
+		return "", err
+	}
+	defer r.Body.Close()
+
+	var claims map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&claims); err != nil {
+		return "", err
+	}
+	return claimGroups(claims[p.groupsClaim]), nil
+}
+
+// PrincipalIDFromClaims returns the name of the user from the claims of a verified id_token.
+func (p *oidcProvider) PrincipalIDFromClaims(claims gojwt.MapClaims) (string, error) {
+	if !claimHas(claims["aud"], p.ClientID) {
+		return "", fmt.Errorf("id_token is not issued for client %s", p.ClientID)
+	}
+	if p.issuer != "" && claims["iss"] != p.issuer {
+		return "", fmt.Errorf("id_token is not issued by %s", p.issuer)
+	}
+
+	id, err := p.Generic.PrincipalIDFromClaims(claims)
+	if err != nil {
+		return "", err
+	}
+	if len(p.Domains) > 0 && !claimHasDomain(id, p.Domains) {
+		return "", fmt.Errorf("not a member of required domain")
+	}
+	return id, nil
+}
+
+// GroupFromClaims returns the comma delimited groups of the user from the claims of a verified id_token.
+func (p *oidcProvider) GroupFromClaims(claims gojwt.MapClaims) (string, error) {
+	return claimGroups(claims[p.groupsClaim]), nil
+}
+
+// claimGroups returns the comma delimited groups of a string or array claim.
+func claimGroups(claim interface{}) string {
+	switch claim := claim.(type) {
+	case string:
+		return claim
+	case []interface{}:
+		groups := make([]string, 0, len(claim))
+		for _, g := range claim {
+			if g, ok := g.(string); ok {
+				groups = append(groups, g)
+			}
+		}
+		return strings.Join(groups, ",")
+	default:
+		return ""
+	}
+}
+
+// claimHas returns whether the string or array claim contains v.
+func claimHas(claim interface{}, v string) bool {
+	switch claim := claim.(type) {
+	case string:
+		return claim == v
+	case []interface{}:
+		for _, c := range claim {
+			if c == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func claimHasDomain(email string, domains []string) bool {
+	for _, d := range domains {
+		if strings.HasSuffix(email, "@"+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// newOAuth2Mux returns the chronograf mux serving the sign-in flow configured by c,
+// that signs users in with sessions of a.
+func newOAuth2Mux(c *OAuth2Config, a *sessionAuthenticator, log *zap.Logger) (*oauth2.AuthMux, error) {
+	clog := &chronografLogger{log.Sugar()}
+	p, err := NewOAuth2Provider(c, clog)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := c.Secret
+	if secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		secret = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	useIDToken := c.Provider == OAuth2ProviderOIDC && c.JWKSURL != ""
+	mux := oauth2.NewAuthMux(p, a, oauth2.NewJWT(secret, c.JWKSURL), "/", clog, useIDToken)
+	mux.FailureURL = "/signin"
+	return mux, nil
+}
+
+// handleOAuth2Providers is the HTTP handler for the GET /api/v2/signin/oauth2 route.
+func (h *SessionHandler) handleOAuth2Providers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res := oauth2ProvidersResponse{
+		Providers: []oauth2ProviderResponse{},
+	}
+	names := make([]string, 0, len(h.oauth2Muxes))
+	for name := range h.oauth2Muxes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		res.Providers = append(res.Providers, oauth2ProviderResponse{
+			Name: name,
+			Links: map[string]string{
+				"signin":   fmt.Sprintf("%s/%s", oauth2SigninPath, name),
+				"callback": fmt.Sprintf("%s/%s/callback", oauth2SigninPath, name),
+			},
+		})
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type oauth2ProvidersResponse struct {
+	Providers []oauth2ProviderResponse `json:"providers"`
+}
+
+type oauth2ProviderResponse struct {
+	Name  string            `json:"name"`
+	Links map[string]string `json:"links"`
+}
+
+// handleOAuth2Signin is the HTTP handler for the GET /api/v2/signin/oauth2/:provider route.
+// It redirects to the provider.
+func (h *SessionHandler) handleOAuth2Signin(w http.ResponseWriter, r *http.Request) {
+	mux, err := h.oauth2Mux(r)
+	if err != nil {
+		h.HandleHTTPError(r.Context(), err, w)
+		return
+	}
+	mux.Login().ServeHTTP(w, r)
+}
+
+// handleOAuth2Callback is the HTTP handler for the GET /api/v2/signin/oauth2/:provider/callback route.
+// It signs in the user authenticated by the provider, and redirects to the UI.
+func (h *SessionHandler) handleOAuth2Callback(w http.ResponseWriter, r *http.Request) {
+	mux, err := h.oauth2Mux(r)
+	if err != nil {
+		h.HandleHTTPError(r.Context(), err, w)
+		return
+	}
+	// The session cookie is only sent back over TLS if it is set over TLS.
+	ctx := context.WithValue(r.Context(), secureCookieContextKey{}, r.TLS != nil)
+	mux.Callback().ServeHTTP(w, r.WithContext(ctx))
+}
+
+// secureCookieContextKey is the context key telling whether the session cookie must be secure.
+type secureCookieContextKey struct{}
+
+func (h *SessionHandler) oauth2Mux(r *http.Request) (*oauth2.AuthMux, error) {
+	name := httprouter.ParamsFromContext(r.Context()).ByName("provider")
+	mux, ok := h.oauth2Muxes[name]
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  fmt.Sprintf("oauth2 provider %q not found", name),
+		}
+	}
+	return mux, nil
+}
+
+var _ oauth2.Authenticator = (*sessionAuthenticator)(nil)
+
+// sessionAuthenticator signs in the principals authenticated by OAuth2 providers with sessions.
+type sessionAuthenticator struct {
+	config *OAuth2Config
+	log    *zap.Logger
+
+	UserService                platform.UserService
+	OrganizationService        platform.OrganizationService
+	UserResourceMappingService platform.UserResourceMappingService
+	SessionService             platform.SessionService
+}
+
+// Validate returns the principal of the session of the request.
+func (a *sessionAuthenticator) Validate(ctx context.Context, r *http.Request) (oauth2.Principal, error) {
+	key, err := decodeCookieSession(ctx, r)
+	if err != nil {
+		return oauth2.Principal{}, oauth2.ErrAuthentication
+	}
+
+	s, e := a.SessionService.FindSession(ctx, key)
+	if e != nil {
+		return oauth2.Principal{}, oauth2.ErrAuthentication
+	}
+
+	return oauth2.Principal{
+		Subject:   s.UserID.String(),
+		ExpiresAt: s.ExpiresAt,
+		IssuedAt:  s.CreatedAt,
+	}, nil
+}
+
+// Authorize signs in the user of the principal p, provisioning it when configured to,
+// and sets the cookie of its new session.
+func (a *sessionAuthenticator) Authorize(ctx context.Context, w http.ResponseWriter, p oauth2.Principal) error {
+	u, err := a.findUser(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	s, err := a.SessionService.CreateSession(ctx, u.Name)
+	if err != nil {
+		return err
+	}
+
+	secure, _ := ctx.Value(secureCookieContextKey{}).(bool)
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieSessionName,
+		Value:    s.Key,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   secure,
+	})
+	return nil
+}
+
+// Extend returns p, as sessions are renewed by the session service.
+func (a *sessionAuthenticator) Extend(ctx context.Context, w http.ResponseWriter, p oauth2.Principal) (oauth2.Principal, error) {
+	return p, nil
+}
+
+// Expire clears the session cookie.
+func (a *sessionAuthenticator) Expire(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:   cookieSessionName,
+		Path:   "/",
+		MaxAge: -1,
+	})
+}
+
+// findUser returns the user linked to the identity of p, the subject of p at its issuer.
+// With auto-provisioning, a new user is linked to the identity if none is, and the user is
+// added as a member of the organizations mapped to its groups. An existing user that is not
+// linked to the identity is never used, even if it is named after the subject.
+func (a *sessionAuthenticator) findUser(ctx context.Context, p oauth2.Principal) (*platform.User, error) {
+	if p.Subject == "" || p.Issuer == "" {
+		return nil, oauth2.ErrAuthentication
+	}
+
+	oauthID := platform.OAuthID(p.Issuer, p.Subject)
+	u, err := a.UserService.FindUser(ctx, platform.UserFilter{OAuthID: &oauthID})
+	if err != nil && platform.ErrorCode(err) != platform.ENotFound {
+		return nil, err
+	}
+	if !a.config.AutoProvision {
+		return u, err
+	}
+
+	if u == nil {
+		if u, err = a.provisionUser(ctx, p, oauthID); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, group := range strings.Split(p.Group, ",") {
+		orgName, ok := a.config.GroupOrgs[strings.TrimSpace(group)]
+		if !ok {
+			continue
+		}
+		if err := a.addOrgMember(ctx, u, orgName); err != nil {
+			return nil, err
+		}
+	}
+
+	return u, nil
+}
+
+// maxProvisionedNames is the number of names tried for a provisioned user.
+const maxProvisionedNames = 10
+
+// provisionUser creates a user linked to the identity oauthID of p. The user is named after
+// the subject of p, or after its identity if another user has that name, numbered if needed.
+func (a *sessionAuthenticator) provisionUser(ctx context.Context, p oauth2.Principal, oauthID string) (*platform.User, error) {
+	var err error
+	for i := 0; i < maxProvisionedNames; i++ {
+		u := &platform.User{
+			Name:    p.Subject,
+			OAuthID: oauthID,
+		}
+		switch i {
+		case 0:
+		case 1:
+			u.Name = oauthID
+		default:
+			u.Name = fmt.Sprintf("%s-%d", oauthID, i)
+		}
+
+		if err = a.UserService.CreateUser(ctx, u); err == nil {
+			a.log.Info("Provisioned user", zap.String("user", u.Name), zap.String("provider", p.Issuer))
+			return u, nil
+		}
+		if platform.ErrorCode(err) != platform.EConflict {
+			break
+		}
+	}
+	a.log.Info("Failed to provision user", zap.String("user", p.Subject), zap.String("provider", p.Issuer), zap.Error(err))
+	return nil, err
+}
+
+// addOrgMember adds u as a member of the organization named orgName, unless it already belongs to it.
+func (a *sessionAuthenticator) addOrgMember(ctx context.Context, u *platform.User, orgName string) error {
+	o, err := a.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &orgName})
+	if err != nil {
+		return err
+	}
+
+	ms, _, err := a.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+		UserID:       u.ID,
+		ResourceID:   o.ID,
+		ResourceType: platform.OrgsResourceType,
+	})
+	if err != nil {
+		return err
+	}
+	if len(ms) > 0 {
+		return nil
+	}
+
+	return a.UserResourceMappingService.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+		UserID:       u.ID,
+		UserType:     platform.Member,
+		ResourceID:   o.ID,
+		ResourceType: platform.OrgsResourceType,
+	})
+}
+
+// chronografLogger adapts a zap logger to the logger of the chronograf OAuth2 providers.
+type chronografLogger struct {
+	*zap.SugaredLogger
+}
+
+func (l *chronografLogger) WithField(key string, value interface{}) chronograf.Logger {
+	return &chronografLogger{l.With(key, value)}
+}
+
+func (l *chronografLogger) Writer() *io.PipeWriter {
+	r, w := io.Pipe()
+	go func() {
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			l.Info(s.Text())
+		}
+	}()
+	return w
+}
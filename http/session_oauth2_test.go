@@ -0,0 +1,282 @@
+package http_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	gojwt "github.com/dgrijalva/jwt-go"
+	platform "github.com/influxdata/influxdb"
+	platformhttp "github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap"
+)
+
+// stubIdP is an OpenID Connect provider issuing id_tokens with fixed claims.
+type stubIdP struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	cert   []byte
+	claims gojwt.MapClaims
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp := &stubIdP{key: key, cert: cert}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", idp.handleToken)
+	mux.HandleFunc("/jwks", idp.handleJWKS)
+	idp.Server = httptest.NewServer(mux)
+	return idp
+}
+
+func (idp *stubIdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, idp.claims)
+	token.Header["kid"] = "stub"
+	idToken, err := token.SignedString(idp.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "stub-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (idp *stubIdP) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []map[string]interface{}{
+			{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": "stub",
+				"x5c": []string{base64.StdEncoding.EncodeToString(idp.cert)},
+			},
+		},
+	})
+}
+
+func TestSessionHandler_OAuth2Signin(t *testing.T) {
+	tests := []struct {
+		name          string
+		autoProvision bool
+		existingUser  *platform.User
+		audience      string
+		tls           bool
+		wantSession   bool
+		wantName      string
+		wantMember    bool
+	}{
+		{
+			name:          "provisions user into organization of group",
+			autoProvision: true,
+			audience:      "influxdb",
+			wantSession:   true,
+			wantName:      "jane@example.com",
+			wantMember:    true,
+		},
+		{
+			name:         "signs in linked user",
+			existingUser: &platform.User{Name: "jane@example.com", OAuthID: "oidc:jane@example.com"},
+			audience:     "influxdb",
+			wantSession:  true,
+			wantName:     "jane@example.com",
+		},
+		{
+			name:         "sets secure session cookie over TLS",
+			existingUser: &platform.User{Name: "jane@example.com", OAuthID: "oidc:jane@example.com"},
+			audience:     "influxdb",
+			tls:          true,
+			wantSession:  true,
+			wantName:     "jane@example.com",
+		},
+		{
+			name:         "rejects user not linked to the identity",
+			existingUser: &platform.User{Name: "jane@example.com"},
+			audience:     "influxdb",
+		},
+		{
+			name:         "rejects user linked to the identity at another provider",
+			existingUser: &platform.User{Name: "jane@example.com", OAuthID: "github:jane@example.com"},
+			audience:     "influxdb",
+		},
+		{
+			name:          "provisions user named after identity over user not linked to it",
+			autoProvision: true,
+			existingUser:  &platform.User{Name: "jane@example.com"},
+			audience:      "influxdb",
+			wantSession:   true,
+			wantName:      "oidc:jane@example.com",
+			wantMember:    true,
+		},
+		{
+			name:     "rejects unknown user without auto-provisioning",
+			audience: "influxdb",
+		},
+		{
+			name:          "rejects id_token of another client",
+			autoProvision: true,
+			audience:      "other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc := kv.NewService(inmem.NewKVStore())
+			if err := svc.Initialize(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			org := &platform.Organization{Name: "acme"}
+			if err := svc.CreateOrganization(ctx, org); err != nil {
+				t.Fatal(err)
+			}
+			if tt.existingUser != nil {
+				if err := svc.CreateUser(ctx, tt.existingUser); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			idp := newStubIdP(t)
+			defer idp.Close()
+			idp.claims = gojwt.MapClaims{
+				"iss":    idp.URL,
+				"sub":    "jane",
+				"aud":    []string{tt.audience},
+				"email":  "jane@example.com",
+				"groups": []string{"eng", "ops"},
+				"iat":    time.Now().Unix(),
+				"exp":    time.Now().Add(time.Hour).Unix(),
+			}
+
+			h := platformhttp.NewSessionHandler(&platformhttp.SessionBackend{
+				Logger:                     zap.NewNop(),
+				HTTPErrorHandler:           platformhttp.ErrorHandler(0),
+				SessionService:             svc,
+				PasswordsService:           svc,
+				UserService:                svc,
+				OrganizationService:        svc,
+				UserResourceMappingService: svc,
+				OAuth2Configs: []*platformhttp.OAuth2Config{
+					{
+						Provider:      platformhttp.OAuth2ProviderOIDC,
+						ClientID:      "influxdb",
+						ClientSecret:  "secret",
+						RedirectURL:   "http://influxdb/api/v2/signin/oauth2/oidc/callback",
+						AuthURL:       idp.URL + "/authorize",
+						TokenURL:      idp.URL + "/token",
+						JWKSURL:       idp.URL + "/jwks",
+						Issuer:        idp.URL,
+						AutoProvision: tt.autoProvision,
+						GroupOrgs:     map[string]string{"eng": "acme"},
+					},
+				},
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", "http://influxdb/api/v2/signin/oauth2/oidc", nil))
+			if w.Code != http.StatusTemporaryRedirect {
+				t.Fatalf("unexpected signin status %d: %s", w.Code, w.Body.String())
+			}
+			loc, err := url.Parse(w.Header().Get("Location"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := loc.Scheme+"://"+loc.Host+loc.Path, idp.URL+"/authorize"; got != want {
+				t.Fatalf("unexpected redirect %s, want %s", got, want)
+			}
+
+			q := url.Values{
+				"state": {loc.Query().Get("state")},
+				"code":  {"stub-code"},
+			}
+			w = httptest.NewRecorder()
+			scheme := "http"
+			if tt.tls {
+				scheme = "https"
+			}
+			h.ServeHTTP(w, httptest.NewRequest("GET", scheme+"://influxdb/api/v2/signin/oauth2/oidc/callback?"+q.Encode(), nil))
+			if w.Code != http.StatusTemporaryRedirect {
+				t.Fatalf("unexpected callback status %d: %s", w.Code, w.Body.String())
+			}
+
+			var session *http.Cookie
+			for _, c := range w.Result().Cookies() {
+				if c.Name == "session" {
+					session = c
+				}
+			}
+			if got := session != nil; got != tt.wantSession {
+				t.Fatalf("got session %v, want %v (redirected to %s)", got, tt.wantSession, w.Header().Get("Location"))
+			}
+			if !tt.wantSession {
+				if got := w.Header().Get("Location"); got != "/signin" {
+					t.Errorf("unexpected redirect %s, want /signin", got)
+				}
+				return
+			}
+			if session.Secure != tt.tls {
+				t.Errorf("got secure session cookie %v, want %v", session.Secure, tt.tls)
+			}
+
+			s, err := svc.FindSession(ctx, session.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u, err := svc.FindUserByID(ctx, s.UserID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.Name != tt.wantName || u.OAuthID != "oidc:jane@example.com" {
+				t.Errorf("unexpected user %s linked to %q", u.Name, u.OAuthID)
+			}
+
+			ms, _, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+				UserID:       u.ID,
+				ResourceID:   org.ID,
+				ResourceType: platform.OrgsResourceType,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(ms) == 1 && ms[0].UserType == platform.Member; got != tt.wantMember {
+				t.Errorf("got member %v, want %v", got, tt.wantMember)
+			}
+		})
+	}
+}
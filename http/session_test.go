@@ -11,6 +11,8 @@ import (
 
 	platform "github.com/influxdata/influxdb"
 	platformhttp "github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
 )
 
@@ -99,3 +101,53 @@ func TestSessionHandler_handleSignin(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionHandler_handleSigninLockout(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore(), kv.ServiceConfig{
+		SessionLength: platform.DefaultSessionLength,
+		SigninLockoutPolicy: platform.SigninLockoutPolicy{
+			MaxAttempts: 2,
+			Duration:    time.Hour,
+			MaxDuration: time.Hour,
+		},
+	})
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUser(ctx, &platform.User{Name: "user1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetPassword(ctx, "user1", "supersecret"); err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewMockSessionBackend()
+	b.HTTPErrorHandler = platformhttp.ErrorHandler(0)
+	b.PasswordsService = svc
+	b.SessionService = svc
+	b.SigninLockoutService = svc
+	h := platformhttp.NewSessionHandler(b)
+
+	signin := func(password string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/signin", nil)
+		r.SetBasicAuth("user1", password)
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	for _, want := range []struct {
+		password string
+		code     int
+	}{
+		{password: "supersecret", code: http.StatusNoContent},
+		{password: "wrong", code: http.StatusUnauthorized},
+		{password: "wrong", code: http.StatusUnauthorized},
+		{password: "supersecret", code: http.StatusTooManyRequests},
+	} {
+		if got := signin(want.password); got != want.code {
+			t.Fatalf("sign-in with %q: got status %d, want %d", want.password, got, want.code)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/repl"
 	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/influxql"
 	"github.com/julienschmidt/httprouter"
@@ -85,6 +86,9 @@ type SourceBackend struct {
 	LabelService    platform.LabelService
 	BucketService   platform.BucketService
 	NewQueryService func(s *platform.Source) (query.ProxyQueryService, error)
+
+	// CompilerMappings creates the compilers of the queries of self sources.
+	CompilerMappings flux.CompilerMappings
 }
 
 // NewSourceBackend returns a new instance of SourceBackend.
@@ -93,10 +97,11 @@ func NewSourceBackend(b *APIBackend) *SourceBackend {
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		Logger:           b.Logger.With(zap.String("handler", "source")),
 
-		SourceService:   b.SourceService,
-		LabelService:    b.LabelService,
-		BucketService:   b.BucketService,
-		NewQueryService: b.NewQueryService,
+		SourceService:    b.SourceService,
+		LabelService:     b.LabelService,
+		BucketService:    b.BucketService,
+		NewQueryService:  b.NewQueryService,
+		CompilerMappings: b.CompilerMappings,
 	}
 }
 
@@ -112,6 +117,9 @@ type SourceHandler struct {
 	// TODO(desa): this was done so in order to remove an import cycle and to allow
 	// for http mocking.
 	NewQueryService func(s *platform.Source) (query.ProxyQueryService, error)
+
+	// CompilerMappings creates the compilers of the queries of self sources.
+	CompilerMappings flux.CompilerMappings
 }
 
 // NewSourceHandler returns a new instance of SourceHandler.
@@ -121,10 +129,11 @@ func NewSourceHandler(b *SourceBackend) *SourceHandler {
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		Logger:           b.Logger,
 
-		SourceService:   b.SourceService,
-		LabelService:    b.LabelService,
-		BucketService:   b.BucketService,
-		NewQueryService: b.NewQueryService,
+		SourceService:    b.SourceService,
+		LabelService:     b.LabelService,
+		BucketService:    b.BucketService,
+		NewQueryService:  b.NewQueryService,
+		CompilerMappings: b.CompilerMappings,
 	}
 
 	h.HandlerFunc("POST", "/api/v2/sources", h.handlePostSource)
@@ -140,7 +149,7 @@ func NewSourceHandler(b *SourceBackend) *SourceHandler {
 	return h
 }
 
-func decodeSourceQueryRequest(r *http.Request) (*query.ProxyRequest, error) {
+func decodeSourceQueryRequest(r *http.Request, mappings flux.CompilerMappings) (*query.ProxyRequest, error) {
 	// starts here
 	request := struct {
 		Spec           *flux.Spec  `json:"spec"`
@@ -174,12 +183,17 @@ func decodeSourceQueryRequest(r *http.Request) (*query.ProxyRequest, error) {
 			Spec: request.Spec,
 		}
 	case influxql.CompilerType:
-		req.Request.Compiler = &influxql.Compiler{
-			Cluster: request.Cluster,
-			DB:      request.DB,
-			RP:      request.RP,
-			Query:   request.Query,
+		c := &influxql.Compiler{}
+		if create, ok := mappings[influxql.CompilerType]; ok {
+			if ic, ok := create().(*influxql.Compiler); ok {
+				c = ic
+			}
 		}
+		c.Cluster = request.Cluster
+		c.DB = request.DB
+		c.RP = request.RP
+		c.Query = request.Query
+		req.Request.Compiler = c
 	default:
 		return nil, fmt.Errorf("compiler type not supported")
 	}
@@ -196,7 +210,7 @@ func (h *SourceHandler) handlePostSourceQuery(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	req, err := decodeSourceQueryRequest(r)
+	req, err := decodeSourceQueryRequest(r, h.CompilerMappings)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
@@ -208,6 +222,24 @@ func (h *SourceHandler) handlePostSourceQuery(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Queries of self sources are executed locally with the authorization of the request.
+	if s.Type == platform.SelfSourceType {
+		if !req.Request.OrganizationID.Valid() {
+			req.Request.OrganizationID = s.OrganizationID
+		}
+		a, err := pcontext.GetAuthorizer(ctx)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		auth, err := queryAuthorization(a, req.Request.OrganizationID)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		req.Request.Authorization = auth
+	}
+
 	querySvc, err := h.NewQueryService(s)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
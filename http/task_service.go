@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
@@ -13,12 +14,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/influxdata/flux/csv"
 	influxdb "github.com/influxdata/influxdb"
 	platform "github.com/influxdata/influxdb"
 	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/backend/executor"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -35,7 +39,9 @@ type TaskBackend struct {
 	UserResourceMappingService platform.UserResourceMappingService
 	LabelService               platform.LabelService
 	UserService                platform.UserService
+	GroupService               platform.GroupService
 	BucketService              platform.BucketService
+	QueryService               query.QueryService
 }
 
 // NewTaskBackend returns a new instance of TaskBackend.
@@ -49,7 +55,9 @@ func NewTaskBackend(b *APIBackend) *TaskBackend {
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
+		GroupService:               b.GroupService,
 		BucketService:              b.BucketService,
+		QueryService:               b.QueryService,
 	}
 }
 
@@ -66,10 +74,12 @@ type TaskHandler struct {
 	LabelService               platform.LabelService
 	UserService                platform.UserService
 	BucketService              platform.BucketService
+	QueryService               query.QueryService
 }
 
 const (
 	tasksPath              = "/api/v2/tasks"
+	tasksDryRunPath        = "/api/v2/tasks/dryrun"
 	tasksIDPath            = "/api/v2/tasks/:id"
 	tasksIDLogsPath        = "/api/v2/tasks/:id/logs"
 	tasksIDMembersPath     = "/api/v2/tasks/:id/members"
@@ -98,6 +108,7 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		QueryService:               b.QueryService,
 	}
 
 	h.HandlerFunc("GET", tasksPath, h.handleGetTasks)
@@ -117,6 +128,7 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 		UserType:                   platform.Member,
 		UserResourceMappingService: b.UserResourceMappingService,
 		UserService:                b.UserService,
+		GroupService:               b.GroupService,
 	}
 	h.HandlerFunc("POST", tasksIDMembersPath, newPostMemberHandler(memberBackend))
 	h.HandlerFunc("GET", tasksIDMembersPath, newGetMembersHandler(memberBackend))
@@ -129,6 +141,7 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 		UserType:                   platform.Owner,
 		UserResourceMappingService: b.UserResourceMappingService,
 		UserService:                b.UserService,
+		GroupService:               b.GroupService,
 	}
 	h.HandlerFunc("POST", tasksIDOwnersPath, newPostMemberHandler(ownerBackend))
 	h.HandlerFunc("GET", tasksIDOwnersPath, newGetMembersHandler(ownerBackend))
@@ -153,6 +166,15 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	return h
 }
 
+// ServeHTTP routes task dry runs, whose path would conflict with the task ID routes of the router, then delegates to the router.
+func (h *TaskHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "POST" && r.URL.Path == tasksDryRunPath {
+		h.handlePostTaskDryRun(w, r)
+		return
+	}
+	h.Router.ServeHTTP(w, r)
+}
+
 type taskResponse struct {
 	Links  map[string]string `json:"links"`
 	Labels []platform.Label  `json:"labels"`
@@ -1186,6 +1208,109 @@ func decodeRetryRunRequest(ctx context.Context, r *http.Request) (*retryRunReque
 	}, nil
 }
 
+// TaskDryRunRequest is the request to execute a task script without writing its data.
+type TaskDryRunRequest struct {
+	Flux           string      `json:"flux"`
+	OrganizationID platform.ID `json:"orgID,omitempty"`
+	Organization   string      `json:"org,omitempty"`
+	// ScheduledFor is the simulated time of the run, in RFC3339, used as the "now" option of the script.
+	// It defaults to the current time.
+	ScheduledFor string `json:"scheduledFor,omitempty"`
+}
+
+func decodeTaskDryRunRequest(ctx context.Context, r *http.Request) (*TaskDryRunRequest, time.Time, error) {
+	req := &TaskDryRunRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, time.Time{}, err
+	}
+	if req.Flux == "" {
+		return nil, time.Time{}, errors.New("missing flux")
+	}
+
+	scheduledFor := time.Now().UTC()
+	if req.ScheduledFor != "" {
+		t, err := time.Parse(time.RFC3339, req.ScheduledFor)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		scheduledFor = t
+	}
+	return req, scheduledFor, nil
+}
+
+// handlePostTaskDryRun executes a task script as a run would, and responds with the data its to() calls would have written, as annotated CSV.
+func (h *TaskHandler) handlePostTaskDryRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, scheduledFor, err := decodeTaskDryRunRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Err:  err,
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request",
+		}, w)
+		return
+	}
+
+	tc := platform.TaskCreate{OrganizationID: req.OrganizationID, Organization: req.Organization}
+	if err := h.populateTaskCreateOrg(ctx, &tc); err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Err: err,
+			Msg: "could not identify organization",
+		}, w)
+		return
+	}
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	var auth *platform.Authorization
+	switch a := a.(type) {
+	case *platform.Authorization:
+		auth = a
+	case *platform.Session:
+		auth = a.EphemeralAuth(tc.OrganizationID)
+	default:
+		h.HandleHTTPError(ctx, platform.ErrAuthorizerNotSupported, w)
+		return
+	}
+
+	// A dry run is allowed to whoever could create the task.
+	p, err := platform.NewPermission(platform.WriteAction, platform.TasksResourceType, tc.OrganizationID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if !a.Allowed(*p) {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  fmt.Sprintf("%s is unauthorized", p),
+		}, w)
+		return
+	}
+
+	res, _, err := executor.DryRun(ctx, h.QueryService, auth, tc.OrganizationID, req.Flux, scheduledFor)
+	if err != nil {
+		if _, ok := err.(*platform.Error); !ok {
+			err = &platform.Error{
+				Err:  err,
+				Code: platform.EInvalid,
+				Msg:  "failed to execute task",
+			}
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := csv.NewResultEncoder(csv.DefaultEncoderConfig()).Encode(w, res); err != nil {
+		h.logger.Info("Error writing dry run response to client", zap.Error(err))
+	}
+}
+
 func (h *TaskHandler) populateTaskCreateOrg(ctx context.Context, tc *platform.TaskCreate) error {
 	if tc.OrganizationID.Valid() && tc.Organization != "" {
 		return nil
@@ -1394,6 +1519,45 @@ func (t TaskService) CreateTask(ctx context.Context, tc platform.TaskCreate) (*p
 	return &tr.Task, nil
 }
 
+// DryRun executes a task script without writing its data, and copies the data that would have been written to w, as annotated CSV.
+func (t TaskService) DryRun(ctx context.Context, dr TaskDryRunRequest, w io.Writer) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, tasksDryRunPath)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(dr)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
 // UpdateTask updates a single task with changeset.
 func (t TaskService) UpdateTask(ctx context.Context, id platform.ID, upd platform.TaskUpdate) (*platform.Task, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
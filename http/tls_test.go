@@ -0,0 +1,90 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	v, err := ParseTLSVersion("1.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != tls.VersionTLS12 {
+		t.Fatalf("unexpected version %x", v)
+	}
+	if _, err := ParseTLSVersion("2.0"); err == nil {
+		t.Fatal("expected an error for an unknown version")
+	}
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	suites, err := ParseCipherSuites([]string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", " tls_ecdhe_ecdsa_with_aes_256_gcm_sha384"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}
+	if len(suites) != len(exp) || suites[0] != exp[0] || suites[1] != exp[1] {
+		t.Fatalf("unexpected cipher suites %v", suites)
+	}
+	if _, err := ParseCipherSuites([]string{"TLS_NOT_A_SUITE"}); err == nil {
+		t.Fatal("expected an error for an unknown cipher suite")
+	}
+	for _, name := range []string{"TLS_RSA_WITH_RC4_128_SHA", "TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA", "tls_ecdhe_rsa_with_aes_128_cbc_sha256"} {
+		if _, err := ParseCipherSuites([]string{name}); err == nil {
+			t.Fatalf("expected an error for the insecure cipher suite %s", name)
+		}
+	}
+}
+
+func TestCertificateReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxdb-tls-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	commonName := func(r *CertificateReloader) string {
+		cert, err := r.GetCertificate(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c.Subject.CommonName
+	}
+
+	_, certPath, keyPath := platformtesting.WriteCertificate(t, dir, "first")
+	r, err := NewCertificateReloader(certPath, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := commonName(r); got != "first" {
+		t.Fatalf("unexpected certificate %q", got)
+	}
+
+	platformtesting.WriteCertificate(t, dir, "second")
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := commonName(r); got != "second" {
+		t.Fatalf("unexpected certificate %q after reload", got)
+	}
+
+	if err := ioutil.WriteFile(keyPath, []byte("garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected an error reloading an invalid key")
+	}
+	if got := commonName(r); got != "second" {
+		t.Fatalf("previous certificate should be kept, got %q", got)
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"strings"
 )
 
-const tokenScheme = "Token " // TODO(goller): I'd like this to be Bearer
+const (
+	tokenScheme  = "Token " // TODO(goller): I'd like this to be Bearer
+	bearerScheme = "Bearer "
+)
 
 // errors
 var (
@@ -16,15 +19,19 @@ var (
 )
 
 // GetToken will parse the token from http Authorization Header.
+// Both the Token and Bearer schemes are accepted, the latter being the one
+// used by clients such as Prometheus.
 func GetToken(r *http.Request) (string, error) {
 	header := r.Header.Get("Authorization")
 	if header == "" {
 		return "", ErrAuthHeaderMissing
 	}
-	if !strings.HasPrefix(header, tokenScheme) {
-		return "", ErrAuthBadScheme
+	for _, scheme := range []string{tokenScheme, bearerScheme} {
+		if strings.HasPrefix(header, scheme) {
+			return header[len(scheme):], nil
+		}
 	}
-	return header[len(tokenScheme):], nil
+	return "", ErrAuthBadScheme
 }
 
 // SetToken adds the token to the request.
@@ -15,18 +15,7 @@ import (
 // $no_proxy) environment variables.
 // This is the same as http.DefaultTransport.
 //
-var defaultTransport http.RoundTripper = &http.Transport{
-	Proxy: http.ProxyFromEnvironment,
-	DialContext: (&net.Dialer{
-		Timeout:   30 * time.Second,
-		KeepAlive: 30 * time.Second,
-		DualStack: true,
-	}).DialContext,
-	MaxIdleConns:          100,
-	IdleConnTimeout:       90 * time.Second,
-	TLSHandshakeTimeout:   10 * time.Second,
-	ExpectContinueTimeout: 1 * time.Second,
-}
+var defaultTransport http.RoundTripper = newTransport(nil)
 
 // skipVerifyTransport is the default implementation of Transport and is
 // used by traceClient (NewClient with insecure set to true). It establishes network connections as needed
@@ -35,17 +24,21 @@ var defaultTransport http.RoundTripper = &http.Transport{
 // $no_proxy) environment variables.
 // This is the same as http.DefaultTransport but with TLS skip verify.
 //
-var skipVerifyTransport = &http.Transport{
-	Proxy: http.ProxyFromEnvironment,
-	DialContext: (&net.Dialer{
-		Timeout:   30 * time.Second,
-		KeepAlive: 30 * time.Second,
-		DualStack: true,
-	}).DialContext,
-	MaxIdleConns:          100,
-	IdleConnTimeout:       90 * time.Second,
-	TLSHandshakeTimeout:   10 * time.Second,
-	ExpectContinueTimeout: 1 * time.Second,
-	// This is the value that changes between this and http.DefaultTransport
-	TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+var skipVerifyTransport http.RoundTripper = newTransport(&tls.Config{InsecureSkipVerify: true})
+
+// newTransport returns a transport with the settings of http.DefaultTransport and the given TLS configuration.
+func newTransport(cfg *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       cfg,
+	}
 }
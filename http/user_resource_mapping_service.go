@@ -34,12 +34,25 @@ func newResourceUserResponse(u *platform.User, userType platform.UserType) *reso
 	}
 }
 
+type resourceGroupResponse struct {
+	Role platform.UserType `json:"role"`
+	*groupResponse
+}
+
+func newResourceGroupResponse(g *platform.Group, userType platform.UserType) *resourceGroupResponse {
+	return &resourceGroupResponse{
+		Role:          userType,
+		groupResponse: newGroupResponse(g),
+	}
+}
+
 type resourceUsersResponse struct {
-	Links map[string]string       `json:"links"`
-	Users []*resourceUserResponse `json:"users"`
+	Links  map[string]string        `json:"links"`
+	Users  []*resourceUserResponse  `json:"users"`
+	Groups []*resourceGroupResponse `json:"groups,omitempty"`
 }
 
-func newResourceUsersResponse(opts platform.FindOptions, f platform.UserResourceMappingFilter, users []*platform.User) *resourceUsersResponse {
+func newResourceUsersResponse(opts platform.FindOptions, f platform.UserResourceMappingFilter, users []*platform.User, groups []*platform.Group) *resourceUsersResponse {
 	rs := resourceUsersResponse{
 		Links: map[string]string{
 			"self": fmt.Sprintf("/api/v2/%s/%s/%ss", f.ResourceType, f.ResourceID, f.UserType),
@@ -50,6 +63,9 @@ func newResourceUsersResponse(opts platform.FindOptions, f platform.UserResource
 	for _, user := range users {
 		rs.Users = append(rs.Users, newResourceUserResponse(user, f.UserType))
 	}
+	for _, group := range groups {
+		rs.Groups = append(rs.Groups, newResourceGroupResponse(group, f.UserType))
+	}
 	return &rs
 }
 
@@ -64,6 +80,8 @@ type MemberBackend struct {
 
 	UserResourceMappingService platform.UserResourceMappingService
 	UserService                platform.UserService
+	// GroupService, if set, allows groups to be mapped to the resource.
+	GroupService platform.GroupService
 }
 
 // newPostMemberHandler returns a handler func for a POST to /members or /owners endpoints
@@ -77,12 +95,6 @@ func newPostMemberHandler(b MemberBackend) http.HandlerFunc {
 			return
 		}
 
-		user, err := b.UserService.FindUserByID(ctx, req.MemberID)
-		if err != nil {
-			b.HandleHTTPError(ctx, err, w)
-			return
-		}
-
 		mapping := &platform.UserResourceMapping{
 			ResourceID:   req.ResourceID,
 			ResourceType: b.ResourceType,
@@ -90,13 +102,39 @@ func newPostMemberHandler(b MemberBackend) http.HandlerFunc {
 			UserType:     b.UserType,
 		}
 
+		var res interface{}
+		if req.MappingType == platform.GroupMappingType {
+			if b.GroupService == nil {
+				b.HandleHTTPError(ctx, &platform.Error{
+					Code: platform.EInvalid,
+					Msg:  fmt.Sprintf("groups cannot be mapped to %s", b.ResourceType),
+				}, w)
+				return
+			}
+
+			group, err := b.GroupService.FindGroupByID(ctx, req.MemberID)
+			if err != nil {
+				b.HandleHTTPError(ctx, err, w)
+				return
+			}
+			mapping.MappingType = platform.GroupMappingType
+			res = newResourceGroupResponse(group, b.UserType)
+		} else {
+			user, err := b.UserService.FindUserByID(ctx, req.MemberID)
+			if err != nil {
+				b.HandleHTTPError(ctx, err, w)
+				return
+			}
+			res = newResourceUserResponse(user, b.UserType)
+		}
+
 		if err := b.UserResourceMappingService.CreateUserResourceMapping(ctx, mapping); err != nil {
 			b.HandleHTTPError(ctx, err, w)
 			return
 		}
 		b.Logger.Debug("member/owner created", zap.String("mapping", fmt.Sprint(mapping)))
 
-		if err := encodeResponse(ctx, w, http.StatusCreated, newResourceUserResponse(user, b.UserType)); err != nil {
+		if err := encodeResponse(ctx, w, http.StatusCreated, res); err != nil {
 			b.HandleHTTPError(ctx, err, w)
 			return
 		}
@@ -104,8 +142,16 @@ func newPostMemberHandler(b MemberBackend) http.HandlerFunc {
 }
 
 type postMemberRequest struct {
-	MemberID   platform.ID
-	ResourceID platform.ID
+	MemberID    platform.ID
+	MappingType platform.MappingType
+	ResourceID  platform.ID
+}
+
+// postMemberBody is the body of a request to add a user,
+// or a group if its mapping type is set to group, to a resource.
+type postMemberBody struct {
+	ID          platform.ID          `json:"id"`
+	MappingType platform.MappingType `json:"mappingType"`
 }
 
 func decodePostMemberRequest(ctx context.Context, r *http.Request) (*postMemberRequest, error) {
@@ -123,7 +169,7 @@ func decodePostMemberRequest(ctx context.Context, r *http.Request) (*postMemberR
 		return nil, err
 	}
 
-	u := &platform.User{}
+	u := &postMemberBody{}
 	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
 		return nil, err
 	}
@@ -136,8 +182,9 @@ func decodePostMemberRequest(ctx context.Context, r *http.Request) (*postMemberR
 	}
 
 	return &postMemberRequest{
-		MemberID:   u.ID,
-		ResourceID: rid,
+		MemberID:    u.ID,
+		MappingType: u.MappingType,
+		ResourceID:  rid,
 	}, nil
 }
 
@@ -167,10 +214,24 @@ func newGetMembersHandler(b MemberBackend) http.HandlerFunc {
 		}
 
 		users := make([]*platform.User, 0, len(mappings))
+		var groups []*platform.Group
 		for _, m := range mappings {
 			if m.MappingType == platform.OrgMappingType {
 				continue
 			}
+			if m.MappingType == platform.GroupMappingType {
+				if b.GroupService == nil {
+					continue
+				}
+				group, err := b.GroupService.FindGroupByID(ctx, m.UserID)
+				if err != nil {
+					b.HandleHTTPError(ctx, err, w)
+					return
+				}
+
+				groups = append(groups, group)
+				continue
+			}
 			user, err := b.UserService.FindUserByID(ctx, m.UserID)
 			if err != nil {
 				b.HandleHTTPError(ctx, err, w)
@@ -179,9 +240,9 @@ func newGetMembersHandler(b MemberBackend) http.HandlerFunc {
 
 			users = append(users, user)
 		}
-		b.Logger.Debug("members/owners retrieved", zap.String("users", fmt.Sprint(users)))
+		b.Logger.Debug("members/owners retrieved", zap.String("users", fmt.Sprint(users)), zap.String("groups", fmt.Sprint(groups)))
 
-		if err := encodeResponse(ctx, w, http.StatusOK, newResourceUsersResponse(opts, filter, users)); err != nil {
+		if err := encodeResponse(ctx, w, http.StatusOK, newResourceUsersResponse(opts, filter, users, groups)); err != nil {
 			b.HandleHTTPError(ctx, err, w)
 			return
 		}
@@ -276,31 +337,30 @@ func decodeDeleteMemberRequest(ctx context.Context, r *http.Request) (*deleteMem
 	}, nil
 }
 
+// FindUserResourceMappings returns the users and groups mapped to the resource of the filter
+// by the members or owners endpoint of the resource under BasePath.
 func (s *UserResourceMappingService) FindUserResourceMappings(ctx context.Context, filter platform.UserResourceMappingFilter, opt ...platform.FindOptions) ([]*platform.UserResourceMapping, int, error) {
-	url, err := NewURL(s.Addr, s.BasePath)
-	if err != nil {
-		return nil, 0, err
+	if !filter.ResourceID.Valid() {
+		return nil, 0, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "resource id is required",
+		}
 	}
 
-	query := url.Query()
-
-	// this is not how this is going to work, lol
-	if filter.ResourceID.Valid() {
-		query.Add("resourceID", filter.ResourceID.String())
-	}
-	if filter.UserID.Valid() {
-		query.Add("userID", filter.UserID.String())
+	userType := filter.UserType
+	if userType == "" {
+		userType = platform.Member
 	}
-	if filter.UserType != "" {
-		query.Add("userType", string(filter.UserType))
+
+	url, err := NewURL(s.Addr, memberTypePath(s.BasePath, filter.ResourceID, userType))
+	if err != nil {
+		return nil, 0, err
 	}
 
 	req, err := http.NewRequest("GET", url.String(), nil)
 	if err != nil {
 		return nil, 0, err
 	}
-
-	req.URL.RawQuery = query.Encode()
 	SetToken(s.Token, req)
 
 	hc := NewClient(url.Scheme, s.InsecureSkipVerify)
@@ -314,21 +374,64 @@ func (s *UserResourceMappingService) FindUserResourceMappings(ctx context.Contex
 		return nil, 0, err
 	}
 
-	// TODO(jm): make this actually work
-	return nil, 0, nil
+	// resourceMember decodes the fields of a user or group needed to map it to the resource.
+	type resourceMember struct {
+		ID   platform.ID       `json:"id"`
+		Role platform.UserType `json:"role"`
+	}
+	var rs struct {
+		Users  []resourceMember `json:"users"`
+		Groups []resourceMember `json:"groups"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rs); err != nil {
+		return nil, 0, err
+	}
+
+	ms := make([]*platform.UserResourceMapping, 0, len(rs.Users)+len(rs.Groups))
+	for _, u := range rs.Users {
+		ms = append(ms, &platform.UserResourceMapping{
+			UserID:       u.ID,
+			UserType:     u.Role,
+			ResourceType: filter.ResourceType,
+			ResourceID:   filter.ResourceID,
+		})
+	}
+	for _, g := range rs.Groups {
+		ms = append(ms, &platform.UserResourceMapping{
+			UserID:       g.ID,
+			UserType:     g.Role,
+			MappingType:  platform.GroupMappingType,
+			ResourceType: filter.ResourceType,
+			ResourceID:   filter.ResourceID,
+		})
+	}
+
+	mappings := ms[:0]
+	for _, m := range ms {
+		if filter.UserID.Valid() && filter.UserID != m.UserID {
+			continue
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, len(mappings), nil
 }
 
+// CreateUserResourceMapping adds the user or group of the mapping to the members or owners
+// of the resource under BasePath.
 func (s *UserResourceMappingService) CreateUserResourceMapping(ctx context.Context, m *platform.UserResourceMapping) error {
 	if err := m.Validate(); err != nil {
 		return err
 	}
 
-	url, err := NewURL(s.Addr, resourceIDPath(s.BasePath, m.ResourceID))
+	url, err := NewURL(s.Addr, memberTypePath(s.BasePath, m.ResourceID, m.UserType))
 	if err != nil {
 		return err
 	}
 
-	octets, err := json.Marshal(m)
+	octets, err := json.Marshal(postMemberBody{
+		ID:          m.UserID,
+		MappingType: m.MappingType,
+	})
 	if err != nil {
 		return err
 	}
@@ -349,16 +452,7 @@ func (s *UserResourceMappingService) CreateUserResourceMapping(ctx context.Conte
 	}
 	defer resp.Body.Close()
 
-	// TODO(jsternberg): Should this check for a 201 explicitly?
-	if err := CheckError(resp); err != nil {
-		return err
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(m); err != nil {
-		return err
-	}
-
-	return nil
+	return CheckError(resp)
 }
 
 func (s *UserResourceMappingService) DeleteUserResourceMapping(ctx context.Context, resourceID platform.ID, userID platform.ID) error {
@@ -387,6 +481,10 @@ func resourceIDPath(basePath string, resourceID platform.ID) string {
 	return path.Join(basePath, resourceID.String())
 }
 
+func memberTypePath(basePath string, resourceID platform.ID, userType platform.UserType) string {
+	return path.Join(basePath, resourceID.String(), string(userType)+"s")
+}
+
 func memberIDPath(basePath string, resourceID platform.ID, memberID platform.ID) string {
 	return path.Join(basePath, resourceID.String(), "members", memberID.String())
 }
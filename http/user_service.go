@@ -376,6 +376,10 @@ func decodeGetUsersRequest(ctx context.Context, r *http.Request) (*getUsersReque
 		req.filter.Name = &name
 	}
 
+	if oauthID := qp.Get("oauthID"); oauthID != "" {
+		req.filter.OAuthID = &oauthID
+	}
+
 	return req, nil
 }
 
@@ -507,7 +511,7 @@ func (s *UserService) FindUserByID(ctx context.Context, id influxdb.ID) (*influx
 
 // FindUser returns the first user that matches filter.
 func (s *UserService) FindUser(ctx context.Context, filter influxdb.UserFilter) (*influxdb.User, error) {
-	if filter.ID == nil && filter.Name == nil {
+	if filter.ID == nil && filter.Name == nil && filter.OAuthID == nil {
 		return nil, &influxdb.Error{
 			Code: influxdb.ENotFound,
 			Msg:  "user not found",
@@ -552,6 +556,9 @@ func (s *UserService) FindUsers(ctx context.Context, filter influxdb.UserFilter,
 	if filter.Name != nil {
 		query.Add("name", *filter.Name)
 	}
+	if filter.OAuthID != nil {
+		query.Add("oauthID", *filter.OAuthID)
+	}
 
 	req.URL.RawQuery = query.Encode()
 	SetToken(s.Token, req)
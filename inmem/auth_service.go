@@ -39,15 +39,15 @@ func (s *Service) PutAuthorization(ctx context.Context, a *platform.Authorizatio
 	return nil
 }
 
-// FindAuthorizationByID returns an authorization given an ID.
+// FindAuthorizationByID returns an authorization given an ID, without its token.
 func (s *Service) FindAuthorizationByID(ctx context.Context, id platform.ID) (*platform.Authorization, error) {
-	var err error
 	a, pe := s.loadAuthorization(ctx, id)
 	if pe != nil {
 		pe.Op = OpPrefix + platform.OpFindAuthorizationByID
-		err = pe
+		return nil, pe
 	}
-	return a, err
+	a.Token = ""
+	return a, nil
 }
 
 // FindAuthorizationByToken returns an authorization given a token.
@@ -109,6 +109,7 @@ func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platfor
 }
 
 // FindAuthorizations returns all authorizations matching the filter.
+// Their tokens are omitted unless they are filtered by token.
 func (s *Service) FindAuthorizations(ctx context.Context, filter platform.AuthorizationFilter, opt ...platform.FindOptions) ([]*platform.Authorization, int, error) {
 	op := OpPrefix + platform.OpFindAuthorizations
 	if filter.ID != nil {
@@ -160,6 +161,10 @@ func (s *Service) FindAuthorizations(ctx context.Context, filter platform.Author
 		}
 
 		if filterF(&a) {
+			// The token is only returned when the authorization is found by token.
+			if filter.Token == nil {
+				a.Token = ""
+			}
 			as = append(as, &a)
 		}
 
@@ -217,13 +222,14 @@ func (s *Service) DeleteAuthorization(ctx context.Context, id platform.ID) error
 	return nil
 }
 
-// UpdateAuthorization updates the status and description if available.
+// UpdateAuthorization updates the status, description and expiration if available.
+// The updated authorization is returned without its token.
 func (s *Service) UpdateAuthorization(ctx context.Context, id platform.ID, upd *platform.AuthorizationUpdate) (*platform.Authorization, error) {
 	op := OpPrefix + platform.OpUpdateAuthorization
-	a, err := s.FindAuthorizationByID(ctx, id)
-	if err != nil {
+	a, pe := s.loadAuthorization(ctx, id)
+	if pe != nil {
 		return nil, &platform.Error{
-			Err: err,
+			Err: pe,
 			Op:  op,
 		}
 	}
@@ -245,6 +251,13 @@ func (s *Service) UpdateAuthorization(ctx context.Context, id platform.ID, upd *
 	if upd.Description != nil {
 		a.Description = *upd.Description
 	}
+	if upd.ExpiresAt != nil {
+		a.ExpiresAt = upd.ExpiresAt
+	}
 
-	return a, s.PutAuthorization(ctx, a)
+	if err := s.PutAuthorization(ctx, a); err != nil {
+		return nil, err
+	}
+	a.Token = ""
+	return a, nil
 }
@@ -100,6 +100,32 @@ func (s *Service) FindUser(ctx context.Context, filter platform.UserFilter) (*pl
 		return u, nil
 	}
 
+	if filter.OAuthID != nil {
+		var u *platform.User
+
+		err := s.forEachUser(ctx, func(user *platform.User) bool {
+			if user.OAuthID == *filter.OAuthID {
+				u = user
+				return false
+			}
+			return true
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if u == nil {
+			return nil, &platform.Error{
+				Code: platform.ENotFound,
+				Op:   op,
+				Msg:  "user not found",
+			}
+		}
+
+		return u, nil
+	}
+
 	return nil, &platform.Error{
 		Code: platform.EInvalid,
 		Op:   op,
@@ -121,7 +147,7 @@ func (s *Service) FindUsers(ctx context.Context, filter platform.UserFilter, opt
 
 		return []*platform.User{u}, 1, nil
 	}
-	if filter.Name != nil {
+	if filter.Name != nil || filter.OAuthID != nil {
 		u, err := s.FindUser(ctx, filter)
 		if err != nil {
 			return nil, 0, &platform.Error{
@@ -156,6 +182,15 @@ func (s *Service) CreateUser(ctx context.Context, u *platform.User) error {
 			Msg:  fmt.Sprintf("user with name %s already exists", u.Name),
 		}
 	}
+	if u.OAuthID != "" {
+		if _, err := s.FindUser(ctx, platform.UserFilter{OAuthID: &u.OAuthID}); err == nil {
+			return &platform.Error{
+				Code: platform.EConflict,
+				Op:   OpPrefix + platform.OpCreateUser,
+				Msg:  fmt.Sprintf("user with oauth id %s already exists", u.OAuthID),
+			}
+		}
+	}
 	u.ID = s.IDGenerator.ID()
 	s.PutUser(ctx, u)
 	return nil
@@ -181,6 +216,10 @@ func (s *Service) UpdateUser(ctx context.Context, id platform.ID, upd platform.U
 		o.Name = *upd.Name
 	}
 
+	if upd.OAuthID != nil {
+		o.OAuthID = *upd.OAuthID
+	}
+
 	s.userKV.Store(o.ID.String(), o)
 
 	return o, nil
@@ -120,3 +120,29 @@ func mustBindPFlag(key string, cmd *cobra.Command) {
 		panic(err)
 	}
 }
+
+// ResolveOptions sets the options whose flag was not set on the command line
+// to their value in viper, so that configuration read by viper after the
+// options were bound, such as a configuration file, applies to them.
+// Flags and environment variables still take precedence over configuration files.
+func ResolveOptions(cmd *cobra.Command, opts []Opt) {
+	for _, o := range opts {
+		if f := cmd.Flags().Lookup(o.Flag); f != nil && f.Changed {
+			continue
+		}
+		switch destP := o.DestP.(type) {
+		case *string:
+			*destP = viper.GetString(o.Flag)
+		case *int:
+			*destP = viper.GetInt(o.Flag)
+		case *bool:
+			*destP = viper.GetBool(o.Flag)
+		case *time.Duration:
+			*destP = viper.GetDuration(o.Flag)
+		case *[]string:
+			*destP = viper.GetStringSlice(o.Flag)
+		default:
+			panic(fmt.Errorf("unknown destination type %t", o.DestP))
+		}
+	}
+}
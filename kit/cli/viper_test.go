@@ -3,7 +3,11 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func ExampleNewCommand() {
@@ -69,3 +73,44 @@ func ExampleNewCommand() {
 	// 1m0s
 	// [foo bar]
 }
+
+func ExampleResolveOptions() {
+	var host string
+	var number int
+	opts := []Opt{
+		{
+			DestP:   &host,
+			Flag:    "resolve-host",
+			Default: "localhost",
+			Desc:    "host",
+		},
+		{
+			DestP:   &number,
+			Flag:    "resolve-number",
+			Default: 2,
+			Desc:    "number",
+		},
+	}
+	cmd := &cobra.Command{
+		Use: "myprogram",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			viper.SetConfigType("toml")
+			if err := viper.ReadConfig(strings.NewReader("resolve-host = \"influxdb\"\nresolve-number = 3\n")); err != nil {
+				return err
+			}
+			ResolveOptions(cmd, opts)
+			fmt.Println(host)
+			fmt.Println(number)
+			return nil
+		},
+	}
+	BindOptions(cmd, opts)
+
+	cmd.SetArgs([]string{"--resolve-number", "4"})
+	if err := cmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	// Output:
+	// influxdb
+	// 4
+}
@@ -0,0 +1,297 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	auditLogBucket = []byte("auditlogv1")
+)
+
+var _ influxdb.AuditLogService = (*Service)(nil)
+
+func (s *Service) initializeAuditLog(ctx context.Context, tx Tx) error {
+	_, err := tx.Bucket(auditLogBucket)
+	return err
+}
+
+// encodeAuditEventKey orders audit events by time, then by ID.
+func encodeAuditEventKey(e *influxdb.AuditEvent) ([]byte, error) {
+	id, err := e.ID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	k := make([]byte, 8, 8+len(id))
+	// This needs to be big-endian so that the iteration order is preserved when scanning keys
+	binary.BigEndian.PutUint64(k, uint64(e.Time.UTC().UnixNano()))
+	return append(k, id...), nil
+}
+
+func decodeAuditEventKeyTime(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k[:8]))).UTC()
+}
+
+// AppendAuditEvent appends an event to the audit log, setting its ID and, if unset, its time.
+func (s *Service) AppendAuditEvent(ctx context.Context, e *influxdb.AuditEvent) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.appendAuditEvent(ctx, tx, e)
+	})
+}
+
+func (s *Service) appendAuditEvent(ctx context.Context, tx Tx, e *influxdb.AuditEvent) error {
+	e.ID = s.IDGenerator.ID()
+	if e.Time.IsZero() {
+		e.Time = s.Now()
+	}
+	e.Time = e.Time.UTC()
+
+	k, err := encodeAuditEventKey(e)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(e)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(auditLogBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(k, v); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// FindAuditEvents returns the audit events matching the filter in time order.
+func (s *Service) FindAuditEvents(ctx context.Context, filter influxdb.AuditEventFilter, opt ...influxdb.FindOptions) ([]*influxdb.AuditEvent, int, error) {
+	var es []*influxdb.AuditEvent
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		es, err = s.findAuditEvents(ctx, tx, filter, opt...)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return es, len(es), nil
+}
+
+func (s *Service) findAuditEvents(ctx context.Context, tx Tx, filter influxdb.AuditEventFilter, opt ...influxdb.FindOptions) ([]*influxdb.AuditEvent, error) {
+	var opts influxdb.FindOptions
+	if len(opt) > 0 {
+		opts = opt[0]
+	}
+
+	b, err := tx.Bucket(auditLogBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	// before and after report whether a key is outside of the time range
+	// on the side the iteration starts from and ends at.
+	first, next := cur.First, cur.Next
+	before := func(t time.Time) bool { return filter.Since != nil && t.Before(*filter.Since) }
+	after := func(t time.Time) bool { return filter.Until != nil && t.After(*filter.Until) }
+	if opts.Descending {
+		first, next = cur.Last, cur.Prev
+		before, after = after, before
+	}
+
+	es := []*influxdb.AuditEvent{}
+	offset := opts.Offset
+	for k, v := first(); k != nil; k, v = next() {
+		t := decodeAuditEventKeyTime(k)
+		if before(t) {
+			continue
+		}
+		if after(t) {
+			break
+		}
+
+		e := &influxdb.AuditEvent{}
+		if err := json.Unmarshal(v, e); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if filter.OrgID != nil && e.OrgID != *filter.OrgID {
+			continue
+		}
+		if filter.UserID != nil && e.UserID != *filter.UserID {
+			continue
+		}
+
+		if offset > 0 {
+			offset--
+			continue
+		}
+		es = append(es, e)
+		if opts.Limit > 0 && len(es) >= opts.Limit {
+			break
+		}
+	}
+	return es, nil
+}
+
+// auditRecordingStore records the changes of the stored resources and secrets into the
+// AuditRecording of the context of the update transactions, if any.
+// A resource is stored at its encoded ID as a JSON object holding that ID.
+type auditRecordingStore struct {
+	Store
+}
+
+type auditStoredChange struct {
+	bucket, key   []byte
+	before, after []byte
+}
+
+// Update records the changes of fn once the transaction has been committed.
+func (s *auditRecordingStore) Update(ctx context.Context, fn func(Tx) error) error {
+	rec := influxdb.AuditRecordingFromContext(ctx)
+	if rec == nil {
+		return s.Store.Update(ctx, fn)
+	}
+
+	var changes []auditStoredChange
+	if err := s.Store.Update(ctx, func(tx Tx) error {
+		changes = changes[:0]
+		return fn(&auditRecordingTx{Tx: tx, changes: &changes})
+	}); err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		if bytes.Equal(c.bucket, secretBucket) {
+			orgID, k, err := decodeSecretKey(c.key)
+			if err == nil {
+				rec.RecordSecret(orgID, k, c.before != nil, c.after != nil)
+			}
+			continue
+		}
+
+		var id influxdb.ID
+		if err := id.Decode(c.key); err != nil {
+			continue
+		}
+		before, after := auditResourceState(id, c.before), auditResourceState(id, c.after)
+		if before != nil || after != nil {
+			rec.RecordState(id, before, after)
+		}
+	}
+	return nil
+}
+
+// auditResourceState returns v if it is the stored state of the resource with the ID.
+func auditResourceState(id influxdb.ID, v []byte) []byte {
+	var s struct {
+		ID *influxdb.ID `json:"id"`
+	}
+	if v == nil || json.Unmarshal(v, &s) != nil || s.ID == nil || *s.ID != id {
+		return nil
+	}
+	return v
+}
+
+type auditRecordingTx struct {
+	Tx
+	changes *[]auditStoredChange
+}
+
+func (tx *auditRecordingTx) Bucket(b []byte) (Bucket, error) {
+	bkt, err := tx.Tx.Bucket(b)
+	if err != nil {
+		return nil, err
+	}
+	return &auditRecordingBucket{Bucket: bkt, name: b, tx: tx}, nil
+}
+
+// auditRecordingBucket captures the values replaced by the writes to the keys that may
+// be those of a resource or a secret.
+type auditRecordingBucket struct {
+	Bucket
+	name []byte
+	tx   *auditRecordingTx
+}
+
+func (b *auditRecordingBucket) recorded(key []byte) bool {
+	return len(key) == influxdb.IDLength || bytes.Equal(b.name, secretBucket)
+}
+
+func (b *auditRecordingBucket) previous(key []byte) ([]byte, error) {
+	v, err := b.Bucket.Get(key)
+	if IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return append([]byte{}, v...), nil
+}
+
+func (b *auditRecordingBucket) Put(key, value []byte) error {
+	if !b.recorded(key) {
+		return b.Bucket.Put(key, value)
+	}
+
+	before, err := b.previous(key)
+	if err != nil {
+		return err
+	}
+	if err := b.Bucket.Put(key, value); err != nil {
+		return err
+	}
+	*b.tx.changes = append(*b.tx.changes, auditStoredChange{
+		bucket: b.name,
+		key:    append([]byte{}, key...),
+		before: before,
+		after:  append([]byte{}, value...),
+	})
+	return nil
+}
+
+func (b *auditRecordingBucket) Delete(key []byte) error {
+	if !b.recorded(key) {
+		return b.Bucket.Delete(key)
+	}
+
+	before, err := b.previous(key)
+	if err != nil {
+		return err
+	}
+	if err := b.Bucket.Delete(key); err != nil {
+		return err
+	}
+	if before != nil {
+		*b.tx.changes = append(*b.tx.changes, auditStoredChange{
+			bucket: b.name,
+			key:    append([]byte{}, key...),
+			before: before,
+		})
+	}
+	return nil
+}
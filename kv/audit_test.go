@@ -0,0 +1,170 @@
+package kv_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestAuditLog(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	orgA, orgB := influxdb.ID(1), influxdb.ID(2)
+	alice, bob := influxdb.ID(10), influxdb.ID(11)
+	start := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	events := []*influxdb.AuditEvent{
+		{OrgID: orgA, UserID: alice, Path: "/0"},
+		{OrgID: orgB, UserID: alice, Path: "/1"},
+		{OrgID: orgA, UserID: bob, Path: "/2"},
+		{UserID: bob, Path: "/3"},
+		{OrgID: orgA, UserID: alice, Path: "/4"},
+	}
+	// Append out of time order, the log is ordered by time.
+	for _, i := range []int{4, 2, 0, 1, 3} {
+		events[i].Time = start.Add(time.Duration(i) * time.Minute)
+		if err := svc.AppendAuditEvent(ctx, events[i]); err != nil {
+			t.Fatal(err)
+		}
+		if !events[i].ID.Valid() {
+			t.Fatalf("event %d has no ID", i)
+		}
+	}
+
+	since, until := start.Add(time.Minute), start.Add(3*time.Minute)
+	tests := []struct {
+		name   string
+		filter influxdb.AuditEventFilter
+		opts   influxdb.FindOptions
+		want   []string
+	}{
+		{
+			name: "all",
+			want: []string{"/0", "/1", "/2", "/3", "/4"},
+		},
+		{
+			name:   "org",
+			filter: influxdb.AuditEventFilter{OrgID: &orgA},
+			want:   []string{"/0", "/2", "/4"},
+		},
+		{
+			name:   "user",
+			filter: influxdb.AuditEventFilter{UserID: &bob},
+			want:   []string{"/2", "/3"},
+		},
+		{
+			name:   "time range",
+			filter: influxdb.AuditEventFilter{Since: &since, Until: &until},
+			want:   []string{"/1", "/2", "/3"},
+		},
+		{
+			name:   "time range descending",
+			filter: influxdb.AuditEventFilter{Since: &since, Until: &until},
+			opts:   influxdb.FindOptions{Descending: true},
+			want:   []string{"/3", "/2", "/1"},
+		},
+		{
+			name:   "paged",
+			filter: influxdb.AuditEventFilter{OrgID: &orgA},
+			opts:   influxdb.FindOptions{Offset: 1, Limit: 1},
+			want:   []string{"/2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es, n, err := svc.FindAuditEvents(ctx, tt.filter, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != len(tt.want) {
+				t.Fatalf("got %d events, want %d", n, len(tt.want))
+			}
+			for i, e := range es {
+				if e.Path != tt.want[i] {
+					t.Errorf("event %d: got %s, want %s", i, e.Path, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAuditRecording(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &influxdb.Organization{Name: "o"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	b := &influxdb.Bucket{OrgID: org.ID, Name: "a"}
+	if err := svc.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	other := &influxdb.Bucket{OrgID: org.ID, Name: "b"}
+	if err := svc.CreateBucket(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := influxdb.NewAuditRecording()
+	rctx := influxdb.ContextWithAuditRecording(ctx, rec)
+	name := "renamed"
+	if _, err := svc.UpdateBucket(rctx, b.ID, influxdb.BucketUpdate{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	// The conflicting rename is rolled back, so it is not recorded.
+	if _, err := svc.UpdateBucket(rctx, other.ID, influxdb.BucketUpdate{Name: &name}); err == nil {
+		t.Fatal("expected a conflict renaming the bucket")
+	}
+	if err := svc.PatchSecrets(rctx, org.ID, map[string]string{"k": "v"}); err != nil {
+		t.Fatal(err)
+	}
+
+	before, after, ok := rec.State(b.ID)
+	if !ok {
+		t.Fatal("expected the renamed bucket to be recorded")
+	}
+	var got [2]influxdb.Bucket
+	for i, v := range [][]byte{before, after} {
+		if err := json.Unmarshal(v, &got[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got[0].Name != "a" || got[1].Name != "renamed" {
+		t.Errorf("unexpected recorded names: got %q and %q", got[0].Name, got[1].Name)
+	}
+	if _, _, ok := rec.State(other.ID); ok {
+		t.Error("unexpected state recorded for the rolled back change")
+	}
+	if _, _, ok := rec.State(org.ID); ok {
+		t.Error("unexpected state recorded for the unchanged organization")
+	}
+
+	secrets := rec.Secrets(org.ID)
+	if c, ok := secrets["k"]; !ok || c.Before != nil || !bytes.Equal(c.After, influxdb.AuditRedacted) {
+		t.Errorf("unexpected recorded secrets %v", secrets)
+	}
+}
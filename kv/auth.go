@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	influxdb "github.com/influxdata/influxdb"
 )
@@ -13,7 +14,10 @@ var (
 	authIndex  = []byte("authorizationindexv1")
 )
 
-var _ influxdb.AuthorizationService = (*Service)(nil)
+var (
+	_ influxdb.AuthorizationService         = (*Service)(nil)
+	_ influxdb.AuthorizationRotationService = (*Service)(nil)
+)
 
 func (s *Service) initializeAuths(ctx context.Context, tx Tx) error {
 	if _, err := tx.Bucket(authBucket); err != nil {
@@ -22,7 +26,16 @@ func (s *Service) initializeAuths(ctx context.Context, tx Tx) error {
 	if _, err := authIndexBucket(tx); err != nil {
 		return err
 	}
-	return nil
+	if err := s.initializeAuthTokenSalt(ctx, tx); err != nil {
+		return err
+	}
+	return s.hashPlaintextAuthTokens(ctx, tx)
+}
+
+// storedAuthorization is an authorization as stored, with the hash of its token instead of the token.
+type storedAuthorization struct {
+	influxdb.Authorization
+	HashedToken string `json:"hashedToken,omitempty"`
 }
 
 // FindAuthorizationByID retrieves a authorization by id.
@@ -45,7 +58,16 @@ func (s *Service) FindAuthorizationByID(ctx context.Context, id influxdb.ID) (*i
 	return a, nil
 }
 
+// findAuthorizationByID returns the authorization id, without its token.
 func (s *Service) findAuthorizationByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Authorization, error) {
+	sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &sa.Authorization, nil
+}
+
+func (s *Service) findStoredAuthorizationByID(ctx context.Context, tx Tx, id influxdb.ID) (*storedAuthorization, error) {
 	encodedID, err := id.Encode()
 	if err != nil {
 		return nil, &influxdb.Error{
@@ -71,15 +93,15 @@ func (s *Service) findAuthorizationByID(ctx context.Context, tx Tx, id influxdb.
 		return nil, err
 	}
 
-	a := &influxdb.Authorization{}
-	if err := decodeAuthorization(v, a); err != nil {
+	sa := &storedAuthorization{}
+	if err := decodeAuthorization(v, sa); err != nil {
 		return nil, &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Err:  err,
 		}
 	}
 
-	return a, nil
+	return sa, nil
 }
 
 // FindAuthorizationByToken returns a authorization by token for a particular authorization.
@@ -109,7 +131,12 @@ func (s *Service) findAuthorizationByToken(ctx context.Context, tx Tx, n string)
 		return nil, err
 	}
 
-	a, err := idx.Get(authIndexKey(n))
+	hashed, err := s.hashAuthToken(ctx, tx, n)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := idx.Get(authIndexKey(hashed))
 	if IsNotFound(err) {
 		return nil, &influxdb.Error{
 			Code: influxdb.ENotFound,
@@ -118,13 +145,20 @@ func (s *Service) findAuthorizationByToken(ctx context.Context, tx Tx, n string)
 	}
 
 	var id influxdb.ID
-	if err := id.Decode(a); err != nil {
+	if err := id.Decode(v); err != nil {
 		return nil, &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Err:  err,
 		}
 	}
-	return s.findAuthorizationByID(ctx, tx, id)
+
+	a, err := s.findAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	// The token is only stored hashed, the caller knows it.
+	a.Token = n
+	return a, nil
 }
 
 func filterAuthorizationsFn(filter influxdb.AuthorizationFilter) func(a *influxdb.Authorization) bool {
@@ -241,6 +275,7 @@ func (s *Service) findAuthorizations(ctx context.Context, tx Tx, f influxdb.Auth
 }
 
 // CreateAuthorization creates a influxdb authorization and sets b.ID, and b.UserID if not provided.
+// The token of the authorization is stored hashed: it is not returned when the authorization is found by ID or listed.
 func (s *Service) CreateAuthorization(ctx context.Context, a *influxdb.Authorization) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
 		return s.createAuthorization(ctx, tx, a)
@@ -286,13 +321,15 @@ func (s *Service) createAuthorization(ctx context.Context, tx Tx, a *influxdb.Au
 }
 
 // PutAuthorization will put a authorization without setting an ID.
+// The authorization must have its token, which is stored hashed.
 func (s *Service) PutAuthorization(ctx context.Context, a *influxdb.Authorization) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
 		return s.putAuthorization(ctx, tx, a)
 	})
 }
 
-func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
+// encodeAuthorization encodes the authorization for storage, with the hash of its token instead of the token.
+func encodeAuthorization(a *influxdb.Authorization, hashedToken string) ([]byte, error) {
 	switch a.Status {
 	case influxdb.Active, influxdb.Inactive:
 	case "":
@@ -304,11 +341,18 @@ func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(a)
+	sa := storedAuthorization{Authorization: *a, HashedToken: hashedToken}
+	sa.Token = ""
+	return json.Marshal(sa)
 }
 
 func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
-	v, err := encodeAuthorization(a)
+	hashed, err := s.hashAuthToken(ctx, tx, a.Token)
+	if err != nil {
+		return err
+	}
+
+	v, err := encodeAuthorization(a, hashed)
 	if err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -329,7 +373,7 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 		return err
 	}
 
-	if err := idx.Put(authIndexKey(a.Token), encodedID); err != nil {
+	if err := idx.Put(authIndexKey(hashed), encodedID); err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInternal,
 			Err:  err,
@@ -354,12 +398,12 @@ func authIndexKey(n string) []byte {
 	return []byte(n)
 }
 
-func decodeAuthorization(b []byte, a *influxdb.Authorization) error {
-	if err := json.Unmarshal(b, a); err != nil {
+func decodeAuthorization(b []byte, sa *storedAuthorization) error {
+	if err := json.Unmarshal(b, sa); err != nil {
 		return err
 	}
-	if a.Status == "" {
-		a.Status = influxdb.Active
+	if sa.Status == "" {
+		sa.Status = influxdb.Active
 	}
 	return nil
 }
@@ -377,12 +421,12 @@ func (s *Service) forEachAuthorization(ctx context.Context, tx Tx, fn func(*infl
 	}
 
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
-		a := &influxdb.Authorization{}
+		sa := &storedAuthorization{}
 
-		if err := decodeAuthorization(v, a); err != nil {
+		if err := decodeAuthorization(v, sa); err != nil {
 			return err
 		}
-		if !fn(a) {
+		if !fn(&sa.Authorization) {
 			break
 		}
 	}
@@ -398,7 +442,7 @@ func (s *Service) DeleteAuthorization(ctx context.Context, id influxdb.ID) error
 }
 
 func (s *Service) deleteAuthorization(ctx context.Context, tx Tx, id influxdb.ID) error {
-	a, err := s.findAuthorizationByID(ctx, tx, id)
+	sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
 	if err != nil {
 		return err
 	}
@@ -408,7 +452,7 @@ func (s *Service) deleteAuthorization(ctx context.Context, tx Tx, id influxdb.ID
 		return err
 	}
 
-	if err := idx.Delete(authIndexKey(a.Token)); err != nil {
+	if err := idx.Delete(authIndexKey(sa.HashedToken)); err != nil {
 		return &influxdb.Error{
 			Err: err,
 		}
@@ -433,7 +477,7 @@ func (s *Service) deleteAuthorization(ctx context.Context, tx Tx, id influxdb.ID
 	return nil
 }
 
-// UpdateAuthorization updates the status and description if available.
+// UpdateAuthorization updates the status, description and expiration if available.
 func (s *Service) UpdateAuthorization(ctx context.Context, id influxdb.ID, upd *influxdb.AuthorizationUpdate) (*influxdb.Authorization, error) {
 	var a *influxdb.Authorization
 	var err error
@@ -445,10 +489,11 @@ func (s *Service) UpdateAuthorization(ctx context.Context, id influxdb.ID, upd *
 }
 
 func (s *Service) updateAuthorization(ctx context.Context, tx Tx, id influxdb.ID, upd *influxdb.AuthorizationUpdate) (*influxdb.Authorization, error) {
-	a, err := s.findAuthorizationByID(ctx, tx, id)
+	sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
 	if err != nil {
 		return nil, err
 	}
+	a := &sa.Authorization
 
 	if upd.Status != nil {
 		a.Status = *upd.Status
@@ -456,8 +501,11 @@ func (s *Service) updateAuthorization(ctx context.Context, tx Tx, id influxdb.ID
 	if upd.Description != nil {
 		a.Description = *upd.Description
 	}
+	if upd.ExpiresAt != nil {
+		a.ExpiresAt = upd.ExpiresAt
+	}
 
-	v, err := encodeAuthorization(a)
+	v, err := encodeAuthorization(a, sa.HashedToken)
 	if err != nil {
 		return nil, &influxdb.Error{
 			Err: err,
@@ -484,6 +532,53 @@ func (s *Service) updateAuthorization(ctx context.Context, tx Tx, id influxdb.ID
 	return a, nil
 }
 
+// RotateAuthorization creates a new authorization with the permissions of the authorization id,
+// and makes the authorization id expire after gracePeriod.
+func (s *Service) RotateAuthorization(ctx context.Context, id influxdb.ID, gracePeriod time.Duration) (*influxdb.Authorization, error) {
+	var a *influxdb.Authorization
+	var err error
+	err = s.kv.Update(ctx, func(tx Tx) error {
+		a, err = s.rotateAuthorization(ctx, tx, id, gracePeriod)
+		return err
+	})
+	return a, err
+}
+
+func (s *Service) rotateAuthorization(ctx context.Context, tx Tx, id influxdb.ID, gracePeriod time.Duration) (*influxdb.Authorization, error) {
+	if gracePeriod < 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "grace period must not be negative",
+		}
+	}
+
+	old, err := s.findAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new authorization does not inherit the expiration of the old one.
+	a := &influxdb.Authorization{
+		Status:      old.Status,
+		Description: old.Description,
+		OrgID:       old.OrgID,
+		UserID:      old.UserID,
+		Permissions: old.Permissions,
+	}
+	if err := s.createAuthorization(ctx, tx, a); err != nil {
+		return nil, err
+	}
+
+	expiresAt := s.Now().Add(gracePeriod)
+	if old.ExpiresAt == nil || expiresAt.Before(*old.ExpiresAt) {
+		if _, err := s.updateAuthorization(ctx, tx, id, &influxdb.AuthorizationUpdate{ExpiresAt: &expiresAt}); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
 func authIndexBucket(tx Tx) (Bucket, error) {
 	b, err := tx.Bucket([]byte(authIndex))
 	if err != nil {
@@ -503,7 +598,12 @@ func UnexpectedAuthIndexError(err error) *influxdb.Error {
 }
 
 func (s *Service) uniqueAuthToken(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
-	err := s.unique(ctx, tx, authIndex, authIndexKey(a.Token))
+	hashed, err := s.hashAuthToken(ctx, tx, a.Token)
+	if err != nil {
+		return err
+	}
+
+	err = s.unique(ctx, tx, authIndex, authIndexKey(hashed))
 	if err == NotUniqueError {
 		// by returning a generic error we are trying to hide when
 		// a token is non-unique.
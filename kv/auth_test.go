@@ -2,10 +2,13 @@ package kv_test
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
 )
 
@@ -91,3 +94,244 @@ func initAuthorizationService(s kv.Store, f influxdbtesting.AuthorizationFields,
 		}
 	}
 }
+
+func TestRotateAuthorization(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	svc := kv.NewService(store)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	old := &influxdb.Authorization{
+		OrgID:       org.ID,
+		UserID:      user.ID,
+		Description: "telegraf",
+		Permissions: influxdb.OperPermissions(),
+	}
+	if err := svc.CreateAuthorization(ctx, old); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := svc.RotateAuthorization(ctx, old.ID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID == old.ID || a.Token == old.Token {
+		t.Fatalf("expected a new authorization, got %+v", a)
+	}
+	if a.Description != old.Description || a.ExpiresAt != nil || len(a.Permissions) != len(old.Permissions) {
+		t.Fatalf("unexpected rotated authorization %+v", a)
+	}
+
+	found, err := svc.FindAuthorizationByToken(ctx, a.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found.IsActive() {
+		t.Fatal("expected the new authorization to be active")
+	}
+
+	found, err = svc.FindAuthorizationByID(ctx, old.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.ExpiresAt == nil || !found.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected the old authorization to expire at %v, got %v", now.Add(time.Hour), found.ExpiresAt)
+	}
+	if err := found.Expired(now.Add(time.Hour)); err != nil {
+		t.Fatalf("expected the old authorization to be unexpired during its grace period: %v", err)
+	}
+
+	// Rotating again without grace period expires the old authorization immediately.
+	if _, err := svc.RotateAuthorization(ctx, old.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	found, err = svc.FindAuthorizationByID(ctx, old.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found.ExpiresAt.Equal(now) {
+		t.Fatalf("expected the old authorization to expire at %v, got %v", now, found.ExpiresAt)
+	}
+	if err := found.Expired(now.Add(time.Nanosecond)); err != influxdb.ErrAuthorizationExpired {
+		t.Fatalf("expected the old authorization to be expired, got %v", err)
+	}
+	if found.IsActive() || found.Allowed(influxdb.OperPermissions()[0]) {
+		t.Fatal("expected the old authorization to be inactive")
+	}
+}
+
+func TestAuthorizationTokensAreHashed(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	a := &influxdb.Authorization{OrgID: org.ID, UserID: user.ID, Permissions: influxdb.OperPermissions()}
+	if err := svc.CreateAuthorization(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Token == "" {
+		t.Fatal("expected the token to be returned on creation")
+	}
+
+	// The token is never stored.
+	err = store.View(ctx, func(tx kv.Tx) error {
+		for _, bucket := range []string{"authorizationsv1", "authorizationindexv1"} {
+			b, err := tx.Bucket([]byte(bucket))
+			if err != nil {
+				return err
+			}
+			cur, err := b.Cursor()
+			if err != nil {
+				return err
+			}
+			for k, v := cur.First(); k != nil; k, v = cur.Next() {
+				if strings.Contains(string(k), a.Token) || strings.Contains(string(v), a.Token) {
+					t.Errorf("token stored in %s", bucket)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := svc.FindAuthorizationByID(ctx, a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Token != "" {
+		t.Fatalf("expected no token, got %q", found.Token)
+	}
+
+	found, err = svc.FindAuthorizationByToken(ctx, a.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.ID != a.ID || found.Token != a.Token {
+		t.Fatalf("unexpected authorization %+v", found)
+	}
+
+	if _, err := svc.FindAuthorizationByToken(ctx, a.Token+"x"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+
+	// Deleting the authorization removes its token from the index.
+	if err := svc.DeleteAuthorization(ctx, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindAuthorizationByToken(ctx, a.Token); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestAuthorizationPlaintextTokensMigration(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	// An authorization stored by a previous version, with its token as index key.
+	ctx := context.Background()
+	const token = "plaintext"
+	var id influxdb.ID = 1
+	encodedID, err := id.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("authorizationsv1"))
+		if err != nil {
+			return err
+		}
+		v := []byte(`{"id":"0000000000000001","token":"` + token + `","status":"active","orgID":"0000000000000002","permissions":[]}`)
+		if err := b.Put(encodedID, v); err != nil {
+			return err
+		}
+		idx, err := tx.Bucket([]byte("authorizationindexv1"))
+		if err != nil {
+			return err
+		}
+		return idx.Put([]byte(token), encodedID)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := kv.NewService(store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := svc.FindAuthorizationByToken(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != id {
+		t.Fatalf("unexpected authorization %+v", a)
+	}
+
+	found, err := svc.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Token != "" {
+		t.Fatalf("expected the token to be hashed, got %q", found.Token)
+	}
+
+	err = store.View(ctx, func(tx kv.Tx) error {
+		idx, err := tx.Bucket([]byte("authorizationindexv1"))
+		if err != nil {
+			return err
+		}
+		if _, err := idx.Get([]byte(token)); !kv.IsNotFound(err) {
+			t.Errorf("expected the plaintext index key to be removed, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Initializing again is a no-op.
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindAuthorizationByToken(ctx, token); err != nil {
+		t.Fatal(err)
+	}
+}
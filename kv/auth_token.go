@@ -0,0 +1,115 @@
+package kv
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	influxdb "github.com/influxdata/influxdb"
+)
+
+var (
+	authTokenSaltBucket = []byte("authorizationtokensaltv1")
+	authTokenSaltKey    = []byte("salt")
+)
+
+// authTokenSaltLength is the length of the salt of the token hashes, in bytes.
+const authTokenSaltLength = 32
+
+// initializeAuthTokenSalt generates the salt of the token hashes, once per store.
+func (s *Service) initializeAuthTokenSalt(ctx context.Context, tx Tx) error {
+	b, err := tx.Bucket(authTokenSaltBucket)
+	if err != nil {
+		return err
+	}
+
+	if _, err := b.Get(authTokenSaltKey); err == nil {
+		return nil
+	} else if !IsNotFound(err) {
+		return err
+	}
+
+	salt := make([]byte, authTokenSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	return b.Put(authTokenSaltKey, salt)
+}
+
+// hashAuthToken returns the hash of token.
+// Tokens are random, so a salted hash that is fast to compute is enough to protect them,
+// and lets tokens be looked up by their hash.
+func (s *Service) hashAuthToken(ctx context.Context, tx Tx, token string) (string, error) {
+	b, err := tx.Bucket(authTokenSaltBucket)
+	if err != nil {
+		return "", err
+	}
+
+	salt, err := b.Get(authTokenSaltKey)
+	if IsNotFound(err) {
+		// Stores that were not initialized get their salt with their first authorization.
+		if err := s.initializeAuthTokenSalt(ctx, tx); err != nil {
+			return "", err
+		}
+		salt, err = b.Get(authTokenSaltKey)
+	}
+	if err != nil {
+		return "", &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to retrieve token salt",
+			Err:  err,
+		}
+	}
+
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// hashPlaintextAuthTokens replaces the tokens stored in plaintext, by previous versions, with their hash.
+func (s *Service) hashPlaintextAuthTokens(ctx context.Context, tx Tx) error {
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var plaintext []*storedAuthorization
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		sa := &storedAuthorization{}
+		if err := json.Unmarshal(v, sa); err != nil {
+			return err
+		}
+		if sa.HashedToken == "" && sa.Token != "" {
+			plaintext = append(plaintext, sa)
+		}
+	}
+
+	if len(plaintext) == 0 {
+		return nil
+	}
+
+	idx, err := authIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, sa := range plaintext {
+		if err := idx.Delete(authIndexKey(sa.Token)); err != nil {
+			return err
+		}
+		if err := s.putAuthorization(ctx, tx, &sa.Authorization); err != nil {
+			return err
+		}
+	}
+
+	s.Logger.Info("Hashed authorization tokens stored in plaintext")
+	return nil
+}
@@ -0,0 +1,497 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var (
+	groupBucket = []byte("groupsv1")
+	groupIndex  = []byte("groupindexv1")
+)
+
+var _ influxdb.GroupService = (*Service)(nil)
+
+func (s *Service) initializeGroups(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(groupBucket); err != nil {
+		return err
+	}
+
+	if _, err := tx.Bucket(groupIndex); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindGroupByID finds a group by its ID.
+func (s *Service) FindGroupByID(ctx context.Context, id influxdb.ID) (*influxdb.Group, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var g *influxdb.Group
+	err := s.kv.View(ctx, func(tx Tx) error {
+		group, err := s.findGroupByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		g = group
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindGroupByID,
+			Err: err,
+		}
+	}
+
+	return g, nil
+}
+
+func (s *Service) findGroupByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Group, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(groupBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrGroupNotFound,
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	g := &influxdb.Group{}
+	if err := json.Unmarshal(v, g); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return g, nil
+}
+
+func filterGroupsFn(filter influxdb.GroupFilter) func(g *influxdb.Group) bool {
+	return func(g *influxdb.Group) bool {
+		return (filter.ID == nil || *filter.ID == g.ID) &&
+			(filter.Name == nil || *filter.Name == g.Name) &&
+			(filter.OrgID == nil || *filter.OrgID == g.OrgID)
+	}
+}
+
+// FindGroups returns a list of groups that match a filter and the total count of matching groups.
+func (s *Service) FindGroups(ctx context.Context, filter influxdb.GroupFilter, opts ...influxdb.FindOptions) ([]*influxdb.Group, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var gs []*influxdb.Group
+	err := s.kv.View(ctx, func(tx Tx) error {
+		groups, err := s.findGroups(ctx, tx, filter, opts...)
+		if err != nil {
+			return err
+		}
+		gs = groups
+		return nil
+	})
+
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindGroups,
+			Err: err,
+		}
+	}
+
+	return gs, len(gs), nil
+}
+
+func (s *Service) findGroups(ctx context.Context, tx Tx, filter influxdb.GroupFilter, opts ...influxdb.FindOptions) ([]*influxdb.Group, error) {
+	var offset, limit, count int
+	if len(opts) > 0 {
+		offset = opts[0].Offset
+		limit = opts[0].Limit
+	}
+
+	gs := []*influxdb.Group{}
+	filterFn := filterGroupsFn(filter)
+	err := s.forEachGroup(ctx, tx, func(g *influxdb.Group) bool {
+		if filterFn(g) {
+			if count >= offset {
+				gs = append(gs, g)
+			}
+			count++
+		}
+
+		if limit > 0 && len(gs) >= limit {
+			return false
+		}
+
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return gs, nil
+}
+
+func (s *Service) forEachGroup(ctx context.Context, tx Tx, fn func(*influxdb.Group) bool) error {
+	b, err := tx.Bucket(groupBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		g := &influxdb.Group{}
+		if err := json.Unmarshal(v, g); err != nil {
+			return err
+		}
+		if !fn(g) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// CreateGroup creates a group and sets its ID.
+func (s *Service) CreateGroup(ctx context.Context, g *influxdb.Group) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := g.Validate(); err != nil {
+			return err
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, g.OrgID); err != nil {
+			return err
+		}
+
+		key, err := groupIndexKey(g.OrgID, g.Name)
+		if err != nil {
+			return err
+		}
+		if err := s.unique(ctx, tx, groupIndex, key); err != nil {
+			return err
+		}
+
+		g.ID = s.IDGenerator.ID()
+		return s.putGroup(ctx, tx, g)
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateGroup,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// PutGroup writes a group without generating a new ID.
+func (s *Service) PutGroup(ctx context.Context, g *influxdb.Group) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.putGroup(ctx, tx, g)
+	})
+}
+
+func (s *Service) putGroup(ctx context.Context, tx Tx, g *influxdb.Group) error {
+	v, err := json.Marshal(g)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	encodedID, err := g.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	key, err := groupIndexKey(g.OrgID, g.Name)
+	if err != nil {
+		return err
+	}
+
+	idx, err := tx.Bucket(groupIndex)
+	if err != nil {
+		return UnexpectedIndexError(err)
+	}
+
+	if err := idx.Put(key, encodedID); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	b, err := tx.Bucket(groupBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(encodedID, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// groupIndexKey is a combination of the orgID and the group name.
+func groupIndexKey(orgID influxdb.ID, name string) ([]byte, error) {
+	orgIDEncoded, err := orgID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	k := make([]byte, influxdb.IDLength+len(name))
+	copy(k, orgIDEncoded)
+	copy(k[influxdb.IDLength:], []byte(name))
+	return k, nil
+}
+
+// UpdateGroup updates a group according to the changeset.
+func (s *Service) UpdateGroup(ctx context.Context, id influxdb.ID, upd influxdb.GroupUpdate) (*influxdb.Group, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var g *influxdb.Group
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		group, err := s.updateGroup(ctx, tx, id, upd)
+		if err != nil {
+			return err
+		}
+		g = group
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateGroup,
+			Err: err,
+		}
+	}
+
+	return g, nil
+}
+
+func (s *Service) updateGroup(ctx context.Context, tx Tx, id influxdb.ID, upd influxdb.GroupUpdate) (*influxdb.Group, error) {
+	g, err := s.findGroupByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if upd.Name != nil && *upd.Name != g.Name {
+		key, err := groupIndexKey(g.OrgID, *upd.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.unique(ctx, tx, groupIndex, key); err != nil {
+			return nil, err
+		}
+
+		if err := s.deleteGroupIndex(ctx, tx, g); err != nil {
+			return nil, err
+		}
+	}
+
+	upd.Apply(g)
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.putGroup(ctx, tx, g); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (s *Service) deleteGroupIndex(ctx context.Context, tx Tx, g *influxdb.Group) error {
+	key, err := groupIndexKey(g.OrgID, g.Name)
+	if err != nil {
+		return err
+	}
+
+	idx, err := tx.Bucket(groupIndex)
+	if err != nil {
+		return UnexpectedIndexError(err)
+	}
+
+	if err := idx.Delete(key); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// DeleteGroup deletes a group along with the mappings of its users and of the resources it is mapped to.
+func (s *Service) DeleteGroup(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteGroup(ctx, tx, id)
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteGroup,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteGroup(ctx context.Context, tx Tx, id influxdb.ID) error {
+	g, err := s.findGroupByID(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID:   id,
+		ResourceType: influxdb.GroupsResourceType,
+	}); err != nil {
+		return err
+	}
+
+	if err := s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		UserID: id,
+	}); err != nil {
+		return err
+	}
+
+	bs, err := s.findRoleBindings(ctx, tx, influxdb.RoleBindingFilter{UserID: &id})
+	if err != nil {
+		return err
+	}
+	for _, rb := range bs {
+		if err := s.deleteRoleBinding(ctx, tx, rb.RoleID, rb.UserID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.deleteGroupIndex(ctx, tx, g); err != nil {
+		return err
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(groupBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Delete(encodedID); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// FindGroupPermissions returns the permissions granted by the resources mapped to the groups of a user in an org.
+func (s *Service) FindGroupPermissions(ctx context.Context, userID, orgID influxdb.ID) ([]influxdb.Permission, error) {
+	var ps []influxdb.Permission
+	err := s.kv.View(ctx, func(tx Tx) error {
+		gps, err := s.findGroupPermissions(ctx, tx, userID, &orgID)
+		if err != nil {
+			return err
+		}
+		ps = gps
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindGroupPermissions,
+			Err: err,
+		}
+	}
+
+	return ps, nil
+}
+
+// findGroupPermissions returns the permissions granted by the resources mapped to the groups of a user.
+// When orgID is set, only the groups of that org are considered.
+func (s *Service) findGroupPermissions(ctx context.Context, tx Tx, userID influxdb.ID, orgID *influxdb.ID) ([]influxdb.Permission, error) {
+	gms, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		UserID:       userID,
+		ResourceType: influxdb.GroupsResourceType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ps []influxdb.Permission
+	for _, gm := range gms {
+		if orgID != nil {
+			g, err := s.findGroupByID(ctx, tx, gm.ResourceID)
+			if err != nil {
+				return nil, err
+			}
+			if g.OrgID != *orgID {
+				continue
+			}
+		}
+
+		ms, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{UserID: gm.ResourceID})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range ms {
+			if m.MappingType != influxdb.GroupMappingType {
+				continue
+			}
+
+			p, err := m.ToPermissions()
+			if err != nil {
+				return nil, err
+			}
+			ps = append(ps, p...)
+		}
+	}
+
+	return ps, nil
+}
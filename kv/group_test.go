@@ -0,0 +1,166 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestGroups(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &influxdb.Bucket{OrgID: org.ID, Name: "bucket"}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	group := &influxdb.Group{OrgID: org.ID, Name: "engineers"}
+	if err := svc.CreateGroup(ctx, group); err != nil {
+		t.Fatal(err)
+	}
+
+	// Names are unique within an organization.
+	if err := svc.CreateGroup(ctx, &influxdb.Group{OrgID: org.ID, Name: group.Name}); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	name := "sre"
+	if _, err := svc.UpdateGroup(ctx, group.ID, influxdb.GroupUpdate{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	gs, n, err := svc.FindGroups(ctx, influxdb.GroupFilter{OrgID: &org.ID, Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || gs[0].ID != group.ID {
+		t.Fatalf("unexpected groups %+v", gs)
+	}
+
+	// Only existing groups can be mapped to a resource.
+	missing := &influxdb.UserResourceMapping{
+		UserID:       influxdb.ID(1),
+		UserType:     influxdb.Member,
+		MappingType:  influxdb.GroupMappingType,
+		ResourceType: influxdb.BucketsResourceType,
+		ResourceID:   bucket.ID,
+	}
+	if err := svc.CreateUserResourceMapping(ctx, missing); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected a missing group, got %v", err)
+	}
+
+	// Groups can only be mapped to the resources of their own organization.
+	other := &influxdb.Organization{Name: "other"}
+	if err := svc.CreateOrganization(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	otherBucket := &influxdb.Bucket{OrgID: other.ID, Name: "bucket"}
+	if err := svc.CreateBucket(ctx, otherBucket); err != nil {
+		t.Fatal(err)
+	}
+	crossOrg := &influxdb.UserResourceMapping{
+		UserID:       group.ID,
+		UserType:     influxdb.Member,
+		MappingType:  influxdb.GroupMappingType,
+		ResourceType: influxdb.BucketsResourceType,
+		ResourceID:   otherBucket.ID,
+	}
+	if err := svc.CreateUserResourceMapping(ctx, crossOrg); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected an invalid mapping, got %v", err)
+	}
+
+	// Sessions of the users of a group are granted the permissions to the resources of the group.
+	mappings := []*influxdb.UserResourceMapping{
+		{
+			UserID:       user.ID,
+			UserType:     influxdb.Member,
+			ResourceType: influxdb.GroupsResourceType,
+			ResourceID:   group.ID,
+		},
+		{
+			UserID:       group.ID,
+			UserType:     influxdb.Owner,
+			MappingType:  influxdb.GroupMappingType,
+			ResourceType: influxdb.BucketsResourceType,
+			ResourceID:   bucket.ID,
+		},
+	}
+	for _, m := range mappings {
+		if err := svc.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeBucket := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &org.ID, ID: &bucket.ID},
+	}
+	sess, err := svc.CreateSession(ctx, user.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err = svc.FindSession(ctx, sess.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sess.Allowed(writeBucket) {
+		t.Fatalf("session should be allowed %s", writeBucket)
+	}
+
+	// Authorizations of the org of the group are granted them as well.
+	ps, err := svc.FindGroupPermissions(ctx, user.ID, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := (&influxdb.Authorization{Status: influxdb.Active, Permissions: ps}); !a.Allowed(writeBucket) {
+		t.Fatalf("authorization should be allowed %s", writeBucket)
+	}
+	if ps, err := svc.FindGroupPermissions(ctx, user.ID, other.ID); err != nil || len(ps) != 0 {
+		t.Fatalf("unexpected permissions in another org %v, %v", ps, err)
+	}
+
+	orgID, err := svc.FindResourceOrganizationID(ctx, influxdb.GroupsResourceType, group.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orgID != org.ID {
+		t.Fatalf("unexpected organization of the group %s", orgID)
+	}
+
+	// Deleting a group deletes its mappings.
+	if err := svc.DeleteGroup(ctx, group.ID); err != nil {
+		t.Fatal(err)
+	}
+	if ms, _, err := svc.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{UserID: group.ID}); err != nil || len(ms) != 0 {
+		t.Fatalf("unexpected mappings %+v, %v", ms, err)
+	}
+	if ms, _, err := svc.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{ResourceID: group.ID}); err != nil || len(ms) != 0 {
+		t.Fatalf("unexpected mappings %+v, %v", ms, err)
+	}
+	sess, err = svc.FindSession(ctx, sess.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.Allowed(writeBucket) {
+		t.Fatalf("session should not be allowed %s", writeBucket)
+	}
+}
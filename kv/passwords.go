@@ -97,8 +97,8 @@ func (s *Service) ComparePassword(ctx context.Context, name string, password str
 }
 
 func (s *Service) setPassword(ctx context.Context, tx Tx, name string, password string) error {
-	if len(password) < MinPasswordLength {
-		return EShortPassword
+	if err := s.validatePassword(password); err != nil {
+		return err
 	}
 
 	u, err := s.findUserByName(ctx, tx, name)
@@ -132,6 +132,16 @@ func (s *Service) setPassword(ctx context.Context, tx Tx, name string, password
 	return nil
 }
 
+// validatePassword checks the password against the password policy of the service.
+// Unless the policy sets a minimum length, passwords are at least MinPasswordLength long.
+func (s *Service) validatePassword(password string) error {
+	p := s.Config.PasswordPolicy
+	if p.MinLength <= 0 && len(password) < MinPasswordLength {
+		return EShortPassword
+	}
+	return p.Validate(password)
+}
+
 func (s *Service) comparePassword(ctx context.Context, tx Tx, name string, password string) error {
 	u, err := s.findUserByName(ctx, tx, name)
 	if err != nil {
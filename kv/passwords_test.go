@@ -505,3 +505,31 @@ func TestService_ComparePassword(t *testing.T) {
 		})
 	}
 }
+
+func TestService_SetPassword_PolicyMinLength(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(store, kv.ServiceConfig{
+		SessionLength:  influxdb.DefaultSessionLength,
+		PasswordPolicy: influxdb.PasswordPolicy{MinLength: 4},
+	})
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUser(ctx, &influxdb.User{Name: "user"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The policy replaces the default minimum length of passwords.
+	if err := svc.SetPassword(ctx, "user", "abcd"); err != nil {
+		t.Fatalf("expected a password of the minimum length to be accepted, got %v", err)
+	}
+	if err := svc.SetPassword(ctx, "user", "abc"); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected a shorter password to be invalid, got %v", err)
+	}
+}
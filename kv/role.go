@@ -0,0 +1,697 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var (
+	roleBucket        = []byte("rolesv1")
+	roleIndex         = []byte("roleindexv1")
+	roleBindingBucket = []byte("rolebindingsv1")
+)
+
+var _ influxdb.RoleService = (*Service)(nil)
+
+func (s *Service) initializeRoles(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(roleBucket); err != nil {
+		return err
+	}
+
+	if _, err := tx.Bucket(roleIndex); err != nil {
+		return err
+	}
+
+	if _, err := tx.Bucket(roleBindingBucket); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindRoleByID finds a role by its ID.
+func (s *Service) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var r *influxdb.Role
+	err := s.kv.View(ctx, func(tx Tx) error {
+		role, err := s.findRoleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		r = role
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindRoleByID,
+			Err: err,
+		}
+	}
+
+	return r, nil
+}
+
+func (s *Service) findRoleByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Role, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(roleBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrRoleNotFound,
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	r := &influxdb.Role{}
+	if err := json.Unmarshal(v, r); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return r, nil
+}
+
+func filterRolesFn(filter influxdb.RoleFilter) func(r *influxdb.Role) bool {
+	return func(r *influxdb.Role) bool {
+		return (filter.ID == nil || *filter.ID == r.ID) &&
+			(filter.Name == nil || *filter.Name == r.Name) &&
+			(filter.OrgID == nil || *filter.OrgID == r.OrgID)
+	}
+}
+
+// FindRoles returns a list of roles that match a filter and the total count of matching roles.
+func (s *Service) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opts ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var rs []*influxdb.Role
+	err := s.kv.View(ctx, func(tx Tx) error {
+		roles, err := s.findRoles(ctx, tx, filter, opts...)
+		if err != nil {
+			return err
+		}
+		rs = roles
+		return nil
+	})
+
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindRoles,
+			Err: err,
+		}
+	}
+
+	return rs, len(rs), nil
+}
+
+func (s *Service) findRoles(ctx context.Context, tx Tx, filter influxdb.RoleFilter, opts ...influxdb.FindOptions) ([]*influxdb.Role, error) {
+	var offset, limit, count int
+	if len(opts) > 0 {
+		offset = opts[0].Offset
+		limit = opts[0].Limit
+	}
+
+	rs := []*influxdb.Role{}
+	filterFn := filterRolesFn(filter)
+	err := s.forEachRole(ctx, tx, func(r *influxdb.Role) bool {
+		if filterFn(r) {
+			if count >= offset {
+				rs = append(rs, r)
+			}
+			count++
+		}
+
+		if limit > 0 && len(rs) >= limit {
+			return false
+		}
+
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rs, nil
+}
+
+func (s *Service) forEachRole(ctx context.Context, tx Tx, fn func(*influxdb.Role) bool) error {
+	b, err := tx.Bucket(roleBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		r := &influxdb.Role{}
+		if err := json.Unmarshal(v, r); err != nil {
+			return err
+		}
+		if !fn(r) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// CreateRole creates a role and sets its ID.
+func (s *Service) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, r.OrgID); err != nil {
+			return err
+		}
+
+		key, err := roleIndexKey(r.OrgID, r.Name)
+		if err != nil {
+			return err
+		}
+		if err := s.unique(ctx, tx, roleIndex, key); err != nil {
+			return err
+		}
+
+		r.ID = s.IDGenerator.ID()
+		return s.putRole(ctx, tx, r)
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateRole,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// PutRole writes a role without generating a new ID.
+func (s *Service) PutRole(ctx context.Context, r *influxdb.Role) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.putRole(ctx, tx, r)
+	})
+}
+
+func (s *Service) putRole(ctx context.Context, tx Tx, r *influxdb.Role) error {
+	v, err := json.Marshal(r)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	encodedID, err := r.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	key, err := roleIndexKey(r.OrgID, r.Name)
+	if err != nil {
+		return err
+	}
+
+	idx, err := tx.Bucket(roleIndex)
+	if err != nil {
+		return UnexpectedIndexError(err)
+	}
+
+	if err := idx.Put(key, encodedID); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	b, err := tx.Bucket(roleBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(encodedID, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// roleIndexKey is a combination of the orgID and the role name.
+func roleIndexKey(orgID influxdb.ID, name string) ([]byte, error) {
+	orgIDEncoded, err := orgID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	k := make([]byte, influxdb.IDLength+len(name))
+	copy(k, orgIDEncoded)
+	copy(k[influxdb.IDLength:], []byte(name))
+	return k, nil
+}
+
+// UpdateRole updates a role according to the changeset.
+func (s *Service) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var r *influxdb.Role
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		role, err := s.updateRole(ctx, tx, id, upd)
+		if err != nil {
+			return err
+		}
+		r = role
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateRole,
+			Err: err,
+		}
+	}
+
+	return r, nil
+}
+
+func (s *Service) updateRole(ctx context.Context, tx Tx, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	r, err := s.findRoleByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if upd.Name != nil && *upd.Name != r.Name {
+		key, err := roleIndexKey(r.OrgID, *upd.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.unique(ctx, tx, roleIndex, key); err != nil {
+			return nil, err
+		}
+
+		if err := s.deleteRoleIndex(ctx, tx, r); err != nil {
+			return nil, err
+		}
+	}
+
+	upd.Apply(r)
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.putRole(ctx, tx, r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (s *Service) deleteRoleIndex(ctx context.Context, tx Tx, r *influxdb.Role) error {
+	key, err := roleIndexKey(r.OrgID, r.Name)
+	if err != nil {
+		return err
+	}
+
+	idx, err := tx.Bucket(roleIndex)
+	if err != nil {
+		return UnexpectedIndexError(err)
+	}
+
+	if err := idx.Delete(key); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// DeleteRole deletes a role and its bindings.
+func (s *Service) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteRole(ctx, tx, id)
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteRole,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteRole(ctx context.Context, tx Tx, id influxdb.ID) error {
+	r, err := s.findRoleByID(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	bs, err := s.findRoleBindings(ctx, tx, influxdb.RoleBindingFilter{RoleID: &id})
+	if err != nil {
+		return err
+	}
+	for _, rb := range bs {
+		if err := s.deleteRoleBinding(ctx, tx, rb.RoleID, rb.UserID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.deleteRoleIndex(ctx, tx, r); err != nil {
+		return err
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(roleBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Delete(encodedID); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// roleBindingKey is a combination of the user ID and the role ID,
+// so that the roles of a user, needed to authorize its sessions, are found with a prefix.
+func roleBindingKey(userID, roleID influxdb.ID) ([]byte, error) {
+	uid, err := userID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	rid, err := roleID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	key := make([]byte, influxdb.IDLength+influxdb.IDLength)
+	copy(key, uid)
+	copy(key[influxdb.IDLength:], rid)
+	return key, nil
+}
+
+// FindRoleBindings returns a list of role bindings that match a filter.
+func (s *Service) FindRoleBindings(ctx context.Context, filter influxdb.RoleBindingFilter) ([]*influxdb.RoleBinding, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var bs []*influxdb.RoleBinding
+	err := s.kv.View(ctx, func(tx Tx) error {
+		bindings, err := s.findRoleBindings(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		bs = bindings
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindRoleBindings,
+			Err: err,
+		}
+	}
+
+	return bs, nil
+}
+
+func (s *Service) findRoleBindings(ctx context.Context, tx Tx, filter influxdb.RoleBindingFilter) ([]*influxdb.RoleBinding, error) {
+	b, err := tx.Bucket(roleBindingBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	var prefix []byte
+	if filter.UserID != nil {
+		if prefix, err = filter.UserID.Encode(); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+	}
+
+	k, v := cur.First()
+	if prefix != nil {
+		k, v = cur.Seek(prefix)
+	}
+
+	bs := []*influxdb.RoleBinding{}
+	for ; k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		rb := &influxdb.RoleBinding{}
+		if err := json.Unmarshal(v, rb); err != nil {
+			return nil, &influxdb.Error{
+				Err: err,
+			}
+		}
+		if filter.RoleID != nil && *filter.RoleID != rb.RoleID {
+			continue
+		}
+		bs = append(bs, rb)
+	}
+
+	return bs, nil
+}
+
+// CreateRoleBinding binds a user or a group to a role.
+// The user or the group must belong to the organization of the role.
+func (s *Service) CreateRoleBinding(ctx context.Context, rb *influxdb.RoleBinding) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := rb.Validate(); err != nil {
+			return err
+		}
+
+		r, err := s.findRoleByID(ctx, tx, rb.RoleID)
+		if err != nil {
+			return err
+		}
+
+		if rb.MappingType == influxdb.GroupMappingType {
+			g, err := s.findGroupByID(ctx, tx, rb.UserID)
+			if err != nil {
+				return err
+			}
+			if g.OrgID != r.OrgID {
+				return &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "group must belong to the organization of the role",
+				}
+			}
+		} else {
+			if _, err := s.findUserByID(ctx, tx, rb.UserID); err != nil {
+				return err
+			}
+			if err := s.checkOrgMember(ctx, tx, rb.UserID, r.OrgID); err != nil {
+				return err
+			}
+		}
+
+		return s.putRoleBinding(ctx, tx, rb)
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateRoleBinding,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// checkOrgMember returns an error if the user is neither a member nor an owner of the org.
+func (s *Service) checkOrgMember(ctx context.Context, tx Tx, userID, orgID influxdb.ID) error {
+	_, err := s.findUserResourceMapping(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   orgID,
+		UserID:       userID,
+	})
+	if err == ErrURMNotFound {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "user must belong to the organization of the role",
+		}
+	}
+	return err
+}
+
+func (s *Service) putRoleBinding(ctx context.Context, tx Tx, rb *influxdb.RoleBinding) error {
+	v, err := json.Marshal(rb)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	key, err := roleBindingKey(rb.UserID, rb.RoleID)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(roleBindingBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(key, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// DeleteRoleBinding unbinds a user or a group from a role.
+func (s *Service) DeleteRoleBinding(ctx context.Context, roleID, userID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteRoleBinding(ctx, tx, roleID, userID)
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteRoleBinding,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteRoleBinding(ctx context.Context, tx Tx, roleID, userID influxdb.ID) error {
+	key, err := roleBindingKey(userID, roleID)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(roleBindingBucket)
+	if err != nil {
+		return err
+	}
+
+	if _, err := b.Get(key); IsNotFound(err) {
+		return &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrRoleBindingNotFound,
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if err := b.Delete(key); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// findRolePermissions returns the permissions of the roles bound to a user
+// and to the groups of the user.
+func (s *Service) findRolePermissions(ctx context.Context, tx Tx, userID influxdb.ID) ([]influxdb.Permission, error) {
+	bs, err := s.findRoleBindings(ctx, tx, influxdb.RoleBindingFilter{UserID: &userID})
+	if err != nil {
+		return nil, err
+	}
+
+	gms, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		UserID:       userID,
+		ResourceType: influxdb.GroupsResourceType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, gm := range gms {
+		gbs, err := s.findRoleBindings(ctx, tx, influxdb.RoleBindingFilter{UserID: &gm.ResourceID})
+		if err != nil {
+			return nil, err
+		}
+		for _, rb := range gbs {
+			if rb.MappingType == influxdb.GroupMappingType {
+				bs = append(bs, rb)
+			}
+		}
+	}
+
+	var ps []influxdb.Permission
+	for _, rb := range bs {
+		r, err := s.findRoleByID(ctx, tx, rb.RoleID)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, r.Permissions...)
+	}
+
+	return ps, nil
+}
@@ -0,0 +1,208 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func TestRoles(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	writeBuckets := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &org.ID},
+	}
+	role := &influxdb.Role{
+		OrgID:       org.ID,
+		Name:        "bucket writers",
+		Permissions: []influxdb.Permission{writeBuckets},
+	}
+	if err := svc.CreateRole(ctx, role); err != nil {
+		t.Fatal(err)
+	}
+
+	// Names are unique within an organization.
+	if err := svc.CreateRole(ctx, &influxdb.Role{OrgID: org.ID, Name: role.Name}); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	// Permissions are restricted to the organization of the role.
+	global := influxdb.Permission{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType}}
+	if err := svc.CreateRole(ctx, &influxdb.Role{OrgID: org.ID, Name: "global", Permissions: []influxdb.Permission{global}}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected an invalid role, got %v", err)
+	}
+
+	name := "writers"
+	if _, err := svc.UpdateRole(ctx, role.ID, influxdb.RoleUpdate{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	rs, n, err := svc.FindRoles(ctx, influxdb.RoleFilter{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || rs[0].ID != role.ID {
+		t.Fatalf("unexpected roles %+v", rs)
+	}
+	if err := svc.CreateRole(ctx, &influxdb.Role{OrgID: org.ID, Name: "bucket writers"}); err != nil {
+		t.Fatalf("previous name of a renamed role should be available: %v", err)
+	}
+
+	// Only the users of the organization of a role can be bound to it.
+	if err := svc.CreateRoleBinding(ctx, &influxdb.RoleBinding{RoleID: role.ID, UserID: user.ID}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected an invalid binding, got %v", err)
+	}
+	if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		UserID:       user.ID,
+		UserType:     influxdb.Member,
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   org.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sessions of the users bound to a role are granted its permissions.
+	if err := svc.CreateRoleBinding(ctx, &influxdb.RoleBinding{RoleID: role.ID, UserID: user.ID}); err != nil {
+		t.Fatal(err)
+	}
+	sess, err := svc.CreateSession(ctx, user.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err = svc.FindSession(ctx, sess.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sess.Allowed(writeBuckets) {
+		t.Fatalf("session should be allowed %s", writeBuckets)
+	}
+
+	bs, err := svc.FindRoleBindings(ctx, influxdb.RoleBindingFilter{RoleID: &role.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bs) != 1 || bs[0].UserID != user.ID {
+		t.Fatalf("unexpected role bindings %+v", bs)
+	}
+
+	// Deleting a role deletes its bindings.
+	if err := svc.DeleteRole(ctx, role.ID); err != nil {
+		t.Fatal(err)
+	}
+	if bs, err := svc.FindRoleBindings(ctx, influxdb.RoleBindingFilter{UserID: &user.ID}); err != nil || len(bs) != 0 {
+		t.Fatalf("unexpected role bindings %+v, %v", bs, err)
+	}
+	sess, err = svc.FindSession(ctx, sess.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.Allowed(writeBuckets) {
+		t.Fatalf("session should not be allowed %s", writeBuckets)
+	}
+}
+
+func TestRoles_GroupBinding(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	group := &influxdb.Group{OrgID: org.ID, Name: "engineers"}
+	if err := svc.CreateGroup(ctx, group); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		UserID:       user.ID,
+		UserType:     influxdb.Member,
+		ResourceType: influxdb.GroupsResourceType,
+		ResourceID:   group.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	readBuckets := influxdb.Permission{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &org.ID},
+	}
+	role := &influxdb.Role{
+		OrgID:       org.ID,
+		Name:        "bucket readers",
+		Permissions: []influxdb.Permission{readBuckets},
+	}
+	if err := svc.CreateRole(ctx, role); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only existing groups can be bound to a role.
+	missing := &influxdb.RoleBinding{RoleID: role.ID, UserID: influxdb.ID(1), MappingType: influxdb.GroupMappingType}
+	if err := svc.CreateRoleBinding(ctx, missing); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected a missing group, got %v", err)
+	}
+
+	// Sessions of the users of a group bound to a role are granted its permissions.
+	if err := svc.CreateRoleBinding(ctx, &influxdb.RoleBinding{RoleID: role.ID, UserID: group.ID, MappingType: influxdb.GroupMappingType}); err != nil {
+		t.Fatal(err)
+	}
+	sess, err := svc.CreateSession(ctx, user.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err = svc.FindSession(ctx, sess.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sess.Allowed(readBuckets) {
+		t.Fatalf("session should be allowed %s", readBuckets)
+	}
+
+	// Deleting a group deletes its bindings.
+	if err := svc.DeleteGroup(ctx, group.ID); err != nil {
+		t.Fatal(err)
+	}
+	if bs, err := svc.FindRoleBindings(ctx, influxdb.RoleBindingFilter{RoleID: &role.ID}); err != nil || len(bs) != 0 {
+		t.Fatalf("unexpected role bindings %+v, %v", bs, err)
+	}
+	sess, err = svc.FindSession(ctx, sess.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.Allowed(readBuckets) {
+		t.Fatalf("session should not be allowed %s", readBuckets)
+	}
+}
@@ -37,7 +37,7 @@ func NewService(kv Store, configs ...ServiceConfig) *Service {
 		IDGenerator:    snowflake.NewIDGenerator(),
 		TokenGenerator: rand.NewTokenGenerator(64),
 		Hash:           &Bcrypt{},
-		kv:             kv,
+		kv:             &auditRecordingStore{Store: kv},
 		TimeGenerator:  influxdb.RealTimeGenerator{},
 	}
 
@@ -53,6 +53,11 @@ func NewService(kv Store, configs ...ServiceConfig) *Service {
 // ServiceConfig allows us to configure Services
 type ServiceConfig struct {
 	SessionLength time.Duration
+
+	// PasswordPolicy is the policy checked when setting passwords.
+	PasswordPolicy influxdb.PasswordPolicy
+	// SigninLockoutPolicy is the lockout of users and addresses failing to sign in.
+	SigninLockoutPolicy influxdb.SigninLockoutPolicy
 }
 
 // Initialize creates Buckets needed.
@@ -62,6 +67,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeAuditLog(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeDocuments(ctx, tx); err != nil {
 			return err
 		}
@@ -74,6 +83,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeGroups(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeKVLog(ctx, tx); err != nil {
 			return err
 		}
@@ -102,6 +115,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeRoles(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeSecrets(ctx, tx); err != nil {
 			return err
 		}
@@ -110,6 +127,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeSigninAttempts(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeSources(ctx, tx); err != nil {
 			return err
 		}
@@ -141,5 +162,5 @@ func (s *Service) Initialize(ctx context.Context) error {
 // WithStore sets kv store for the service.
 // Should only be used in tests for mocking.
 func (s *Service) WithStore(store Store) {
-	s.kv = store
+	s.kv = &auditRecordingStore{Store: store}
 }
@@ -114,6 +114,18 @@ func (s *Service) findSession(ctx context.Context, tx Tx, key string) (*influxdb
 	}
 	ps = append(ps, influxdb.MePermissions(sn.UserID)...)
 
+	rps, err := s.findRolePermissions(ctx, tx, sn.UserID)
+	if err != nil {
+		return nil, err
+	}
+	ps = append(ps, rps...)
+
+	gps, err := s.findGroupPermissions(ctx, tx, sn.UserID, nil)
+	if err != nil {
+		return nil, err
+	}
+	ps = append(ps, gps...)
+
 	// TODO(desa): this is super expensive, we should keep a list of a users maximal privileges somewhere
 	// we did this so that the oper token would be used in a users permissions.
 	af := influxdb.AuthorizationFilter{UserID: &sn.UserID}
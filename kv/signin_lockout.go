@@ -0,0 +1,272 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	signinAttemptsBucket = []byte("signinattemptsv1")
+
+	// signinAttemptsPrunedKey holds the time the forgotten failed sign-ins were last pruned.
+	signinAttemptsPrunedKey = []byte("pruned")
+)
+
+var _ influxdb.SigninLockoutService = (*Service)(nil)
+
+// signinAttempts are the consecutive failed sign-ins of a user or an address.
+type signinAttempts struct {
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"lastFailure"`
+	LockedUntil time.Time `json:"lockedUntil,omitempty"`
+}
+
+func (s *Service) initializeSigninAttempts(ctx context.Context, tx Tx) error {
+	_, err := tx.Bucket(signinAttemptsBucket)
+	return err
+}
+
+func userSigninAttemptsKey(name string) []byte {
+	return []byte("user/" + name)
+}
+
+func addrSigninAttemptsKey(addr string) []byte {
+	return []byte("ip/" + addr)
+}
+
+func signinAttemptsKeys(name, addr string) [][]byte {
+	keys := [][]byte{userSigninAttemptsKey(name)}
+	if addr != "" {
+		keys = append(keys, addrSigninAttemptsKey(addr))
+	}
+	return keys
+}
+
+// RecordSigninAttempt returns an error if the user name or the address addr is locked out.
+// Otherwise it records a sign-in attempt of the user name from the address addr, which
+// counts as failed until RecordSigninSuccess is called, and locks them out after too many
+// consecutive failures.
+func (s *Service) RecordSigninAttempt(ctx context.Context, name, addr string) error {
+	policy := s.Config.SigninLockoutPolicy
+	if policy.MaxAttempts <= 0 {
+		return nil
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		now := s.Now()
+		if err := s.pruneSigninAttempts(ctx, tx, now); err != nil {
+			return err
+		}
+
+		keys := signinAttemptsKeys(name, addr)
+		attempts := make([]*signinAttempts, len(keys))
+		for i, k := range keys {
+			a, err := s.findSigninAttempts(ctx, tx, k)
+			if err != nil {
+				return err
+			}
+			if now.Before(a.LockedUntil) {
+				return &influxdb.Error{
+					Code: influxdb.ETooManyRequests,
+					Msg:  influxdb.ErrSigninLocked,
+				}
+			}
+			attempts[i] = a
+		}
+
+		for i, k := range keys {
+			a := attempts[i]
+			if policy.MaxDuration > 0 && now.Sub(a.LastFailure) > policy.MaxDuration {
+				a = &signinAttempts{}
+			}
+			a.Failures++
+			a.LastFailure = now
+
+			if d := policy.LockoutDuration(a.Failures); d > 0 {
+				a.LockedUntil = now.Add(d)
+				if err := s.signinLockedOut(ctx, tx, name, addr, k, a); err != nil {
+					return err
+				}
+			}
+
+			if err := s.putSigninAttempts(ctx, tx, k, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RecordSigninSuccess resets the failed sign-ins of the user name, and withdraws
+// the attempt recorded for the address addr.
+func (s *Service) RecordSigninSuccess(ctx context.Context, name, addr string) error {
+	policy := s.Config.SigninLockoutPolicy
+	return s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(signinAttemptsBucket)
+		if err != nil {
+			return err
+		}
+		if err := b.Delete(userSigninAttemptsKey(name)); err != nil {
+			return err
+		}
+		if addr == "" {
+			return nil
+		}
+
+		k := addrSigninAttemptsKey(addr)
+		a, err := s.findSigninAttempts(ctx, tx, k)
+		if err != nil {
+			return err
+		}
+		if a.Failures <= 1 {
+			return b.Delete(k)
+		}
+
+		// The address was not locked out when the attempt was recorded, so any
+		// lockout it no longer accounts for was caused by the attempt itself.
+		a.Failures--
+		if policy.LockoutDuration(a.Failures) == 0 {
+			a.LockedUntil = time.Time{}
+		}
+		return s.putSigninAttempts(ctx, tx, k, a)
+	})
+}
+
+// pruneSigninAttempts deletes the failed sign-ins that are forgotten, once per maximum lockout
+// duration, so that the attempts of the addresses that stop signing in do not accumulate.
+// Failed sign-ins are never forgotten without a maximum lockout duration.
+func (s *Service) pruneSigninAttempts(ctx context.Context, tx Tx, now time.Time) error {
+	maxDuration := s.Config.SigninLockoutPolicy.MaxDuration
+	if maxDuration <= 0 {
+		return nil
+	}
+
+	b, err := tx.Bucket(signinAttemptsBucket)
+	if err != nil {
+		return err
+	}
+
+	var prunedAt time.Time
+	v, err := b.Get(signinAttemptsPrunedKey)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		if err := prunedAt.UnmarshalText(v); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+	}
+	if now.Sub(prunedAt) < maxDuration {
+		return nil
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var stale [][]byte
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		if string(k) == string(signinAttemptsPrunedKey) {
+			continue
+		}
+		a := &signinAttempts{}
+		if err := json.Unmarshal(v, a); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if !now.Before(a.LockedUntil) && now.Sub(a.LastFailure) > maxDuration {
+			// Keys are copied since they are only valid during the iteration.
+			stale = append(stale, append([]byte(nil), k...))
+		}
+	}
+	for _, k := range stale {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	v, err = now.MarshalText()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return b.Put(signinAttemptsPrunedKey, v)
+}
+
+// signinLockedOut logs the lockout of the user or the address, and appends it
+// to the operation log of the user.
+func (s *Service) signinLockedOut(ctx context.Context, tx Tx, name, addr string, k []byte, a *signinAttempts) error {
+	if string(k) != string(userSigninAttemptsKey(name)) {
+		s.Logger.Warn("address locked out after failed sign-ins",
+			zap.String("addr", addr),
+			zap.Int("failures", a.Failures),
+			zap.Time("lockedUntil", a.LockedUntil))
+		return nil
+	}
+
+	s.Logger.Warn("user locked out after failed sign-ins",
+		zap.String("user", name),
+		zap.String("addr", addr),
+		zap.Int("failures", a.Failures),
+		zap.Time("lockedUntil", a.LockedUntil))
+
+	u, err := s.findUserByName(ctx, tx, name)
+	if err != nil {
+		// Unknown users have no operation log.
+		return nil
+	}
+	return s.appendUserEventToLog(ctx, tx, u.ID, userLockedOutEvent)
+}
+
+func (s *Service) findSigninAttempts(ctx context.Context, tx Tx, k []byte) (*signinAttempts, error) {
+	b, err := tx.Bucket(signinAttemptsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return &signinAttempts{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a := &signinAttempts{}
+	if err := json.Unmarshal(v, a); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return a, nil
+}
+
+func (s *Service) putSigninAttempts(ctx context.Context, tx Tx, k []byte, a *signinAttempts) error {
+	v, err := json.Marshal(a)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(signinAttemptsBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(k, v)
+}
@@ -0,0 +1,150 @@
+package kv_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestService_SigninLockout(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	now := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	svc := kv.NewService(store, kv.ServiceConfig{
+		SessionLength: influxdb.DefaultSessionLength,
+		SigninLockoutPolicy: influxdb.SigninLockoutPolicy{
+			MaxAttempts: 3,
+			Duration:    time.Minute,
+			MaxDuration: time.Hour,
+		},
+	})
+	setNow := func(t time.Time) {
+		svc.TimeGenerator = mock.TimeGenerator{FakeValue: t}
+	}
+	setNow(now)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	u := &influxdb.User{Name: "jane"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	attempt := func(name, addr string, wantLocked bool) {
+		t.Helper()
+		err := svc.RecordSigninAttempt(ctx, name, addr)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.ETooManyRequests {
+			t.Fatal(err)
+		}
+		if got := err != nil; got != wantLocked {
+			t.Fatalf("%s from %s: got locked %v, want %v", name, addr, got, wantLocked)
+		}
+	}
+	succeed := func(name, addr string) {
+		t.Helper()
+		if err := svc.RecordSigninSuccess(ctx, name, addr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	attempt("jane", "10.0.0.1", false)
+	attempt("jane", "10.0.0.2", false)
+	attempt("jane", "10.0.0.3", false)
+	attempt("jane", "10.0.0.4", true)
+	attempt("john", "10.0.0.1", false)
+
+	// The first lockout expires after a minute, the next failure doubles it.
+	setNow(now.Add(time.Minute + time.Second))
+	attempt("jane", "10.0.0.4", false)
+	setNow(now.Add(2 * time.Minute))
+	attempt("jane", "10.0.0.4", true)
+
+	// A successful sign-in resets the failures of the user.
+	setNow(now.Add(3*time.Minute + 2*time.Second))
+	attempt("jane", "10.0.0.5", false)
+	succeed("jane", "10.0.0.5")
+	attempt("jane", "10.0.0.6", false)
+	attempt("jane", "10.0.0.6", false)
+
+	// Failures of any user lock out the address.
+	attempt("a", "10.0.0.7", false)
+	attempt("b", "10.0.0.7", false)
+	attempt("c", "10.0.0.7", false)
+	attempt("d", "10.0.0.7", true)
+
+	// A successful sign-in withdraws its attempt from the address, along with the lockout it caused.
+	attempt("e", "10.0.0.8", false)
+	attempt("f", "10.0.0.8", false)
+	attempt("g", "10.0.0.8", false)
+	succeed("g", "10.0.0.8")
+	attempt("h", "10.0.0.8", false)
+	attempt("i", "10.0.0.8", true)
+
+	log, _, err := svc.GetUserOperationLog(ctx, u.ID, influxdb.FindOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lockouts int
+	for _, e := range log {
+		if e.Description == "User Locked Out" {
+			lockouts++
+		}
+	}
+	if lockouts != 3 {
+		t.Errorf("got %d lockouts in the user operation log, want 3", lockouts)
+	}
+
+	// Concurrent sign-ins are not allowed more attempts than the policy.
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := svc.RecordSigninAttempt(ctx, "k", fmt.Sprintf("10.0.1.%d", i)); err == nil {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if allowed != 3 {
+		t.Errorf("got %d concurrent sign-ins allowed, want 3", allowed)
+	}
+
+	// Failed sign-ins older than the maximum lockout duration are pruned.
+	setNow(now.Add(2 * time.Hour))
+	attempt("l", "10.0.0.9", false)
+	err = store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("signinattemptsv1"))
+		if err != nil {
+			return err
+		}
+		for _, k := range []string{"user/jane", "ip/10.0.0.1", "ip/10.0.0.7"} {
+			if _, err := b.Get([]byte(k)); !kv.IsNotFound(err) {
+				return fmt.Errorf("expected the failed sign-ins of %s to be pruned, got %v", k, err)
+			}
+		}
+		_, err = b.Get([]byte("ip/10.0.0.9"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
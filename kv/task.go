@@ -44,7 +44,7 @@ func (s *Service) initializeTasks(ctx context.Context, tx Tx) error {
 	if _, err := tx.Bucket(taskIndexBucket); err != nil {
 		return err
 	}
-	return nil
+	return s.initializeTaskLeases(ctx, tx)
 }
 
 // FindTaskByID returns a single task
@@ -736,6 +736,11 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 			return influxdb.ErrUnexpectedTaskBucketErr(err)
 		}
 	}
+	// remove the lease
+	if err := s.deleteTaskLease(ctx, tx, task.ID); err != nil {
+		return err
+	}
+
 	// remove the task
 	key, err := taskKey(task.ID)
 	if err != nil {
@@ -1570,6 +1575,34 @@ func (s *Service) finishRun(ctx context.Context, tx Tx, taskID, runID influxdb.I
 	return r, nil
 }
 
+// SetLatestCompleted moves the task's latest completed time forward to latestCompleted.
+// If the task has already completed a later run, it is left unchanged.
+func (s *Service) SetLatestCompleted(ctx context.Context, taskID influxdb.ID, latestCompleted time.Time) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.setLatestCompleted(ctx, tx, taskID, latestCompleted)
+	})
+}
+
+func (s *Service) setLatestCompleted(ctx context.Context, tx Tx, taskID influxdb.ID, latestCompleted time.Time) error {
+	task, err := s.findTaskByID(ctx, tx, taskID)
+	if err != nil {
+		return err
+	}
+
+	current, err := time.Parse(time.RFC3339, task.LatestCompleted)
+	if err != nil {
+		return influxdb.ErrTaskTimeParse(err)
+	}
+
+	if !latestCompleted.After(current) {
+		return nil
+	}
+
+	lc := latestCompleted.UTC().Format(time.RFC3339)
+	_, err = s.updateTask(ctx, tx, taskID, influxdb.TaskUpdate{LatestCompleted: &lc})
+	return err
+}
+
 // NextDueRun returns the Unix timestamp of when the next call to CreateNextRun will be ready.
 // The returned timestamp reflects the task's offset, so it does not necessarily exactly match the schedule time.
 func (s *Service) NextDueRun(ctx context.Context, taskID influxdb.ID) (int64, error) {
@@ -1704,6 +1737,44 @@ func (s *Service) addRunLog(ctx context.Context, tx Tx, taskID, runID influxdb.I
 	return nil
 }
 
+// SetRunStatistics records the statistics of the query executed by the run.
+func (s *Service) SetRunStatistics(ctx context.Context, taskID, runID influxdb.ID, stats influxdb.RunStatistics) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.setRunStatistics(ctx, tx, taskID, runID, stats)
+	})
+	return err
+}
+
+func (s *Service) setRunStatistics(ctx context.Context, tx Tx, taskID, runID influxdb.ID, stats influxdb.RunStatistics) error {
+	// find run
+	run, err := s.findRunByID(ctx, tx, taskID, runID)
+	if err != nil {
+		return err
+	}
+	run.Statistics = &stats
+	// save run
+	b, err := tx.Bucket(taskRunBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	runBytes, err := json.Marshal(run)
+	if err != nil {
+		return influxdb.ErrInternalTaskServiceError(err)
+	}
+
+	runKey, err := taskRunKey(taskID, run.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(runKey, runBytes); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	return nil
+}
+
 func (s *Service) findLatestCompleted(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Run, error) {
 	bucket, err := tx.Bucket(taskRunBucket)
 	if err != nil {
@@ -0,0 +1,246 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend"
+)
+
+// Task Lease Storage Schema
+// taskLeaseBucket:
+//   <taskID>: lease of the scheduler that owns the task
+// taskOwnerBucket:
+//   <owner>: time until which the owner is considered alive
+
+var (
+	taskLeaseBucket = []byte("taskLeasesv1")
+	taskOwnerBucket = []byte("taskOwnersv1")
+)
+
+var _ backend.TaskLeaseService = (*Service)(nil)
+
+func (s *Service) initializeTaskLeases(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(taskLeaseBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(taskOwnerBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AcquireTaskLease takes or renews the lease on a task for owner, expiring ttl from now.
+func (s *Service) AcquireTaskLease(ctx context.Context, taskID influxdb.ID, owner string, ttl time.Duration) (*backend.TaskLease, error) {
+	var l *backend.TaskLease
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		lease, err := s.acquireTaskLease(ctx, tx, taskID, owner, ttl)
+		if err != nil {
+			return err
+		}
+		l = lease
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (s *Service) acquireTaskLease(ctx context.Context, tx Tx, taskID influxdb.ID, owner string, ttl time.Duration) (*backend.TaskLease, error) {
+	now := s.Now()
+	current, err := s.findTaskLease(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.Owner != owner && !current.Expired(now) {
+		return nil, influxdb.ErrTaskLeaseHeld
+	}
+
+	lease := &backend.TaskLease{
+		TaskID:    taskID,
+		Owner:     owner,
+		ExpiresAt: now.Add(ttl).UTC(),
+	}
+	if err := s.putTaskLease(ctx, tx, lease); err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+// ReleaseTaskLease gives up owner's lease on a task.
+func (s *Service) ReleaseTaskLease(ctx context.Context, taskID influxdb.ID, owner string) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		current, err := s.findTaskLease(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.Owner != owner {
+			return nil
+		}
+		return s.deleteTaskLease(ctx, tx, taskID)
+	})
+}
+
+// FindTaskLeases returns all task leases, including expired ones.
+func (s *Service) FindTaskLeases(ctx context.Context) ([]*backend.TaskLease, error) {
+	var leases []*backend.TaskLease
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(taskLeaseBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			lease := &backend.TaskLease{}
+			if err := json.Unmarshal(v, lease); err != nil {
+				return influxdb.ErrInternalTaskServiceError(err)
+			}
+			leases = append(leases, lease)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+func (s *Service) findTaskLease(ctx context.Context, tx Tx, taskID influxdb.ID) (*backend.TaskLease, error) {
+	key, err := taskKey(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := tx.Bucket(taskLeaseBucket)
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	lease := &backend.TaskLease{}
+	if err := json.Unmarshal(v, lease); err != nil {
+		return nil, influxdb.ErrInternalTaskServiceError(err)
+	}
+	return lease, nil
+}
+
+func (s *Service) putTaskLease(ctx context.Context, tx Tx, lease *backend.TaskLease) error {
+	key, err := taskKey(lease.TaskID)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(taskLeaseBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	v, err := json.Marshal(lease)
+	if err != nil {
+		return influxdb.ErrInternalTaskServiceError(err)
+	}
+
+	if err := b.Put(key, v); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	return nil
+}
+
+func (s *Service) deleteTaskLease(ctx context.Context, tx Tx, taskID influxdb.ID) error {
+	key, err := taskKey(taskID)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(taskLeaseBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	if err := b.Delete(key); err != nil && !IsNotFound(err) {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	return nil
+}
+
+// RegisterTaskOwner announces that owner is alive, until ttl from now.
+func (s *Service) RegisterTaskOwner(ctx context.Context, owner string, ttl time.Duration) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(taskOwnerBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		v, err := s.Now().Add(ttl).UTC().MarshalText()
+		if err != nil {
+			return influxdb.ErrInternalTaskServiceError(err)
+		}
+
+		if err := b.Put([]byte(owner), v); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+		return nil
+	})
+}
+
+// UnregisterTaskOwner removes the registration of owner.
+func (s *Service) UnregisterTaskOwner(ctx context.Context, owner string) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(taskOwnerBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		if err := b.Delete([]byte(owner)); err != nil && !IsNotFound(err) {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+		return nil
+	})
+}
+
+// FindTaskOwners returns the owners whose registration has not expired.
+func (s *Service) FindTaskOwners(ctx context.Context) ([]string, error) {
+	var owners []string
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(taskOwnerBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		now := s.Now()
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			var expiresAt time.Time
+			if err := expiresAt.UnmarshalText(v); err != nil {
+				return influxdb.ErrInternalTaskServiceError(err)
+			}
+			if now.Before(expiresAt) {
+				owners = append(owners, string(k))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
@@ -0,0 +1,123 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestTaskLeases(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	svc := kv.NewService(store)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	const taskID influxdb.ID = 1
+	const ttl = time.Minute
+
+	lease, err := svc.AcquireTaskLease(ctx, taskID, "a", ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.Owner != "a" || !lease.ExpiresAt.Equal(now.Add(ttl)) {
+		t.Fatalf("unexpected lease %+v", lease)
+	}
+
+	// Another owner cannot take an unexpired lease, but the owner can renew it.
+	if _, err := svc.AcquireTaskLease(ctx, taskID, "b", ttl); err != influxdb.ErrTaskLeaseHeld {
+		t.Fatalf("expected %v, got %v", influxdb.ErrTaskLeaseHeld, err)
+	}
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now.Add(ttl / 2)}
+	if _, err := svc.AcquireTaskLease(ctx, taskID, "a", ttl); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once the lease expires, another owner takes it over.
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now.Add(2 * ttl)}
+	if _, err := svc.AcquireTaskLease(ctx, taskID, "b", ttl); err != nil {
+		t.Fatal(err)
+	}
+
+	// Releasing a lease held by another owner is a no-op.
+	if err := svc.ReleaseTaskLease(ctx, taskID, "a"); err != nil {
+		t.Fatal(err)
+	}
+	leases, err := svc.FindTaskLeases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leases) != 1 || leases[0].Owner != "b" {
+		t.Fatalf("unexpected leases %+v", leases)
+	}
+
+	if err := svc.ReleaseTaskLease(ctx, taskID, "b"); err != nil {
+		t.Fatal(err)
+	}
+	leases, err = svc.FindTaskLeases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leases) != 0 {
+		t.Fatalf("expected no leases, got %+v", leases)
+	}
+}
+
+func TestTaskOwners(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	svc := kv.NewService(store)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.RegisterTaskOwner(ctx, "a", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RegisterTaskOwner(ctx, "b", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RegisterTaskOwner(ctx, "c", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UnregisterTaskOwner(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	owners, err := svc.FindTaskOwners(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owners) != 2 || owners[0] != "a" || owners[1] != "b" {
+		t.Fatalf("unexpected owners %v", owners)
+	}
+
+	// Owners that stop registering expire.
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now.Add(2 * time.Minute)}
+	owners, err = svc.FindTaskOwners(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owners) != 1 || owners[0] != "b" {
+		t.Fatalf("unexpected owners %v", owners)
+	}
+}
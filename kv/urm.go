@@ -125,11 +125,39 @@ func (s *Service) findUserResourceMapping(ctx context.Context, tx Tx, filter inf
 // CreateUserResourceMapping associates a user to a resource either as a member
 // or owner.
 func (s *Service) CreateUserResourceMapping(ctx context.Context, m *influxdb.UserResourceMapping) error {
+	if m.MappingType == influxdb.GroupMappingType {
+		if err := s.checkGroupResourceOrg(ctx, m); err != nil {
+			return err
+		}
+	}
+
 	return s.kv.Update(ctx, func(tx Tx) error {
 		return s.createUserResourceMapping(ctx, tx, m)
 	})
 }
 
+// checkGroupResourceOrg checks that a group is only mapped to the resources of its own organization.
+func (s *Service) checkGroupResourceOrg(ctx context.Context, m *influxdb.UserResourceMapping) error {
+	g, err := s.FindGroupByID(ctx, m.UserID)
+	if err != nil {
+		return err
+	}
+
+	orgID, err := s.FindResourceOrganizationID(ctx, m.ResourceType, m.ResourceID)
+	if err != nil {
+		return err
+	}
+
+	if orgID != g.OrgID {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "group and resource must belong to the same organization",
+		}
+	}
+
+	return nil
+}
+
 func (s *Service) createUserResourceMapping(ctx context.Context, tx Tx, m *influxdb.UserResourceMapping) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
@@ -138,6 +166,12 @@ func (s *Service) createUserResourceMapping(ctx context.Context, tx Tx, m *influ
 		return err
 	}
 
+	if m.MappingType == influxdb.GroupMappingType {
+		if _, err := s.findGroupByID(ctx, tx, m.UserID); err != nil {
+			return err
+		}
+	}
+
 	v, err := json.Marshal(m)
 	if err != nil {
 		return ErrUnprocessableMapping(err)
@@ -180,6 +214,7 @@ func (s *Service) createOrgDependentMappings(ctx context.Context, tx Tx, m *infl
 			ResourceID:   b.ID,
 			UserType:     m.UserType,
 			UserID:       m.UserID,
+			MappingType:  m.MappingType,
 		}
 		if err := s.createUserResourceMapping(ctx, tx, m); err != nil {
 			return err
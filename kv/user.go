@@ -11,8 +11,9 @@ import (
 )
 
 var (
-	userBucket = []byte("usersv1")
-	userIndex  = []byte("userindexv1")
+	userBucket     = []byte("usersv1")
+	userIndex      = []byte("userindexv1")
+	userOAuthIndex = []byte("useroauthindexv1")
 )
 
 var _ influxdb.UserService = (*Service)(nil)
@@ -27,6 +28,10 @@ func (s *Service) initializeUsers(ctx context.Context, tx Tx) error {
 	if _, err := s.userIndexBucket(tx); err != nil {
 		return err
 	}
+
+	if _, err := s.userOAuthIndexBucket(tx); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -48,6 +53,15 @@ func (s *Service) userIndexBucket(tx Tx) (Bucket, error) {
 	return b, nil
 }
 
+func (s *Service) userOAuthIndexBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(userOAuthIndex)
+	if err != nil {
+		return nil, UnexpectedUserIndexError(err)
+	}
+
+	return b, nil
+}
+
 // FindUserByID retrieves a user by id.
 func (s *Service) FindUserByID(ctx context.Context, id influxdb.ID) (*influxdb.User, error) {
 	var u *influxdb.User
@@ -148,8 +162,45 @@ func (s *Service) findUserByName(ctx context.Context, tx Tx, n string) (*influxd
 	return s.findUserByID(ctx, tx, id)
 }
 
+// FindUserByOAuthID returns the user linked to the OAuth ID.
+func (s *Service) FindUserByOAuthID(ctx context.Context, oauthID string) (*influxdb.User, error) {
+	var u *influxdb.User
+
+	err := s.kv.View(ctx, func(tx Tx) error {
+		usr, err := s.findUserByOAuthID(ctx, tx, oauthID)
+		if err != nil {
+			return err
+		}
+		u = usr
+		return nil
+	})
+
+	return u, err
+}
+
+func (s *Service) findUserByOAuthID(ctx context.Context, tx Tx, oauthID string) (*influxdb.User, error) {
+	b, err := s.userOAuthIndexBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	uid, err := b.Get(userIndexKey(oauthID))
+	if err == ErrKeyNotFound {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, ErrInternalUserServiceError(err)
+	}
+
+	var id influxdb.ID
+	if err := id.Decode(uid); err != nil {
+		return nil, ErrCorruptUserID(err)
+	}
+	return s.findUserByID(ctx, tx, id)
+}
+
 // FindUser retrives a user using an arbitrary user filter.
-// Filters using ID, or Name should be efficient.
+// Filters using ID, Name or OAuthID should be efficient.
 // Other filters will do a linear scan across users until it finds a match.
 func (s *Service) FindUser(ctx context.Context, filter influxdb.UserFilter) (*influxdb.User, error) {
 	if filter.ID != nil {
@@ -164,6 +215,10 @@ func (s *Service) FindUser(ctx context.Context, filter influxdb.UserFilter) (*in
 		return s.FindUserByName(ctx, *filter.Name)
 	}
 
+	if filter.OAuthID != nil {
+		return s.FindUserByOAuthID(ctx, *filter.OAuthID)
+	}
+
 	return nil, ErrUserNotFound
 }
 
@@ -180,11 +235,17 @@ func filterUsersFn(filter influxdb.UserFilter) func(u *influxdb.User) bool {
 		}
 	}
 
+	if filter.OAuthID != nil {
+		return func(u *influxdb.User) bool {
+			return u.OAuthID == *filter.OAuthID
+		}
+	}
+
 	return func(u *influxdb.User) bool { return true }
 }
 
 // FindUsers retrives all users that match an arbitrary user filter.
-// Filters using ID, or Name should be efficient.
+// Filters using ID, Name or OAuthID should be efficient.
 // Other filters will do a linear scan across all users searching for a match.
 func (s *Service) FindUsers(ctx context.Context, filter influxdb.UserFilter, opt ...influxdb.FindOptions) ([]*influxdb.User, int, error) {
 	if filter.ID != nil {
@@ -205,6 +266,15 @@ func (s *Service) FindUsers(ctx context.Context, filter influxdb.UserFilter, opt
 		return []*influxdb.User{u}, 1, nil
 	}
 
+	if filter.OAuthID != nil {
+		u, err := s.FindUserByOAuthID(ctx, *filter.OAuthID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return []*influxdb.User{u}, 1, nil
+	}
+
 	us := []*influxdb.User{}
 	filterFn := filterUsersFn(filter)
 	err := s.kv.View(ctx, func(tx Tx) error {
@@ -235,6 +305,10 @@ func (s *Service) createUser(ctx context.Context, tx Tx, u *influxdb.User) error
 		return err
 	}
 
+	if err := s.uniqueUserOAuthID(ctx, tx, u.OAuthID); err != nil {
+		return err
+	}
+
 	u.ID = s.IDGenerator.ID()
 	if err := s.appendUserEventToLog(ctx, tx, u.ID, userCreatedEvent); err != nil {
 		return err
@@ -269,6 +343,17 @@ func (s *Service) putUser(ctx context.Context, tx Tx, u *influxdb.User) error {
 		return ErrInternalUserServiceError(err)
 	}
 
+	if u.OAuthID != "" {
+		oidx, err := s.userOAuthIndexBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		if err := oidx.Put(userIndexKey(u.OAuthID), encodedID); err != nil {
+			return ErrInternalUserServiceError(err)
+		}
+	}
+
 	b, err := s.userBucket(tx)
 	if err != nil {
 		return err
@@ -322,6 +407,19 @@ func (s *Service) uniqueUserName(ctx context.Context, tx Tx, u *influxdb.User) e
 	return err
 }
 
+// uniqueUserOAuthID returns an error if another user is already linked to the OAuth ID.
+func (s *Service) uniqueUserOAuthID(ctx context.Context, tx Tx, oauthID string) error {
+	if oauthID == "" {
+		return nil
+	}
+
+	err := s.unique(ctx, tx, userOAuthIndex, userIndexKey(oauthID))
+	if err == NotUniqueError {
+		return UserOAuthIDAlreadyExistsError(oauthID)
+	}
+	return err
+}
+
 // UpdateUser updates a user according the parameters set on upd.
 func (s *Service) UpdateUser(ctx context.Context, id influxdb.ID, upd influxdb.UserUpdate) (*influxdb.User, error) {
 	var u *influxdb.User
@@ -355,6 +453,17 @@ func (s *Service) updateUser(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		u.Name = *upd.Name
 	}
 
+	if upd.OAuthID != nil && *upd.OAuthID != u.OAuthID {
+		if err := s.uniqueUserOAuthID(ctx, tx, *upd.OAuthID); err != nil {
+			return nil, err
+		}
+		if err := s.removeUserFromOAuthIndex(ctx, tx, u.OAuthID); err != nil {
+			return nil, err
+		}
+
+		u.OAuthID = *upd.OAuthID
+	}
+
 	if err := s.appendUserEventToLog(ctx, tx, u.ID, userUpdatedEvent); err != nil {
 		return nil, err
 	}
@@ -381,6 +490,24 @@ func (s *Service) removeUserFromIndex(ctx context.Context, tx Tx, id influxdb.ID
 	return nil
 }
 
+// removeUserFromOAuthIndex unlinks the OAuth ID from its user.
+func (s *Service) removeUserFromOAuthIndex(ctx context.Context, tx Tx, oauthID string) error {
+	if oauthID == "" {
+		return nil
+	}
+
+	idx, err := s.userOAuthIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Delete(userIndexKey(oauthID)); err != nil {
+		return ErrInternalUserServiceError(err)
+	}
+
+	return nil
+}
+
 // DeleteUser deletes a user and prunes it from the index.
 func (s *Service) DeleteUser(ctx context.Context, id influxdb.ID) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
@@ -412,6 +539,10 @@ func (s *Service) deleteUser(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return ErrInternalUserServiceError(err)
 	}
 
+	if err := s.removeUserFromOAuthIndex(ctx, tx, u.OAuthID); err != nil {
+		return err
+	}
+
 	b, err := s.userBucket(tx)
 	if err != nil {
 		return err
@@ -480,8 +611,9 @@ const userOperationLogKeyPrefix = "user"
 
 // TODO(desa): what do we want these to be?
 const (
-	userCreatedEvent = "User Created"
-	userUpdatedEvent = "User Updated"
+	userCreatedEvent   = "User Created"
+	userUpdatedEvent   = "User Updated"
+	userLockedOutEvent = "User Locked Out"
 )
 
 func encodeUserOperationLogKey(id influxdb.ID) ([]byte, error) {
@@ -543,6 +675,15 @@ func UserAlreadyExistsError(n string) *influxdb.Error {
 	}
 }
 
+// UserOAuthIDAlreadyExistsError is used when attempting to link a user to an OAuth ID
+// that is already linked to another user.
+func UserOAuthIDAlreadyExistsError(oauthID string) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  fmt.Sprintf("user with oauth id %s already exists", oauthID),
+	}
+}
+
 // UnexpectedUserBucketError is used when the error comes from an internal system.
 func UnexpectedUserBucketError(err error) *influxdb.Error {
 	return &influxdb.Error{
@@ -66,3 +66,64 @@ func initUserService(s kv.Store, f influxdbtesting.UserFields, t *testing.T) (in
 		}
 	}
 }
+
+func TestUserService_OAuthID(t *testing.T) {
+	store, closeStore, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(store)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	jane := &influxdb.User{Name: "jane", OAuthID: influxdb.OAuthID("oidc", "jane@example.com")}
+	if err := svc.CreateUser(ctx, jane); err != nil {
+		t.Fatal(err)
+	}
+	local := &influxdb.User{Name: "jane@example.com"}
+	if err := svc.CreateUser(ctx, local); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := svc.FindUser(ctx, influxdb.UserFilter{OAuthID: &jane.OAuthID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != jane.ID {
+		t.Errorf("found user %s, want %s", u.ID, jane.ID)
+	}
+
+	// An identity is linked to a single user.
+	err = svc.CreateUser(ctx, &influxdb.User{Name: "jane2", OAuthID: jane.OAuthID})
+	if influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Errorf("got error %v, want conflict", err)
+	}
+	_, err = svc.UpdateUser(ctx, local.ID, influxdb.UserUpdate{OAuthID: &jane.OAuthID})
+	if influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Errorf("got error %v, want conflict", err)
+	}
+
+	// Relinking the user unlinks its previous identity.
+	github := influxdb.OAuthID("github", "jane")
+	if _, err := svc.UpdateUser(ctx, jane.ID, influxdb.UserUpdate{OAuthID: &github}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindUser(ctx, influxdb.UserFilter{OAuthID: &jane.OAuthID}); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("got error %v, want not found", err)
+	}
+	if u, err := svc.FindUser(ctx, influxdb.UserFilter{OAuthID: &github}); err != nil || u.ID != jane.ID {
+		t.Errorf("got user %v and error %v, want %s", u, err, jane.ID)
+	}
+
+	// Deleting the user unlinks its identity.
+	if err := svc.DeleteUser(ctx, jane.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindUser(ctx, influxdb.UserFilter{OAuthID: &github}); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("got error %v, want not found", err)
+	}
+}
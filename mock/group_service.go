@@ -0,0 +1,69 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.GroupService = &GroupService{}
+
+// GroupService is a mock implementation of platform.GroupService
+type GroupService struct {
+	FindGroupByIDFn func(context.Context, platform.ID) (*platform.Group, error)
+	FindGroupsFn    func(context.Context, platform.GroupFilter, ...platform.FindOptions) ([]*platform.Group, int, error)
+	CreateGroupFn   func(context.Context, *platform.Group) error
+	UpdateGroupFn   func(context.Context, platform.ID, platform.GroupUpdate) (*platform.Group, error)
+	DeleteGroupFn   func(context.Context, platform.ID) error
+}
+
+// NewGroupService returns a mock of GroupService
+// where its methods will return zero values.
+func NewGroupService() *GroupService {
+	return &GroupService{
+		FindGroupByIDFn: func(context.Context, platform.ID) (*platform.Group, error) { return nil, nil },
+		FindGroupsFn: func(context.Context, platform.GroupFilter, ...platform.FindOptions) ([]*platform.Group, int, error) {
+			return nil, 0, nil
+		},
+		CreateGroupFn: func(context.Context, *platform.Group) error { return nil },
+		UpdateGroupFn: func(context.Context, platform.ID, platform.GroupUpdate) (*platform.Group, error) { return nil, nil },
+		DeleteGroupFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindGroupByID finds a group by its ID.
+func (s *GroupService) FindGroupByID(ctx context.Context, id platform.ID) (*platform.Group, error) {
+	return s.FindGroupByIDFn(ctx, id)
+}
+
+// FindGroups finds groups that match a given filter.
+func (s *GroupService) FindGroups(ctx context.Context, filter platform.GroupFilter, opt ...platform.FindOptions) ([]*platform.Group, int, error) {
+	return s.FindGroupsFn(ctx, filter, opt...)
+}
+
+// CreateGroup creates a group.
+func (s *GroupService) CreateGroup(ctx context.Context, g *platform.Group) error {
+	return s.CreateGroupFn(ctx, g)
+}
+
+// UpdateGroup updates a group.
+func (s *GroupService) UpdateGroup(ctx context.Context, id platform.ID, upd platform.GroupUpdate) (*platform.Group, error) {
+	return s.UpdateGroupFn(ctx, id, upd)
+}
+
+// DeleteGroup deletes a group.
+func (s *GroupService) DeleteGroup(ctx context.Context, id platform.ID) error {
+	return s.DeleteGroupFn(ctx, id)
+}
+
+var _ platform.GroupPermissionService = &GroupPermissionService{}
+
+// GroupPermissionService is a mock implementation of platform.GroupPermissionService
+type GroupPermissionService struct {
+	FindGroupPermissionsFn func(context.Context, platform.ID, platform.ID) ([]platform.Permission, error)
+}
+
+// FindGroupPermissions returns the permissions granted to a user by the groups of an org.
+func (s *GroupPermissionService) FindGroupPermissions(ctx context.Context, userID, orgID platform.ID) ([]platform.Permission, error) {
+	return s.FindGroupPermissionsFn(ctx, userID, orgID)
+}
@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.RoleService = &RoleService{}
+
+// RoleService is a mock implementation of platform.RoleService
+type RoleService struct {
+	FindRoleByIDFn      func(context.Context, platform.ID) (*platform.Role, error)
+	FindRolesFn         func(context.Context, platform.RoleFilter, ...platform.FindOptions) ([]*platform.Role, int, error)
+	CreateRoleFn        func(context.Context, *platform.Role) error
+	UpdateRoleFn        func(context.Context, platform.ID, platform.RoleUpdate) (*platform.Role, error)
+	DeleteRoleFn        func(context.Context, platform.ID) error
+	FindRoleBindingsFn  func(context.Context, platform.RoleBindingFilter) ([]*platform.RoleBinding, error)
+	CreateRoleBindingFn func(context.Context, *platform.RoleBinding) error
+	DeleteRoleBindingFn func(context.Context, platform.ID, platform.ID) error
+}
+
+// NewRoleService returns a mock of RoleService
+// where its methods will return zero values.
+func NewRoleService() *RoleService {
+	return &RoleService{
+		FindRoleByIDFn: func(context.Context, platform.ID) (*platform.Role, error) { return nil, nil },
+		FindRolesFn: func(context.Context, platform.RoleFilter, ...platform.FindOptions) ([]*platform.Role, int, error) {
+			return nil, 0, nil
+		},
+		CreateRoleFn:        func(context.Context, *platform.Role) error { return nil },
+		UpdateRoleFn:        func(context.Context, platform.ID, platform.RoleUpdate) (*platform.Role, error) { return nil, nil },
+		DeleteRoleFn:        func(context.Context, platform.ID) error { return nil },
+		FindRoleBindingsFn:  func(context.Context, platform.RoleBindingFilter) ([]*platform.RoleBinding, error) { return nil, nil },
+		CreateRoleBindingFn: func(context.Context, *platform.RoleBinding) error { return nil },
+		DeleteRoleBindingFn: func(context.Context, platform.ID, platform.ID) error { return nil },
+	}
+}
+
+// FindRoleByID finds a role by its ID.
+func (s *RoleService) FindRoleByID(ctx context.Context, id platform.ID) (*platform.Role, error) {
+	return s.FindRoleByIDFn(ctx, id)
+}
+
+// FindRoles finds roles that match a given filter.
+func (s *RoleService) FindRoles(ctx context.Context, filter platform.RoleFilter, opt ...platform.FindOptions) ([]*platform.Role, int, error) {
+	return s.FindRolesFn(ctx, filter, opt...)
+}
+
+// CreateRole creates a role.
+func (s *RoleService) CreateRole(ctx context.Context, r *platform.Role) error {
+	return s.CreateRoleFn(ctx, r)
+}
+
+// UpdateRole updates a role.
+func (s *RoleService) UpdateRole(ctx context.Context, id platform.ID, upd platform.RoleUpdate) (*platform.Role, error) {
+	return s.UpdateRoleFn(ctx, id, upd)
+}
+
+// DeleteRole deletes a role.
+func (s *RoleService) DeleteRole(ctx context.Context, id platform.ID) error {
+	return s.DeleteRoleFn(ctx, id)
+}
+
+// FindRoleBindings finds role bindings that match a given filter.
+func (s *RoleService) FindRoleBindings(ctx context.Context, filter platform.RoleBindingFilter) ([]*platform.RoleBinding, error) {
+	return s.FindRoleBindingsFn(ctx, filter)
+}
+
+// CreateRoleBinding binds a user to a role.
+func (s *RoleService) CreateRoleBinding(ctx context.Context, b *platform.RoleBinding) error {
+	return s.CreateRoleBindingFn(ctx, b)
+}
+
+// DeleteRoleBinding unbinds a user from a role.
+func (s *RoleService) DeleteRoleBinding(ctx context.Context, roleID, userID platform.ID) error {
+	return s.DeleteRoleBindingFn(ctx, roleID, userID)
+}
@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.RunningQueryService = &RunningQueryService{}
+
+// RunningQueryService is a mock implementation of platform.RunningQueryService
+type RunningQueryService struct {
+	FindRunningQueriesFn   func(context.Context, platform.RunningQueryFilter) ([]*platform.RunningQuery, error)
+	FindRunningQueryByIDFn func(context.Context, platform.ID) (*platform.RunningQuery, error)
+	KillRunningQueryFn     func(context.Context, platform.ID) error
+}
+
+// NewRunningQueryService returns a mock of RunningQueryService
+// where its methods will return zero values.
+func NewRunningQueryService() *RunningQueryService {
+	return &RunningQueryService{
+		FindRunningQueriesFn: func(context.Context, platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+			return nil, nil
+		},
+		FindRunningQueryByIDFn: func(context.Context, platform.ID) (*platform.RunningQuery, error) { return nil, nil },
+		KillRunningQueryFn:     func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindRunningQueries returns the running queries matching the filter.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+	return s.FindRunningQueriesFn(ctx, filter)
+}
+
+// FindRunningQueryByID returns a single running query by ID.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id platform.ID) (*platform.RunningQuery, error) {
+	return s.FindRunningQueryByIDFn(ctx, id)
+}
+
+// KillRunningQuery cancels a running query.
+func (s *RunningQueryService) KillRunningQuery(ctx context.Context, id platform.ID) error {
+	return s.KillRunningQueryFn(ctx, id)
+}
@@ -1,6 +1,12 @@
 package influxdb
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
 
 // PasswordsService is the service for managing basic auth passwords.
 type PasswordsService interface {
@@ -13,3 +19,105 @@ type PasswordsService interface {
 	// updates to the new password.
 	CompareAndSetPassword(ctx context.Context, name string, old string, new string) error
 }
+
+// PasswordPolicy represents the strength rules of passwords.
+type PasswordPolicy struct {
+	// MinLength is the minimum number of characters of passwords.
+	// Services fall back to their own minimum when it is zero.
+	MinLength int
+	// RequireUpper, RequireLower, RequireDigit and RequireSymbol require passwords
+	// to contain upper case letters, lower case letters, digits and symbols.
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// Validate returns an error if password does not follow the policy.
+func (p PasswordPolicy) Validate(password string) error {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	var missing []string
+	if p.RequireUpper && !upper {
+		missing = append(missing, "an upper case letter")
+	}
+	if p.RequireLower && !lower {
+		missing = append(missing, "a lower case letter")
+	}
+	if p.RequireDigit && !digit {
+		missing = append(missing, "a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		missing = append(missing, "a symbol")
+	}
+
+	if n := len([]rune(password)); n < p.MinLength {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("passwords must be at least %d characters long", p.MinLength),
+		}
+	}
+	if len(missing) > 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "passwords must contain " + strings.Join(missing, ", "),
+		}
+	}
+	return nil
+}
+
+// ErrSigninLocked is the error message of sign-ins of locked out users or addresses.
+const ErrSigninLocked = "too many failed sign-ins, try again later"
+
+// SigninLockoutService locks out the users and the remote addresses failing to sign in too often.
+type SigninLockoutService interface {
+	// RecordSigninAttempt returns an error if the user name or the address addr is locked out.
+	// Otherwise it records a sign-in attempt of the user name from the address addr, which
+	// counts as failed until RecordSigninSuccess is called, and locks them out after too many
+	// consecutive failures. Checking and counting at once keeps concurrent sign-ins from
+	// exceeding the attempts allowed.
+	RecordSigninAttempt(ctx context.Context, name, addr string) error
+	// RecordSigninSuccess resets the failed sign-ins of the user name, and withdraws
+	// the attempt recorded for the address addr.
+	RecordSigninSuccess(ctx context.Context, name, addr string) error
+}
+
+// SigninLockoutPolicy represents the lockout of users and addresses failing to sign in.
+type SigninLockoutPolicy struct {
+	// MaxAttempts is the number of consecutive failed sign-ins locking out a user or an address.
+	// Zero disables lockouts.
+	MaxAttempts int
+	// Duration is the duration of the first lockout, which doubles with each further failed sign-in
+	// up to MaxDuration. Failed sign-ins older than MaxDuration are forgotten and pruned.
+	Duration    time.Duration
+	MaxDuration time.Duration
+}
+
+// LockoutDuration returns the duration of the lockout after failures consecutive failed sign-ins,
+// or zero if they are not locked out.
+func (p SigninLockoutPolicy) LockoutDuration(failures int) time.Duration {
+	if p.MaxAttempts <= 0 || failures < p.MaxAttempts {
+		return 0
+	}
+
+	d := p.Duration
+	for i := p.MaxAttempts; i < failures && d < p.MaxDuration; i++ {
+		d *= 2
+	}
+	if p.MaxDuration > 0 && d > p.MaxDuration {
+		d = p.MaxDuration
+	}
+	return d
+}
@@ -0,0 +1,58 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := influxdb.PasswordPolicy{
+		MinLength:     10,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	tests := []struct {
+		password string
+		wantErr  string
+	}{
+		{password: "Correct-Horse-9", wantErr: ""},
+		{password: "Short-9", wantErr: "passwords must be at least 10 characters long"},
+		{password: "correct-horse-9", wantErr: "passwords must contain an upper case letter"},
+		{password: "CorrectHorse", wantErr: "passwords must contain a digit, a symbol"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+			if got := influxdb.ErrorMessage(err); got != tt.wantErr {
+				t.Errorf("got error %q, want %q", got, tt.wantErr)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("got error code %q, want %q", influxdb.ErrorCode(err), influxdb.EInvalid)
+			}
+		})
+	}
+}
+
+func TestSigninLockoutPolicy_LockoutDuration(t *testing.T) {
+	policy := influxdb.SigninLockoutPolicy{
+		MaxAttempts: 3,
+		Duration:    time.Minute,
+		MaxDuration: 5 * time.Minute,
+	}
+
+	for failures, want := range []time.Duration{0, 0, 0, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute} {
+		if got := policy.LockoutDuration(failures); got != want {
+			t.Errorf("%d failures: got %s, want %s", failures, got, want)
+		}
+	}
+
+	if got := (influxdb.SigninLockoutPolicy{}).LockoutDuration(100); got != 0 {
+		t.Errorf("disabled policy: got %s, want 0", got)
+	}
+}
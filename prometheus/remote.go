@@ -0,0 +1,357 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	proto "github.com/golang/protobuf/proto"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// The messages below mirror those of the Prometheus remote storage protocol
+// (prompb), which are exchanged as snappy compressed protocol buffers.
+
+// WriteRequest is the body of a remote write.
+type WriteRequest struct {
+	Timeseries []*TimeSeries `protobuf:"bytes,1,rep,name=timeseries" json:"timeseries,omitempty"`
+}
+
+func (m *WriteRequest) Reset()         { *m = WriteRequest{} }
+func (m *WriteRequest) String() string { return proto.CompactTextString(m) }
+func (*WriteRequest) ProtoMessage()    {}
+
+// ReadRequest is the body of a remote read.
+type ReadRequest struct {
+	Queries []*Query `protobuf:"bytes,1,rep,name=queries" json:"queries,omitempty"`
+}
+
+func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
+func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadRequest) ProtoMessage()    {}
+
+// ReadResponse has a result for each query of a ReadRequest.
+type ReadResponse struct {
+	Results []*QueryResult `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+}
+
+func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
+func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadResponse) ProtoMessage()    {}
+
+// Query selects the samples of the series matching all of its matchers between
+// the start and end timestamps, inclusive.
+type Query struct {
+	StartTimestampMs int64           `protobuf:"varint,1,opt,name=start_timestamp_ms,json=startTimestampMs,proto3" json:"start_timestamp_ms,omitempty"`
+	EndTimestampMs   int64           `protobuf:"varint,2,opt,name=end_timestamp_ms,json=endTimestampMs,proto3" json:"end_timestamp_ms,omitempty"`
+	Matchers         []*LabelMatcher `protobuf:"bytes,3,rep,name=matchers" json:"matchers,omitempty"`
+}
+
+func (m *Query) Reset()         { *m = Query{} }
+func (m *Query) String() string { return proto.CompactTextString(m) }
+func (*Query) ProtoMessage()    {}
+
+// QueryResult is the series selected by a Query.
+type QueryResult struct {
+	Timeseries []*TimeSeries `protobuf:"bytes,1,rep,name=timeseries" json:"timeseries,omitempty"`
+}
+
+func (m *QueryResult) Reset()         { *m = QueryResult{} }
+func (m *QueryResult) String() string { return proto.CompactTextString(m) }
+func (*QueryResult) ProtoMessage()    {}
+
+// TimeSeries is the samples of a series identified by its labels.
+type TimeSeries struct {
+	Labels  []*Label  `protobuf:"bytes,1,rep,name=labels" json:"labels,omitempty"`
+	Samples []*Sample `protobuf:"bytes,2,rep,name=samples" json:"samples,omitempty"`
+}
+
+func (m *TimeSeries) Reset()         { *m = TimeSeries{} }
+func (m *TimeSeries) String() string { return proto.CompactTextString(m) }
+func (*TimeSeries) ProtoMessage()    {}
+
+// Label is a label of a series.
+type Label struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *Label) Reset()         { *m = Label{} }
+func (m *Label) String() string { return proto.CompactTextString(m) }
+func (*Label) ProtoMessage()    {}
+
+// Sample is a value of a series at a timestamp in milliseconds.
+type Sample struct {
+	Value     float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	Timestamp int64   `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *Sample) Reset()         { *m = Sample{} }
+func (m *Sample) String() string { return proto.CompactTextString(m) }
+func (*Sample) ProtoMessage()    {}
+
+// MatchType is the comparison of a LabelMatcher.
+type MatchType int32
+
+const (
+	MatchEqual MatchType = iota
+	MatchNotEqual
+	MatchRegexp
+	MatchNotRegexp
+)
+
+// LabelMatcher selects series by the value of a label.
+type LabelMatcher struct {
+	Type  MatchType `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Name  string    `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value string    `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *LabelMatcher) Reset()         { *m = LabelMatcher{} }
+func (m *LabelMatcher) String() string { return proto.CompactTextString(m) }
+func (*LabelMatcher) ProtoMessage()    {}
+
+const (
+	// MetricNameLabel is the label of the name of a metric.
+	MetricNameLabel = "__name__"
+
+	// RemoteValueField is the field of the samples of remote writes.
+	RemoteValueField = "value"
+)
+
+// remoteReadFields are the fields read back as samples by remote reads:
+// those of remote writes and of the gauges and counters of the scrapers.
+var remoteReadFields = []string{RemoteValueField, "gauge", "counter"}
+
+// EncodeRemoteWrite converts the series of a remote write into line protocol.
+// The metric name of a series is its measurement, its other labels are tags
+// and its samples are written to the value field. NaN samples, such as the
+// staleness markers of Prometheus, are dropped.
+func EncodeRemoteWrite(req *WriteRequest) ([]byte, error) {
+	var b bytes.Buffer
+	for _, ts := range req.Timeseries {
+		var name string
+		tags := make(models.Tags, 0, len(ts.Labels))
+		for _, l := range ts.Labels {
+			if l.Name == MetricNameLabel {
+				name = l.Value
+				continue
+			}
+			tags = append(tags, models.NewTag([]byte(l.Name), []byte(l.Value)))
+		}
+		if name == "" {
+			return nil, errors.New("series is missing a metric name")
+		}
+		sort.Sort(tags)
+
+		for _, s := range ts.Samples {
+			if math.IsNaN(s.Value) {
+				continue
+			}
+			pt, err := models.NewPoint(name, tags, models.Fields{RemoteValueField: s.Value}, time.Unix(0, s.Timestamp*nsPerMilliseconds))
+			if err != nil {
+				return nil, err
+			}
+			b.WriteString(pt.String())
+			b.WriteByte('\n')
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// RemoteReader reads the series of remote reads from a bucket of the store.
+type RemoteReader struct {
+	Store reads.Store
+}
+
+// Read returns the series selected by the queries of req from the bucket of the organization.
+// The series are restricted to the predicates of the authorization of the query request on ctx.
+func (r *RemoteReader) Read(ctx context.Context, orgID, bucketID uint64, req *ReadRequest) (*ReadResponse, error) {
+	source, err := types.MarshalAny(r.Store.GetSource(orgID, bucketID))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ReadResponse{
+		Results: make([]*QueryResult, 0, len(req.Queries)),
+	}
+	for _, q := range req.Queries {
+		pred, err := RemoteReadPredicate(q.Matchers)
+		if err != nil {
+			return nil, err
+		}
+		pred, err = reads.AuthorizedPredicate(ctx, platform.ID(orgID), platform.ID(bucketID), pred)
+		if err != nil {
+			return nil, err
+		}
+
+		// The end of the range of the store is exclusive.
+		rs, err := r.Store.ReadFilter(ctx, &datatypes.ReadFilterRequest{
+			ReadSource: source,
+			Range: datatypes.TimestampRange{
+				Start: q.StartTimestampMs * nsPerMilliseconds,
+				End:   q.EndTimestampMs*nsPerMilliseconds + 1,
+			},
+			Predicate: pred,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result := &QueryResult{}
+		if rs != nil {
+			result.Timeseries, err = readTimeSeries(rs)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+func readTimeSeries(rs reads.ResultSet) ([]*TimeSeries, error) {
+	defer rs.Close()
+
+	var series []*TimeSeries
+	for rs.Next() {
+		ts := &TimeSeries{}
+		for _, t := range rs.Tags() {
+			// The store emits the measurement and field as the _measurement and _field tags.
+			switch string(t.Key) {
+			case "_field":
+			case "_measurement":
+				ts.Labels = append(ts.Labels, &Label{Name: MetricNameLabel, Value: string(t.Value)})
+			default:
+				ts.Labels = append(ts.Labels, &Label{Name: string(t.Key), Value: string(t.Value)})
+			}
+		}
+		sort.Slice(ts.Labels, func(i, j int) bool { return ts.Labels[i].Name < ts.Labels[j].Name })
+
+		if err := readSamples(rs.Cursor(), ts); err != nil {
+			return nil, err
+		}
+		if len(ts.Samples) > 0 {
+			series = append(series, ts)
+		}
+	}
+	return series, rs.Err()
+}
+
+func readSamples(cur cursors.Cursor, ts *TimeSeries) error {
+	if cur == nil {
+		return nil
+	}
+	defer cur.Close()
+
+	add := func(t int64, v float64) {
+		ts.Samples = append(ts.Samples, &Sample{Value: v, Timestamp: t / nsPerMilliseconds})
+	}
+	switch c := cur.(type) {
+	case cursors.FloatArrayCursor:
+		for a := c.Next(); a.Len() > 0; a = c.Next() {
+			for i, t := range a.Timestamps {
+				add(t, a.Values[i])
+			}
+		}
+	case cursors.IntegerArrayCursor:
+		for a := c.Next(); a.Len() > 0; a = c.Next() {
+			for i, t := range a.Timestamps {
+				add(t, float64(a.Values[i]))
+			}
+		}
+	case cursors.UnsignedArrayCursor:
+		for a := c.Next(); a.Len() > 0; a = c.Next() {
+			for i, t := range a.Timestamps {
+				add(t, float64(a.Values[i]))
+			}
+		}
+	default:
+		// Strings and booleans are not samples.
+	}
+	return cur.Err()
+}
+
+// RemoteReadPredicate translates the label matchers of a remote read into a storage predicate
+// that also restricts the fields to those holding samples.
+// The metric name matches the measurement and regular expressions are fully anchored.
+func RemoteReadPredicate(matchers []*LabelMatcher) (*datatypes.Predicate, error) {
+	var fields *datatypes.Node
+	for _, f := range remoteReadFields {
+		n := comparisonNode(datatypes.ComparisonEqual, models.FieldKeyTagKey, &datatypes.Node{
+			NodeType: datatypes.NodeTypeLiteral,
+			Value:    &datatypes.Node_StringValue{StringValue: f},
+		})
+		fields = logicalNode(datatypes.LogicalOr, fields, n)
+	}
+	root := &datatypes.Node{
+		NodeType: datatypes.NodeTypeParenExpression,
+		Children: []*datatypes.Node{fields},
+	}
+
+	for _, m := range matchers {
+		key := m.Name
+		if key == MetricNameLabel {
+			key = models.MeasurementTagKey
+		}
+
+		var (
+			op    datatypes.Node_Comparison
+			value = &datatypes.Node{NodeType: datatypes.NodeTypeLiteral}
+		)
+		switch m.Type {
+		case MatchEqual, MatchNotEqual:
+			op = datatypes.ComparisonEqual
+			if m.Type == MatchNotEqual {
+				op = datatypes.ComparisonNotEqual
+			}
+			value.Value = &datatypes.Node_StringValue{StringValue: m.Value}
+		case MatchRegexp, MatchNotRegexp:
+			op = datatypes.ComparisonRegex
+			if m.Type == MatchNotRegexp {
+				op = datatypes.ComparisonNotRegex
+			}
+			value.Value = &datatypes.Node_RegexValue{RegexValue: "^(?:" + m.Value + ")$"}
+		default:
+			return nil, fmt.Errorf("unknown label matcher type %d", m.Type)
+		}
+		root = logicalNode(datatypes.LogicalAnd, root, comparisonNode(op, key, value))
+	}
+
+	return &datatypes.Predicate{Root: root}, nil
+}
+
+func comparisonNode(op datatypes.Node_Comparison, key string, value *datatypes.Node) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeComparisonExpression,
+		Value:    &datatypes.Node_Comparison_{Comparison: op},
+		Children: []*datatypes.Node{
+			{
+				NodeType: datatypes.NodeTypeTagRef,
+				Value:    &datatypes.Node_TagRefValue{TagRefValue: key},
+			},
+			value,
+		},
+	}
+}
+
+// logicalNode combines the nodes with op, or returns right when left is nil.
+func logicalNode(op datatypes.Node_Logical, left, right *datatypes.Node) *datatypes.Node {
+	if left == nil {
+		return right
+	}
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeLogicalExpression,
+		Value:    &datatypes.Node_Logical_{Logical: op},
+		Children: []*datatypes.Node{left, right},
+	}
+}
@@ -0,0 +1,120 @@
+package prometheus_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pr "github.com/influxdata/influxdb/prometheus"
+	"github.com/influxdata/influxdb/storage/reads"
+)
+
+func TestEncodeRemoteWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *pr.WriteRequest
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "samples",
+			req: &pr.WriteRequest{
+				Timeseries: []*pr.TimeSeries{
+					{
+						Labels: []*pr.Label{
+							{Name: "__name__", Value: "up"},
+							{Name: "job", Value: "node"},
+							{Name: "instance", Value: "a:9100"},
+						},
+						Samples: []*pr.Sample{
+							{Value: 1, Timestamp: 1500000000000},
+							{Value: math.NaN(), Timestamp: 1500000015000},
+							{Value: 0.5, Timestamp: 1500000030000},
+						},
+					},
+				},
+			},
+			want: "up,instance=a:9100,job=node value=1 1500000000000000000\n" +
+				"up,instance=a:9100,job=node value=0.5 1500000030000000000\n",
+		},
+		{
+			name: "missing metric name",
+			req: &pr.WriteRequest{
+				Timeseries: []*pr.TimeSeries{
+					{
+						Labels:  []*pr.Label{{Name: "job", Value: "node"}},
+						Samples: []*pr.Sample{{Value: 1, Timestamp: 1500000000000}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Round trip the request through its wire format as a remote write would.
+			data, err := proto.Marshal(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := &pr.WriteRequest{}
+			if err := proto.Unmarshal(data, req); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := pr.EncodeRemoteWrite(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EncodeRemoteWrite() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("EncodeRemoteWrite() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteReadPredicate(t *testing.T) {
+	tests := []struct {
+		name     string
+		matchers []*pr.LabelMatcher
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "no matchers",
+			want: "( '\xff' = \"value\" OR '\xff' = \"gauge\" OR '\xff' = \"counter\" )",
+		},
+		{
+			name: "matchers",
+			matchers: []*pr.LabelMatcher{
+				{Type: pr.MatchEqual, Name: "__name__", Value: "up"},
+				{Type: pr.MatchNotEqual, Name: "job", Value: "db"},
+				{Type: pr.MatchRegexp, Name: "instance", Value: "a|b"},
+				{Type: pr.MatchNotRegexp, Name: "env", Value: "dev.*"},
+			},
+			want: "( '\xff' = \"value\" OR '\xff' = \"gauge\" OR '\xff' = \"counter\" ) AND '\x00' = \"up\" AND 'job' != \"db\" AND 'instance' =~ /^(?:a|b)$/ AND 'env' !~ /^(?:dev.*)$/",
+		},
+		{
+			name:     "unknown matcher type",
+			matchers: []*pr.LabelMatcher{{Type: 7, Name: "job", Value: "db"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pr.RemoteReadPredicate(tt.matchers)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RemoteReadPredicate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if s := reads.PredicateToExprString(got); s != tt.want {
+				t.Errorf("RemoteReadPredicate() = %q, want %q", s, tt.want)
+			}
+			if _, err := reads.NodeToExpr(got.Root, nil); err != nil {
+				t.Errorf("predicate is not a valid expression: %v", err)
+			}
+		})
+	}
+}
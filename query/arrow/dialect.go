@@ -0,0 +1,34 @@
+package arrow
+
+import (
+	"net/http"
+
+	"github.com/influxdata/flux"
+)
+
+const DialectType = "arrow"
+
+// ContentType is the media type of Arrow IPC streams.
+const ContentType = "application/vnd.apache.arrow.stream"
+
+// AddDialectMappings adds the arrow specific dialect mappings.
+func AddDialectMappings(mappings flux.DialectMappings) error {
+	return mappings.Add(DialectType, func() flux.Dialect {
+		return new(Dialect)
+	})
+}
+
+// Dialect describes the encoding of query results as Apache Arrow IPC streams.
+type Dialect struct{}
+
+func (d *Dialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ContentType)
+}
+
+func (d *Dialect) Encoder() flux.MultiResultEncoder {
+	return new(MultiResultEncoder)
+}
+
+func (d *Dialect) DialectType() flux.DialectType {
+	return DialectType
+}
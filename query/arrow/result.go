@@ -0,0 +1,161 @@
+package arrow
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	apachearrow "github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/iocounter"
+)
+
+// Metadata keys describing the tables of the results.
+const (
+	// ResultKey is the schema metadata holding the name of the result of a table.
+	ResultKey = "result"
+	// TableKey is the schema metadata holding the index of a table within its result.
+	TableKey = "table"
+	// GroupKey is the field metadata set to "true" for the columns of the group key of a table.
+	GroupKey = "group"
+	// ErrorKey is the schema metadata holding the error of the results in the stream ending them.
+	ErrorKey = "error"
+)
+
+// MultiResultEncoder encodes results as a sequence of Arrow IPC streams, one for each table.
+// The schema of a stream identifies its table with the ResultKey and TableKey metadata,
+// and the columns of the group key with the GroupKey field metadata.
+// Each buffer of a table is written as a record batch as soon as it is read, so the
+// tables are streamed without being held in memory.
+//
+// Arrow streams cannot hold errors, so when the results fail after some of them were
+// written, the stream of the table being written is ended and followed by a stream
+// without any field, whose schema holds the error with the ErrorKey metadata.
+type MultiResultEncoder struct{}
+
+// Encode writes the tables of the results to w. If the results fail before anything is
+// written, the error is returned so that it can be reported by the caller. Otherwise
+// it is written as the last stream.
+func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+
+	err := encodeResults(wc, results)
+	if err == nil || wc.Count() == 0 {
+		return wc.Count(), err
+	}
+	return wc.Count(), encodeError(wc, err)
+}
+
+func encodeResults(w io.Writer, results flux.ResultIterator) error {
+	mem := memory.NewGoAllocator()
+	for results.More() {
+		res := results.Next()
+		table := 0
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			schema := tableSchema(res.Name(), table, tbl)
+			table++
+
+			sw := ipc.NewWriter(w, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+			err := tbl.Do(func(cr flux.ColReader) error {
+				rec, err := newRecord(schema, cr)
+				if err != nil {
+					return err
+				}
+				defer rec.Release()
+				return sw.Write(rec)
+			})
+			// The stream is ended even if the table failed, so that the error can follow it.
+			if cerr := sw.Close(); err == nil {
+				err = cerr
+			}
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	return results.Err()
+}
+
+// encodeError writes the stream holding the error of the results.
+func encodeError(w io.Writer, err error) error {
+	md := apachearrow.NewMetadata([]string{ErrorKey}, []string{err.Error()})
+	return ipc.NewWriter(w, ipc.WithSchema(apachearrow.NewSchema(nil, &md))).Close()
+}
+
+// tableSchema returns the schema of the stream of a table.
+func tableSchema(result string, table int, tbl flux.Table) *apachearrow.Schema {
+	cols := tbl.Cols()
+	fields := make([]apachearrow.Field, len(cols))
+	for j, c := range cols {
+		group := tbl.Key().HasCol(c.Label)
+		fields[j] = apachearrow.Field{
+			Name:     c.Label,
+			Type:     dataType(c.Type),
+			Nullable: true,
+			Metadata: apachearrow.NewMetadata([]string{GroupKey}, []string{strconv.FormatBool(group)}),
+		}
+	}
+	md := apachearrow.NewMetadata(
+		[]string{ResultKey, TableKey},
+		[]string{result, strconv.Itoa(table)},
+	)
+	return apachearrow.NewSchema(fields, &md)
+}
+
+func dataType(typ flux.ColType) apachearrow.DataType {
+	switch typ {
+	case flux.TBool:
+		return apachearrow.FixedWidthTypes.Boolean
+	case flux.TInt:
+		return apachearrow.PrimitiveTypes.Int64
+	case flux.TUInt:
+		return apachearrow.PrimitiveTypes.Uint64
+	case flux.TFloat:
+		return apachearrow.PrimitiveTypes.Float64
+	case flux.TString:
+		return apachearrow.BinaryTypes.String
+	case flux.TTime:
+		return apachearrow.FixedWidthTypes.Timestamp_ns
+	default:
+		return apachearrow.Null
+	}
+}
+
+// newRecord returns a record batch of a buffer of a table.
+// Flux stores strings as binary arrays and times as integer arrays, so these
+// are converted to string and timestamp arrays sharing the same buffers.
+func newRecord(schema *apachearrow.Schema, cr flux.ColReader) (array.Record, error) {
+	cols := make([]array.Interface, len(cr.Cols()))
+	for j, c := range cr.Cols() {
+		switch c.Type {
+		case flux.TBool:
+			cols[j] = cr.Bools(j)
+		case flux.TInt:
+			cols[j] = cr.Ints(j)
+		case flux.TUInt:
+			cols[j] = cr.UInts(j)
+		case flux.TFloat:
+			cols[j] = cr.Floats(j)
+		case flux.TString:
+			cols[j] = convert(cr.Strings(j), schema.Field(j).Type)
+			defer cols[j].Release()
+		case flux.TTime:
+			cols[j] = convert(cr.Times(j), schema.Field(j).Type)
+			defer cols[j].Release()
+		default:
+			return nil, fmt.Errorf("unsupported column type %v of column %q", c.Type, c.Label)
+		}
+	}
+	return array.NewRecord(schema, cols, int64(cr.Len())), nil
+}
+
+// convert returns an array of the data type sharing the buffers of arr.
+func convert(arr array.Interface, dt apachearrow.DataType) array.Interface {
+	d := arr.Data()
+	data := array.NewData(dt, d.Len(), d.Buffers(), nil, d.NullN(), d.Offset())
+	defer data.Release()
+	return array.MakeFromData(data)
+}
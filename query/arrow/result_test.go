@@ -0,0 +1,234 @@
+package arrow_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	apachearrow "github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/influxdb/query/arrow"
+)
+
+// table is a table decoded from an Arrow IPC stream.
+type table struct {
+	Result, Table string
+	Error         string
+	Columns       []string
+	Group         []string
+	Values        [][]interface{}
+}
+
+func metadata(md apachearrow.Metadata, key string) string {
+	if i := md.FindKey(key); i >= 0 {
+		return md.Values()[i]
+	}
+	return ""
+}
+
+// decodeTables reads the streams of the tables until the end of b.
+func decodeTables(t *testing.T, b []byte) []table {
+	t.Helper()
+	var tables []table
+	r := bytes.NewReader(b)
+	for r.Len() > 0 {
+		sr, err := ipc.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		schema := sr.Schema()
+		tbl := table{
+			Result: metadata(schema.Metadata(), arrow.ResultKey),
+			Table:  metadata(schema.Metadata(), arrow.TableKey),
+			Error:  metadata(schema.Metadata(), arrow.ErrorKey),
+		}
+		for _, f := range schema.Fields() {
+			tbl.Columns = append(tbl.Columns, f.Name+":"+f.Type.Name())
+			if metadata(f.Metadata, arrow.GroupKey) == "true" {
+				tbl.Group = append(tbl.Group, f.Name)
+			}
+		}
+		for sr.Next() {
+			for _, col := range sr.Record().Columns() {
+				tbl.Values = append(tbl.Values, values(col))
+			}
+		}
+		if err := sr.Err(); err != nil {
+			t.Fatal(err)
+		}
+		sr.Release()
+		tables = append(tables, tbl)
+	}
+	return tables
+}
+
+// values returns the values of an array, with nil for the null values.
+func values(arr array.Interface) []interface{} {
+	vs := make([]interface{}, arr.Len())
+	for i := range vs {
+		if arr.IsNull(i) {
+			continue
+		}
+		switch a := arr.(type) {
+		case *array.Timestamp:
+			vs[i] = int64(a.Value(i))
+		case *array.String:
+			vs[i] = a.Value(i)
+		case *array.Float64:
+			vs[i] = a.Value(i)
+		case *array.Boolean:
+			vs[i] = a.Value(i)
+		case *array.Uint64:
+			vs[i] = a.Value(i)
+		case *array.Int64:
+			vs[i] = a.Value(i)
+		}
+	}
+	return vs
+}
+
+func TestMultiResultEncoder_Encode(t *testing.T) {
+	const in = `#datatype,string,long,dateTime:RFC3339,string,double,boolean,unsignedLong
+#group,false,false,false,true,false,false,false
+#default,_result,,,,,,
+,result,table,_time,host,_value,ok,count
+,,0,1970-01-01T00:00:01Z,a,1.5,true,1
+,,0,1970-01-01T00:00:02Z,a,,false,2
+,,1,1970-01-01T00:00:01Z,b,2,true,3
+
+#datatype,string,long,string,long
+#group,false,false,true,false
+#default,empty,0,a,
+,result,table,host,_value
+`
+	results, err := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{}).Decode(ioutil.NopCloser(strings.NewReader(in)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err := new(arrow.MultiResultEncoder).Encode(&buf, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("unexpected byte count: got %d, want %d", n, buf.Len())
+	}
+
+	columns := []string{"_time:timestamp", "host:utf8", "_value:float64", "ok:bool", "count:uint64"}
+	want := []table{
+		{
+			Result:  "_result",
+			Table:   "0",
+			Columns: columns,
+			Group:   []string{"host"},
+			Values: [][]interface{}{
+				{int64(1e9), int64(2e9)},
+				{"a", "a"},
+				{1.5, nil},
+				{true, false},
+				{uint64(1), uint64(2)},
+			},
+		},
+		{
+			Result:  "_result",
+			Table:   "1",
+			Columns: columns,
+			Group:   []string{"host"},
+			Values: [][]interface{}{
+				{int64(1e9)},
+				{"b"},
+				{2.0},
+				{true},
+				{uint64(3)},
+			},
+		},
+		{
+			Result:  "empty",
+			Table:   "0",
+			Columns: []string{"host:utf8", "_value:int64"},
+			Group:   []string{"host"},
+		},
+	}
+	if diff := cmp.Diff(decodeTables(t, buf.Bytes()), want); diff != "" {
+		t.Errorf("unexpected tables -got/+want\n%s", diff)
+	}
+}
+
+// failingResults fails after the first result of its results.
+type failingResults struct {
+	flux.ResultIterator
+	n int
+}
+
+func (r *failingResults) More() bool {
+	return r.n == 0 && r.ResultIterator.More()
+}
+
+func (r *failingResults) Next() flux.Result {
+	r.n++
+	return r.ResultIterator.Next()
+}
+
+func (r *failingResults) Err() error {
+	return errors.New("query failed")
+}
+
+func TestMultiResultEncoder_EncodeError(t *testing.T) {
+	const in = `#datatype,string,long,string,long
+#group,false,false,true,false
+#default,_result,,,
+,result,table,host,_value
+,,0,a,1
+
+#datatype,string,long,string,long
+#group,false,false,true,false
+#default,other,,,
+,result,table,host,_value
+,,0,a,2
+`
+	decode := func() flux.ResultIterator {
+		results, err := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{}).Decode(ioutil.NopCloser(strings.NewReader(in)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+
+	// The error is written after the tables of the results written before it.
+	var buf bytes.Buffer
+	results := &failingResults{ResultIterator: decode()}
+	if _, err := new(arrow.MultiResultEncoder).Encode(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	results.Release()
+	want := []table{
+		{
+			Result:  "_result",
+			Table:   "0",
+			Columns: []string{"host:utf8", "_value:int64"},
+			Group:   []string{"host"},
+			Values:  [][]interface{}{{"a"}, {int64(1)}},
+		},
+		{
+			Error: "query failed",
+		},
+	}
+	if diff := cmp.Diff(decodeTables(t, buf.Bytes()), want); diff != "" {
+		t.Errorf("unexpected tables -got/+want\n%s", diff)
+	}
+
+	// An error before anything is written is returned.
+	buf.Reset()
+	results = &failingResults{ResultIterator: decode(), n: 1}
+	if _, err := new(arrow.MultiResultEncoder).Encode(&buf, results); err == nil || buf.Len() != 0 {
+		t.Errorf("expected the error to be returned, got %v and %d bytes", err, buf.Len())
+	}
+	results.Release()
+}
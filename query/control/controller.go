@@ -50,7 +50,7 @@ type Controller struct {
 	lastID     uint64
 	queriesMu  sync.RWMutex
 	queries    map[QueryID]*Query
-	queryQueue chan *Query
+	queryQueue *fairQueue
 	wg         sync.WaitGroup
 	shutdown   bool
 	done       chan struct{}
@@ -58,6 +58,7 @@ type Controller struct {
 	abort      chan struct{}
 
 	memoryBytesQuotaPerQuery int64
+	memory                   *memoryPool
 
 	metrics   *controllerMetrics
 	labelKeys []string
@@ -73,13 +74,34 @@ type Config struct {
 	// MemoryBytesQuotaPerQuery is the maximum number of bytes (in table memory) a query is allowed to use at
 	// any given time.
 	//
-	// The maximum amount of memory the controller is allowed to consume is
+	// Unless MemoryBytesQuota is set, the maximum amount of memory the controller is allowed to consume is
 	//   ConcurrencyQuota * MemoryBytesQuotaPerQuery
 	MemoryBytesQuotaPerQuery int64
+	// MemoryBytesQuota is the maximum number of bytes (in table memory) all of the executing queries are
+	// allowed to use together. The queries borrow from it when they start executing, each up to
+	// MemoryBytesQuotaPerQuery, so a query may use more memory when few other queries are executing.
+	// Zero means there is no limit besides MemoryBytesQuotaPerQuery.
+	MemoryBytesQuota int64
+	// InitialMemoryBytesQuotaPerQuery is the number of bytes that must be free in MemoryBytesQuota for a
+	// query to start executing. Queries wait in the queue for up to MemoryWaitTimeout until it is. Zero
+	// means an equal share of MemoryBytesQuota for each of the ConcurrencyQuota queries.
+	InitialMemoryBytesQuotaPerQuery int64
+	// MemoryWaitTimeout is how long a query waits in the queue for InitialMemoryBytesQuotaPerQuery to be
+	// free before it is rejected. Zero means a query is rejected as soon as the memory is not free.
+	MemoryWaitTimeout time.Duration
 	// QueueSize is the number of queries that are allowed to be awaiting execution before new queries are
 	// rejected.
 	QueueSize int
-	Logger    *zap.Logger
+	// ConcurrencyQuotaPerOrg is the number of queries of a single organization that are allowed to execute
+	// concurrently. Zero means the queries of an organization may use the whole ConcurrencyQuota.
+	ConcurrencyQuotaPerOrg int
+	// QueueSizePerOrg is the number of queries of a single organization that are allowed to be awaiting
+	// execution. Zero means the queries of an organization may fill the whole queue.
+	QueueSizePerOrg int
+	// OrgWeights maps organization IDs to their share of the executed queries relative to the
+	// other organizations with queued queries. Organizations that are not listed have a weight of 1.
+	OrgWeights map[influxdb.ID]int
+	Logger     *zap.Logger
 	// MetricLabelKeys is a list of labels to add to the metrics produced by the controller.
 	// The value for a given key will be read off the context.
 	// The context value must be a string or an implementation of the Stringer interface.
@@ -98,6 +120,29 @@ func (c *Config) Validate() error {
 	if c.QueueSize <= 0 {
 		return errors.New("QueueSize must be positive")
 	}
+	if c.MemoryBytesQuota < 0 {
+		return errors.New("MemoryBytesQuota must not be negative")
+	}
+	if c.InitialMemoryBytesQuotaPerQuery < 0 {
+		return errors.New("InitialMemoryBytesQuotaPerQuery must not be negative")
+	}
+	if c.InitialMemoryBytesQuotaPerQuery > c.MemoryBytesQuota {
+		return errors.New("InitialMemoryBytesQuotaPerQuery must not exceed MemoryBytesQuota")
+	}
+	if c.MemoryWaitTimeout < 0 {
+		return errors.New("MemoryWaitTimeout must not be negative")
+	}
+	if c.ConcurrencyQuotaPerOrg < 0 {
+		return errors.New("ConcurrencyQuotaPerOrg must not be negative")
+	}
+	if c.QueueSizePerOrg < 0 {
+		return errors.New("QueueSizePerOrg must not be negative")
+	}
+	for orgID, w := range c.OrgWeights {
+		if w <= 0 {
+			return fmt.Errorf("weight of organization %s must be positive", orgID)
+		}
+	}
 	return nil
 }
 
@@ -115,18 +160,26 @@ func New(c Config) (*Controller, error) {
 	logger.Info("Starting query controller",
 		zap.Int("concurrency_quota", c.ConcurrencyQuota),
 		zap.Int64("memory_bytes_quota_per_query", c.MemoryBytesQuotaPerQuery),
-		zap.Int("queue_size", c.QueueSize))
+		zap.Int("queue_size", c.QueueSize),
+		zap.Int("concurrency_quota_per_org", c.ConcurrencyQuotaPerOrg),
+		zap.Int("queue_size_per_org", c.QueueSizePerOrg),
+		zap.Int64("memory_bytes_quota", c.MemoryBytesQuota),
+		zap.Duration("memory_wait_timeout", c.MemoryWaitTimeout))
+	metrics := newControllerMetrics(c.MetricLabelKeys)
 	ctrl := &Controller{
 		queries:                  make(map[QueryID]*Query),
-		queryQueue:               make(chan *Query, c.QueueSize),
+		queryQueue:               newFairQueue(c, metrics),
 		done:                     make(chan struct{}),
 		abort:                    make(chan struct{}),
 		memoryBytesQuotaPerQuery: c.MemoryBytesQuotaPerQuery,
 		logger:                   logger,
-		metrics:                  newControllerMetrics(c.MetricLabelKeys),
+		metrics:                  metrics,
 		labelKeys:                c.MetricLabelKeys,
 		dependencies:             c.ExecutorDependencies,
 	}
+	if c.MemoryBytesQuota > 0 {
+		ctrl.memory = newMemoryPool(c, metrics)
+	}
 	ctrl.wg.Add(c.ConcurrencyQuota)
 	for i := 0; i < c.ConcurrencyQuota; i++ {
 		go func() {
@@ -157,7 +210,7 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 // query submits a query for execution returning immediately.
 // Done must be called on any returned Query objects.
 func (c *Controller) query(ctx context.Context, compiler flux.Compiler) (flux.Query, error) {
-	q, err := c.createQuery(ctx, compiler.CompilerType())
+	q, err := c.createQuery(ctx, compiler)
 	if err != nil {
 		return nil, handleFluxError(err)
 	}
@@ -177,7 +230,7 @@ func (c *Controller) query(ctx context.Context, compiler flux.Compiler) (flux.Qu
 	return q, nil
 }
 
-func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Query, error) {
+func (c *Controller) createQuery(ctx context.Context, compiler flux.Compiler) (*Query, error) {
 	c.queriesMu.RLock()
 	if c.shutdown {
 		c.queriesMu.RUnlock()
@@ -200,9 +253,11 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 		labelValues[i] = str
 		compileLabelValues[i] = str
 	}
+	ct := compiler.CompilerType()
 	compileLabelValues[len(compileLabelValues)-1] = string(ct)
 
-	cctx, cancel := context.WithCancel(ctx)
+	writeStats := new(query.WriteStatistics)
+	cctx, cancel := context.WithCancel(query.ContextWithWriteStatistics(ctx, writeStats))
 	parentSpan, parentCtx := StartSpanFromContext(
 		cctx,
 		"all",
@@ -218,8 +273,18 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 		results:            make(chan flux.Result),
 		parentCtx:          parentCtx,
 		parentSpan:         parentSpan,
+		writeStats:         writeStats,
 		cancel:             cancel,
 		doneCh:             make(chan struct{}),
+		createdAt:          time.Now(),
+		priority:           query.PriorityFromContext(ctx),
+		compilerType:       ct,
+		text:               query.CompilerText(compiler),
+		alloc:              &memory.Allocator{Limit: func(v int64) *int64 { return &v }(c.memoryBytesQuotaPerQuery)},
+	}
+	if req := query.RequestFromContext(ctx); req != nil {
+		q.orgID = req.OrganizationID
+		q.auth = req.Authorization
 	}
 
 	// Lock the queries mutex for the rest of this method.
@@ -301,26 +366,17 @@ func (c *Controller) enqueueQuery(q *Query) error {
 		}
 	}
 
-	select {
-	case c.queryQueue <- q:
-	default:
-		return &flux.Error{
-			Code: codes.ResourceExhausted,
-			Msg:  "queue length exceeded",
-		}
-	}
-
-	return nil
+	return c.queryQueue.push(q)
 }
 
 func (c *Controller) processQueryQueue() {
 	for {
-		select {
-		case <-c.done:
+		q, ok := c.queryQueue.pop()
+		if !ok {
 			return
-		case q := <-c.queryQueue:
-			c.executeQuery(q)
 		}
+		c.executeQuery(q)
+		c.queryQueue.release(q)
 	}
 }
 
@@ -340,6 +396,20 @@ func (c *Controller) executeQuery(q *Query) {
 		}
 	}()
 
+	if c.memory != nil {
+		// The query remains queued until it reserves its memory. If the query is
+		// canceled while it waits, it fails to transition to executing below.
+		// The reservation is held until the query is done, since its results
+		// are still allocating while they are read after the worker returns.
+		if n, err := c.memory.reserve(q.parentCtx); err == nil {
+			q.memoryReserved = n
+			*q.alloc.Limit = n
+		} else if q.parentCtx.Err() == nil {
+			q.setErr(err)
+			return
+		}
+	}
+
 	ctx, ok := q.tryExec()
 	if !ok {
 		// This may happen if the query was cancelled (either because the
@@ -353,8 +423,6 @@ func (c *Controller) executeQuery(q *Query) {
 		return
 	}
 
-	q.alloc = new(memory.Allocator)
-	q.alloc.Limit = func(v int64) *int64 { return &v }(c.memoryBytesQuotaPerQuery)
 	exec, err := q.program.Start(ctx, q.alloc)
 	if err != nil {
 		q.setErr(err)
@@ -369,6 +437,7 @@ func (c *Controller) finish(q *Query) {
 	delete(c.queries, q.id)
 	if len(c.queries) == 0 && c.shutdown {
 		close(c.done)
+		c.queryQueue.close()
 	}
 	c.queriesMu.Unlock()
 }
@@ -442,6 +511,18 @@ type Query struct {
 
 	c *Controller
 
+	// The request the query was submitted with. These are set when the query is
+	// created and are not modified afterwards.
+	createdAt    time.Time
+	orgID        influxdb.ID
+	auth         *influxdb.Authorization
+	compilerType flux.CompilerType
+	text         string
+	priority     query.Priority
+
+	// queuedAt is when the query was added to the queue.
+	queuedAt time.Time
+
 	// query state. The stateMu protects access for the group below.
 	stateMu     sync.RWMutex
 	state       State
@@ -452,6 +533,7 @@ type Query struct {
 	parentCtx               context.Context
 	parentSpan, currentSpan *span
 	stats                   flux.Statistics
+	writeStats              *query.WriteStatistics
 
 	done   sync.Once
 	doneCh chan struct{}
@@ -460,6 +542,9 @@ type Query struct {
 	exec    flux.Query
 	results chan flux.Result
 	alloc   *memory.Allocator
+
+	// memoryReserved is the number of bytes reserved for the query from the memory pool.
+	memoryReserved int64
 }
 
 // ID reports an ephemeral unique ID for the query.
@@ -524,6 +609,10 @@ func (q *Query) Done() {
 			// Merge the metadata from the program into the controller stats.
 			stats := q.exec.Statistics()
 			q.stats.Metadata = stats.Metadata
+			if q.stats.Metadata == nil {
+				q.stats.Metadata = make(flux.Metadata)
+			}
+			q.stats.Metadata.AddAll(q.writeStats.Metadata())
 		}
 
 		// Retrieve the runtime errors that have been accumulated.
@@ -533,6 +622,11 @@ func (q *Query) Done() {
 		}
 		q.stats.RuntimeErrors = errMsgs
 
+		// The program is done allocating, so its memory is returned to the pool.
+		if q.memoryReserved > 0 {
+			q.c.memory.release(q.memoryReserved)
+		}
+
 		// Mark the query as finished so it is removed from the query map.
 		q.c.finish(q)
 
@@ -19,6 +19,7 @@ import (
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/control"
 	"github.com/prometheus/client_golang/prometheus"
@@ -649,6 +650,164 @@ func TestController_PerQueryMemoryLimit(t *testing.T) {
 	}
 }
 
+func TestController_MemoryBytesQuota(t *testing.T) {
+	config := config
+	config.ConcurrencyQuota = 2
+	config.MemoryBytesQuotaPerQuery = 1024
+	config.MemoryBytesQuota = 1024
+	config.InitialMemoryBytesQuotaPerQuery = 256
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	// A query executing alone may use the memory that is not set aside
+	// for the other query that may execute concurrently.
+	for _, tt := range []struct {
+		size    int
+		wantErr bool
+	}{
+		{size: 768},
+		{size: 769, wantErr: true},
+	} {
+		size := tt.size
+		compiler := &mock.Compiler{
+			CompileFn: func(ctx context.Context) (flux.Program, error) {
+				return &mock.Program{
+					ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+						if err := alloc.Allocate(size); err != nil {
+							q.SetErr(err)
+						}
+					},
+				}, nil
+			},
+		}
+
+		q, err := ctrl.Query(context.Background(), makeRequest(compiler))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range q.Results() {
+			// discard the results
+		}
+		q.Done()
+
+		if got := q.Err() != nil; got != tt.wantErr {
+			t.Errorf("unexpected error allocating %d bytes: %v", size, q.Err())
+		}
+	}
+}
+
+func TestController_MemoryBytesQuotaHeldUntilDone(t *testing.T) {
+	config := config
+	config.ConcurrencyQuota = 1
+	config.MemoryBytesQuotaPerQuery = 1024
+	config.MemoryBytesQuota = 1024
+	config.InitialMemoryBytesQuotaPerQuery = 1024
+	config.MemoryWaitTimeout = time.Minute
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	executing := make(chan struct{}, 2)
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					executing <- struct{}{}
+				},
+			}, nil
+		},
+	}
+
+	// The first query has handed over its results, but they are still being
+	// read, so it keeps the memory it reserved.
+	first, err := ctrl.Query(context.Background(), makeRequest(compiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Done()
+	<-executing
+
+	second, err := ctrl.Query(context.Background(), makeRequest(compiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Done()
+
+	select {
+	case <-executing:
+		t.Fatal("expected the second query to wait for the memory of the first query")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	for range first.Results() {
+		// discard the results
+	}
+	first.Done()
+
+	select {
+	case <-executing:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the second query to execute once the first query is done")
+	}
+}
+
+func TestController_MemoryWaitTimeout(t *testing.T) {
+	config := config
+	config.ConcurrencyQuota = 2
+	config.MemoryBytesQuotaPerQuery = 1024
+	config.MemoryBytesQuota = 1024
+	config.InitialMemoryBytesQuotaPerQuery = 1024
+	config.MemoryWaitTimeout = 10 * time.Millisecond
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	executing := make(chan struct{}, 2)
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					executing <- struct{}{}
+				},
+			}, nil
+		},
+	}
+
+	first, err := ctrl.Query(context.Background(), makeRequest(compiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Done()
+	<-executing
+
+	// The second query is rejected once it waited for the memory of the first query too long.
+	second, err := ctrl.Query(context.Background(), makeRequest(compiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range second.Results() {
+		// discard the results
+	}
+	second.Done()
+
+	// Exhausted resources are reported as invalid requests.
+	if err := second.Err(); influxdb.ErrorCode(err) != influxdb.EInvalid || !strings.Contains(err.Error(), "not enough memory") {
+		t.Errorf("expected the query to be rejected for lack of memory, got %v", err)
+	}
+	select {
+	case <-executing:
+		t.Error("expected the second query not to execute")
+	default:
+	}
+}
+
 func TestController_ConcurrencyQuota(t *testing.T) {
 	const (
 		numQueries       = 3
@@ -785,6 +944,67 @@ func TestController_QueueSize(t *testing.T) {
 	}
 }
 
+func TestController_ConcurrencyQuotaPerOrg(t *testing.T) {
+	config := config
+	config.ConcurrencyQuota = 2
+	config.ConcurrencyQuotaPerOrg = 1
+	config.QueueSize = 2
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	// This channel blocks program execution until we are done
+	// with running the test.
+	done := make(chan struct{})
+	defer close(done)
+
+	executing := make(chan influxdb.ID, 3)
+	start := func(orgID influxdb.ID) {
+		req := makeRequest(&mock.Compiler{
+			CompileFn: func(ctx context.Context) (flux.Program, error) {
+				return &mock.Program{
+					ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+						executing <- orgID
+						// Block until test is finished
+						<-done
+					},
+				}, nil
+			},
+		})
+		req.OrganizationID = orgID
+		q, err := ctrl.Query(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			for range q.Results() {
+				// discard the results
+			}
+			q.Done()
+		}()
+	}
+
+	start(1)
+	if orgID := <-executing; orgID != 1 {
+		t.Fatalf("expected a query of org 1 to execute, got org %s", orgID)
+	}
+
+	// The second query of the first organization waits for the first one to finish
+	// while the query of the second organization takes the remaining concurrency.
+	start(1)
+	start(2)
+	if orgID := <-executing; orgID != 2 {
+		t.Fatalf("expected a query of org 2 to execute, got org %s", orgID)
+	}
+	select {
+	case orgID := <-executing:
+		t.Fatalf("expected no more queries to execute, got a query of org %s", orgID)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 // Test that rapidly starting and canceling the query and then calling done will correctly
 // cancel the query and not result in a race condition.
 func TestController_CancelDone(t *testing.T) {
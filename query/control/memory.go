@@ -0,0 +1,136 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+)
+
+// memoryPool holds the memory that the executing queries borrow from.
+//
+// A query reserves memory from the pool before it executes and returns it once
+// it is done. It is granted as much of the free memory as it may use, while
+// leaving the initial quota free for each of the other queries that may execute
+// concurrently, so a query executing alone can use more memory than one executing
+// when the server is busy. A query that cannot be granted its initial quota waits
+// in the queue for at most the wait timeout for enough memory to be returned to
+// the pool, and is rejected otherwise.
+type memoryPool struct {
+	mu       sync.Mutex
+	size     int64
+	reserved int64
+	initial  int64
+	perQuery int64
+	wait     time.Duration
+
+	// slots is the number of queries that may execute concurrently,
+	// and held the number of queries holding a reservation.
+	slots int
+	held  int
+
+	// released is closed and replaced whenever memory is returned to the pool.
+	released chan struct{}
+
+	metrics *controllerMetrics
+}
+
+func newMemoryPool(c Config, metrics *controllerMetrics) *memoryPool {
+	initial := c.InitialMemoryBytesQuotaPerQuery
+	if initial == 0 {
+		initial = c.MemoryBytesQuota / int64(c.ConcurrencyQuota)
+	}
+	if initial > c.MemoryBytesQuotaPerQuery {
+		initial = c.MemoryBytesQuotaPerQuery
+	}
+	metrics.memoryQuota.Set(float64(c.MemoryBytesQuota))
+	return &memoryPool{
+		size:     c.MemoryBytesQuota,
+		initial:  initial,
+		perQuery: c.MemoryBytesQuotaPerQuery,
+		wait:     c.MemoryWaitTimeout,
+		slots:    c.ConcurrencyQuota,
+		released: make(chan struct{}),
+		metrics:  metrics,
+	}
+}
+
+// reserve borrows memory from the pool for a query, waiting for at most the wait
+// timeout until the initial quota is free if necessary. It returns the number of
+// bytes the query is allowed to use, or an error if they could not be reserved
+// before the timeout or before the context is done.
+func (p *memoryPool) reserve(ctx context.Context) (int64, error) {
+	var timeout <-chan time.Time
+	if p.wait > 0 {
+		timer := time.NewTimer(p.wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for {
+		p.mu.Lock()
+		if free := p.size - p.reserved; free >= p.initial {
+			n := p.grant(free)
+			p.reserved += n
+			p.held++
+			p.metrics.memoryReserved.Set(float64(p.reserved))
+			p.mu.Unlock()
+			return n, nil
+		}
+		released := p.released
+		p.mu.Unlock()
+
+		if timeout == nil {
+			return 0, p.exhausted()
+		}
+		p.metrics.memoryWaiting.Inc()
+		select {
+		case <-released:
+			p.metrics.memoryWaiting.Dec()
+		case <-timeout:
+			p.metrics.memoryWaiting.Dec()
+			return 0, p.exhausted()
+		case <-ctx.Done():
+			p.metrics.memoryWaiting.Dec()
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// exhausted returns the error of a query that could not reserve its initial quota.
+func (p *memoryPool) exhausted() error {
+	return &flux.Error{
+		Code: codes.ResourceExhausted,
+		Msg:  fmt.Sprintf("not enough memory to execute the query: %d bytes must be free", p.initial),
+	}
+}
+
+// grant returns the number of bytes to reserve for a query out of the free memory.
+// It must be called with the lock held.
+func (p *memoryPool) grant(free int64) int64 {
+	others := p.slots - p.held - 1
+	if others < 0 {
+		others = 0
+	}
+	n := free - int64(others)*p.initial
+	if n < p.initial {
+		n = p.initial
+	}
+	if n > p.perQuery {
+		n = p.perQuery
+	}
+	return n
+}
+
+// release returns the memory reserved for a query to the pool.
+func (p *memoryPool) release(n int64) {
+	p.mu.Lock()
+	p.reserved -= n
+	p.held--
+	p.metrics.memoryReserved.Set(float64(p.reserved))
+	close(p.released)
+	p.released = make(chan struct{})
+	p.mu.Unlock()
+}
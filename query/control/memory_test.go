@@ -0,0 +1,128 @@
+package control
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+)
+
+func newTestMemoryPool(c Config) *memoryPool {
+	return newMemoryPool(c, newControllerMetrics(nil))
+}
+
+func TestMemoryPool_Reserve(t *testing.T) {
+	p := newTestMemoryPool(Config{
+		ConcurrencyQuota:                3,
+		MemoryBytesQuota:                1000,
+		InitialMemoryBytesQuotaPerQuery: 200,
+		MemoryBytesQuotaPerQuery:        500,
+	})
+	ctx := context.Background()
+
+	// Each reservation leaves the initial quota for the queries that may still
+	// execute, up to the quota of a single query.
+	for _, want := range []int64{500, 300, 200} {
+		if got, err := p.reserve(ctx); err != nil || got != want {
+			t.Errorf("unexpected reservation: got %d, want %d: %v", got, want, err)
+		}
+	}
+	p.release(300)
+
+	// Memory returned to the pool is available to the next query.
+	if got, _ := p.reserve(ctx); got != 300 {
+		t.Errorf("unexpected reservation: got %d, want %d", got, 300)
+	}
+}
+
+func TestMemoryPool_DefaultInitialQuota(t *testing.T) {
+	p := newTestMemoryPool(Config{
+		ConcurrencyQuota:         4,
+		MemoryBytesQuota:         1000,
+		MemoryBytesQuotaPerQuery: 1000,
+	})
+	if got, _ := p.reserve(context.Background()); got != 250 {
+		t.Errorf("unexpected reservation: got %d, want %d", got, 250)
+	}
+}
+
+func TestMemoryPool_Wait(t *testing.T) {
+	// The pool does not hold the initial quota of every query that may execute.
+	p := newTestMemoryPool(Config{
+		ConcurrencyQuota:                3,
+		MemoryBytesQuota:                500,
+		InitialMemoryBytesQuotaPerQuery: 200,
+		MemoryBytesQuotaPerQuery:        500,
+		MemoryWaitTimeout:               time.Minute,
+	})
+	ctx := context.Background()
+
+	first, _ := p.reserve(ctx)
+	second, _ := p.reserve(ctx)
+	if first != 200 || second != 200 {
+		t.Fatalf("unexpected reservations: got %d and %d", first, second)
+	}
+
+	reserved := make(chan int64)
+	go func() {
+		n, _ := p.reserve(ctx)
+		reserved <- n
+	}()
+
+	select {
+	case n := <-reserved:
+		t.Fatalf("expected the reservation to wait, got %d", n)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	p.release(second)
+	if n := <-reserved; n != 200 {
+		t.Errorf("unexpected reservation: got %d, want %d", n, 200)
+	}
+}
+
+func TestMemoryPool_WaitCanceled(t *testing.T) {
+	p := newTestMemoryPool(Config{
+		ConcurrencyQuota:                2,
+		MemoryBytesQuota:                100,
+		InitialMemoryBytesQuotaPerQuery: 100,
+		MemoryBytesQuotaPerQuery:        100,
+		MemoryWaitTimeout:               time.Minute,
+	})
+	if _, err := p.reserve(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.reserve(ctx); err != context.Canceled {
+		t.Errorf("expected the reservation to be canceled, got %v", err)
+	}
+}
+
+func TestMemoryPool_Exhausted(t *testing.T) {
+	for _, wait := range []time.Duration{0, 10 * time.Millisecond} {
+		p := newTestMemoryPool(Config{
+			ConcurrencyQuota:                2,
+			MemoryBytesQuota:                100,
+			InitialMemoryBytesQuotaPerQuery: 100,
+			MemoryBytesQuotaPerQuery:        100,
+			MemoryWaitTimeout:               wait,
+		})
+		if _, err := p.reserve(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		// The reservation is rejected at once without a wait timeout, or once it expires.
+		start := time.Now()
+		_, err := p.reserve(context.Background())
+		if got, want := flux.ErrorCode(err), codes.ResourceExhausted; got != want {
+			t.Errorf("unexpected error code with a wait of %v: got %v, want %v", wait, got, want)
+		}
+		if elapsed := time.Since(start); elapsed < wait {
+			t.Errorf("expected the reservation to wait %v, returned after %v", wait, elapsed)
+		}
+	}
+}
@@ -16,6 +16,13 @@ type controllerMetrics struct {
 	compilingDur *prometheus.HistogramVec
 	queueingDur  *prometheus.HistogramVec
 	executingDur *prometheus.HistogramVec
+
+	queueLength *prometheus.GaugeVec
+	queueWait   *prometheus.HistogramVec
+
+	memoryQuota    prometheus.Gauge
+	memoryReserved prometheus.Gauge
+	memoryWaiting  prometheus.Gauge
 }
 
 type requestsLabel string
@@ -107,6 +114,44 @@ func newControllerMetrics(labels []string) *controllerMetrics {
 			Help:      "Histogram of times spent executing queries",
 			Buckets:   prometheus.ExponentialBuckets(1e-3, 5, 7),
 		}, labels),
+
+		// The queue is divided by organization so its metrics are only labelled by organization.
+		queueLength: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "queue_length",
+			Help:      "Number of queries of an organization waiting in the queue",
+		}, []string{orgLabel}),
+
+		queueWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "queue_wait_duration_seconds",
+			Help:      "Histogram of times queries of an organization waited in the queue",
+			Buckets:   prometheus.ExponentialBuckets(1e-3, 5, 7),
+		}, []string{orgLabel}),
+
+		// The memory pool is shared by all of the queries so its metrics are not labelled.
+		memoryQuota: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "memory_quota_bytes",
+			Help:      "Number of bytes in the memory pool executing queries borrow from",
+		}),
+
+		memoryReserved: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "memory_reserved_bytes",
+			Help:      "Number of bytes of the memory pool reserved by executing queries",
+		}),
+
+		memoryWaiting: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "memory_waiting_active",
+			Help:      "Number of queries waiting for memory to be returned to the memory pool",
+		}),
 	}
 }
 
@@ -125,5 +170,12 @@ func (cm *controllerMetrics) PrometheusCollectors() []prometheus.Collector {
 		cm.compilingDur,
 		cm.queueingDur,
 		cm.executingDur,
+
+		cm.queueLength,
+		cm.queueWait,
+
+		cm.memoryQuota,
+		cm.memoryReserved,
+		cm.memoryWaiting,
 	}
 }
@@ -0,0 +1,195 @@
+package control
+
+import (
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+// fairQueue holds the queries awaiting execution in a queue per organization,
+// so that one organization submitting many queries does not delay the others.
+//
+// The organizations are dequeued in weighted fair order using stride scheduling.
+// Every organization has a virtual time that advances by the inverse of its weight
+// each time one of its queries is dequeued, and the next query is taken from the
+// organization with the least virtual time that is below its concurrency quota.
+// Within an organization, queries are dequeued by priority and then in FIFO order.
+type fairQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	closed bool
+
+	size              int
+	sizePerOrg        int
+	concurrencyPerOrg int
+	weights           map[influxdb.ID]int
+	metrics           *controllerMetrics
+
+	len  int
+	orgs map[influxdb.ID]*orgQueue
+
+	// vtime is the virtual time of the organization dequeued last. An organization
+	// that becomes active starts from it so it cannot make up for the time it was idle.
+	vtime float64
+}
+
+// orgQueue holds the queued queries of an organization.
+type orgQueue struct {
+	queues  [numPriorities][]*Query
+	len     int
+	running int
+	vtime   float64
+	stride  float64
+}
+
+const numPriorities = int(query.HighPriority-query.LowPriority) + 1
+
+func newFairQueue(c Config, metrics *controllerMetrics) *fairQueue {
+	f := &fairQueue{
+		size:              c.QueueSize,
+		sizePerOrg:        c.QueueSizePerOrg,
+		concurrencyPerOrg: c.ConcurrencyQuotaPerOrg,
+		weights:           c.OrgWeights,
+		metrics:           metrics,
+		orgs:              make(map[influxdb.ID]*orgQueue),
+	}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// priorityIndex returns the index of the queue of the priority within an orgQueue.
+// Priorities outside of the known range are treated as the closest known priority.
+func priorityIndex(p query.Priority) int {
+	switch {
+	case p < query.LowPriority:
+		p = query.LowPriority
+	case p > query.HighPriority:
+		p = query.HighPriority
+	}
+	return int(p - query.LowPriority)
+}
+
+// push adds the query to the queue of its organization.
+func (f *fairQueue) push(q *Query) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	o := f.orgs[q.orgID]
+	if f.len >= f.size || (o != nil && f.sizePerOrg > 0 && o.len >= f.sizePerOrg) {
+		return &flux.Error{
+			Code: codes.ResourceExhausted,
+			Msg:  "queue length exceeded",
+		}
+	}
+
+	if o == nil {
+		stride := 1.0
+		if w := f.weights[q.orgID]; w > 0 {
+			stride = 1 / float64(w)
+		}
+		o = &orgQueue{stride: stride}
+		f.orgs[q.orgID] = o
+	}
+	if o.len == 0 && o.vtime < f.vtime {
+		o.vtime = f.vtime
+	}
+
+	i := priorityIndex(q.priority)
+	o.queues[i] = append(o.queues[i], q)
+	o.len++
+	f.len++
+
+	q.queuedAt = time.Now()
+	f.metrics.queueLength.WithLabelValues(q.orgID.String()).Inc()
+
+	f.cond.Signal()
+	return nil
+}
+
+// pop removes the next query to execute from the queue, waiting until there is one.
+// The query counts against the concurrency quota of its organization until it is
+// released. It returns false once the queue is closed.
+func (f *fairQueue) pop() (*Query, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for {
+		if f.closed {
+			return nil, false
+		}
+		if q := f.next(); q != nil {
+			return q, true
+		}
+		f.cond.Wait()
+	}
+}
+
+// next removes the next query to execute from the queue, or returns nil if no
+// organization with queued queries is below its concurrency quota.
+// It must be called with the lock held.
+func (f *fairQueue) next() *Query {
+	var (
+		orgID influxdb.ID
+		o     *orgQueue
+	)
+	for id, oq := range f.orgs {
+		if oq.len == 0 || (f.concurrencyPerOrg > 0 && oq.running >= f.concurrencyPerOrg) {
+			continue
+		}
+		// Break ties by ID so the order does not depend on the map iteration.
+		if o == nil || oq.vtime < o.vtime || (oq.vtime == o.vtime && id < orgID) {
+			orgID, o = id, oq
+		}
+	}
+	if o == nil {
+		return nil
+	}
+
+	var q *Query
+	for i := numPriorities - 1; i >= 0; i-- {
+		if queue := o.queues[i]; len(queue) > 0 {
+			q = queue[0]
+			queue[0] = nil
+			o.queues[i] = queue[1:]
+			break
+		}
+	}
+	o.len--
+	o.running++
+	f.len--
+
+	org := orgID.String()
+	f.metrics.queueLength.WithLabelValues(org).Dec()
+	f.metrics.queueWait.WithLabelValues(org).Observe(time.Since(q.queuedAt).Seconds())
+
+	f.vtime = o.vtime
+	o.vtime += o.stride
+	return q
+}
+
+// release marks a query returned by pop as no longer executing.
+func (f *fairQueue) release(q *Query) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	o := f.orgs[q.orgID]
+	o.running--
+	if o.len == 0 && o.running == 0 {
+		delete(f.orgs, q.orgID)
+	}
+
+	// The organization may have been waiting for its concurrency quota.
+	f.cond.Broadcast()
+}
+
+// close wakes up and returns all of the callers waiting in pop.
+func (f *fairQueue) close() {
+	f.mu.Lock()
+	f.closed = true
+	f.cond.Broadcast()
+	f.mu.Unlock()
+}
@@ -0,0 +1,172 @@
+package control
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/prom/promtest"
+	"github.com/influxdata/influxdb/query"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newTestQueue(c Config) *fairQueue {
+	if c.QueueSize == 0 {
+		c.QueueSize = 100
+	}
+	return newFairQueue(c, newControllerMetrics(nil))
+}
+
+// pushQueries queues n queries of the organization with consecutive IDs starting at id.
+func pushQueries(t *testing.T, f *fairQueue, orgID influxdb.ID, id QueryID, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := f.push(&Query{id: id + QueryID(i), orgID: orgID}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
+
+// popQueries dequeues n queries, releasing each of them, and returns their IDs.
+func popQueries(t *testing.T, f *fairQueue, n int) []QueryID {
+	t.Helper()
+	ids := make([]QueryID, 0, n)
+	for i := 0; i < n; i++ {
+		q, ok := f.pop()
+		if !ok {
+			t.Fatal("expected a query")
+		}
+		ids = append(ids, q.id)
+		f.release(q)
+	}
+	return ids
+}
+
+func TestFairQueue_Fairness(t *testing.T) {
+	f := newTestQueue(Config{})
+	pushQueries(t, f, 1, 10, 4)
+	pushQueries(t, f, 2, 20, 2)
+
+	want := []QueryID{10, 20, 11, 21, 12, 13}
+	if diff := cmp.Diff(popQueries(t, f, 6), want); diff != "" {
+		t.Errorf("unexpected order -got/+want\n%s", diff)
+	}
+}
+
+func TestFairQueue_Weights(t *testing.T) {
+	f := newTestQueue(Config{OrgWeights: map[influxdb.ID]int{1: 2}})
+	pushQueries(t, f, 1, 10, 6)
+	pushQueries(t, f, 2, 20, 6)
+
+	want := []QueryID{10, 20, 11, 12, 21, 13}
+	if diff := cmp.Diff(popQueries(t, f, 6), want); diff != "" {
+		t.Errorf("unexpected order -got/+want\n%s", diff)
+	}
+}
+
+func TestFairQueue_IdleOrganization(t *testing.T) {
+	f := newTestQueue(Config{})
+	pushQueries(t, f, 1, 10, 8)
+	popQueries(t, f, 4)
+
+	// An organization that starts queueing later does not get to execute
+	// the queries it did not queue while it was idle, and alternates
+	// with the organization that was active from then on.
+	pushQueries(t, f, 2, 20, 3)
+
+	want := []QueryID{20, 14, 21, 15, 22, 16, 17}
+	if diff := cmp.Diff(popQueries(t, f, 7), want); diff != "" {
+		t.Errorf("unexpected order -got/+want\n%s", diff)
+	}
+}
+
+func TestFairQueue_Priority(t *testing.T) {
+	f := newTestQueue(Config{})
+	for i, p := range []query.Priority{query.LowPriority, query.NormalPriority, query.HighPriority, query.NormalPriority} {
+		if err := f.push(&Query{id: QueryID(i), orgID: 1, priority: p}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	want := []QueryID{2, 1, 3, 0}
+	if diff := cmp.Diff(popQueries(t, f, 4), want); diff != "" {
+		t.Errorf("unexpected order -got/+want\n%s", diff)
+	}
+}
+
+func TestFairQueue_ConcurrencyQuotaPerOrg(t *testing.T) {
+	f := newTestQueue(Config{ConcurrencyQuotaPerOrg: 1})
+	pushQueries(t, f, 1, 10, 2)
+	pushQueries(t, f, 2, 20, 1)
+
+	first, _ := f.pop()
+	second, _ := f.pop()
+	if first.id != 10 || second.id != 20 {
+		t.Fatalf("expected queries 10 and 20 to execute, got %d and %d", first.id, second.id)
+	}
+
+	popped := make(chan *Query)
+	go func() {
+		q, _ := f.pop()
+		popped <- q
+	}()
+
+	// The remaining query waits until the first query of its organization is released.
+	f.release(second)
+	f.release(first)
+	if q := <-popped; q.id != 11 {
+		t.Errorf("expected query 11 to execute, got %d", q.id)
+	}
+}
+
+func TestFairQueue_QueueSize(t *testing.T) {
+	f := newTestQueue(Config{QueueSize: 3, QueueSizePerOrg: 2})
+	pushQueries(t, f, 1, 10, 2)
+
+	if err := f.push(&Query{id: 12, orgID: 1}); err == nil {
+		t.Error("expected the queue of the organization to be full")
+	}
+	pushQueries(t, f, 2, 20, 1)
+	if err := f.push(&Query{id: 30, orgID: 3}); err == nil {
+		t.Error("expected the queue to be full")
+	}
+}
+
+func TestFairQueue_Close(t *testing.T) {
+	f := newTestQueue(Config{})
+
+	done := make(chan bool)
+	go func() {
+		_, ok := f.pop()
+		done <- ok
+	}()
+
+	f.close()
+	if ok := <-done; ok {
+		t.Error("expected no query from a closed queue")
+	}
+}
+
+func TestFairQueue_Metrics(t *testing.T) {
+	metrics := newControllerMetrics(nil)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(metrics.PrometheusCollectors()...)
+
+	f := newFairQueue(Config{QueueSize: 10}, metrics)
+	pushQueries(t, f, 1, 10, 3)
+	pushQueries(t, f, 2, 20, 1)
+	popQueries(t, f, 2)
+
+	mfs := promtest.MustGather(t, reg)
+	for org, want := range map[influxdb.ID]float64{1: 2, 2: 0} {
+		labels := map[string]string{orgLabel: org.String()}
+		m := promtest.MustFindMetric(t, mfs, "query_control_queue_length", labels)
+		if got := m.GetGauge().GetValue(); got != want {
+			t.Errorf("unexpected queue length of org %s: got %v want %v", org, got, want)
+		}
+		m = promtest.MustFindMetric(t, mfs, "query_control_queue_wait_duration_seconds", labels)
+		if got := m.GetHistogram().GetSampleCount(); got != 1 {
+			t.Errorf("unexpected number of waits of org %s: got %d want 1", org, got)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package control
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RunningQueryService = (*Controller)(nil)
+
+// FindRunningQueries reports the active queries matching the filter, oldest first.
+func (c *Controller) FindRunningQueries(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+	queries := c.Queries()
+	rqs := make([]*influxdb.RunningQuery, 0, len(queries))
+	for _, q := range queries {
+		rq := q.runningQuery()
+		if filter.OrgID != nil && rq.OrgID != *filter.OrgID {
+			continue
+		}
+		if filter.UserID != nil && rq.UserID != *filter.UserID {
+			continue
+		}
+		rqs = append(rqs, rq)
+	}
+	sort.Slice(rqs, func(i, j int) bool {
+		return rqs[i].ID < rqs[j].ID
+	})
+	return rqs, nil
+}
+
+// FindRunningQueryByID reports the active query with the id.
+func (c *Controller) FindRunningQueryByID(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+	q, ok := c.findQuery(id)
+	if !ok {
+		return nil, influxdb.ErrRunningQueryNotFound
+	}
+	return q.runningQuery(), nil
+}
+
+// KillRunningQuery cancels the active query with the id. The query is removed
+// once the client that submitted it observes the cancellation.
+func (c *Controller) KillRunningQuery(ctx context.Context, id influxdb.ID) error {
+	q, ok := c.findQuery(id)
+	if !ok {
+		return influxdb.ErrRunningQueryNotFound
+	}
+	q.Cancel()
+	return nil
+}
+
+func (c *Controller) findQuery(id influxdb.ID) (*Query, bool) {
+	c.queriesMu.RLock()
+	defer c.queriesMu.RUnlock()
+	q, ok := c.queries[QueryID(id)]
+	return q, ok
+}
+
+// runningQuery reports the current state and resource usage of the query.
+func (q *Query) runningQuery() *influxdb.RunningQuery {
+	rq := &influxdb.RunningQuery{
+		ID:             influxdb.ID(q.id),
+		OrgID:          q.orgID,
+		CompilerType:   string(q.compilerType),
+		Query:          q.text,
+		State:          q.State().String(),
+		CreatedAt:      q.createdAt,
+		MemoryBytes:    q.alloc.Allocated(),
+		MaxMemoryBytes: q.alloc.MaxAllocated(),
+	}
+	if q.auth != nil {
+		rq.UserID = q.auth.UserID
+		rq.AuthorizationID = q.auth.ID
+	}
+
+	q.stateMu.RLock()
+	rq.QueueDuration = q.stats.QueueDuration
+	rq.ExecuteDuration = q.stats.ExecuteDuration
+	// The duration of the current state is only added to the
+	// statistics once the query leaves it.
+	if q.currentSpan != nil {
+		switch elapsed := time.Since(q.currentSpan.start); q.state {
+		case Queueing:
+			rq.QueueDuration += elapsed
+		case Executing:
+			rq.ExecuteDuration += elapsed
+		}
+	}
+	q.stateMu.RUnlock()
+	return rq
+}
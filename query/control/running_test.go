@@ -0,0 +1,96 @@
+package control_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/mock"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query/control"
+)
+
+func TestController_KillRunningQuery(t *testing.T) {
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	executing := make(chan struct{})
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					close(executing)
+					<-ctx.Done()
+				},
+			}, nil
+		},
+	}
+
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+	req := makeRequest(compiler)
+	req.OrganizationID = orgID
+	req.Authorization = &influxdb.Authorization{ID: 3, UserID: 4}
+
+	ctx := context.Background()
+	q, err := ctrl.Query(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Wait until execution has started.
+	<-executing
+
+	qs, err := ctrl.FindRunningQueries(ctx, influxdb.RunningQueryFilter{OrgID: &otherOrgID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(qs) != 0 {
+		t.Fatalf("expected no queries of the other org, got %d", len(qs))
+	}
+
+	qs, err = ctrl.FindRunningQueries(ctx, influxdb.RunningQueryFilter{OrgID: &orgID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(qs) != 1 {
+		t.Fatalf("expected one query, got %d", len(qs))
+	}
+	rq := qs[0]
+	if rq.UserID != 4 || rq.AuthorizationID != 3 {
+		t.Errorf("unexpected user %s and authorization %s", rq.UserID, rq.AuthorizationID)
+	}
+	if got, want := rq.CompilerType, "mockCompiler"; got != want {
+		t.Errorf("unexpected compiler type -want/+got\n\t- %s\n\t+ %s", want, got)
+	}
+	if got, want := rq.State, "executing"; got != want {
+		t.Errorf("unexpected state -want/+got\n\t- %s\n\t+ %s", want, got)
+	}
+	if rq.QueueDuration == 0 || rq.ExecuteDuration == 0 {
+		t.Errorf("expected queue and execute durations to be above zero, got %s and %s", rq.QueueDuration, rq.ExecuteDuration)
+	}
+
+	if err := ctrl.KillRunningQuery(ctx, rq.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	rq, err = ctrl.FindRunningQueryByID(ctx, rq.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if got, want := rq.State, "canceled"; got != want {
+		t.Errorf("unexpected state -want/+got\n\t- %s\n\t+ %s", want, got)
+	}
+
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+
+	if _, err := ctrl.FindRunningQueryByID(ctx, rq.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected query to be removed once done, got %v", err)
+	}
+	if err := ctrl.KillRunningQuery(ctx, rq.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -13,9 +13,12 @@ import (
 const CompilerType = "influxql"
 
 // AddCompilerMappings adds the influxql specific compiler mappings.
-func AddCompilerMappings(mappings flux.CompilerMappings, dbrpMappingSvc platform.DBRPMappingService) error {
+// The schemaMapper is used to expand wildcards and may be nil.
+func AddCompilerMappings(mappings flux.CompilerMappings, dbrpMappingSvc platform.DBRPMappingService, schemaMapper SchemaMapper) error {
 	return mappings.Add(CompilerType, func() flux.Compiler {
-		return NewCompiler(dbrpMappingSvc)
+		c := NewCompiler(dbrpMappingSvc)
+		c.WithSchemaMapper(schemaMapper)
+		return c
 	})
 }
 
@@ -30,6 +33,7 @@ type Compiler struct {
 	logicalPlannerOptions []plan.LogicalOption
 
 	dbrpMappingSvc platform.DBRPMappingService
+	schemaMapper   SchemaMapper
 }
 
 var _ flux.Compiler = &Compiler{}
@@ -57,6 +61,7 @@ func (c *Compiler) Compile(ctx context.Context) (flux.Program, error) {
 			Now:                    now,
 		},
 	)
+	transpiler.WithSchemaMapper(c.schemaMapper)
 	astPkg, err := transpiler.Transpile(ctx, c.Query)
 	if err != nil {
 		return nil, err
@@ -69,6 +74,11 @@ func (c *Compiler) CompilerType() flux.CompilerType {
 	return CompilerType
 }
 
+// WithSchemaMapper sets the SchemaMapper used to expand wildcards.
+func (c *Compiler) WithSchemaMapper(m SchemaMapper) {
+	c.schemaMapper = m
+}
+
 func (c *Compiler) WithLogicalPlannerOptions(opts ...plan.LogicalOption) {
 	c.logicalPlannerOptions = opts
 }
@@ -1,7 +1,9 @@
 package influxql
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
@@ -30,40 +32,39 @@ type varRefCursor struct {
 	ref  *influxql.VarRef
 }
 
-// createVarRefCursor creates a new cursor from a variable reference using the sources
-// in the transpilerState.
-func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error) {
+// createSourceCursor creates a new cursor that reads the variable references from the
+// source in the transpilerState.
+func createSourceCursor(ctx context.Context, t *transpilerState, refs []*influxql.VarRef) (cursor, error) {
 	if len(t.stmt.Sources) != 1 {
 		// TODO(jsternberg): Support multiple sources.
 		return nil, errors.New("unimplemented: only one source is allowed")
 	}
 
-	// Only support a direct measurement. Subqueries are not supported yet.
-	mm, ok := t.stmt.Sources[0].(*influxql.Measurement)
-	if !ok {
-		return nil, errors.New("unimplemented: source must be a measurement")
+	switch src := t.stmt.Sources[0].(type) {
+	case *influxql.Measurement:
+		return createMeasurementCursor(ctx, t, src, refs)
+	case *influxql.SubQuery:
+		return createSubqueryCursor(ctx, t, src, refs)
+	default:
+		return nil, errors.New("unimplemented: source must be a measurement or a subquery")
 	}
+}
 
+// createMeasurementCursor creates a new cursor that reads the fields of the variable references
+// from a measurement. When more than one field is read, the fields are pivoted into a column
+// for each of them.
+func createMeasurementCursor(ctx context.Context, t *transpilerState, mm *influxql.Measurement, refs []*influxql.VarRef) (cursor, error) {
 	// Create the from spec and add it to the list of operations.
-	from, err := t.from(mm)
+	from, err := t.from(ctx, mm)
 	if err != nil {
 		return nil, err
 	}
 
-	valuer := influxql.NowValuer{Now: t.config.Now}
-	_, tr, err := influxql.ConditionExpr(t.stmt.Condition, &valuer)
+	tr, err := t.timeRange()
 	if err != nil {
 		return nil, err
 	}
 
-	// If the maximum is not set and we have a windowing function, then
-	// the end time will be set to now.
-	if tr.Max.IsZero() {
-		if window, err := t.stmt.GroupByInterval(); err == nil && window > 0 {
-			tr.Max = t.config.Now
-		}
-	}
-
 	range_ := &ast.PipeExpression{
 		Argument: from,
 		Call: &ast.CallExpression{
@@ -95,7 +96,31 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 		},
 	}
 
-	expr := &ast.PipeExpression{
+	// Match any of the fields that were referenced.
+	var fieldExpr ast.Expression
+	for _, ref := range refs {
+		expr := &ast.BinaryExpression{
+			Operator: ast.EqualOperator,
+			Left: &ast.MemberExpression{
+				Object:   &ast.Identifier{Name: "r"},
+				Property: &ast.Identifier{Name: "_field"},
+			},
+			Right: &ast.StringLiteral{
+				Value: ref.Val,
+			},
+		}
+		if fieldExpr == nil {
+			fieldExpr = expr
+			continue
+		}
+		fieldExpr = &ast.LogicalExpression{
+			Operator: ast.OrOperator,
+			Left:     fieldExpr,
+			Right:    expr,
+		}
+	}
+
+	expr := ast.Expression(&ast.PipeExpression{
 		Argument: range_,
 		Call: &ast.CallExpression{
 			Callee: &ast.Identifier{
@@ -126,16 +151,7 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 											Value: mm.Name,
 										},
 									},
-									Right: &ast.BinaryExpression{
-										Operator: ast.EqualOperator,
-										Left: &ast.MemberExpression{
-											Object:   &ast.Identifier{Name: "r"},
-											Property: &ast.Identifier{Name: "_field"},
-										},
-										Right: &ast.StringLiteral{
-											Value: ref.Val,
-										},
-									},
+									Right: fieldExpr,
 								},
 							},
 						},
@@ -143,13 +159,135 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 				},
 			},
 		},
+	})
+	if len(refs) == 1 {
+		return &varRefCursor{
+			expr: expr,
+			ref:  refs[0],
+		}, nil
 	}
-	return &varRefCursor{
+
+	expr = pipeCall(expr, "pivot",
+		&ast.Property{
+			Key: &ast.Identifier{Name: "rowKey"},
+			Value: &ast.ArrayExpression{
+				Elements: []ast.Expression{
+					&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+				},
+			},
+		},
+		&ast.Property{
+			Key: &ast.Identifier{Name: "columnKey"},
+			Value: &ast.ArrayExpression{
+				Elements: []ast.Expression{
+					&ast.StringLiteral{Value: "_field"},
+				},
+			},
+		},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "valueColumn"},
+			Value: &ast.StringLiteral{Value: execute.DefaultValueColLabel},
+		},
+	)
+	return &columnsCursor{
 		expr: expr,
-		ref:  ref,
+		refs: refs,
 	}, nil
 }
 
+// createSubqueryCursor creates a new cursor that reads the variable references from the
+// columns produced by a subquery.
+func createSubqueryCursor(ctx context.Context, t *transpilerState, src *influxql.SubQuery, refs []*influxql.VarRef) (cursor, error) {
+	// Transpile each subquery once and assign its results to a variable
+	// so it can be shared by each of the groups that read from it.
+	sub, ok := t.subqueries[src]
+	if !ok {
+		stmt := src.Statement
+		if len(stmt.SortFields) > 0 && stmt.TimeAscending() != t.stmt.TimeAscending() {
+			return nil, errors.New("subqueries must be ordered in the same direction as the query itself")
+		}
+
+		// A subquery with functions that does not have its own interval uses the interval
+		// of the statement that encloses it.
+		if interval, err := stmt.GroupByInterval(); err != nil {
+			return nil, err
+		} else if interval == 0 && !stmt.IsRawQuery {
+			for _, d := range t.stmt.Dimensions {
+				if call, ok := d.Expr.(*influxql.Call); ok && call.Name == "time" {
+					stmt = stmt.Clone()
+					stmt.Dimensions = append(stmt.Dimensions, &influxql.Dimension{
+						Expr: influxql.CloneExpr(call),
+					})
+					break
+				}
+			}
+		}
+
+		inner := &transpilerState{
+			config:         t.config,
+			file:           t.file,
+			assignments:    t.assignments,
+			dbrpMappingSvc: t.dbrpMappingSvc,
+			schema:         t.schema,
+			schemas:        t.schemas,
+			parent:         t,
+		}
+		cur, err := inner.transpileSelect(ctx, stmt)
+		if err != nil {
+			return nil, err
+		}
+
+		columns := make(map[string]struct{})
+		for _, name := range inner.stmt.ColumnNames() {
+			columns[name] = struct{}{}
+		}
+		sub = &subqueryCursor{
+			expr:    t.assignment(cur.Expr()),
+			columns: columns,
+		}
+		t.subqueries[src] = sub
+	}
+
+	var keys []*influxql.VarRef
+	for _, ref := range refs {
+		if _, ok := sub.columns[ref.Val]; !ok {
+			return nil, fmt.Errorf("unimplemented: variable %s is not a column of the subquery", ref.Val)
+		}
+		keys = append(keys, ref)
+	}
+	return &columnsCursor{
+		expr: sub.expr,
+		refs: keys,
+	}, nil
+}
+
+// timeRange returns the time range of the statement. The time range of a
+// subquery is limited to the time range of the statement that encloses it.
+func (t *transpilerState) timeRange() (influxql.TimeRange, error) {
+	valuer := influxql.NowValuer{Now: t.config.Now}
+	_, tr, err := influxql.ConditionExpr(t.stmt.Condition, &valuer)
+	if err != nil {
+		return influxql.TimeRange{}, err
+	}
+
+	if t.parent != nil {
+		outer, err := t.parent.timeRange()
+		if err != nil {
+			return influxql.TimeRange{}, err
+		}
+		tr = tr.Intersect(outer)
+	}
+
+	// If the maximum is not set and we have a windowing function, then
+	// the end time will be set to now.
+	if tr.Max.IsZero() {
+		if window, err := t.stmt.GroupByInterval(); err == nil && window > 0 {
+			tr.Max = t.config.Now
+		}
+	}
+	return tr, nil
+}
+
 func (c *varRefCursor) Expr() ast.Expression {
 	return c.expr
 }
@@ -164,8 +302,8 @@ func (c *varRefCursor) Value(expr influxql.Expr) (string, bool) {
 		return "", false
 	}
 
-	// If these are the same variable reference (by pointer), then they are equal.
-	if ref == c.ref || *ref == *c.ref {
+	// If these reference the same variable, then they are equal.
+	if ref == c.ref || ref.Val == c.ref.Val {
 		return execute.DefaultValueColLabel, true
 	}
 	return "", false
@@ -179,3 +317,43 @@ type pipeCursor struct {
 }
 
 func (c *pipeCursor) Expr() ast.Expression { return c.expr }
+
+// columnsCursor contains a cursor for multiple variables that are each
+// stored in the column with the name of the variable.
+type columnsCursor struct {
+	expr ast.Expression
+	refs []*influxql.VarRef
+}
+
+func (c *columnsCursor) Expr() ast.Expression {
+	return c.expr
+}
+
+func (c *columnsCursor) Keys() []influxql.Expr {
+	keys := make([]influxql.Expr, 0, len(c.refs))
+	for _, ref := range c.refs {
+		keys = append(keys, ref)
+	}
+	return keys
+}
+
+func (c *columnsCursor) Value(expr influxql.Expr) (string, bool) {
+	ref, ok := expr.(*influxql.VarRef)
+	if !ok {
+		return "", false
+	}
+
+	for _, r := range c.refs {
+		if r == ref || r.Val == ref.Val {
+			return ref.Val, true
+		}
+	}
+	return "", false
+}
+
+// subqueryCursor holds the variable that the results of a subquery are assigned to
+// and the names of the columns it produces.
+type subqueryCursor struct {
+	expr    ast.Expression
+	columns map[string]struct{}
+}
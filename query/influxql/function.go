@@ -3,6 +3,8 @@ package influxql
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
@@ -32,13 +34,22 @@ func parseFunction(expr *influxql.Call) (*function, error) {
 				Ref:  ref,
 				call: expr,
 			}, nil
+		case *influxql.Distinct:
+			// Treat count(DISTINCT value) the same as count(distinct(value)).
+			expr.Args[0] = ref.NewCall()
+			return parseFunction(expr)
 		case *influxql.Call:
-			if ref.Name == "distinct" {
-				return nil, errors.New("unimplemented: count(distinct)")
+			if ref.Name != "distinct" {
+				return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
 			}
-			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
-		case *influxql.Distinct:
-			return nil, errors.New("unimplemented: count(distinct)")
+			fn, err := parseFunction(ref)
+			if err != nil {
+				return nil, err
+			}
+			return &function{
+				Ref:  fn.Ref,
+				call: expr,
+			}, nil
 		case *influxql.Wildcard:
 			return nil, errors.New("unimplemented: wildcard function")
 		case *influxql.RegexLiteral:
@@ -46,7 +57,22 @@ func parseFunction(expr *influxql.Call) (*function, error) {
 		default:
 			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
 		}
-	case "min", "max", "sum", "first", "last", "mean", "median":
+	case "distinct":
+		if len(expr.Args) == 0 {
+			return nil, errors.New("distinct function requires at least one argument")
+		} else if len(expr.Args) != 1 {
+			return nil, errors.New("distinct function can only have one argument")
+		}
+
+		ref, ok := expr.Args[0].(*influxql.VarRef)
+		if !ok {
+			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
+		}
+		return &function{
+			Ref:  ref,
+			call: expr,
+		}, nil
+	case "min", "max", "sum", "first", "last", "mean", "median", "stddev", "spread", "mode":
 		if exp, got := 1, len(expr.Args); exp != got {
 			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
 		}
@@ -93,12 +119,111 @@ func parseFunction(expr *influxql.Call) (*function, error) {
 			Ref:  functionRef,
 			call: expr,
 		}, nil
+	case "top", "bottom":
+		if got := len(expr.Args); got < 2 {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected at least %d, got %d", expr.Name, 2, got)
+		}
+
+		ref, ok := expr.Args[0].(*influxql.VarRef)
+		if !ok {
+			return nil, fmt.Errorf("expected first argument to be a field in %s(), found %s", expr.Name, expr.Args[0])
+		}
+
+		last := expr.Args[len(expr.Args)-1]
+		if lit, ok := last.(*influxql.IntegerLiteral); !ok {
+			return nil, fmt.Errorf("expected integer as last argument in %s(), found %s", expr.Name, last)
+		} else if lit.Val <= 0 {
+			return nil, fmt.Errorf("limit (%d) in %s function must be at least 1", lit.Val, expr.Name)
+		}
+
+		// The arguments between the field and the limit are tags.
+		tags := expr.Args[1 : len(expr.Args)-1]
+		for _, tag := range tags {
+			if _, ok := tag.(*influxql.VarRef); !ok {
+				return nil, fmt.Errorf("only fields or tags are allowed in %s(), found %s", expr.Name, tag)
+			}
+		}
+		if len(tags) > 0 {
+			return nil, fmt.Errorf("unimplemented: %s with tags", expr.Name)
+		}
+
+		return &function{
+			Ref:  ref,
+			call: expr,
+		}, nil
+	case "derivative", "non_negative_derivative", "elapsed",
+		"difference", "non_negative_difference", "cumulative_sum", "moving_average":
+		switch expr.Name {
+		case "derivative", "non_negative_derivative", "elapsed":
+			if got := len(expr.Args); got < 1 || got > 2 {
+				return nil, fmt.Errorf("invalid number of arguments for %s, expected at least %d but no more than %d, got %d", expr.Name, 1, 2, got)
+			}
+			if len(expr.Args) == 2 {
+				if lit, ok := expr.Args[1].(*influxql.DurationLiteral); !ok {
+					return nil, fmt.Errorf("second argument to %s must be a duration, got %T", expr.Name, expr.Args[1])
+				} else if lit.Val <= 0 {
+					return nil, fmt.Errorf("duration argument must be positive, got %s", lit)
+				}
+			}
+		case "moving_average":
+			if exp, got := 2, len(expr.Args); exp != got {
+				return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+			}
+			if lit, ok := expr.Args[1].(*influxql.IntegerLiteral); !ok {
+				return nil, fmt.Errorf("second argument for %s must be an integer, got %T", expr.Name, expr.Args[1])
+			} else if lit.Val <= 1 {
+				return nil, fmt.Errorf("moving_average window must be greater than 1, got %d", lit.Val)
+			}
+		default:
+			if exp, got := 1, len(expr.Args); exp != got {
+				return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+			}
+		}
+
+		switch ref := expr.Args[0].(type) {
+		case *influxql.VarRef:
+			return &function{
+				Ref:  ref,
+				call: expr,
+			}, nil
+		case *influxql.Call:
+			// The transformation is applied to the result of an aggregate.
+			if isTransformation(ref) {
+				return nil, fmt.Errorf("unsupported nested function %s() in %s()", ref.Name, expr.Name)
+			}
+			fn, err := parseFunction(ref)
+			if err != nil {
+				return nil, err
+			}
+			return &function{
+				Ref:  fn.Ref,
+				call: expr,
+			}, nil
+		case *influxql.Wildcard:
+			return nil, errors.New("unimplemented: wildcard function")
+		case *influxql.RegexLiteral:
+			return nil, errors.New("unimplemented: wildcard regex function")
+		default:
+			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
+		}
 	default:
 		return nil, fmt.Errorf("unimplemented function: %q", expr.Name)
 	}
 
 }
 
+// isTransformation returns true if the call computes a value for each point of the
+// series it is given rather than aggregating or selecting points.
+func isTransformation(call *influxql.Call) bool {
+	switch call.Name {
+	case "derivative", "non_negative_derivative", "elapsed",
+		"difference", "non_negative_difference", "cumulative_sum", "moving_average":
+		return true
+	default:
+		return false
+	}
+}
+
 // createFunctionCursor creates a new cursor that calls a function on one of the columns
 // and returns the result.
 func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, normalize bool) (cursor, error) {
@@ -107,48 +232,51 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 		parent: in,
 	}
 	switch call.Name {
-	case "count", "min", "max", "sum", "first", "last", "mean":
+	case "count":
+		// count(distinct(value)) counts the distinct values.
+		if distinct, ok := call.Args[0].(*influxql.Call); ok {
+			value, ok := in.Value(distinct.Args[0])
+			if !ok {
+				return nil, fmt.Errorf("undefined variable: %s", distinct.Args[0])
+			}
+			// The distinct values are always in the value column.
+			cur.expr = pipeCall(pipeCall(in.Expr(), "distinct", columnArgs(value)...), "count")
+			cur.value = execute.DefaultValueColLabel
+			cur.exclude = map[influxql.Expr]struct{}{distinct: {}, distinct.Args[0]: {}}
+			break
+		}
 		value, ok := in.Value(call.Args[0])
 		if !ok {
 			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
 		}
-		cur.expr = &ast.PipeExpression{
-			Argument: in.Expr(),
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{
-					Name: call.Name,
-				},
-			},
+		cur.expr = pipeCall(in.Expr(), "count", columnArgs(value)...)
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "min", "max", "sum", "first", "last", "mean", "stddev", "spread", "mode", "distinct":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
 		}
+		cur.expr = pipeCall(in.Expr(), call.Name, columnArgs(value)...)
 		cur.value = value
+		if call.Name == "distinct" || call.Name == "mode" {
+			// The result of these functions is always in the value column.
+			cur.value = execute.DefaultValueColLabel
+		}
 		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
 	case "median":
 		value, ok := in.Value(call.Args[0])
 		if !ok {
 			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
 		}
-		cur.expr = &ast.PipeExpression{
-			Argument: in.Expr(),
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{
-					Name: "median",
-				},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: []*ast.Property{
-							{
-								Key: &ast.Identifier{
-									Name: "method",
-								},
-								Value: &ast.StringLiteral{
-									Value: "exact_mean",
-								},
-							},
-						},
-					},
-				},
+		cur.expr = pipeCall(in.Expr(), "median", append([]*ast.Property{{
+			Key: &ast.Identifier{
+				Name: "method",
 			},
-		}
+			Value: &ast.StringLiteral{
+				Value: "exact_mean",
+			},
+		}}, columnArgs(value)...)...)
 		cur.value = value
 		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
 	case "percentile":
@@ -193,18 +321,7 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 				},
 			},
 		}
-		if fieldName != execute.DefaultValueColLabel {
-			args = append(args, &ast.Property{
-				Key: &ast.Identifier{
-					Name: "columns",
-				},
-				Value: &ast.ArrayExpression{
-					Elements: []ast.Expression{
-						&ast.StringLiteral{Value: fieldName},
-					},
-				},
-			})
-		}
+		args = append(args, columnArgs(fieldName)...)
 		cur.expr = &ast.PipeExpression{
 			Argument: in.Expr(),
 			Call: &ast.CallExpression{
@@ -220,6 +337,129 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 		}
 		cur.value = fieldName
 		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "top", "bottom":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+		n := call.Args[1].(*influxql.IntegerLiteral)
+
+		// The selected points are returned in time order.
+		cur.expr = pipeCall(
+			pipeCall(in.Expr(), call.Name, append([]*ast.Property{{
+				Key:   &ast.Identifier{Name: "n"},
+				Value: &ast.IntegerLiteral{Value: n.Val},
+			}}, columnsArgs(value)...)...),
+			"sort",
+			&ast.Property{
+				Key: &ast.Identifier{Name: "columns"},
+				Value: &ast.ArrayExpression{
+					Elements: []ast.Expression{
+						&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+					},
+				},
+			},
+		)
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+
+		// Top and bottom keep the time of the points they select.
+		normalize = false
+	case "derivative", "non_negative_derivative":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		// The unit defaults to the interval of the aggregate when there is one.
+		unit := time.Second
+		if len(call.Args) == 2 {
+			unit = call.Args[1].(*influxql.DurationLiteral).Val
+		} else if interval, err := t.stmt.GroupByInterval(); err != nil {
+			return nil, err
+		} else if interval > 0 {
+			unit = interval
+		}
+
+		args := []*ast.Property{{
+			Key: &ast.Identifier{Name: "unit"},
+			Value: &ast.DurationLiteral{
+				Values: durationLiteral(unit),
+			},
+		}}
+		if call.Name == "non_negative_derivative" {
+			args = append(args, &ast.Property{
+				Key:   &ast.Identifier{Name: "nonNegative"},
+				Value: &ast.BooleanLiteral{Value: true},
+			})
+		}
+		args = append(args, columnsArgs(value)...)
+		cur.expr = pipeCall(in.Expr(), "derivative", args...)
+		if call.Name == "non_negative_derivative" {
+			cur.expr = dropNulls(cur.expr, value)
+		}
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "difference", "non_negative_difference":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		var args []*ast.Property
+		if call.Name == "non_negative_difference" {
+			args = append(args, &ast.Property{
+				Key:   &ast.Identifier{Name: "nonNegative"},
+				Value: &ast.BooleanLiteral{Value: true},
+			})
+		}
+		args = append(args, columnsArgs(value)...)
+		cur.expr = pipeCall(in.Expr(), "difference", args...)
+		if call.Name == "non_negative_difference" {
+			cur.expr = dropNulls(cur.expr, value)
+		}
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "cumulative_sum":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+		cur.expr = pipeCall(in.Expr(), "cumulativeSum", columnsArgs(value)...)
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "moving_average":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		} else if value != execute.DefaultValueColLabel {
+			// The moving average can only be computed for the value column.
+			return nil, fmt.Errorf("unimplemented: %s of a field read with other fields", call.Name)
+		}
+		cur.expr = pipeCall(in.Expr(), "movingAverage", &ast.Property{
+			Key:   &ast.Identifier{Name: "n"},
+			Value: &ast.IntegerLiteral{Value: call.Args[1].(*influxql.IntegerLiteral).Val},
+		})
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "elapsed":
+		if _, ok := in.Value(call.Args[0]); !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		// The elapsed time is in nanoseconds unless a unit is given.
+		unit := time.Nanosecond
+		if len(call.Args) == 2 {
+			unit = call.Args[1].(*influxql.DurationLiteral).Val
+		}
+		cur.expr = pipeCall(in.Expr(), "elapsed", &ast.Property{
+			Key: &ast.Identifier{Name: "unit"},
+			Value: &ast.DurationLiteral{
+				Values: durationLiteral(unit),
+			},
+		})
+		cur.value = "elapsed"
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
 	default:
 		return nil, fmt.Errorf("unimplemented function: %q", call.Name)
 	}
@@ -284,6 +524,64 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 	return cur, nil
 }
 
+// pipeCall pipes the argument into a call of the named function with the properties as its arguments.
+func pipeCall(arg ast.Expression, name string, properties ...*ast.Property) ast.Expression {
+	call := &ast.CallExpression{
+		Callee: &ast.Identifier{
+			Name: name,
+		},
+	}
+	if len(properties) > 0 {
+		call.Arguments = []ast.Expression{
+			&ast.ObjectExpression{
+				Properties: properties,
+			},
+		}
+	}
+	return &ast.PipeExpression{
+		Argument: arg,
+		Call:     call,
+	}
+}
+
+// columnArgs returns the column argument of an aggregate or selector function
+// when the value is not stored in the default value column.
+func columnArgs(value string) []*ast.Property {
+	if value == execute.DefaultValueColLabel {
+		return nil
+	}
+	return []*ast.Property{stringProperty("column", value)}
+}
+
+// columnsArgs returns the columns argument of a function when the value
+// is not stored in the default value column.
+func columnsArgs(value string) []*ast.Property {
+	if value == execute.DefaultValueColLabel {
+		return nil
+	}
+	return []*ast.Property{columnsProperty(value)}
+}
+
+// dropNulls filters out the rows of the argument without a value in the column. The non-negative
+// transformations of Flux replace negative values with null, but InfluxQL omits those points.
+func dropNulls(arg ast.Expression, column string) ast.Expression {
+	return pipeCall(arg, "filter", &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: &ast.UnaryExpression{
+				Operator: ast.ExistsOperator,
+				Argument: &ast.MemberExpression{
+					Object:   &ast.Identifier{Name: "r"},
+					Property: columnKey(column),
+				},
+			},
+		},
+	})
+}
+
 type functionCursor struct {
 	expr    ast.Expression
 	call    *influxql.Call
@@ -317,3 +615,11 @@ func (c *functionCursor) Value(expr influxql.Expr) (string, bool) {
 	}
 	return c.parent.Value(expr)
 }
+
+// columnKey returns the property key used to access a column of a row.
+func columnKey(column string) ast.PropertyKey {
+	if strings.HasPrefix(column, "_") {
+		return &ast.Identifier{Name: column}
+	}
+	return &ast.StringLiteral{Value: column}
+}
@@ -1,6 +1,7 @@
 package influxql
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -57,6 +58,32 @@ func (v *groupVisitor) Visit(n influxql.Node) influxql.Visitor {
 	return v
 }
 
+// validateWildcardFunctions ensures a function with a wildcard is not combined with other fields.
+// A wildcard function may expand to multiple selectors so it is treated as an aggregate.
+func validateWildcardFunctions(stmt *influxql.SelectStatement) error {
+	var hasWildcardCall, hasAux bool
+	for _, f := range stmt.Fields {
+		switch expr := f.Expr.(type) {
+		case *influxql.Call:
+			if ref, ok := callArg(expr).(*influxql.Wildcard); ok && ref != nil {
+				hasWildcardCall = true
+			} else if _, ok := callArg(expr).(*influxql.RegexLiteral); ok {
+				hasWildcardCall = true
+			}
+		case *influxql.VarRef:
+			if expr.Val != "time" {
+				hasAux = true
+			}
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			hasAux = true
+		}
+	}
+	if hasWildcardCall && hasAux {
+		return errors.New("mixing aggregate and non-aggregate queries is not supported")
+	}
+	return nil
+}
+
 // identifyGroups will identify the groups for creating data access cursors.
 func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
 	v := &groupVisitor{}
@@ -65,6 +92,24 @@ func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
 		return nil, v.err
 	}
 
+	// Top, bottom and distinct produce multiple rows so they cannot be combined with anything else.
+	for _, fn := range v.calls {
+		switch fn.call.Name {
+		case "top", "bottom":
+			if len(v.calls) > 1 {
+				return nil, fmt.Errorf("selector function %s() cannot be combined with other functions", fn.call.Name)
+			}
+			n := fn.call.Args[len(fn.call.Args)-1].(*influxql.IntegerLiteral)
+			if stmt.Limit > 0 && int(n.Val) > stmt.Limit {
+				return nil, fmt.Errorf("limit (%d) in %s function can not be larger than the LIMIT (%d) in the select statement", n.Val, fn.call.Name, stmt.Limit)
+			}
+		case "distinct":
+			if len(v.calls) > 1 || len(v.refs) > 0 {
+				return nil, fmt.Errorf("aggregate function %s() cannot be combined with other functions or fields", fn.call.Name)
+			}
+		}
+	}
+
 	// Attempt to take the calls and variables and put them into groups.
 	if len(v.refs) > 0 {
 		// If any of the calls are not selectors, we have an error message.
@@ -105,92 +150,82 @@ func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
 	return groups, nil
 }
 
-func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
-	// Create all of the cursors for every variable reference.
-	// TODO(jsternberg): Determine which of these cursors are from fields and which are tags.
-	var cursors []cursor
+func (gr *groupInfo) createCursor(ctx context.Context, t *transpilerState) (cursor, error) {
+	// Identify the fields that need to be read for every variable reference.
+	// The variables that are tags are read from the series of the fields.
+	var (
+		fields []*influxql.VarRef
+		tags   map[influxql.VarRef]struct{}
+	)
+	addField := func(ref *influxql.VarRef) {
+		for _, f := range fields {
+			if f.Val == ref.Val {
+				return
+			}
+		}
+		fields = append(fields, ref)
+	}
+	addTag := func(ref *influxql.VarRef) {
+		if tags == nil {
+			tags = make(map[influxql.VarRef]struct{})
+		}
+		tags[*ref] = struct{}{}
+	}
+
 	if gr.call != nil {
-		ref, ok := gr.call.Args[0].(*influxql.VarRef)
+		ref, ok := callRef(gr.call)
 		if !ok {
 			// TODO(jsternberg): This should be validated and figured out somewhere else.
 			return nil, fmt.Errorf("first argument to %q must be a variable", gr.call.Name)
 		}
-		cur, err := createVarRefCursor(t, ref)
-		if err != nil {
-			return nil, err
-		}
-		cursors = append(cursors, cur)
+		addField(ref)
 	}
 
 	for _, ref := range gr.refs {
-		cur, err := createVarRefCursor(t, ref)
-		if err != nil {
-			return nil, err
+		if t.typeOf(ref) == influxql.Tag {
+			addTag(ref)
+			continue
 		}
-		cursors = append(cursors, cur)
+		addField(ref)
 	}
 
-	// TODO(jsternberg): Establish which variables in the condition are tags and which are fields.
-	// We need to create the references to fields here so they can be joined.
-	var (
-		tags map[influxql.VarRef]struct{}
-		cond influxql.Expr
-	)
+	var cond influxql.Expr
 	valuer := influxql.NowValuer{Now: t.config.Now}
 	if t.stmt.Condition != nil {
 		var err error
 		if cond, _, err = influxql.ConditionExpr(t.stmt.Condition, &valuer); err != nil {
 			return nil, err
 		} else if cond != nil {
-			tags = make(map[influxql.VarRef]struct{})
-
 			// Walk through the condition for every variable reference. There will be no function
 			// calls here.
-			var condErr error
 			influxql.WalkFunc(cond, func(node influxql.Node) {
-				if condErr != nil {
-					return
-				}
 				ref, ok := node.(*influxql.VarRef)
 				if !ok {
 					return
 				}
 
-				// If the variable reference is in any of the cursors, it is definitely
-				// a field and we do not have to inspect it further.
-				for _, cur := range cursors {
-					if _, ok := cur.Value(ref); ok {
-						return
-					}
-				}
-
-				// This may be a field or a tag. If it is a field, we need to create the cursor
-				// and add it to the listing of cursors so it can be joined before we evaluate the condition.
-				switch typ := t.mapType(ref); typ {
+				// This may be a field or a tag. If it is a field, we need to read it
+				// so it is available before we evaluate the condition.
+				switch typ := t.mapType(ref, cond); typ {
 				case influxql.Tag:
 					// Add this variable name to the listing of tags.
-					tags[*ref] = struct{}{}
+					addTag(ref)
 				default:
-					cur, err := createVarRefCursor(t, ref)
-					if err != nil {
-						condErr = err
-						return
-					}
-					cursors = append(cursors, cur)
+					addField(ref)
 				}
 			})
 		}
 	}
 
-	// Join the cursors using an inner join.
-	// TODO(jsternberg): We need to differentiate between various join types and this needs to be
-	// except: ["_field"] rather than joining on the _measurement. This also needs to specify what the time
-	// column should be.
-	if len(cursors) > 1 {
-		return nil, errors.New("unimplemented: joining fields within a cursor")
+	if len(fields) == 0 {
+		return nil, errors.New("at least 1 non-time field must be queried")
+	}
+
+	cur, err := createSourceCursor(ctx, t, fields)
+	if err != nil {
+		return nil, err
 	}
 
-	cur := Join(t, cursors, []string{"_measurement"})
 	if len(tags) > 0 {
 		cur = &tagsCursor{cursor: cur, tags: tags}
 	}
@@ -242,7 +277,24 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 
 	// If a function call is present, evaluate the function call.
 	if gr.call != nil {
-		c, err := createFunctionCursor(t, gr.call, cur, !gr.selector || interval > 0)
+		// A transformation is either applied to the raw points or to the result
+		// of the aggregate nested within it, which requires a time interval.
+		call, transform := gr.call, (*influxql.Call)(nil)
+		if isTransformation(call) {
+			transform = call
+			inner, ok := call.Args[0].(*influxql.Call)
+			if !ok {
+				if interval > 0 {
+					return nil, fmt.Errorf("aggregate function required inside the call to %s", call.Name)
+				}
+				return createFunctionCursor(t, call, cur, false)
+			} else if interval == 0 {
+				return nil, fmt.Errorf("%s aggregate requires a GROUP BY interval", call.Name)
+			}
+			call = inner
+		}
+
+		c, err := createFunctionCursor(t, call, cur, !gr.selector || interval > 0)
 		if err != nil {
 			return nil, err
 		}
@@ -269,6 +321,15 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 				cursor: cur,
 			}
 		}
+
+		// Apply the transformation to the aggregated series.
+		if transform != nil {
+			c, err := createFunctionCursor(t, transform, cur, false)
+			if err != nil {
+				return nil, err
+			}
+			cur = c
+		}
 	} else {
 		// If we do not have a function, but we have a field option,
 		// return the appropriate error message if there is something wrong with the flux.
@@ -452,6 +513,30 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 	return in, nil
 }
 
+// callArg returns the first argument of the innermost call.
+func callArg(call *influxql.Call) influxql.Expr {
+	if len(call.Args) == 0 {
+		return nil
+	}
+	if inner, ok := call.Args[0].(*influxql.Call); ok {
+		return callArg(inner)
+	}
+	return call.Args[0]
+}
+
+// callRef returns the variable reference of the field the call is applied to,
+// looking through any nested calls.
+func callRef(call *influxql.Call) (*influxql.VarRef, bool) {
+	switch arg := call.Args[0].(type) {
+	case *influxql.VarRef:
+		return arg, true
+	case *influxql.Call:
+		return callRef(arg)
+	default:
+		return nil, false
+	}
+}
+
 // tagsCursor is a pseudo-cursor that can be used to access tags within the cursor.
 type tagsCursor struct {
 	cursor
@@ -0,0 +1,103 @@
+package influxql
+
+import (
+	"context"
+	"errors"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxql"
+)
+
+// SchemaMapper looks up the schema of the measurements stored within a bucket.
+// It is used to expand wildcards and to determine which variables are fields and which are tags.
+type SchemaMapper interface {
+	// FieldDimensions returns the fields of the measurement with their types and the tag keys of the measurement.
+	FieldDimensions(ctx context.Context, orgID, bucketID platform.ID, measurement string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error)
+}
+
+// measurementSchema holds the result of a schema lookup for a measurement.
+type measurementSchema struct {
+	fields     map[string]influxql.DataType
+	dimensions map[string]struct{}
+	err        error
+}
+
+// fieldMapper implements the influxql.FieldMapper and influxql.CallTypeMapper
+// interfaces by consulting the SchemaMapper of the transpiler.
+type fieldMapper struct {
+	ctx context.Context
+	t   *transpilerState
+
+	// err holds the first error encountered while mapping a type.
+	// MapType cannot return an error so it is recorded here instead.
+	err error
+}
+
+func (m *fieldMapper) FieldDimensions(mm *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+	if m.t.schema == nil {
+		return nil, nil, errors.New("unimplemented: wildcard expansion requires a schema mapper")
+	} else if mm.Regex != nil {
+		return nil, nil, errors.New("unimplemented: wildcard expansion for a measurement regex")
+	}
+
+	mapping, err := m.t.mapping(m.ctx, mm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Look up each measurement only once per query.
+	key := mapping.BucketID.String() + "/" + mm.Name
+	s, ok := m.t.schemas[key]
+	if !ok {
+		s = &measurementSchema{}
+		s.fields, s.dimensions, s.err = m.t.schema.FieldDimensions(m.ctx, mapping.OrganizationID, mapping.BucketID, mm.Name)
+		m.t.schemas[key] = s
+	}
+	return s.fields, s.dimensions, s.err
+}
+
+// MapType returns the type of the field within the schemas looked up to expand the wildcards.
+// It never looks up a schema itself so the queries without a wildcard do not read the store
+// and the variables of those queries have an unknown type.
+func (m *fieldMapper) MapType(mm *influxql.Measurement, field string) influxql.DataType {
+	if len(m.t.schemas) == 0 || mm.Regex != nil {
+		return influxql.Unknown
+	}
+
+	mapping, err := m.t.mapping(m.ctx, mm)
+	if err != nil {
+		if m.err == nil {
+			m.err = err
+		}
+		return influxql.Unknown
+	}
+	s, ok := m.t.schemas[mapping.BucketID.String()+"/"+mm.Name]
+	if !ok || s.err != nil {
+		return influxql.Unknown
+	}
+	if typ, ok := s.fields[field]; ok {
+		return typ
+	} else if _, ok := s.dimensions[field]; ok {
+		return influxql.Tag
+	}
+	return influxql.Unknown
+}
+
+func (m *fieldMapper) CallType(name string, args []influxql.DataType) (influxql.DataType, error) {
+	switch name {
+	case "count":
+		return influxql.Integer, nil
+	case "mean", "median", "stddev", "integral", "derivative", "non_negative_derivative",
+		"moving_average", "holt_winters", "holt_winters_with_fit", "exp", "ln", "log", "log2", "log10",
+		"sqrt", "pow", "sin", "cos", "tan", "asin", "acos", "atan", "atan2":
+		return influxql.Float, nil
+	case "elapsed":
+		return influxql.Integer, nil
+	default:
+		// The remaining functions return the type of the field they are applied to.
+		if len(args) == 0 {
+			return influxql.Unknown, nil
+		}
+		return args[0], nil
+	}
+}
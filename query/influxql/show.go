@@ -0,0 +1,440 @@
+package influxql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxql"
+)
+
+func (t *transpilerState) transpileShowMeasurements(ctx context.Context, stmt *influxql.ShowMeasurementsStatement) (ast.Expression, error) {
+	var sources influxql.Sources
+	if stmt.Source != nil {
+		sources = influxql.Sources{stmt.Source}
+	}
+	expr, err := t.showSeries(ctx, stmt.Database, sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the distinct measurement names and report them as the measurements series.
+	expr = pipeCall(expr, "keep", columnsProperty("_measurement"))
+	expr = pipeCall(expr, "group")
+	expr = pipeCall(expr, "distinct", stringProperty("column", "_measurement"))
+	expr = pipeCall(expr, "sort")
+	if expr, err = limitCall(expr, stmt.Limit, stmt.Offset); err != nil {
+		return nil, err
+	}
+	expr = renameCall(expr, "_value", "name")
+	expr = pipeCall(expr, "set",
+		stringProperty("key", "_measurement"),
+		stringProperty("value", "measurements"),
+	)
+	return groupCall(expr, "_measurement"), nil
+}
+
+func (t *transpilerState) transpileShowTagKeys(ctx context.Context, stmt *influxql.ShowTagKeysStatement) (ast.Expression, error) {
+	if stmt.SLimit > 0 || stmt.SOffset > 0 {
+		return nil, errors.New("unimplemented: SLIMIT and SOFFSET")
+	}
+
+	expr, err := t.showSeries(ctx, stmt.Database, stmt.Sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	// The keys of every series are listed with the keys of the table that are not tags.
+	// Find the distinct keys within each measurement and discard the ones that are not tags.
+	expr = pipeCall(expr, "keys")
+	expr = pipeCall(expr, "keep", columnsProperty("_measurement", "_value"))
+	expr = groupCall(expr, "_measurement")
+	expr = pipeCall(expr, "distinct")
+	expr = filterCall(expr, &ast.BinaryExpression{
+		Operator: ast.NotRegexpMatchOperator,
+		Left: &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "r"},
+			Property: &ast.Identifier{Name: "_value"},
+		},
+		Right: &ast.RegexpLiteral{Value: nonTagKeys},
+	})
+	expr = pipeCall(expr, "sort")
+	if expr, err = limitCall(expr, stmt.Limit, stmt.Offset); err != nil {
+		return nil, err
+	}
+	return renameCall(expr, "_value", "tagKey"), nil
+}
+
+func (t *transpilerState) transpileShowFieldKeys(ctx context.Context, stmt *influxql.ShowFieldKeysStatement) (ast.Expression, error) {
+	expr, err := t.showSeries(ctx, stmt.Database, stmt.Sources, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// A field may have a different type in each shard, so its series do not
+	// all have the same type. The type with the highest precedence is listed,
+	// like InfluxDB 1.x and reads.SchemaMapper do.
+	schema := t.requireImport("influxdata/influxdb/v1/schema")
+	expr = schemaCall(expr, schema, "fieldTypes")
+	expr = pipeCall(expr, "keep", columnsProperty("_measurement", "_field", "fieldType"))
+	expr = groupCall(expr, "_measurement")
+	expr = fieldTypeRankCall(expr)
+	expr = pipeCall(expr, "sort", columnsProperty("_field", "rank"))
+	expr = pipeCall(expr, "unique", stringProperty("column", "_field"))
+	expr = pipeCall(expr, "drop", columnsProperty("rank"))
+	expr = renameCall(expr, "_field", "fieldKey")
+	expr = pipeCall(expr, "sort", columnsProperty("fieldKey"))
+	return limitCall(expr, stmt.Limit, stmt.Offset)
+}
+
+func (t *transpilerState) transpileShowSeries(ctx context.Context, stmt *influxql.ShowSeriesStatement) (ast.Expression, error) {
+	expr, err := t.showSeries(ctx, stmt.Database, stmt.Sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	expr = t.seriesKeys(expr)
+	expr = pipeCall(expr, "keep", columnsProperty("key"))
+	expr = pipeCall(expr, "group")
+	expr = pipeCall(expr, "distinct", stringProperty("column", "key"))
+	expr = pipeCall(expr, "sort")
+	if expr, err = limitCall(expr, stmt.Limit, stmt.Offset); err != nil {
+		return nil, err
+	}
+	return renameCall(expr, "_value", "key"), nil
+}
+
+func (t *transpilerState) transpileShowMeasurementCardinality(ctx context.Context, stmt *influxql.ShowMeasurementCardinalityStatement) (ast.Expression, error) {
+	if err := validateCardinality(stmt.Dimensions, stmt.Limit, stmt.Offset); err != nil {
+		return nil, err
+	}
+
+	expr, err := t.showSeries(ctx, stmt.Database, stmt.Sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	expr = pipeCall(expr, "keep", columnsProperty("_measurement"))
+	expr = pipeCall(expr, "group")
+	expr = pipeCall(expr, "distinct", stringProperty("column", "_measurement"))
+	expr = pipeCall(expr, "count")
+	return renameCall(expr, "_value", "count"), nil
+}
+
+func (t *transpilerState) transpileShowSeriesCardinality(ctx context.Context, stmt *influxql.ShowSeriesCardinalityStatement) (ast.Expression, error) {
+	if err := validateCardinality(stmt.Dimensions, stmt.Limit, stmt.Offset); err != nil {
+		return nil, err
+	}
+
+	expr, err := t.showSeries(ctx, stmt.Database, stmt.Sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	// The exact cardinality is reported for each measurement while the estimated
+	// cardinality is the total for the database.
+	expr = t.seriesKeys(expr)
+	expr = pipeCall(expr, "keep", columnsProperty("_measurement", "key"))
+	if stmt.Exact {
+		expr = groupCall(expr, "_measurement")
+	} else {
+		expr = pipeCall(expr, "group")
+	}
+	expr = pipeCall(expr, "distinct", stringProperty("column", "key"))
+	expr = pipeCall(expr, "count")
+	return renameCall(expr, "_value", "count"), nil
+}
+
+// nonTagKeys matches the columns of the group key of a series that are not tags.
+var nonTagKeys = regexp.MustCompile(`^_(start|stop|measurement|field)$`)
+
+// validateCardinality returns an error for the options of the cardinality statements
+// that are not supported.
+func validateCardinality(dimensions influxql.Dimensions, limit, offset int) error {
+	if len(dimensions) > 0 {
+		return errors.New("unimplemented: GROUP BY in cardinality statements")
+	} else if limit > 0 || offset > 0 {
+		return errors.New("unimplemented: LIMIT and OFFSET in cardinality statements")
+	}
+	return nil
+}
+
+// showSeries returns the series of the database that match the sources and the condition
+// of a SHOW statement. When the condition does not restrict the time, the series that were
+// written within the last hour are returned.
+func (t *transpilerState) showSeries(ctx context.Context, database string, sources influxql.Sources, condition influxql.Expr) (ast.Expression, error) {
+	if database == "" {
+		if t.config.DefaultDatabase == "" {
+			return nil, errDatabaseNameRequired
+		}
+		database = t.config.DefaultDatabase
+	}
+
+	expr, err := t.from(ctx, &influxql.Measurement{Database: database})
+	if err != nil {
+		return nil, err
+	}
+
+	valuer := influxql.NowValuer{Now: t.config.Now}
+	cond, tr, err := influxql.ConditionExpr(condition, &valuer)
+	if err != nil {
+		return nil, err
+	}
+	expr = pipeCall(expr, "range", showRange(tr)...)
+
+	pred, err := t.showFilter(sources, cond)
+	if err != nil {
+		return nil, err
+	} else if pred != nil {
+		expr = filterCall(expr, pred)
+	}
+	return expr, nil
+}
+
+// showRange returns the arguments to range for the time range of a SHOW statement.
+func showRange(tr influxql.TimeRange) []*ast.Property {
+	if tr.IsZero() {
+		return []*ast.Property{{
+			Key: &ast.Identifier{Name: "start"},
+			Value: &ast.DurationLiteral{
+				Values: []ast.Duration{{
+					Magnitude: -1,
+					Unit:      "h",
+				}},
+			},
+		}}
+	}
+	return []*ast.Property{
+		{
+			Key:   &ast.Identifier{Name: "start"},
+			Value: &ast.DateTimeLiteral{Value: tr.MinTime().UTC()},
+		},
+		{
+			Key:   &ast.Identifier{Name: "stop"},
+			Value: &ast.DateTimeLiteral{Value: tr.MaxTime().UTC()},
+		},
+	}
+}
+
+// showFilter returns the predicate that matches the measurements of the sources and the
+// tags of the condition of a SHOW statement. It returns nil if the statement matches every series.
+func (t *transpilerState) showFilter(sources influxql.Sources, cond influxql.Expr) (ast.Expression, error) {
+	var pred ast.Expression
+	for i := len(sources) - 1; i >= 0; i-- {
+		mm, ok := sources[i].(*influxql.Measurement)
+		if !ok {
+			return nil, fmt.Errorf("unsupported source type: %T", sources[i])
+		}
+
+		name := &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "r"},
+			Property: &ast.Identifier{Name: "_measurement"},
+		}
+		var expr ast.Expression
+		if mm.Regex != nil {
+			expr = &ast.BinaryExpression{
+				Operator: ast.RegexpMatchOperator,
+				Left:     name,
+				Right:    &ast.RegexpLiteral{Value: mm.Regex.Val},
+			}
+		} else {
+			expr = &ast.BinaryExpression{
+				Operator: ast.EqualOperator,
+				Left:     name,
+				Right:    &ast.StringLiteral{Value: mm.Name},
+			}
+		}
+
+		if pred != nil {
+			expr = &ast.LogicalExpression{
+				Operator: ast.OrOperator,
+				Left:     expr,
+				Right:    pred,
+			}
+		}
+		pred = expr
+	}
+
+	if cond != nil {
+		expr, err := t.mapField(cond, metaCursor{})
+		if err != nil {
+			return nil, err
+		}
+		if pred != nil {
+			expr = &ast.LogicalExpression{
+				Operator: ast.AndOperator,
+				Left:     pred,
+				Right:    expr,
+			}
+		}
+		pred = expr
+	}
+	return pred, nil
+}
+
+// fieldTypePrecedence lists the names of the field types from the highest precedence.
+var fieldTypePrecedence = []string{"float", "integer", "unsigned", "string", "boolean"}
+
+// fieldTypeRankCall pipes the expression into a map adding the rank column with the index
+// of the fieldType column within fieldTypePrecedence.
+func fieldTypeRankCall(expr ast.Expression) ast.Expression {
+	column := func(name string) ast.Expression {
+		return &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "r"},
+			Property: &ast.Identifier{Name: name},
+		}
+	}
+
+	last := len(fieldTypePrecedence) - 1
+	var rank ast.Expression = &ast.IntegerLiteral{Value: int64(last)}
+	for i := last - 1; i >= 0; i-- {
+		rank = &ast.ConditionalExpression{
+			Test: &ast.BinaryExpression{
+				Operator: ast.EqualOperator,
+				Left:     column("fieldType"),
+				Right:    &ast.StringLiteral{Value: fieldTypePrecedence[i]},
+			},
+			Consequent: &ast.IntegerLiteral{Value: int64(i)},
+			Alternate:  rank,
+		}
+	}
+
+	properties := make([]*ast.Property, 0, 4)
+	for _, name := range []string{"_measurement", "_field", "fieldType"} {
+		properties = append(properties, &ast.Property{
+			Key:   &ast.Identifier{Name: name},
+			Value: column(name),
+		})
+	}
+	properties = append(properties, &ast.Property{
+		Key:   &ast.Identifier{Name: "rank"},
+		Value: rank,
+	})
+	return pipeCall(expr, "map", &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: &ast.ObjectExpression{
+				Properties: properties,
+			},
+		},
+	})
+}
+
+// seriesKeys describes each series with a single row with the series key in the key column.
+func (t *transpilerState) seriesKeys(expr ast.Expression) ast.Expression {
+	schema := t.requireImport("influxdata/influxdb/v1/schema")
+	return schemaCall(expr, schema, "seriesKeys")
+}
+
+// metaCursor resolves the variables within the condition of a SHOW statement.
+// Every variable is a tag except for _name, which is the measurement name.
+type metaCursor struct{}
+
+func (metaCursor) Expr() ast.Expression  { return nil }
+func (metaCursor) Keys() []influxql.Expr { return nil }
+
+func (metaCursor) Value(expr influxql.Expr) (string, bool) {
+	ref, ok := expr.(*influxql.VarRef)
+	if !ok {
+		return "", false
+	}
+	if ref.Val == "_name" {
+		return "_measurement", true
+	}
+	return ref.Val, true
+}
+
+// limitCall pipes the expression into a call to limit when there is a limit or an offset.
+func limitCall(expr ast.Expression, limit, offset int) (ast.Expression, error) {
+	if limit == 0 {
+		if offset > 0 {
+			return nil, errors.New("unimplemented: OFFSET without LIMIT")
+		}
+		return expr, nil
+	}
+
+	properties := []*ast.Property{{
+		Key:   &ast.Identifier{Name: "n"},
+		Value: &ast.IntegerLiteral{Value: int64(limit)},
+	}}
+	if offset > 0 {
+		properties = append(properties, &ast.Property{
+			Key:   &ast.Identifier{Name: "offset"},
+			Value: &ast.IntegerLiteral{Value: int64(offset)},
+		})
+	}
+	return pipeCall(expr, "limit", properties...), nil
+}
+
+// filterCall pipes the expression into a filter with the predicate as the body of its function.
+func filterCall(expr, pred ast.Expression) ast.Expression {
+	return pipeCall(expr, "filter", &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: pred,
+		},
+	})
+}
+
+// groupCall pipes the expression into a group by the columns.
+func groupCall(expr ast.Expression, columns ...string) ast.Expression {
+	return pipeCall(expr, "group",
+		columnsProperty(columns...),
+		stringProperty("mode", "by"),
+	)
+}
+
+// renameCall pipes the expression into a rename of a single column.
+func renameCall(expr ast.Expression, from, to string) ast.Expression {
+	return pipeCall(expr, "rename", &ast.Property{
+		Key: &ast.Identifier{Name: "columns"},
+		Value: &ast.ObjectExpression{
+			Properties: []*ast.Property{
+				stringProperty(from, to),
+			},
+		},
+	})
+}
+
+// schemaCall pipes the expression into a function of the influxdata/influxdb/v1/schema package.
+func schemaCall(expr ast.Expression, schema *ast.Identifier, name string) ast.Expression {
+	return &ast.PipeExpression{
+		Argument: expr,
+		Call: &ast.CallExpression{
+			Callee: &ast.MemberExpression{
+				Object:   schema,
+				Property: &ast.Identifier{Name: name},
+			},
+		},
+	}
+}
+
+// columnsProperty returns the columns argument with the list of column names.
+func columnsProperty(columns ...string) *ast.Property {
+	elements := make([]ast.Expression, 0, len(columns))
+	for _, name := range columns {
+		elements = append(elements, &ast.StringLiteral{Value: name})
+	}
+	return &ast.Property{
+		Key: &ast.Identifier{Name: "columns"},
+		Value: &ast.ArrayExpression{
+			Elements: elements,
+		},
+	}
+}
+
+// stringProperty returns an argument with a string value.
+func stringProperty(key, value string) *ast.Property {
+	return &ast.Property{
+		Key:   &ast.Identifier{Name: key},
+		Value: &ast.StringLiteral{Value: value},
+	}
+}
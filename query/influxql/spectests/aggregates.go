@@ -8,7 +8,11 @@ import (
 
 var aggregateFuncNames = []string{
 	"count",
+	"distinct",
 	"mean",
+	"mode",
+	"spread",
+	"stddev",
 	"sum",
 }
 
@@ -0,0 +1,55 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW MEASUREMENT CARDINALITY ON "db0"`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> keep(columns: ["_measurement"])
+	|> group()
+	|> distinct(column: "_measurement")
+	|> count()
+	|> rename(columns: {_value: "count"})
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SHOW SERIES CARDINALITY ON "db0" FROM "cpu"`,
+			`package main
+
+import schema "influxdata/influxdb/v1/schema"
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> schema.seriesKeys()
+	|> keep(columns: ["_measurement", "key"])
+	|> group()
+	|> distinct(column: "key")
+	|> count()
+	|> rename(columns: {_value: "count"})
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SHOW SERIES EXACT CARDINALITY ON "db0"`,
+			`package main
+
+import schema "influxdata/influxdb/v1/schema"
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> schema.seriesKeys()
+	|> keep(columns: ["_measurement", "key"])
+	|> group(columns: ["_measurement"], mode: "by")
+	|> distinct(column: "key")
+	|> count()
+	|> rename(columns: {_value: "count"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,27 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW FIELD KEYS ON "db0" FROM "cpu"`,
+			`package main
+
+import schema "influxdata/influxdb/v1/schema"
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> schema.fieldTypes()
+	|> keep(columns: ["_measurement", "_field", "fieldType"])
+	|> group(columns: ["_measurement"], mode: "by")
+	|> map(fn: (r) => ({_measurement: r._measurement, _field: r._field, fieldType: r.fieldType, rank: if r.fieldType == "float" then 0 else if r.fieldType == "integer" then 1 else if r.fieldType == "unsigned" then 2 else if r.fieldType == "string" then 3 else 4}))
+	|> sort(columns: ["_field", "rank"])
+	|> unique(column: "_field")
+	|> drop(columns: ["rank"])
+	|> rename(columns: {_field: "fieldKey"})
+	|> sort(columns: ["fieldKey"])
+	|> yield(name: "0")
+`,
+		),
+	)
+}
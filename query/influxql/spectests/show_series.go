@@ -0,0 +1,25 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW SERIES ON "db0" FROM /c.*/ WHERE "host" = 'server01' AND time > now() - 10m LIMIT 100`,
+			`package main
+
+import schema "influxdata/influxdb/v1/schema"
+
+from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00.000000001Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement =~ /c.*/ and r["host"] == "server01")
+	|> schema.seriesKeys()
+	|> keep(columns: ["key"])
+	|> group()
+	|> distinct(column: "key")
+	|> sort()
+	|> limit(n: 100)
+	|> rename(columns: {_value: "key"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
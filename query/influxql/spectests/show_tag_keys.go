@@ -0,0 +1,24 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW TAG KEYS ON "db0" FROM "cpu", "mem" WHERE "region" = 'west' LIMIT 2 OFFSET 1`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => (r._measurement == "cpu" or r._measurement == "mem") and r["region"] == "west")
+	|> keys()
+	|> keep(columns: ["_measurement", "_value"])
+	|> group(columns: ["_measurement"], mode: "by")
+	|> distinct()
+	|> filter(fn: (r) => r._value !~ /^_(start|stop|measurement|field)$/)
+	|> sort()
+	|> limit(n: 2, offset: 1)
+	|> rename(columns: {_value: "tagKey"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
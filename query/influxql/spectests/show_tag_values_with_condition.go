@@ -0,0 +1,22 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW TAG VALUES ON "db0" FROM /cpu.*/ WITH KEY = "host" WHERE "region" = 'west' LIMIT 5`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement =~ /cpu.*/ and r["region"] == "west")
+	|> keyValues(keyColumns: ["host"])
+	|> group(columns: ["_measurement", "_key"], mode: "by")
+	|> distinct()
+	|> group(columns: ["_measurement"], mode: "by")
+	|> rename(columns: {_key: "key", _value: "value"})
+	|> limit(n: 5)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
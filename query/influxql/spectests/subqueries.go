@@ -0,0 +1,95 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT max(mean) FROM (SELECT mean(value) FROM db0..cpu GROUP BY host)`,
+			`package main
+
+t0 = from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start", "host"], mode: "by")
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> map(fn: (r) => ({_time: r._time, mean: r._value}), mergeKey: true)
+t0
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> max(column: "mean")
+	|> map(fn: (r) => ({_time: r._time, max: r["mean"]}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT mean(max) FROM (SELECT max(value) FROM db0..cpu GROUP BY time(1m), host) WHERE time >= now() - 10m GROUP BY time(5m)`,
+			`package main
+
+t0 = from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start", "host"], mode: "by")
+	|> window(every: 1m)
+	|> max()
+	|> drop(columns: ["_time"])
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, max: r._value}), mergeKey: true)
+t0
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 5m)
+	|> mean(column: "max")
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, mean: r["max"]}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT max(mean) FROM (SELECT mean(value) FROM (SELECT value FROM db0..cpu WHERE host = 'server01'))`,
+			`package main
+
+t0 = from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> filter(fn: (r) => r["host"] == "server01")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> map(fn: (r) => ({_time: r._time, value: r._value}), mergeKey: true)
+t1 = t0
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> mean(column: "value")
+	|> duplicate(column: "_start", as: "_time")
+	|> map(fn: (r) => ({_time: r._time, mean: r["value"]}), mergeKey: true)
+t1
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> max(column: "mean")
+	|> map(fn: (r) => ({_time: r._time, max: r["mean"]}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT max(derivative) FROM (SELECT derivative(mean(value)) FROM db0..cpu) WHERE time >= now() - 10m GROUP BY time(5m)`,
+			`package main
+
+t0 = from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 5m)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> derivative(unit: 5m)
+	|> map(fn: (r) => ({_time: r._time, derivative: r._value}), mergeKey: true)
+t0
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 5m)
+	|> max(column: "derivative")
+	|> drop(columns: ["_time"])
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, max: r["derivative"]}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
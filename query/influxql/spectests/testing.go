@@ -14,6 +14,7 @@ import (
 	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/query/influxql"
 	platformtesting "github.com/influxdata/influxdb/testing"
+	ql "github.com/influxdata/influxql"
 )
 
 var dbrpMappingSvc = mock.NewDBRPMappingService()
@@ -59,6 +60,22 @@ func init() {
 	}
 }
 
+// schemaMapper returns the same schema for every measurement.
+type schemaMapper struct{}
+
+func (schemaMapper) FieldDimensions(ctx context.Context, orgID, bucketID platform.ID, measurement string) (map[string]ql.DataType, map[string]struct{}, error) {
+	fields := map[string]ql.DataType{
+		"value": ql.Float,
+		"total": ql.Float,
+		"state": ql.String,
+	}
+	dimensions := map[string]struct{}{
+		"host":   {},
+		"region": {},
+	}
+	return fields, dimensions, nil
+}
+
 // Fixture is a structure that will run tests.
 type Fixture interface {
 	Run(t *testing.T)
@@ -102,6 +119,7 @@ func (f *fixture) Run(t *testing.T) {
 				Now:             Now(),
 			},
 		)
+		transpiler.WithSchemaMapper(schemaMapper{})
 		pkg, err := transpiler.Transpile(context.Background(), f.stmt)
 		if err != nil {
 			t.Fatalf("%s:%d: unexpected error: %s", f.file, f.line, err)
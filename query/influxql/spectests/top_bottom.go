@@ -0,0 +1,56 @@
+package spectests
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+)
+
+func TopBottomTest(fn func(name string) (stmt, want string)) Fixture {
+	_, file, line, _ := runtime.Caller(1)
+	fixture := &collection{
+		file: filepath.Base(file),
+		line: line,
+	}
+
+	for _, name := range []string{"top", "bottom"} {
+		stmt, want := fn(name)
+		fixture.Add(stmt, want)
+	}
+	return fixture
+}
+
+func init() {
+	RegisterFixture(
+		TopBottomTest(func(name string) (stmt, want string) {
+			return fmt.Sprintf(`SELECT %s(value, 3) FROM db0..cpu`, name),
+				`package main
+
+` + fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String()) + `
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> ` + name + `(n: 3)
+	|> sort(columns: ["_time"])
+	|> map(fn: (r) => ({_time: r._time, ` + name + `: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`
+		}),
+		TopBottomTest(func(name string) (stmt, want string) {
+			return fmt.Sprintf(`SELECT %s(value, 3) FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(5m)`, name),
+				`package main
+
+` + fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String()) + `
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 5m)
+	|> ` + name + `(n: 3)
+	|> sort(columns: ["_time"])
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, ` + name + `: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`
+		}),
+	)
+}
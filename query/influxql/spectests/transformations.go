@@ -0,0 +1,125 @@
+package spectests
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+)
+
+// dropNulls is the filter that follows the non-negative transformations.
+const dropNulls = `
+	|> filter(fn: (r) => exists r._value)`
+
+// transformationFuncs are the functions that transform each point of a series
+// and the flux call they are expected to be transpiled to, both when applied to
+// the raw points and when applied to the mean of 1m windows.
+var transformationFuncs = []struct {
+	stmt, raw, windowed string
+}{
+	{stmt: "derivative(%s)", raw: "derivative(unit: 1s)", windowed: "derivative(unit: 1m)"},
+	{stmt: "derivative(%s, 10s)", raw: "derivative(unit: 10s)", windowed: "derivative(unit: 10s)"},
+	{stmt: "non_negative_derivative(%s)", raw: "derivative(unit: 1s, nonNegative: true)" + dropNulls, windowed: "derivative(unit: 1m, nonNegative: true)" + dropNulls},
+	{stmt: "difference(%s)", raw: "difference()", windowed: "difference()"},
+	{stmt: "non_negative_difference(%s)", raw: "difference(nonNegative: true)" + dropNulls, windowed: "difference(nonNegative: true)" + dropNulls},
+	{stmt: "moving_average(%s, 3)", raw: "movingAverage(n: 3)", windowed: "movingAverage(n: 3)"},
+	{stmt: "cumulative_sum(%s)", raw: "cumulativeSum()", windowed: "cumulativeSum()"},
+}
+
+func TransformationTest(fn func(stmt, call string) (string, string)) Fixture {
+	_, file, line, _ := runtime.Caller(1)
+	fixture := &collection{
+		file: filepath.Base(file),
+		line: line,
+	}
+
+	for _, f := range transformationFuncs {
+		stmt, want := fn(f.stmt, f.raw)
+		fixture.Add(stmt, want)
+	}
+	return fixture
+}
+
+func WindowedTransformationTest(fn func(stmt, call string) (string, string)) Fixture {
+	_, file, line, _ := runtime.Caller(1)
+	fixture := &collection{
+		file: filepath.Base(file),
+		line: line,
+	}
+
+	for _, f := range transformationFuncs {
+		stmt, want := fn(f.stmt, f.windowed)
+		fixture.Add(stmt, want)
+	}
+	return fixture
+}
+
+// columnName returns the name of the column of a transformation statement.
+func columnName(stmt string) string {
+	for i, c := range stmt {
+		if c == '(' {
+			return stmt[:i]
+		}
+	}
+	return stmt
+}
+
+func init() {
+	RegisterFixture(
+		TransformationTest(func(stmt, call string) (string, string) {
+			return fmt.Sprintf(`SELECT `+stmt+` FROM db0..cpu`, "value"),
+				`package main
+
+` + fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String()) + `
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> ` + call + `
+	|> map(fn: (r) => ({_time: r._time, ` + columnName(stmt) + `: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`
+		}),
+		WindowedTransformationTest(func(stmt, call string) (string, string) {
+			return fmt.Sprintf(`SELECT `+stmt+` FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(1m)`, "mean(value)"),
+				`package main
+
+` + fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String()) + `
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> ` + call + `
+	|> map(fn: (r) => ({_time: r._time, ` + columnName(stmt) + `: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`
+		}),
+		NewFixture(
+			`SELECT elapsed(value, 1s) FROM db0..cpu`,
+			`package main
+
+`+fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String())+`
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> elapsed(unit: 1s)
+	|> map(fn: (r) => ({_time: r._time, elapsed: r["elapsed"]}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT elapsed(value) FROM db0..cpu`,
+			`package main
+
+`+fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String())+`
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> elapsed(unit: 1ns)
+	|> map(fn: (r) => ({_time: r._time, elapsed: r["elapsed"]}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -18,6 +18,7 @@ import (
 type Transpiler struct {
 	Config         *Config
 	dbrpMappingSvc platform.DBRPMappingService
+	schemaMapper   SchemaMapper
 }
 
 func NewTranspiler(dbrpMappingSvc platform.DBRPMappingService) *Transpiler {
@@ -31,6 +32,12 @@ func NewTranspilerWithConfig(dbrpMappingSvc platform.DBRPMappingService, cfg Con
 	}
 }
 
+// WithSchemaMapper sets the SchemaMapper used to look up the fields and tags of measurements.
+// Wildcards cannot be expanded without one.
+func (t *Transpiler) WithSchemaMapper(m SchemaMapper) {
+	t.schemaMapper = m
+}
+
 func (t *Transpiler) Transpile(ctx context.Context, txt string) (*ast.Package, error) {
 	// Parse the text of the query.
 	q, err := influxql.ParseQuery(txt)
@@ -38,7 +45,7 @@ func (t *Transpiler) Transpile(ctx context.Context, txt string) (*ast.Package, e
 		return nil, err
 	}
 
-	transpiler := newTranspilerState(t.dbrpMappingSvc, t.Config)
+	transpiler := newTranspilerState(t.dbrpMappingSvc, t.schemaMapper, t.Config)
 	for i, s := range q.Statements {
 		if err := transpiler.Transpile(ctx, i, s); err != nil {
 			return nil, err
@@ -58,9 +65,17 @@ type transpilerState struct {
 	file           *ast.File
 	assignments    map[string]ast.Expression
 	dbrpMappingSvc platform.DBRPMappingService
+	schema         SchemaMapper
+	schemas        map[string]*measurementSchema
+	typmap         *fieldMapper
+
+	// parent is the state of the enclosing statement when transpiling a subquery.
+	parent *transpilerState
+	// subqueries holds the cursors created for the subqueries of the statement.
+	subqueries map[*influxql.SubQuery]*subqueryCursor
 }
 
-func newTranspilerState(dbrpMappingSvc platform.DBRPMappingService, config *Config) *transpilerState {
+func newTranspilerState(dbrpMappingSvc platform.DBRPMappingService, schema SchemaMapper, config *Config) *transpilerState {
 	state := &transpilerState{
 		file: &ast.File{
 			Package: &ast.PackageClause{
@@ -71,6 +86,8 @@ func newTranspilerState(dbrpMappingSvc platform.DBRPMappingService, config *Conf
 		},
 		assignments:    make(map[string]ast.Expression),
 		dbrpMappingSvc: dbrpMappingSvc,
+		schema:         schema,
+		schemas:        make(map[string]*measurementSchema),
 	}
 	if config != nil {
 		state.config = *config
@@ -124,6 +141,18 @@ func (t *transpilerState) transpile(ctx context.Context, s influxql.Statement) (
 		return t.transpileShowDatabases(ctx, stmt)
 	case *influxql.ShowRetentionPoliciesStatement:
 		return t.transpileShowRetentionPolicies(ctx, stmt)
+	case *influxql.ShowMeasurementsStatement:
+		return t.transpileShowMeasurements(ctx, stmt)
+	case *influxql.ShowTagKeysStatement:
+		return t.transpileShowTagKeys(ctx, stmt)
+	case *influxql.ShowFieldKeysStatement:
+		return t.transpileShowFieldKeys(ctx, stmt)
+	case *influxql.ShowSeriesStatement:
+		return t.transpileShowSeries(ctx, stmt)
+	case *influxql.ShowMeasurementCardinalityStatement:
+		return t.transpileShowMeasurementCardinality(ctx, stmt)
+	case *influxql.ShowSeriesCardinalityStatement:
+		return t.transpileShowSeriesCardinality(ctx, stmt)
 	default:
 		return nil, fmt.Errorf("unknown statement type %T", s)
 	}
@@ -134,109 +163,11 @@ func (t *transpilerState) transpileShowTagValues(ctx context.Context, stmt *infl
 	// not actually contain the database and we do not factor in retention policies. So we are always going to use
 	// the default retention policy when evaluating which bucket we are querying and we do not have to consult
 	// the sources in the statement.
-	if stmt.Database == "" {
-		if t.config.DefaultDatabase == "" {
-			return nil, errDatabaseNameRequired
-		}
-		stmt.Database = t.config.DefaultDatabase
-	}
-
-	expr, err := t.from(&influxql.Measurement{Database: stmt.Database})
+	expr, err := t.showSeries(ctx, stmt.Database, stmt.Sources, stmt.Condition)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO(jsternberg): Read the range from the condition expression. 1.x doesn't actually do this so it isn't
-	// urgent to implement this functionality so we can use the default range.
-	expr = &ast.PipeExpression{
-		Argument: expr,
-		Call: &ast.CallExpression{
-			Callee: &ast.Identifier{
-				Name: "range",
-			},
-			Arguments: []ast.Expression{
-				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "start",
-							},
-							Value: &ast.DurationLiteral{
-								Values: []ast.Duration{{
-									Magnitude: -1,
-									Unit:      "h",
-								}},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	// If we have a list of sources, look through it and add each of the measurement names.
-	measurementNames := make([]string, 0, len(stmt.Sources))
-	for _, source := range stmt.Sources {
-		mm := source.(*influxql.Measurement)
-		measurementNames = append(measurementNames, mm.Name)
-	}
-
-	if len(measurementNames) > 0 {
-		var filterExpr ast.Expression = &ast.BinaryExpression{
-			Operator: ast.EqualOperator,
-			Left: &ast.MemberExpression{
-				Object:   &ast.Identifier{Name: "r"},
-				Property: &ast.Identifier{Name: "_measurement"},
-			},
-			Right: &ast.StringLiteral{
-				Value: measurementNames[len(measurementNames)-1],
-			},
-		}
-		for i := len(measurementNames) - 2; i >= 0; i-- {
-			filterExpr = &ast.LogicalExpression{
-				Operator: ast.OrOperator,
-				Left: &ast.BinaryExpression{
-					Operator: ast.EqualOperator,
-					Left: &ast.MemberExpression{
-						Object:   &ast.Identifier{Name: "r"},
-						Property: &ast.Identifier{Name: "_measurement"},
-					},
-					Right: &ast.StringLiteral{
-						Value: measurementNames[i],
-					},
-				},
-				Right: filterExpr,
-			}
-		}
-		expr = &ast.PipeExpression{
-			Argument: expr,
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{
-					Name: "filter",
-				},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: []*ast.Property{
-							{
-								Key: &ast.Identifier{Name: "fn"},
-								Value: &ast.FunctionExpression{
-									Params: []*ast.Property{
-										{
-											Key: &ast.Identifier{Name: "r"},
-										},
-									},
-									Body: filterExpr,
-								},
-							},
-						},
-					},
-				},
-			},
-		}
-	}
-
-	// TODO(jsternberg): Add the condition filter for the where clause.
-
 	// Create the key values op spec from the
 	var keyColumns []ast.Expression
 	switch expr := stmt.TagKeyExpr.(type) {
@@ -287,8 +218,9 @@ func (t *transpilerState) transpileShowTagValues(ctx context.Context, stmt *infl
 	}
 
 	// Group by the measurement and key, find distinct values, then group by the measurement
-	// to join all of the different keys together. Finish by renaming the columns. This is static.
-	return &ast.PipeExpression{
+	// to join all of the different keys together. Finish by renaming the columns and limiting the
+	// number of values within each measurement.
+	expr = &ast.PipeExpression{
 		Argument: &ast.PipeExpression{
 			Argument: &ast.PipeExpression{
 				Argument: &ast.PipeExpression{
@@ -388,7 +320,8 @@ func (t *transpilerState) transpileShowTagValues(ctx context.Context, stmt *infl
 				},
 			},
 		},
-	}, nil
+	}
+	return limitCall(expr, stmt.Limit, stmt.Offset)
 }
 
 func (t *transpilerState) transpileShowDatabases(ctx context.Context, stmt *influxql.ShowDatabasesStatement) (ast.Expression, error) {
@@ -618,6 +551,30 @@ func (t *transpilerState) transpileSelect(ctx context.Context, stmt *influxql.Se
 	// Clone the select statement and omit the time from the list of column names.
 	t.stmt = stmt.Clone()
 	t.stmt.OmitTime = true
+	t.typmap = &fieldMapper{ctx: ctx, t: t}
+	t.subqueries = make(map[*influxql.SubQuery]*subqueryCursor)
+
+	// Expand the wildcards using the schema of the sources.
+	if hasWildcard(t.stmt) {
+		if err := validateWildcardFunctions(t.stmt); err != nil {
+			return nil, err
+		}
+
+		stmt, err := t.stmt.RewriteFields(t.typmap)
+		if err != nil {
+			return nil, err
+		} else if t.typmap.err != nil {
+			return nil, t.typmap.err
+		}
+		t.stmt = stmt
+	}
+
+	// Treat SELECT DISTINCT value the same as SELECT distinct(value).
+	for _, f := range t.stmt.Fields {
+		if d, ok := f.Expr.(*influxql.Distinct); ok {
+			f.Expr = d.NewCall()
+		}
+	}
 
 	groups, err := identifyGroups(t.stmt)
 	if err != nil {
@@ -628,13 +585,18 @@ func (t *transpilerState) transpileSelect(ctx context.Context, stmt *influxql.Se
 
 	cursors := make([]cursor, 0, len(groups))
 	for _, gr := range groups {
-		cur, err := gr.createCursor(t)
+		cur, err := gr.createCursor(ctx, t)
 		if err != nil {
 			return nil, err
 		}
 		cursors = append(cursors, cur)
 	}
 
+	// Report any error from looking up the schema while the types of the variables were mapped.
+	if t.typmap.err != nil {
+		return nil, t.typmap.err
+	}
+
 	// Join the cursors together on the measurement name.
 	// TODO(jsternberg): This needs to join on all remaining group keys.
 	cur := Join(t, cursors, []string{"_time", "_measurement"})
@@ -647,36 +609,72 @@ func (t *transpilerState) transpileSelect(ctx context.Context, stmt *influxql.Se
 	return cur, nil
 }
 
-func (t *transpilerState) mapType(ref *influxql.VarRef) influxql.DataType {
-	// TODO(jsternberg): Actually evaluate the type against the schema.
+// typeOf returns the type of a variable from the schema of the sources.
+// It returns unknown if the type of the variable could not be determined.
+func (t *transpilerState) typeOf(ref *influxql.VarRef) influxql.DataType {
+	if ref.Type != influxql.Unknown {
+		return ref.Type
+	}
+	return influxql.EvalType(ref, t.stmt.Sources, t.typmap)
+}
+
+// mapType returns the type of a variable in the condition. A variable that could
+// not be found in the schema is assumed to be a tag, unless the condition compares
+// it to a literal that is not a string as the values of the tags are strings.
+func (t *transpilerState) mapType(ref *influxql.VarRef, cond influxql.Expr) influxql.DataType {
+	if typ := t.typeOf(ref); typ != influxql.Unknown {
+		return typ
+	} else if typ := literalType(ref, cond); typ != influxql.Unknown {
+		return typ
+	}
 	return influxql.Tag
 }
 
-func (t *transpilerState) from(m *influxql.Measurement) (ast.Expression, error) {
-	db, rp := m.Database, m.RetentionPolicy
-	if db == "" {
-		if t.config.DefaultDatabase == "" {
-			return nil, errors.New("database is required")
+// literalType returns the type of the literal the variable is compared to in the condition.
+// It returns unknown if the variable is not compared to a literal that is not a string.
+func literalType(ref *influxql.VarRef, cond influxql.Expr) influxql.DataType {
+	typ := influxql.Unknown
+	influxql.WalkFunc(cond, func(node influxql.Node) {
+		expr, ok := node.(*influxql.BinaryExpr)
+		if !ok || typ != influxql.Unknown {
+			return
 		}
-		db = t.config.DefaultDatabase
-	}
-	if rp == "" {
-		if t.config.DefaultRetentionPolicy != "" {
-			rp = t.config.DefaultRetentionPolicy
+		lhs, rhs := expr.LHS, expr.RHS
+		if r, ok := rhs.(*influxql.VarRef); ok && r.Val == ref.Val {
+			lhs, rhs = rhs, lhs
 		}
-	}
+		if l, ok := lhs.(*influxql.VarRef); !ok || l.Val != ref.Val {
+			return
+		}
+		switch rhs.(type) {
+		case *influxql.NumberLiteral:
+			typ = influxql.Float
+		case *influxql.IntegerLiteral:
+			typ = influxql.Integer
+		case *influxql.UnsignedLiteral:
+			typ = influxql.Unsigned
+		case *influxql.BooleanLiteral:
+			typ = influxql.Boolean
+		}
+	})
+	return typ
+}
 
-	var filter platform.DBRPMappingFilter
-	filter.Cluster = &t.config.Cluster
-	if db != "" {
-		filter.Database = &db
+// hasWildcard returns true if the statement or any of its subqueries select a wildcard.
+func hasWildcard(stmt *influxql.SelectStatement) bool {
+	if stmt.HasFieldWildcard() || stmt.HasDimensionWildcard() {
+		return true
 	}
-	if rp != "" {
-		filter.RetentionPolicy = &rp
+	for _, src := range stmt.Sources {
+		if sub, ok := src.(*influxql.SubQuery); ok && hasWildcard(sub.Statement) {
+			return true
+		}
 	}
-	defaultRP := rp == ""
-	filter.Default = &defaultRP
-	mapping, err := t.dbrpMappingSvc.Find(context.TODO(), filter)
+	return false
+}
+
+func (t *transpilerState) from(ctx context.Context, m *influxql.Measurement) (ast.Expression, error) {
+	mapping, err := t.mapping(ctx, m)
 	if err != nil {
 		return nil, err
 	}
@@ -702,6 +700,34 @@ func (t *transpilerState) from(m *influxql.Measurement) (ast.Expression, error)
 	}, nil
 }
 
+// mapping finds the bucket for the database and retention policy of the measurement.
+func (t *transpilerState) mapping(ctx context.Context, m *influxql.Measurement) (*platform.DBRPMapping, error) {
+	db, rp := m.Database, m.RetentionPolicy
+	if db == "" {
+		if t.config.DefaultDatabase == "" {
+			return nil, errors.New("database is required")
+		}
+		db = t.config.DefaultDatabase
+	}
+	if rp == "" {
+		if t.config.DefaultRetentionPolicy != "" {
+			rp = t.config.DefaultRetentionPolicy
+		}
+	}
+
+	var filter platform.DBRPMappingFilter
+	filter.Cluster = &t.config.Cluster
+	if db != "" {
+		filter.Database = &db
+	}
+	if rp != "" {
+		filter.RetentionPolicy = &rp
+	}
+	defaultRP := rp == ""
+	filter.Default = &defaultRP
+	return t.dbrpMappingSvc.Find(ctx, filter)
+}
+
 func (t *transpilerState) assignment(expr ast.Expression) *ast.Identifier {
 	for i := 0; ; i++ {
 		key := fmt.Sprintf("t%d", i)
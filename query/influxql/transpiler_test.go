@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/influxdb/query/influxql"
 	"github.com/influxdata/influxdb/query/influxql/spectests"
 	platformtesting "github.com/influxdata/influxdb/testing"
+	ql "github.com/influxdata/influxql"
 	"github.com/pkg/errors"
 )
 
@@ -35,6 +36,21 @@ func init() {
 	}
 }
 
+// schemaMapper returns the same schema for every measurement.
+type schemaMapper struct{}
+
+func (schemaMapper) FieldDimensions(ctx context.Context, orgID, bucketID platform.ID, measurement string) (map[string]ql.DataType, map[string]struct{}, error) {
+	fields := map[string]ql.DataType{
+		"value": ql.Float,
+		"total": ql.Float,
+	}
+	dimensions := map[string]struct{}{
+		"host":   {},
+		"region": {},
+	}
+	return fields, dimensions, nil
+}
+
 func TestTranspiler(t *testing.T) {
 	for _, fixture := range spectests.All() {
 		fixture.Run(t)
@@ -43,6 +59,48 @@ func TestTranspiler(t *testing.T) {
 
 // TestTranspiler_Compile contains the compilation tests from influxdb. It only verifies if
 // each of these queries either succeeds or it fails with the proper message for compatibility.
+// countingSchemaMapper counts the schema lookups of the transpiler.
+type countingSchemaMapper struct {
+	schemaMapper
+	n int
+}
+
+func (m *countingSchemaMapper) FieldDimensions(ctx context.Context, orgID, bucketID platform.ID, measurement string) (map[string]ql.DataType, map[string]struct{}, error) {
+	m.n++
+	return m.schemaMapper.FieldDimensions(ctx, orgID, bucketID, measurement)
+}
+
+func TestTranspiler_SchemaLookups(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want int
+	}{
+		{s: `SELECT value FROM cpu WHERE time > now() - 1m`},
+		{s: `SELECT value FROM cpu WHERE host = 'a' AND total > 5`},
+		{s: `SELECT mean(value) FROM cpu GROUP BY time(1m), host`},
+		{s: `SELECT * FROM cpu`, want: 1},
+		{s: `SELECT mean(value) FROM cpu GROUP BY *`, want: 1},
+		{s: `SELECT * FROM cpu WHERE total > 5 AND host = 'a'`, want: 1},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			transpiler := influxql.NewTranspilerWithConfig(
+				dbrpMappingSvc,
+				influxql.Config{
+					DefaultDatabase: "db0",
+				},
+			)
+			m := &countingSchemaMapper{}
+			transpiler.WithSchemaMapper(m)
+			if _, err := transpiler.Transpile(context.Background(), tt.s); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if m.n != tt.want {
+				t.Errorf("unexpected number of schema lookups: got %d, want %d", m.n, tt.want)
+			}
+		})
+	}
+}
+
 func TestTranspiler_Compile(t *testing.T) {
 	for _, tt := range []struct {
 		s   string
@@ -393,6 +451,7 @@ func TestTranspiler_Compile(t *testing.T) {
 					DefaultDatabase: "db0",
 				},
 			)
+			transpiler.WithSchemaMapper(schemaMapper{})
 			if _, err := transpiler.Transpile(context.Background(), tt.s); err != nil {
 				if got, want := err.Error(), tt.err; got != want {
 					if cause := errors.Cause(err); strings.HasPrefix(cause.Error(), "unimplemented") {
@@ -21,6 +21,10 @@ type LoggingProxyQueryService struct {
 	QueryLogger       Logger
 	NowFunction       func() time.Time
 	Logger            *zap.Logger
+
+	// Threshold is the total duration from which the queries are logged.
+	// Every query is logged if it is zero.
+	Threshold time.Duration
 }
 
 // Query executes and logs the query.
@@ -28,7 +32,7 @@ func (s *LoggingProxyQueryService) Query(ctx context.Context, w io.Writer, req *
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
-	var n int64
+	wc := &iocounter.Writer{Writer: w}
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("panic: %v", r)
@@ -37,6 +41,9 @@ func (s *LoggingProxyQueryService) Query(ctx context.Context, w io.Writer, req *
 				entry.Write(zap.Error(err))
 			}
 		}
+		if stats.TotalDuration < s.Threshold {
+			return
+		}
 		var now time.Time
 		if s.NowFunction != nil {
 			now = s.NowFunction()
@@ -46,7 +53,7 @@ func (s *LoggingProxyQueryService) Query(ctx context.Context, w io.Writer, req *
 		log := Log{
 			OrganizationID: req.Request.OrganizationID,
 			ProxyRequest:   req,
-			ResponseSize:   n,
+			ResponseSize:   wc.Count(),
 			Time:           now,
 			Statistics:     stats,
 			Error:          err,
@@ -54,12 +61,10 @@ func (s *LoggingProxyQueryService) Query(ctx context.Context, w io.Writer, req *
 		s.QueryLogger.Log(log)
 	}()
 
-	wc := &iocounter.Writer{Writer: w}
 	stats, err = s.ProxyQueryService.Query(ctx, wc, req)
 	if err != nil {
 		return stats, tracing.LogError(span, err)
 	}
-	n = wc.Count()
 	return stats, nil
 }
 
@@ -3,6 +3,7 @@ package query_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"testing"
 	"time"
@@ -94,3 +95,72 @@ func TestLoggingProxyQueryService(t *testing.T) {
 		t.Errorf("unexpected query logs: -want/+got\n%s", cmp.Diff(wantLogs, logs, opts...))
 	}
 }
+
+func TestLoggingProxyQueryService_Error(t *testing.T) {
+	wantErr := errors.New("query failed")
+	wantBytes := 10
+	var logs []query.Log
+	lpqs := query.LoggingProxyQueryService{
+		ProxyQueryService: &mock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				w.Write(make([]byte, wantBytes))
+				return flux.Statistics{}, wantErr
+			},
+		},
+		QueryLogger: &mock.QueryLogger{
+			LogFn: func(l query.Log) error {
+				logs = append(logs, l)
+				return nil
+			},
+		},
+	}
+
+	req := &query.ProxyRequest{Request: query.Request{OrganizationID: orgID}}
+	if _, err := lpqs.Query(context.Background(), &bytes.Buffer{}, req); err != wantErr {
+		t.Fatalf("unexpected error: got %v, want %v", err, wantErr)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("expected the failed query to be logged, got %d logs", len(logs))
+	}
+	if got := logs[0].ResponseSize; got != int64(wantBytes) {
+		t.Errorf("unexpected logged response size: got %d, want %d", got, wantBytes)
+	}
+	if logs[0].Error != wantErr {
+		t.Errorf("unexpected logged error: got %v, want %v", logs[0].Error, wantErr)
+	}
+}
+
+func TestLoggingProxyQueryService_Threshold(t *testing.T) {
+	var logs []query.Log
+	lpqs := query.LoggingProxyQueryService{
+		QueryLogger: &mock.QueryLogger{
+			LogFn: func(l query.Log) error {
+				logs = append(logs, l)
+				return nil
+			},
+		},
+		Threshold: time.Second,
+	}
+
+	for _, d := range []time.Duration{time.Millisecond, time.Second, time.Minute} {
+		d := d
+		lpqs.ProxyQueryService = &mock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				return flux.Statistics{TotalDuration: d}, nil
+			},
+		}
+		req := &query.ProxyRequest{Request: query.Request{OrganizationID: orgID}}
+		if _, err := lpqs.Query(context.Background(), &bytes.Buffer{}, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("expected the queries over the threshold to be logged, got %d logs", len(logs))
+	}
+	for i, want := range []time.Duration{time.Second, time.Minute} {
+		if got := logs[i].Statistics.TotalDuration; got != want {
+			t.Errorf("unexpected logged query duration: got %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package ndjson
+
+import (
+	"net/http"
+
+	"github.com/influxdata/flux"
+)
+
+const DialectType = "ndjson"
+
+// ContentType is the media type of newline delimited JSON.
+const ContentType = "application/x-ndjson"
+
+// AddDialectMappings adds the ndjson specific dialect mappings.
+func AddDialectMappings(mappings flux.DialectMappings) error {
+	return mappings.Add(DialectType, func() flux.Dialect {
+		return new(Dialect)
+	})
+}
+
+// Dialect describes the encoding of query results as newline delimited JSON.
+type Dialect struct{}
+
+func (d *Dialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ContentType)
+}
+
+func (d *Dialect) Encoder() flux.MultiResultEncoder {
+	return new(MultiResultEncoder)
+}
+
+func (d *Dialect) DialectType() flux.DialectType {
+	return DialectType
+}
@@ -0,0 +1,181 @@
+package ndjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/iocounter"
+)
+
+// Table is a line of the results holding a buffer of a table.
+// A table is written as one line for each of its buffers, or a single line
+// without values when it is empty.
+type Table struct {
+	Result  string   `json:"result"`
+	Table   int      `json:"table"`
+	Columns []Column `json:"columns"`
+	// Values holds the values of each of the columns.
+	Values [][]interface{} `json:"values"`
+}
+
+// Column describes a column of a table.
+// Its data type is named as in the datatype annotation of annotated CSV.
+type Column struct {
+	Label    string `json:"label"`
+	DataType string `json:"datatype"`
+	Group    bool   `json:"group"`
+}
+
+// Error is the line ending the results when the query fails after some of them were written.
+type Error struct {
+	Error string `json:"error"`
+}
+
+// MultiResultEncoder encodes results as newline delimited JSON.
+//
+// Null values, as well as the NaN and infinite floats that JSON cannot represent, are encoded as null.
+// Times are encoded in RFC3339 format with nanosecond precision.
+type MultiResultEncoder struct{}
+
+// Encode writes the tables of the results to w. If the results fail before anything is
+// written, the error is returned so that it can be reported by the caller. Otherwise
+// it is written as the last line.
+func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+	enc := json.NewEncoder(wc)
+
+	err := encodeResults(enc, results)
+	if err == nil || wc.Count() == 0 {
+		return wc.Count(), err
+	}
+	return wc.Count(), enc.Encode(&Error{Error: err.Error()})
+}
+
+func encodeResults(enc *json.Encoder, results flux.ResultIterator) error {
+	for results.More() {
+		res := results.Next()
+		table := 0
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			t := &Table{
+				Result:  res.Name(),
+				Table:   table,
+				Columns: columns(tbl),
+			}
+			table++
+
+			empty := true
+			if err := tbl.Do(func(cr flux.ColReader) error {
+				empty = false
+				values, err := columnValues(cr)
+				if err != nil {
+					return err
+				}
+				t.Values = values
+				return enc.Encode(t)
+			}); err != nil {
+				return err
+			}
+			if empty {
+				t.Values = make([][]interface{}, len(t.Columns))
+				for j := range t.Values {
+					t.Values[j] = []interface{}{}
+				}
+				return enc.Encode(t)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return results.Err()
+}
+
+func columns(tbl flux.Table) []Column {
+	cols := make([]Column, len(tbl.Cols()))
+	for j, c := range tbl.Cols() {
+		cols[j] = Column{
+			Label:    c.Label,
+			DataType: dataType(c.Type),
+			Group:    tbl.Key().HasCol(c.Label),
+		}
+	}
+	return cols
+}
+
+func dataType(typ flux.ColType) string {
+	switch typ {
+	case flux.TBool:
+		return "boolean"
+	case flux.TInt:
+		return "long"
+	case flux.TUInt:
+		return "unsignedLong"
+	case flux.TFloat:
+		return "double"
+	case flux.TString:
+		return "string"
+	case flux.TTime:
+		return "dateTime:RFC3339"
+	default:
+		return "invalid"
+	}
+}
+
+func columnValues(cr flux.ColReader) ([][]interface{}, error) {
+	values := make([][]interface{}, len(cr.Cols()))
+	for j, c := range cr.Cols() {
+		vs := make([]interface{}, cr.Len())
+		switch c.Type {
+		case flux.TBool:
+			arr := cr.Bools(j)
+			for i := range vs {
+				if arr.IsValid(i) {
+					vs[i] = arr.Value(i)
+				}
+			}
+		case flux.TInt:
+			arr := cr.Ints(j)
+			for i := range vs {
+				if arr.IsValid(i) {
+					vs[i] = arr.Value(i)
+				}
+			}
+		case flux.TUInt:
+			arr := cr.UInts(j)
+			for i := range vs {
+				if arr.IsValid(i) {
+					vs[i] = arr.Value(i)
+				}
+			}
+		case flux.TFloat:
+			arr := cr.Floats(j)
+			for i := range vs {
+				if v := arr.Value(i); arr.IsValid(i) && !math.IsNaN(v) && !math.IsInf(v, 0) {
+					vs[i] = v
+				}
+			}
+		case flux.TString:
+			arr := cr.Strings(j)
+			for i := range vs {
+				if arr.IsValid(i) {
+					vs[i] = arr.ValueString(i)
+				}
+			}
+		case flux.TTime:
+			arr := cr.Times(j)
+			for i := range vs {
+				if arr.IsValid(i) {
+					vs[i] = time.Unix(0, arr.Value(i)).UTC().Format(time.RFC3339Nano)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unsupported column type %v of column %q", c.Type, c.Label)
+		}
+		values[j] = vs
+	}
+	return values, nil
+}
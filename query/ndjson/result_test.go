@@ -0,0 +1,68 @@
+package ndjson_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/influxdb/query/ndjson"
+)
+
+func TestMultiResultEncoder_Encode(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{
+			name: "tables",
+			in: `#datatype,string,long,dateTime:RFC3339,string,double,boolean
+#group,false,false,false,true,false,false
+#default,_result,,,,,
+,result,table,_time,host,_value,ok
+,,0,2019-01-01T00:00:01Z,a,1.5,true
+,,0,2019-01-01T00:00:02Z,a,,false
+,,1,2019-01-01T00:00:01Z,b,2,true
+`,
+			out: `{"result":"_result","table":0,"columns":[{"label":"_time","datatype":"dateTime:RFC3339","group":false},{"label":"host","datatype":"string","group":true},{"label":"_value","datatype":"double","group":false},{"label":"ok","datatype":"boolean","group":false}],"values":[["2019-01-01T00:00:01Z","2019-01-01T00:00:02Z"],["a","a"],[1.5,null],[true,false]]}
+{"result":"_result","table":1,"columns":[{"label":"_time","datatype":"dateTime:RFC3339","group":false},{"label":"host","datatype":"string","group":true},{"label":"_value","datatype":"double","group":false},{"label":"ok","datatype":"boolean","group":false}],"values":[["2019-01-01T00:00:01Z"],["b"],[2],[true]]}
+`,
+		},
+		{
+			name: "empty table",
+			in: `#datatype,string,long,string,unsignedLong
+#group,false,false,true,false
+#default,counts,0,a,
+,result,table,host,_value
+`,
+			out: `{"result":"counts","table":0,"columns":[{"label":"host","datatype":"string","group":true},{"label":"_value","datatype":"unsignedLong","group":false}],"values":[[],[]]}
+`,
+		},
+		{
+			name: "no results",
+			in:   "",
+			out:  "",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{}).Decode(ioutil.NopCloser(strings.NewReader(tt.in)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			n, err := new(ndjson.MultiResultEncoder).Encode(&buf, results)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.out {
+				t.Errorf("unexpected output -got/+want\n%s\n%s", got, tt.out)
+			}
+			if n != int64(buf.Len()) {
+				t.Errorf("unexpected byte count: got %d, want %d", n, buf.Len())
+			}
+		})
+	}
+}
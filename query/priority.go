@@ -0,0 +1,45 @@
+package query
+
+import "context"
+
+// Priority is the scheduling priority of a query. Among the queued queries of an
+// organization, the queries with a higher priority are executed first.
+type Priority int
+
+// Priorities of queries. A query has the normal priority unless its context sets another.
+// Interactive queries over HTTP have the high priority and the queries of tasks the low priority.
+const (
+	LowPriority    Priority = -1
+	NormalPriority Priority = 0
+	HighPriority   Priority = 1
+)
+
+func (p Priority) String() string {
+	switch p {
+	case LowPriority:
+		return "low"
+	case NormalPriority:
+		return "normal"
+	case HighPriority:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+type priorityContextKey struct{}
+
+// ContextWithPriority returns a new context with the scheduling priority of the queries submitted with it.
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, p)
+}
+
+// PriorityFromContext returns the scheduling priority set on the context,
+// or the normal priority if none is set.
+func PriorityFromContext(ctx context.Context) Priority {
+	p, ok := ctx.Value(priorityContextKey{}).(Priority)
+	if !ok {
+		return NormalPriority
+	}
+	return p
+}
@@ -0,0 +1,26 @@
+package promql
+
+import (
+	"net/http"
+
+	"github.com/influxdata/flux"
+)
+
+const DialectType = "promql"
+
+// Dialect describes the output format of the Prometheus HTTP API.
+type Dialect struct {
+	Instant bool // Instant encodes the steps of an instant query as a vector instead of a matrix.
+}
+
+func (d *Dialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+}
+
+func (d *Dialect) Encoder() flux.MultiResultEncoder {
+	return &MultiResultEncoder{Instant: d.Instant}
+}
+
+func (d *Dialect) DialectType() flux.DialectType {
+	return DialectType
+}
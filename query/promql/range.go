@@ -0,0 +1,268 @@
+package promql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+// LookbackDelta is how far back the evaluation of a query at a time looks for the latest sample of a series.
+const LookbackDelta = 5 * time.Minute
+
+// MaxSteps is the maximum number of steps a range query is evaluated at.
+const MaxSteps = 11000
+
+// Range is the evaluation range of a query.
+// An instant query is evaluated once at End and has a zero Step.
+type Range struct {
+	Start time.Time
+	End   time.Time
+	Step  time.Duration
+}
+
+// Instant returns the range of an instant query evaluated at t.
+func Instant(t time.Time) Range {
+	return Range{Start: t, End: t}
+}
+
+// IsInstant reports whether the range is evaluated at a single time.
+func (r Range) IsInstant() bool {
+	return r.Step == 0
+}
+
+// Validate returns an error if the range cannot be evaluated.
+func (r Range) Validate() error {
+	if r.End.Before(r.Start) {
+		return fmt.Errorf("end time must not be before start time")
+	}
+	if r.Step < 0 {
+		return fmt.Errorf("step must be positive")
+	}
+	if r.Step == 0 && !r.Start.Equal(r.End) {
+		return fmt.Errorf("step is required for range queries")
+	}
+	if r.Step > 0 && r.End.Sub(r.Start)/r.Step >= MaxSteps {
+		return fmt.Errorf("exceeded the maximum of %d steps, increase the step", MaxSteps)
+	}
+	return nil
+}
+
+// BuildRange builds a flux query specification that evaluates promql over r,
+// reading the metrics from bucket.
+//
+// Metrics are read as they are written by the scrapers: the metric name is
+// the measurement and labels are tags. The value of a series at each step is
+// its last sample within the LookbackDelta preceding it. The rows of evaluated
+// steps are timestamped by _stop, while the samples of a range vector selector
+// keep their _time.
+func BuildRange(promql, bucket string, r Range, opts ...Option) (*flux.Spec, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	parsed, err := ParsePromQL(promql, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var expr *AggregateExpr
+	switch p := parsed.(type) {
+	case *Selector:
+		expr = &AggregateExpr{Selector: p}
+	case *AggregateExpr:
+		expr = p
+	default:
+		return nil, fmt.Errorf("unable to evaluate %T over a range", parsed)
+	}
+
+	sel := expr.Selector
+	if sel.Range > 0 && (expr.Op != nil || !r.IsInstant()) {
+		return nil, fmt.Errorf("range vector selectors are only supported by instant queries")
+	}
+
+	b := &specBuilder{}
+	b.add("from", &influxdb.FromOpSpec{
+		Bucket: bucket,
+	})
+
+	where, err := newWhereOperation("_measurement", sel.Name, sel.LabelMatchers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flux ranges and windows exclude their stop while the samples at an evaluation time are included,
+	// so everything is read a nanosecond later and the bounds are shifted back.
+	if sel.Range > 0 {
+		b.add("range", newAbsoluteRangeOp(r.End.Add(-sel.Range-sel.Offset+time.Nanosecond), r.End.Add(-sel.Offset+time.Nanosecond)))
+		b.add(where.ID, where.Spec)
+		if sel.Offset != 0 {
+			b.add("shift", &universe.ShiftOpSpec{
+				Shift:   flux.Duration(sel.Offset),
+				Columns: []string{"_start", "_stop", "_time"},
+			})
+		}
+		return b.spec(), nil
+	}
+
+	// The windows of the steps span the lookback delta up to the evaluation times,
+	// so the range is aligned to the last evaluation time that is not after the end of r.
+	every := LookbackDelta
+	stop := r.Start
+	if !r.IsInstant() {
+		every = r.Step
+		stop = r.Start.Add(r.End.Sub(r.Start) / r.Step * r.Step)
+	}
+	start := r.Start.Add(-LookbackDelta - sel.Offset + time.Nanosecond)
+	stop = stop.Add(-sel.Offset + time.Nanosecond)
+
+	b.add("range", newAbsoluteRangeOp(start, stop))
+	b.add(where.ID, where.Spec)
+	b.add("window", &universe.WindowOpSpec{
+		Every:       flux.Duration(every),
+		Period:      flux.Duration(LookbackDelta),
+		Offset:      flux.Duration(windowOffset(stop, every)),
+		TimeColumn:  "_time",
+		StartColumn: "_start",
+		StopColumn:  "_stop",
+	})
+	b.add("last", &universe.LastOpSpec{
+		SelectorConfig: execute.SelectorConfig{Column: "_value"},
+	})
+	b.add("drop", &universe.DropOpSpec{
+		Columns: []string{"_time"},
+	})
+	if every < LookbackDelta {
+		// Windows overlap when steps are shorter than the lookback delta, and the windows of
+		// the steps before the first and after the last evaluation time are clipped to the range.
+		b.add("steps", newStepsFilterOp(start.Add(LookbackDelta), stop.Add(-LookbackDelta)))
+	}
+	b.add("shift", &universe.ShiftOpSpec{
+		Shift:   flux.Duration(sel.Offset - time.Nanosecond),
+		Columns: []string{"_start", "_stop"},
+	})
+
+	if expr.Op != nil {
+		keys := []string{"_start", "_stop"}
+		if expr.Aggregate != nil {
+			if expr.Aggregate.Without {
+				return nil, fmt.Errorf("unable to merge using `without`")
+			}
+			for _, l := range expr.Aggregate.Labels {
+				keys = append(keys, l.Name)
+			}
+		}
+		b.add("merge", &universe.GroupOpSpec{
+			Columns: keys,
+			Mode:    "by",
+		})
+
+		id, agg, err := expr.Op.rangeSpec()
+		if err != nil {
+			return nil, err
+		}
+		b.add(id, agg)
+	}
+
+	return b.spec(), nil
+}
+
+// rangeSpec returns the operation that aggregates the series of each step.
+func (o *Operator) rangeSpec() (flux.OperationID, flux.OperationSpec, error) {
+	values := execute.AggregateConfig{Columns: []string{"_value"}}
+	switch o.Kind {
+	case SumKind:
+		return "sum", &universe.SumOpSpec{AggregateConfig: values}, nil
+	case CountKind:
+		return "count", &universe.CountOpSpec{AggregateConfig: values}, nil
+	case AvgKind:
+		return "mean", &universe.MeanOpSpec{AggregateConfig: values}, nil
+	case StdevKind:
+		return "stddev", &universe.StddevOpSpec{Mode: "population", AggregateConfig: values}, nil
+	case MinKind:
+		return "min", &universe.MinOpSpec{SelectorConfig: execute.SelectorConfig{Column: "_value"}}, nil
+	case MaxKind:
+		return "max", &universe.MaxOpSpec{SelectorConfig: execute.SelectorConfig{Column: "_value"}}, nil
+	default:
+		return "", nil, fmt.Errorf("unable to run %d yet", o.Kind)
+	}
+}
+
+func newAbsoluteRangeOp(start, stop time.Time) flux.OperationSpec {
+	return &universe.RangeOpSpec{
+		Start:       flux.Time{Absolute: start},
+		Stop:        flux.Time{Absolute: stop},
+		TimeColumn:  "_time",
+		StartColumn: "_start",
+		StopColumn:  "_stop",
+	}
+}
+
+// newStepsFilterOp returns a filter keeping the windows of the evaluated steps,
+// which stop no earlier than firstStop and start no later than lastStart.
+func newStepsFilterOp(firstStop, lastStart time.Time) flux.OperationSpec {
+	bound := func(op ast.OperatorKind, column string, t time.Time) semantic.Expression {
+		return &semantic.BinaryExpression{
+			Operator: op,
+			Left: &semantic.MemberExpression{
+				Object:   &semantic.IdentifierExpression{Name: "r"},
+				Property: column,
+			},
+			Right: &semantic.DateTimeLiteral{Value: t},
+		}
+	}
+	return &universe.FilterOpSpec{
+		Fn: &semantic.FunctionExpression{
+			Block: &semantic.FunctionBlock{
+				Parameters: &semantic.FunctionParameters{
+					List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: "r"}}},
+				},
+				Body: &semantic.LogicalExpression{
+					Operator: ast.AndOperator,
+					Left:     bound(ast.GreaterThanEqualOperator, "_stop", firstStop),
+					Right:    bound(ast.LessThanEqualOperator, "_start", lastStart),
+				},
+			},
+		},
+	}
+}
+
+// windowOffset returns the offset of windows of the period whose boundaries include t.
+func windowOffset(t time.Time, period time.Duration) time.Duration {
+	offset := time.Duration(t.UnixNano()) % period
+	if offset < 0 {
+		offset += period
+	}
+	return offset
+}
+
+// specBuilder chains operations into a linear query specification.
+type specBuilder struct {
+	ops   []*flux.Operation
+	edges []flux.Edge
+}
+
+func (b *specBuilder) add(id flux.OperationID, spec flux.OperationSpec) {
+	if len(b.ops) > 0 {
+		b.edges = append(b.edges, flux.Edge{
+			Parent: b.ops[len(b.ops)-1].ID,
+			Child:  id,
+		})
+	}
+	b.ops = append(b.ops, &flux.Operation{
+		ID:   id,
+		Spec: spec,
+	})
+}
+
+func (b *specBuilder) spec() *flux.Spec {
+	return &flux.Spec{
+		Operations: b.ops,
+		Edges:      b.edges,
+	}
+}
@@ -0,0 +1,139 @@
+package promql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+func TestBuildRange(t *testing.T) {
+	end := time.Unix(1500000000, 0)
+	tests := []struct {
+		name    string
+		promql  string
+		rng     Range
+		wantOps []flux.OperationID
+		check   func(t *testing.T, ops map[flux.OperationID]flux.OperationSpec)
+		wantErr bool
+	}{
+		{
+			name:    "instant selector",
+			promql:  `up{job=~"node|db"}`,
+			rng:     Instant(end),
+			wantOps: []flux.OperationID{"from", "range", "where", "window", "last", "drop", "shift"},
+			check: func(t *testing.T, ops map[flux.OperationID]flux.OperationSpec) {
+				if got := ops["from"].(*influxdb.FromOpSpec).Bucket; got != "metrics" {
+					t.Errorf("unexpected bucket %q", got)
+				}
+				rng := ops["range"].(*universe.RangeOpSpec)
+				if want := end.Add(-LookbackDelta + time.Nanosecond); !rng.Start.Absolute.Equal(want) {
+					t.Errorf("unexpected range start %v, want %v", rng.Start.Absolute, want)
+				}
+				if want := end.Add(time.Nanosecond); !rng.Stop.Absolute.Equal(want) {
+					t.Errorf("unexpected range stop %v, want %v", rng.Stop.Absolute, want)
+				}
+				if w := ops["window"].(*universe.WindowOpSpec); w.Every != flux.Duration(LookbackDelta) || w.Offset != flux.Duration(time.Nanosecond) {
+					t.Errorf("unexpected window %+v", w)
+				}
+			},
+		},
+		{
+			name:    "range aggregate",
+			promql:  `sum(node_cpu{mode="user"} offset 1m) by (host)`,
+			rng:     Range{Start: end.Add(-time.Hour), End: end.Add(10 * time.Second), Step: 15 * time.Second},
+			wantOps: []flux.OperationID{"from", "range", "where", "window", "last", "drop", "steps", "shift", "merge", "sum"},
+			check: func(t *testing.T, ops map[flux.OperationID]flux.OperationSpec) {
+				rng := ops["range"].(*universe.RangeOpSpec)
+				if want := end.Add(-time.Hour - LookbackDelta - time.Minute + time.Nanosecond); !rng.Start.Absolute.Equal(want) {
+					t.Errorf("unexpected range start %v, want %v", rng.Start.Absolute, want)
+				}
+				// Every step looks back for the lookback delta.
+				if w := ops["window"].(*universe.WindowOpSpec); w.Every != flux.Duration(15*time.Second) || w.Period != flux.Duration(LookbackDelta) {
+					t.Errorf("unexpected window %+v", w)
+				}
+				// The last step is at the end of the hour, which is 10s before the end of the range.
+				if want := end.Add(-time.Minute + time.Nanosecond); !rng.Stop.Absolute.Equal(want) {
+					t.Errorf("unexpected range stop %v, want %v", rng.Stop.Absolute, want)
+				}
+				if got := ops["shift"].(*universe.ShiftOpSpec).Shift; got != flux.Duration(time.Minute-time.Nanosecond) {
+					t.Errorf("unexpected shift %v", got)
+				}
+				if got, want := ops["merge"].(*universe.GroupOpSpec).Columns, []string{"_start", "_stop", "host"}; !cmp.Equal(got, want) {
+					t.Errorf("unexpected group columns -want/+got\n%s", cmp.Diff(want, got))
+				}
+			},
+		},
+		{
+			name:    "range vector",
+			promql:  `http_requests_total[5m]`,
+			rng:     Instant(end),
+			wantOps: []flux.OperationID{"from", "range", "where"},
+		},
+		{
+			name:    "range vector of a range query",
+			promql:  `http_requests_total[5m]`,
+			rng:     Range{Start: end.Add(-time.Hour), End: end, Step: time.Minute},
+			wantErr: true,
+		},
+		{
+			name:    "range requires a step",
+			promql:  `up`,
+			rng:     Range{Start: end.Add(-time.Hour), End: end},
+			wantErr: true,
+		},
+		{
+			name:    "range with steps longer than the lookback delta",
+			promql:  `up`,
+			rng:     Range{Start: end.Add(-time.Hour), End: end, Step: 10 * time.Minute},
+			wantOps: []flux.OperationID{"from", "range", "where", "window", "last", "drop", "shift"},
+			check: func(t *testing.T, ops map[flux.OperationID]flux.OperationSpec) {
+				if w := ops["window"].(*universe.WindowOpSpec); w.Every != flux.Duration(10*time.Minute) || w.Period != flux.Duration(LookbackDelta) {
+					t.Errorf("unexpected window %+v", w)
+				}
+			},
+		},
+		{
+			name:    "range with too many steps",
+			promql:  `up`,
+			rng:     Range{Start: end.Add(-MaxSteps * time.Second), End: end, Step: time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported aggregate",
+			promql:  `topk(up)`,
+			rng:     Instant(end),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := BuildRange(tt.promql, "metrics", tt.rng)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildRange() %s error = %v, wantErr %v", tt.promql, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []flux.OperationID
+			ops := make(map[flux.OperationID]flux.OperationSpec)
+			for _, op := range spec.Operations {
+				got = append(got, op.ID)
+				ops[op.ID] = op.Spec
+			}
+			if !cmp.Equal(tt.wantOps, got) {
+				t.Fatalf("unexpected operations -want/+got\n%s", cmp.Diff(tt.wantOps, got))
+			}
+			if len(spec.Edges) != len(spec.Operations)-1 {
+				t.Fatalf("expected the operations to be chained, got edges %v", spec.Edges)
+			}
+			if tt.check != nil {
+				tt.check(t, ops)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package promql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/iocounter"
+)
+
+// Result types of the Prometheus HTTP API.
+const (
+	VectorResult = "vector"
+	MatrixResult = "matrix"
+)
+
+// Error types of the Prometheus HTTP API.
+const (
+	ErrorBadData   = "bad_data"
+	ErrorExecution = "execution"
+	ErrorInternal  = "internal"
+)
+
+// Response is a response of the Prometheus HTTP API.
+type Response struct {
+	Status    string `json:"status"`
+	Data      *Data  `json:"data,omitempty"`
+	ErrorType string `json:"errorType,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// NewErrorResponse returns the response to a failed query.
+func NewErrorResponse(errorType string, err error) *Response {
+	return &Response{
+		Status:    "error",
+		ErrorType: errorType,
+		Error:     err.Error(),
+	}
+}
+
+// Data is the result of a successful query.
+type Data struct {
+	ResultType string    `json:"resultType"`
+	Result     []*Series `json:"result"`
+}
+
+// Series is a set of samples identified by its labels.
+// The series of a vector have a single Value, while those of a matrix have Values.
+type Series struct {
+	Metric map[string]string `json:"metric"`
+	Value  *Sample           `json:"value,omitempty"`
+	Values []Sample          `json:"values,omitempty"`
+}
+
+// Sample is a value of a series at a time.
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// MarshalJSON encodes the sample as a pair of its time in seconds and its value as a string.
+func (s Sample) MarshalJSON() ([]byte, error) {
+	secs := float64(s.Time.UnixNano()/int64(time.Millisecond)) / 1e3
+	return json.Marshal([]interface{}{
+		json.Number(strconv.FormatFloat(secs, 'f', -1, 64)),
+		formatValue(s.Value),
+	})
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+}
+
+// DefaultMaxSamples is the default maximum number of samples of the results of a query.
+const DefaultMaxSamples = 50000000
+
+// MultiResultEncoder encodes results in the format of the Prometheus HTTP API.
+type MultiResultEncoder struct {
+	Instant bool
+	// MaxSamples is the maximum number of samples the results are allowed to have,
+	// since they are held in memory until they are written. Zero means DefaultMaxSamples.
+	MaxSamples int
+}
+
+// Encode writes the series of the results to w.
+// Expectations/Assumptions:
+//  1. The string columns of the group key are the labels of a series. The _measurement is
+//     its __name__ and the _field is ignored. Tables with the same labels belong to the same series.
+//  2. Samples are timestamped by the _time column, or by the _stop of their step when there is no _time.
+//  3. The _value column is numeric.
+//
+// Nothing is written if the results fail so that the error can be reported by the caller.
+func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	var (
+		series  []*Series
+		index   = make(map[string]*Series)
+		raw     bool
+		samples int
+	)
+	maxSamples := e.MaxSamples
+	if maxSamples <= 0 {
+		maxSamples = DefaultMaxSamples
+	}
+
+	for results.More() {
+		res := results.Next()
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			metric := make(map[string]string)
+			for j, c := range tbl.Key().Cols() {
+				if c.Type != flux.TString || c.Label == "_field" {
+					continue
+				}
+				label := c.Label
+				if label == "_measurement" {
+					label = "__name__"
+				}
+				metric[label] = tbl.Key().ValueString(j)
+			}
+
+			timeIdx := execute.ColIdx(execute.DefaultTimeColLabel, tbl.Cols())
+			if timeIdx >= 0 {
+				raw = true
+			} else {
+				timeIdx = execute.ColIdx(execute.DefaultStopColLabel, tbl.Cols())
+			}
+			valueIdx := execute.ColIdx(execute.DefaultValueColLabel, tbl.Cols())
+			if timeIdx < 0 || valueIdx < 0 {
+				return fmt.Errorf("result tables require a time and a %s column", execute.DefaultValueColLabel)
+			}
+
+			key := seriesKey(metric)
+			s, ok := index[key]
+			if !ok {
+				s = &Series{Metric: metric}
+				index[key] = s
+				series = append(series, s)
+			}
+
+			return tbl.Do(func(cr flux.ColReader) error {
+				times := cr.Times(timeIdx)
+				for i := 0; i < cr.Len(); i++ {
+					if !times.IsValid(i) {
+						continue
+					}
+					v, ok, err := floatValue(cr, valueIdx, i)
+					if err != nil {
+						return err
+					}
+					if !ok {
+						continue
+					}
+					if samples++; samples > maxSamples {
+						return fmt.Errorf("query exceeded the maximum of %d samples", maxSamples)
+					}
+					s.Values = append(s.Values, Sample{
+						Time:  time.Unix(0, times.Value(i)).UTC(),
+						Value: v,
+					})
+				}
+				return nil
+			})
+		}); err != nil {
+			return 0, err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return 0, err
+	}
+
+	data := &Data{
+		ResultType: MatrixResult,
+		Result:     make([]*Series, 0, len(series)),
+	}
+	if e.Instant && !raw {
+		data.ResultType = VectorResult
+	}
+	for _, s := range series {
+		if len(s.Values) == 0 {
+			continue
+		}
+		sort.SliceStable(s.Values, func(i, j int) bool {
+			return s.Values[i].Time.Before(s.Values[j].Time)
+		})
+		if data.ResultType == VectorResult {
+			s.Value = &s.Values[len(s.Values)-1]
+			s.Values = nil
+		}
+		data.Result = append(data.Result, s)
+	}
+
+	wc := &iocounter.Writer{Writer: w}
+	err := json.NewEncoder(wc).Encode(&Response{
+		Status: "success",
+		Data:   data,
+	})
+	return wc.Count(), err
+}
+
+// seriesKey returns a key that is unique to the labels of a series.
+func seriesKey(metric map[string]string) string {
+	labels := make([]string, 0, len(metric))
+	for k := range metric {
+		labels = append(labels, k)
+	}
+	sort.Strings(labels)
+
+	var b strings.Builder
+	for _, l := range labels {
+		b.WriteString(strconv.Quote(l))
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(metric[l]))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+func floatValue(cr flux.ColReader, j, i int) (float64, bool, error) {
+	switch typ := cr.Cols()[j].Type; typ {
+	case flux.TFloat:
+		vs := cr.Floats(j)
+		return vs.Value(i), vs.IsValid(i), nil
+	case flux.TInt:
+		vs := cr.Ints(j)
+		return float64(vs.Value(i)), vs.IsValid(i), nil
+	case flux.TUInt:
+		vs := cr.UInts(j)
+		return float64(vs.Value(i)), vs.IsValid(i), nil
+	default:
+		return 0, false, fmt.Errorf("unsupported value type %s", typ)
+	}
+}
@@ -0,0 +1,99 @@
+package promql_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/influxdb/query/promql"
+)
+
+func TestMultiResultEncoder_Encode(t *testing.T) {
+	const steps = `#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,string,string,string,double
+#group,false,false,true,true,true,true,true,false
+#default,_result,,,,,,,
+,result,table,_start,_stop,_measurement,_field,host,_value
+,,0,2017-07-14T02:40:30Z,2017-07-14T02:41:00Z,up,gauge,a,1
+,,1,2017-07-14T02:40:00Z,2017-07-14T02:40:30Z,up,gauge,a,0.5
+`
+	const samples = `#datatype,string,long,dateTime:RFC3339,string,long
+#group,false,false,false,true,false
+#default,_result,,,,
+,result,table,_time,_measurement,_value
+,,0,2017-07-14T02:40:01Z,requests,2
+,,0,2017-07-14T02:40:02Z,requests,3
+`
+	for _, tt := range []struct {
+		name    string
+		in      string
+		instant bool
+		out     string
+	}{
+		{
+			name: "range query",
+			in:   steps,
+			out:  `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up","host":"a"},"values":[[1500000030,"0.5"],[1500000060,"1"]]}]}}`,
+		},
+		{
+			name:    "instant query",
+			in:      steps,
+			instant: true,
+			out:     `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"up","host":"a"},"value":[1500000060,"1"]}]}}`,
+		},
+		{
+			name:    "range vector",
+			in:      samples,
+			instant: true,
+			out:     `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"requests"},"values":[[1500000001,"2"],[1500000002,"3"]]}]}}`,
+		},
+		{
+			name:    "no results",
+			in:      "",
+			instant: true,
+			out:     `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{}).Decode(ioutil.NopCloser(strings.NewReader(tt.in)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer results.Release()
+
+			var buf bytes.Buffer
+			enc := &promql.MultiResultEncoder{Instant: tt.instant}
+			if _, err := enc.Encode(&buf, results); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := buf.String(), tt.out+"\n"; got != want {
+				t.Errorf("unexpected output:\n\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestMultiResultEncoder_MaxSamples(t *testing.T) {
+	const samples = `#datatype,string,long,dateTime:RFC3339,string,long
+#group,false,false,false,true,false
+#default,_result,,,,
+,result,table,_time,_measurement,_value
+,,0,2017-07-14T02:40:01Z,requests,2
+,,0,2017-07-14T02:40:02Z,requests,3
+`
+	results, err := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{}).Decode(ioutil.NopCloser(strings.NewReader(samples)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer results.Release()
+
+	var buf bytes.Buffer
+	enc := &promql.MultiResultEncoder{Instant: true, MaxSamples: 1}
+	if _, err := enc.Encode(&buf, results); err == nil || !strings.Contains(err.Error(), "maximum of 1 samples") {
+		t.Fatalf("expected the samples to exceed the maximum, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected output %s", buf.String())
+	}
+}
@@ -2,6 +2,7 @@ package promql
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -204,18 +205,23 @@ func NewRangeOp(rng, offset time.Duration) (*flux.Operation, error) {
 var operatorLookup = map[MatchKind]ast.OperatorKind{
 	Equal:        ast.EqualOperator,
 	NotEqual:     ast.NotEqualOperator,
-	RegexMatch:   ast.EqualOperator,
-	RegexNoMatch: ast.NotEqualOperator,
+	RegexMatch:   ast.RegexpMatchOperator,
+	RegexNoMatch: ast.NotRegexpMatchOperator,
 }
 
 func NewWhereOperation(metricName string, labels []*LabelMatcher) (*flux.Operation, error) {
+	return newWhereOperation("_metric", metricName, labels)
+}
+
+// newWhereOperation filters the rows of the metric, named by the metric column, by the label matchers.
+func newWhereOperation(metricColumn, metricName string, labels []*LabelMatcher) (*flux.Operation, error) {
 	var node semantic.Expression = &semantic.BinaryExpression{
 		Operator: ast.EqualOperator,
 		Left: &semantic.MemberExpression{
 			Object: &semantic.IdentifierExpression{
 				Name: "r",
 			},
-			Property: "_metric",
+			Property: metricColumn,
 		},
 		Right: &semantic.StringLiteral{
 			Value: metricName,
@@ -233,7 +239,16 @@ func NewWhereOperation(metricName string, labels []*LabelMatcher) (*flux.Operati
 			Property: label.Name,
 		}
 		var value semantic.Expression
-		if label.Value.Type() == StringKind {
+		if label.Kind == RegexMatch || label.Kind == RegexNoMatch {
+			// Regular expressions of label matchers are fully anchored.
+			re, err := regexp.Compile("^(?:" + fmt.Sprint(label.Value.Value()) + ")$")
+			if err != nil {
+				return nil, err
+			}
+			value = &semantic.RegexpLiteral{
+				Value: re,
+			}
+		} else if label.Value.Type() == StringKind {
 			value = &semantic.StringLiteral{
 				Value: label.Value.Value().(string),
 			}
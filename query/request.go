@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -151,3 +153,23 @@ func (r ProxyRequest) MarshalJSON() ([]byte, error) {
 	}
 	return json.Marshal(raw)
 }
+
+// CompilerText returns the text of the query compiled by the compiler,
+// or an empty string if the query was not submitted as Flux.
+func CompilerText(compiler flux.Compiler) string {
+	switch c := compiler.(type) {
+	case lang.FluxCompiler:
+		return c.Query
+	case *lang.FluxCompiler:
+		return c.Query
+	case lang.ASTCompiler:
+		if c.AST != nil {
+			return ast.Format(c.AST)
+		}
+	case *lang.ASTCompiler:
+		if c.AST != nil {
+			return ast.Format(c.AST)
+		}
+	}
+	return ""
+}
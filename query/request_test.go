@@ -0,0 +1,38 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+)
+
+func TestCompilerText(t *testing.T) {
+	const text = `from(bucket: "db")`
+	pkg := parser.ParseSource(text)
+	formatted := ast.Format(pkg)
+	for _, tt := range []struct {
+		name     string
+		compiler flux.Compiler
+		want     string
+	}{
+		{name: "flux", compiler: lang.FluxCompiler{Query: text}, want: text},
+		{name: "flux pointer", compiler: &lang.FluxCompiler{Query: text}, want: text},
+		{name: "ast", compiler: lang.ASTCompiler{AST: pkg}, want: formatted},
+		{name: "ast pointer", compiler: &lang.ASTCompiler{AST: pkg}, want: formatted},
+		{name: "nil ast", compiler: lang.ASTCompiler{}},
+		{name: "nil ast pointer", compiler: &lang.ASTCompiler{}},
+		{name: "influxql", compiler: &influxql.Compiler{Query: "SELECT * FROM m"}},
+		{name: "nil"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := query.CompilerText(tt.compiler); got != tt.want {
+				t.Errorf("unexpected query text: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package query
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/influxdata/flux"
+)
+
+// Metadata keys used by the influxdb flux functions to report statistics
+// in the flux.Statistics of a query.
+const (
+	ScannedValuesMetadataKey = "influxdb/scanned-values"
+	ScannedBytesMetadataKey  = "influxdb/scanned-bytes"
+	WrittenRowsMetadataKey   = "influxdb/written-rows"
+	WrittenBytesMetadataKey  = "influxdb/written-bytes"
+)
+
+// WriteStatistics accumulates the amount of data written by a query.
+// It is safe for concurrent use.
+type WriteStatistics struct {
+	rows  int64
+	bytes int64
+}
+
+// Add records that rows were written, encoded as bytes of line protocol.
+func (s *WriteStatistics) Add(rows, bytes int64) {
+	atomic.AddInt64(&s.rows, rows)
+	atomic.AddInt64(&s.bytes, bytes)
+}
+
+// Metadata returns the statistics as flux metadata.
+func (s *WriteStatistics) Metadata() flux.Metadata {
+	return flux.Metadata{
+		WrittenRowsMetadataKey:  []interface{}{atomic.LoadInt64(&s.rows)},
+		WrittenBytesMetadataKey: []interface{}{atomic.LoadInt64(&s.bytes)},
+	}
+}
+
+type writeStatisticsContextKey struct{}
+
+// ContextWithWriteStatistics returns a new context with a reference to the write statistics of a query.
+func ContextWithWriteStatistics(ctx context.Context, s *WriteStatistics) context.Context {
+	return context.WithValue(ctx, writeStatisticsContextKey{}, s)
+}
+
+// WriteStatisticsFromContext retrieves the write statistics of the query from the context.
+// If there are none, it returns nil.
+func WriteStatisticsFromContext(ctx context.Context) *WriteStatistics {
+	s, _ := ctx.Value(writeStatisticsContextKey{}).(*WriteStatistics)
+	return s
+}
+
+// SumMetadata returns the sum of all integer values recorded under key in md.
+func SumMetadata(md flux.Metadata, key string) int64 {
+	var sum int64
+	for _, v := range md[key] {
+		switch v := v.(type) {
+		case int64:
+			sum += v
+		case int:
+			sum += int64(v)
+		}
+	}
+	return sum
+}
@@ -1,6 +1,7 @@
 package influxdb_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/influxdata/flux/querytest"
 	"github.com/influxdata/flux/stdlib/universe"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
 	pquerytest "github.com/influxdata/influxdb/query/querytest"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 )
@@ -190,3 +192,83 @@ func TestFromValidation(t *testing.T) {
 		t.Errorf("unexpected error; -want/+got\n- %s\n+ %s", want, got)
 	}
 }
+
+func TestReadRangePhysSpec_LookupBucketID_SystemBuckets(t *testing.T) {
+	orgID := platform.ID(1)
+	auditBucketID := platform.ID(platform.BucketTypeAudit)
+	queryLogBucketID := platform.ID(platform.BucketTypeQueryLog)
+	bucketID := platform.ID(20)
+	bucketRead, err := platform.NewPermissionAtID(bucketID, platform.ReadAction, platform.BucketsResourceType, orgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditRead, err := platform.NewPermission(platform.ReadAction, platform.AuditResourceType, orgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queriesRead, err := platform.NewPermission(platform.ReadAction, platform.QueriesResourceType, orgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		bucketID platform.ID
+		auth     *platform.Authorization
+		wantErr  bool
+	}{
+		{
+			name:     "bucket",
+			bucketID: bucketID,
+			auth:     &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{*bucketRead}},
+		},
+		{
+			name:     "audit bucket without authorization",
+			bucketID: auditBucketID,
+			wantErr:  true,
+		},
+		{
+			name:     "audit bucket with bucket permission",
+			bucketID: auditBucketID,
+			auth:     &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{*bucketRead}},
+			wantErr:  true,
+		},
+		{
+			name:     "audit bucket with audit permission",
+			bucketID: auditBucketID,
+			auth:     &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{*auditRead}},
+		},
+		{
+			name:     "query log bucket with audit permission",
+			bucketID: queryLogBucketID,
+			auth:     &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{*auditRead}},
+			wantErr:  true,
+		},
+		{
+			name:     "query log bucket with queries permission",
+			bucketID: queryLogBucketID,
+			auth:     &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{*queriesRead}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := query.ContextWithRequest(context.Background(), &query.Request{
+				Authorization:  tt.auth,
+				OrganizationID: orgID,
+			})
+			spec := &influxdb.ReadRangePhysSpec{BucketID: tt.bucketID.String()}
+			got, err := spec.LookupBucketID(ctx, orgID, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error reading the system bucket")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tt.bucketID {
+				t.Errorf("unexpected bucket ID: got %s, want %s", got, tt.bucketID)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
 )
 
 const (
@@ -100,6 +101,9 @@ func (s *ReadRangePhysSpec) LookupBucketID(ctx context.Context, orgID influxdb.I
 				Err:  err,
 			}
 		}
+		if err := authorizeSystemBucket(ctx, orgID, b); err != nil {
+			return 0, err
+		}
 		return b, nil
 	default:
 		return 0, &flux.Error{
@@ -109,6 +113,23 @@ func (s *ReadRangePhysSpec) LookupBucketID(ctx context.Context, orgID influxdb.I
 	}
 }
 
+// authorizeSystemBucket ensures the authorization of the query request on ctx may read
+// the system bucket with the ID. The system buckets are not found by the bucket service,
+// so they can only be read by ID and are authorized here instead.
+func authorizeSystemBucket(ctx context.Context, orgID, bucketID influxdb.ID) error {
+	p, ok := influxdb.SystemBucketReadPermission(orgID, bucketID)
+	if !ok {
+		return nil
+	}
+	if req := query.RequestFromContext(ctx); req == nil || req.Authorization == nil || !req.Authorization.Allowed(*p) {
+		return &flux.Error{
+			Code: codes.PermissionDenied,
+			Msg:  fmt.Sprintf("no read permission for system bucket %s", bucketID),
+		}
+	}
+	return nil
+}
+
 // TimeBounds implements plan.BoundsAwareProcedureSpec.
 func (s *ReadRangePhysSpec) TimeBounds(predecessorBounds *plan.Bounds) *plan.Bounds {
 	return &plan.Bounds{
@@ -58,8 +58,8 @@ func (s *Source) AddTransformation(t execute.Transformation) {
 
 func (s *Source) Metadata() flux.Metadata {
 	return flux.Metadata{
-		"influxdb/scanned-bytes":  []interface{}{s.stats.ScannedBytes},
-		"influxdb/scanned-values": []interface{}{s.stats.ScannedValues},
+		query.ScannedBytesMetadataKey:  []interface{}{s.stats.ScannedBytes},
+		query.ScannedValuesMetadataKey: []interface{}{s.stats.ScannedValues},
 	}
 }
 
@@ -20,6 +20,7 @@ import (
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/tsdb"
 )
@@ -333,6 +334,11 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 			Msg:  "You must specify org and bucket",
 		}
 	}
+	var pw storage.PointsWriter = deps.PointsWriter
+	if c := query.WriteCaptureFromContext(ctx); c != nil {
+		// Dry run: record the points instead of writing them.
+		pw = c
+	}
 	return &ToTransformation{
 		Ctx:                ctx,
 		OrgID:              *orgID,
@@ -343,7 +349,7 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 		spec:               toSpec,
 		implicitTagColumns: spec.TagColumns == nil,
 		deps:               deps,
-		buf:                storage.NewBufferedPointsWriter(DefaultBufferSize, deps.PointsWriter),
+		buf:                storage.NewBufferedPointsWriter(DefaultBufferSize, pw),
 	}, nil
 }
 
@@ -665,7 +671,18 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 			}
 		}
 
-		return t.buf.WritePoints(ctx, points)
+		if err := t.buf.WritePoints(ctx, points); err != nil {
+			return err
+		}
+
+		if ws := query.WriteStatisticsFromContext(ctx); ws != nil {
+			var n int
+			for _, pt := range points {
+				n += pt.StringSize()
+			}
+			ws.Add(int64(er.Len()), int64(n))
+		}
+		return nil
 	})
 }
 
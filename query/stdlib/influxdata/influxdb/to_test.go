@@ -15,6 +15,7 @@ import (
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
 	_ "github.com/influxdata/influxdb/query/builtin"
 	pquerytest "github.com/influxdata/influxdb/query/querytest"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
@@ -712,13 +713,15 @@ c _hello=4 41`),
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			deps := mockDependencies()
+			stats := new(query.WriteStatistics)
+			ctx := query.ContextWithWriteStatistics(context.Background(), stats)
 			executetest.ProcessTestHelper(
 				t,
 				tc.data,
 				tc.want.tables,
 				nil,
 				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
-					newT, err := influxdb.NewToTransformation(context.Background(), d, c, tc.spec, deps)
+					newT, err := influxdb.NewToTransformation(ctx, d, c, tc.spec, deps)
 					if err != nil {
 						t.Error(err)
 					}
@@ -736,6 +739,22 @@ c _hello=4 41`),
 			if !cmp.Equal(gotStr, wantStr) {
 				t.Errorf("got other than expected %s", cmp.Diff(gotStr, wantStr))
 			}
+
+			// Every row written is recorded, along with the size of its points in line protocol.
+			var wantRows, wantBytes int64
+			for _, tbl := range tc.want.tables {
+				wantRows += int64(len(tbl.Data))
+			}
+			for _, pt := range tc.want.result.Points {
+				wantBytes += int64(pt.StringSize())
+			}
+			md := stats.Metadata()
+			if got := query.SumMetadata(md, query.WrittenRowsMetadataKey); got != wantRows {
+				t.Errorf("unexpected rows written: got %d, want %d", got, wantRows)
+			}
+			if got := query.SumMetadata(md, query.WrittenBytesMetadataKey); got != wantBytes {
+				t.Errorf("unexpected bytes written: got %d, want %d", got, wantBytes)
+			}
 		})
 	}
 }
@@ -0,0 +1,231 @@
+// Package schema implements the influxdata/influxdb/v1/schema flux package with the transformations
+// used to describe the series of a bucket the way InfluxDB 1.x does.
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/influxdb/models"
+)
+
+const (
+	PackagePath    = "influxdata/influxdb/v1/schema"
+	SeriesKeysKind = "seriesKeys"
+	FieldTypesKind = "fieldTypes"
+)
+
+// source declares the builtin functions of the package.
+const source = `package schema
+
+// SeriesKeys returns the InfluxDB 1.x series key of each table in the key column.
+builtin seriesKeys
+
+// FieldTypes returns the InfluxQL name of the type of the _value column of each table in the fieldType column.
+builtin fieldTypes
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	pkg.Path = PackagePath
+	flux.RegisterPackage(pkg)
+
+	signature := flux.FunctionSignature(map[string]semantic.PolyType{}, nil)
+	for _, kind := range []flux.OperationKind{SeriesKeysKind, FieldTypesKind} {
+		flux.RegisterPackageValue(PackagePath, string(kind), flux.FunctionValue(string(kind), createDescribeOpSpec(kind), signature))
+		flux.RegisterOpSpec(kind, newDescribeOp(kind))
+		plan.RegisterProcedureSpec(plan.ProcedureKind(kind), newDescribeProcedure, kind)
+		execute.RegisterTransformation(plan.ProcedureKind(kind), createDescribeTransformation)
+	}
+}
+
+// DescribeOpSpec describes each table with a single row. The kind of
+// the operation determines the column that describes the table.
+type DescribeOpSpec struct {
+	kind flux.OperationKind
+}
+
+func createDescribeOpSpec(kind flux.OperationKind) flux.CreateOperationSpec {
+	return func(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+		if err := a.AddParentFromArgs(args); err != nil {
+			return nil, err
+		}
+		return &DescribeOpSpec{kind: kind}, nil
+	}
+}
+
+func newDescribeOp(kind flux.OperationKind) flux.NewOperationSpec {
+	return func() flux.OperationSpec {
+		return &DescribeOpSpec{kind: kind}
+	}
+}
+
+func (s *DescribeOpSpec) Kind() flux.OperationKind {
+	return s.kind
+}
+
+type DescribeProcedureSpec struct {
+	plan.DefaultCost
+	kind plan.ProcedureKind
+}
+
+func newDescribeProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*DescribeOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+	return &DescribeProcedureSpec{kind: plan.ProcedureKind(spec.kind)}, nil
+}
+
+func (s *DescribeProcedureSpec) Kind() plan.ProcedureKind {
+	return s.kind
+}
+
+func (s *DescribeProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := new(DescribeProcedureSpec)
+	*ns = *s
+	return ns
+}
+
+// TriggerSpec implements plan.TriggerAwareProcedureSpec
+func (s *DescribeProcedureSpec) TriggerSpec() plan.TriggerSpec {
+	return plan.NarrowTransformationTriggerSpec{}
+}
+
+func createDescribeTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*DescribeProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t, err := NewDescribeTransformation(d, cache, s.kind)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, d, nil
+}
+
+// NewDescribeTransformation returns the transformation describing each table
+// with the series key (SeriesKeysKind) or the field type (FieldTypesKind).
+func NewDescribeTransformation(d execute.Dataset, cache execute.TableBuilderCache, kind plan.ProcedureKind) (execute.Transformation, error) {
+	t := &describeTransformation{
+		d:     d,
+		cache: cache,
+	}
+	switch kind {
+	case SeriesKeysKind:
+		t.column, t.describe = "key", seriesKey
+	case FieldTypesKind:
+		t.column, t.describe = "fieldType", fieldType
+	default:
+		return nil, fmt.Errorf("unknown describe kind %q", kind)
+	}
+	return t, nil
+}
+
+type describeTransformation struct {
+	d     execute.Dataset
+	cache execute.TableBuilderCache
+
+	column   string
+	describe func(tbl flux.Table) (string, error)
+}
+
+func (t *describeTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *describeTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	builder, created := t.cache.TableBuilder(tbl.Key())
+	if !created {
+		return fmt.Errorf("%s found duplicate table with key: %v", t.column, tbl.Key())
+	}
+
+	value, err := t.describe(tbl)
+	if err != nil {
+		return err
+	}
+
+	if err := execute.AddTableKeyCols(tbl.Key(), builder); err != nil {
+		return err
+	}
+	colIdx, err := builder.AddCol(flux.ColMeta{Label: t.column, Type: flux.TString})
+	if err != nil {
+		return err
+	}
+	if err := execute.AppendKeyValues(tbl.Key(), builder); err != nil {
+		return err
+	}
+	if err := builder.AppendString(colIdx, value); err != nil {
+		return err
+	}
+
+	// The rows of the table are not needed, but the table must still be consumed.
+	return tbl.Do(func(flux.ColReader) error {
+		return nil
+	})
+}
+
+func (t *describeTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+
+func (t *describeTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *describeTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
+
+// seriesKey returns the series key of the table. The key is made of the _measurement
+// column and the string columns of the group key that are not prefixed with an underscore,
+// which are the tags of the series.
+func seriesKey(tbl flux.Table) (string, error) {
+	var (
+		name string
+		tags = make(map[string]string)
+	)
+	key := tbl.Key()
+	for j, c := range key.Cols() {
+		if c.Type != flux.TString || key.IsNull(j) {
+			continue
+		}
+		if c.Label == "_measurement" {
+			name = key.ValueString(j)
+		} else if !strings.HasPrefix(c.Label, "_") {
+			tags[c.Label] = key.ValueString(j)
+		}
+	}
+	return string(models.MakeKey([]byte(name), models.NewTags(tags))), nil
+}
+
+// fieldType returns the InfluxQL name of the type of the _value column of the table.
+func fieldType(tbl flux.Table) (string, error) {
+	idx := execute.ColIdx(execute.DefaultValueColLabel, tbl.Cols())
+	if idx < 0 {
+		return "", fmt.Errorf("no column %q exists", execute.DefaultValueColLabel)
+	}
+
+	switch typ := tbl.Cols()[idx].Type; typ {
+	case flux.TFloat:
+		return "float", nil
+	case flux.TInt:
+		return "integer", nil
+	case flux.TUInt:
+		return "unsigned", nil
+	case flux.TString:
+		return "string", nil
+	case flux.TBool:
+		return "boolean", nil
+	default:
+		return "", fmt.Errorf("unsupported field type %s", typ)
+	}
+}
@@ -0,0 +1,165 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/v1/schema"
+)
+
+// seriesTable returns a table of the series of the measurement cpu with the field and value.
+// The tags are the string columns of the group key after the field.
+func seriesTable(field string, value interface{}, typ flux.ColType, tags ...string) *executetest.Table {
+	cols := []flux.ColMeta{
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "_field", Type: flux.TString},
+	}
+	keyCols := []string{"_measurement", "_field"}
+	row := []interface{}{"cpu", field}
+	for i := 0; i < len(tags); i += 2 {
+		cols = append(cols, flux.ColMeta{Label: tags[i], Type: flux.TString})
+		keyCols = append(keyCols, tags[i])
+		row = append(row, tags[i+1])
+	}
+	cols = append(cols,
+		flux.ColMeta{Label: "_time", Type: flux.TTime},
+		flux.ColMeta{Label: "_value", Type: typ},
+	)
+	row = append(row, execute.Time(0), value)
+	return &executetest.Table{
+		KeyCols: keyCols,
+		ColMeta: cols,
+		Data:    [][]interface{}{row},
+	}
+}
+
+// describedTable returns the table describing the series of seriesTable with the value in the column.
+func describedTable(column, value, field string, tags ...string) *executetest.Table {
+	cols := []flux.ColMeta{
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "_field", Type: flux.TString},
+	}
+	keyCols := []string{"_measurement", "_field"}
+	row := []interface{}{"cpu", field}
+	for i := 0; i < len(tags); i += 2 {
+		cols = append(cols, flux.ColMeta{Label: tags[i], Type: flux.TString})
+		keyCols = append(keyCols, tags[i])
+		row = append(row, tags[i+1])
+	}
+	cols = append(cols, flux.ColMeta{Label: column, Type: flux.TString})
+	row = append(row, value)
+	return &executetest.Table{
+		KeyCols: keyCols,
+		ColMeta: cols,
+		Data:    [][]interface{}{row},
+	}
+}
+
+func TestSeriesKeys_Process(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []flux.Table
+		want []*executetest.Table
+	}{
+		{
+			name: "series key from group key",
+			data: []flux.Table{
+				seriesTable("usage", 1.0, flux.TFloat, "region", "west", "host", "a"),
+				seriesTable("usage", 2.0, flux.TFloat),
+			},
+			want: []*executetest.Table{
+				describedTable("key", "cpu,host=a,region=west", "usage", "region", "west", "host", "a"),
+				describedTable("key", "cpu", "usage"),
+			},
+		},
+		{
+			name: "multiple fields per series",
+			data: []flux.Table{
+				seriesTable("usage", 1.0, flux.TFloat, "host", "a"),
+				seriesTable("state", "idle", flux.TString, "host", "a"),
+			},
+			want: []*executetest.Table{
+				describedTable("key", "cpu,host=a", "usage", "host", "a"),
+				describedTable("key", "cpu,host=a", "state", "host", "a"),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			executetest.ProcessTestHelper(
+				t,
+				tc.data,
+				tc.want,
+				nil,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					tr, err := schema.NewDescribeTransformation(d, c, plan.ProcedureKind(schema.SeriesKeysKind))
+					if err != nil {
+						t.Fatal(err)
+					}
+					return tr
+				},
+			)
+		})
+	}
+}
+
+func TestFieldTypes_Process(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []flux.Table
+		want []*executetest.Table
+	}{
+		{
+			name: "field type names",
+			data: []flux.Table{
+				seriesTable("f", 1.0, flux.TFloat),
+				seriesTable("i", int64(1), flux.TInt),
+				seriesTable("u", uint64(1), flux.TUInt),
+				seriesTable("s", "a", flux.TString),
+				seriesTable("b", true, flux.TBool),
+			},
+			want: []*executetest.Table{
+				describedTable("fieldType", "float", "f"),
+				describedTable("fieldType", "integer", "i"),
+				describedTable("fieldType", "unsigned", "u"),
+				describedTable("fieldType", "string", "s"),
+				describedTable("fieldType", "boolean", "b"),
+			},
+		},
+		{
+			name: "multiple fields per series",
+			data: []flux.Table{
+				seriesTable("usage", 1.0, flux.TFloat, "host", "a"),
+				seriesTable("state", "idle", flux.TString, "host", "a"),
+				seriesTable("count", int64(3), flux.TInt, "host", "a"),
+			},
+			want: []*executetest.Table{
+				describedTable("fieldType", "float", "usage", "host", "a"),
+				describedTable("fieldType", "string", "state", "host", "a"),
+				describedTable("fieldType", "integer", "count", "host", "a"),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			executetest.ProcessTestHelper(
+				t,
+				tc.data,
+				tc.want,
+				nil,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					tr, err := schema.NewDescribeTransformation(d, c, plan.ProcedureKind(schema.FieldTypesKind))
+					if err != nil {
+						t.Fatal(err)
+					}
+					return tr
+				},
+			)
+		})
+	}
+}
@@ -4,5 +4,6 @@ package stdlib
 import (
 	_ "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 	_ "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/v1"
+	_ "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/v1/schema"
 	_ "github.com/influxdata/influxdb/query/stdlib/testing"
 )
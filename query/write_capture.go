@@ -0,0 +1,44 @@
+package query
+
+import (
+	"context"
+	"sync"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// WriteCapture collects the points a query writes, instead of writing them to storage.
+// It is used to run a query without side effects. It is safe for concurrent use.
+type WriteCapture struct {
+	mu     sync.Mutex
+	points []models.Point
+}
+
+// WritePoints records points.
+func (c *WriteCapture) WritePoints(ctx context.Context, points []models.Point) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.points = append(c.points, points...)
+	return nil
+}
+
+// Points returns the points recorded so far.
+func (c *WriteCapture) Points() []models.Point {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.points
+}
+
+type writeCaptureContextKey struct{}
+
+// ContextWithWriteCapture returns a new context that makes the query record its writes to c instead of writing them.
+func ContextWithWriteCapture(ctx context.Context, c *WriteCapture) context.Context {
+	return context.WithValue(ctx, writeCaptureContextKey{}, c)
+}
+
+// WriteCaptureFromContext retrieves the write capture of the query from the context.
+// If there is none, the query writes to storage and it returns nil.
+func WriteCaptureFromContext(ctx context.Context) *WriteCapture {
+	c, _ := ctx.Value(writeCaptureContextKey{}).(*WriteCapture)
+	return c
+}
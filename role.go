@@ -0,0 +1,170 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// ErrRoleNotFound is the error for a missing Role.
+const ErrRoleNotFound = "role not found"
+
+// ErrRoleBindingNotFound is the error for a missing RoleBinding.
+const ErrRoleBindingNotFound = "role binding not found"
+
+const (
+	OpFindRoleByID      = "FindRoleByID"
+	OpFindRoles         = "FindRoles"
+	OpCreateRole        = "CreateRole"
+	OpUpdateRole        = "UpdateRole"
+	OpDeleteRole        = "DeleteRole"
+	OpFindRoleBindings  = "FindRoleBindings"
+	OpCreateRoleBinding = "CreateRoleBinding"
+	OpDeleteRoleBinding = "DeleteRoleBinding"
+)
+
+// RoleService represents a service for managing roles and their bindings.
+type RoleService interface {
+	// FindRoleByID returns a single role by ID.
+	FindRoleByID(ctx context.Context, id ID) (*Role, error)
+
+	// FindRoles returns a list of roles that match filter and the total count of matching roles.
+	FindRoles(ctx context.Context, filter RoleFilter, opt ...FindOptions) ([]*Role, int, error)
+
+	// CreateRole creates a new role and sets r.ID with the new identifier.
+	CreateRole(ctx context.Context, r *Role) error
+
+	// UpdateRole updates a role with a changeset.
+	UpdateRole(ctx context.Context, id ID, upd RoleUpdate) (*Role, error)
+
+	// DeleteRole deletes a role and its bindings.
+	DeleteRole(ctx context.Context, id ID) error
+
+	// FindRoleBindings returns a list of role bindings that match filter.
+	FindRoleBindings(ctx context.Context, filter RoleBindingFilter) ([]*RoleBinding, error)
+
+	// CreateRoleBinding binds a user or a group to a role.
+	CreateRoleBinding(ctx context.Context, b *RoleBinding) error
+
+	// DeleteRoleBinding unbinds a user or a group from a role.
+	DeleteRoleBinding(ctx context.Context, roleID, userID ID) error
+}
+
+// Role is a named set of permissions within an organization.
+// Users bound to a role are granted its permissions.
+// Permissions apply to a type of resource or to a single resource: they cannot select
+// resources by label, since the authorizer checks permissions without looking up labels.
+type Role struct {
+	ID          ID           `json:"id,omitempty"`
+	OrgID       ID           `json:"orgID,omitempty"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Validate returns an error if the role is invalid.
+// The permissions of a role are restricted to the resources of its organization.
+func (r *Role) Validate() error {
+	if r.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role name is required",
+		}
+	}
+
+	if !r.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+
+	for i := range r.Permissions {
+		p := r.Permissions[i]
+		if err := p.Valid(); err != nil {
+			return err
+		}
+		if !r.inOrg(p) {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "permission " + p.String() + " is outside of the organization of the role",
+			}
+		}
+	}
+
+	return nil
+}
+
+// inOrg returns whether the permission p only applies to resources of the organization of the role.
+func (r *Role) inOrg(p Permission) bool {
+	if p.Resource.Type == OrgsResourceType {
+		return p.Resource.ID != nil && *p.Resource.ID == r.OrgID
+	}
+	return p.Resource.OrgID != nil && *p.Resource.OrgID == r.OrgID
+}
+
+// RoleUpdate represents a changeset for a role.
+// Only the properties specified are updated.
+type RoleUpdate struct {
+	Name        *string       `json:"name,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Permissions *[]Permission `json:"permissions,omitempty"`
+}
+
+// Apply applies the changeset to r.
+func (u RoleUpdate) Apply(r *Role) {
+	if u.Name != nil {
+		r.Name = *u.Name
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.Permissions != nil {
+		r.Permissions = *u.Permissions
+	}
+}
+
+// RoleFilter represents a set of filters that restrict the returned results.
+type RoleFilter struct {
+	ID    *ID
+	Name  *string
+	OrgID *ID
+}
+
+// RoleBinding binds a user or a group to a role.
+// The MappingType of the binding is GroupMappingType when it binds a group,
+// in which case the UserID of the binding is the ID of the group.
+type RoleBinding struct {
+	RoleID      ID          `json:"roleID"`
+	UserID      ID          `json:"userID"`
+	MappingType MappingType `json:"mappingType"`
+}
+
+// Validate returns an error if the binding is invalid.
+func (b *RoleBinding) Validate() error {
+	if !b.RoleID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role id is required",
+		}
+	}
+	if !b.UserID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "user id is required",
+		}
+	}
+	switch b.MappingType {
+	case UserMappingType, GroupMappingType:
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role binding must bind a user or a group",
+		}
+	}
+	return nil
+}
+
+// RoleBindingFilter represents a set of filters that restrict the returned results.
+type RoleBindingFilter struct {
+	RoleID *ID
+	UserID *ID
+}
@@ -0,0 +1,56 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ErrRunningQueryNotFound is returned when a running query cannot be found.
+var ErrRunningQueryNotFound = &Error{
+	Code: ENotFound,
+	Msg:  "query not found",
+}
+
+// RunningQuery describes a query that is being compiled, queued or executed.
+type RunningQuery struct {
+	// ID is an ephemeral identifier of the query; it is not reused while the server runs.
+	ID    ID `json:"id"`
+	OrgID ID `json:"orgID"`
+	// UserID and AuthorizationID identify the authorization the query was submitted with, if any.
+	UserID          ID `json:"userID,omitempty"`
+	AuthorizationID ID `json:"authorizationID,omitempty"`
+
+	// CompilerType is the language of the query, such as flux or influxql.
+	CompilerType string `json:"compilerType"`
+	// Query is the text of the query, if it was submitted as text.
+	Query string `json:"query,omitempty"`
+	State string `json:"state"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	// QueueDuration and ExecuteDuration are the time spent in the queue and executing so far, in nanoseconds.
+	QueueDuration   time.Duration `json:"queueDuration"`
+	ExecuteDuration time.Duration `json:"executeDuration"`
+
+	// MemoryBytes is the table memory currently allocated by the query and
+	// MaxMemoryBytes is the most it has allocated at any point.
+	MemoryBytes    int64 `json:"memoryBytes"`
+	MaxMemoryBytes int64 `json:"maxMemoryBytes"`
+}
+
+// RunningQueryFilter represents a set of filters that restrict the returned running queries.
+type RunningQueryFilter struct {
+	OrgID  *ID
+	UserID *ID
+}
+
+// RunningQueryService lists and kills the queries running on the server.
+type RunningQueryService interface {
+	// FindRunningQueries returns the running queries matching the filter, oldest first.
+	FindRunningQueries(ctx context.Context, filter RunningQueryFilter) ([]*RunningQuery, error)
+
+	// FindRunningQueryByID returns a single running query by ID.
+	FindRunningQueryByID(ctx context.Context, id ID) (*RunningQuery, error)
+
+	// KillRunningQuery cancels a running query.
+	KillRunningQuery(ctx context.Context, id ID) error
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+// AuditSystemBucketID is the fixed system bucket ID audit events are mirrored into.
+const AuditSystemBucketID = platform.ID(platform.BucketTypeAudit)
+
+// AuditLogService wraps an existing platform.AuditLogService implementation.
+//
+// AuditLogService mirrors the appended audit events of organizations as points
+// of the audit measurement into the audit system bucket of the organization.
+type AuditLogService struct {
+	platform.AuditLogService
+	pw     PointsWriter
+	logger *zap.Logger
+}
+
+// NewAuditLogService returns a new AuditLogService writing the mirrored events to pw.
+func NewAuditLogService(s platform.AuditLogService, pw PointsWriter, logger *zap.Logger) *AuditLogService {
+	return &AuditLogService{
+		AuditLogService: s,
+		pw:              pw,
+		logger:          logger,
+	}
+}
+
+// AppendAuditEvent appends an event to the audit log, then mirrors it into the audit system bucket.
+// Failing to mirror the event is logged but does not fail the append.
+func (s *AuditLogService) AppendAuditEvent(ctx context.Context, e *platform.AuditEvent) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := s.AuditLogService.AppendAuditEvent(ctx, e); err != nil {
+		return err
+	}
+
+	if !e.OrgID.Valid() {
+		return nil
+	}
+
+	if err := s.writeAuditEvent(ctx, e); err != nil {
+		s.logger.Error("Failed to mirror audit event", zap.Stringer("id", e.ID), zap.Error(err))
+	}
+	return nil
+}
+
+func (s *AuditLogService) writeAuditEvent(ctx context.Context, e *platform.AuditEvent) error {
+	tags := models.Tags{
+		models.NewTag([]byte("action"), []byte(e.Action)),
+		models.NewTag([]byte("method"), []byte(e.Method)),
+		models.NewTag([]byte("resourceType"), []byte(e.ResourceType)),
+		models.NewTag([]byte("status"), []byte(strconv.Itoa(e.Status))),
+	}
+
+	fields := map[string]interface{}{
+		"id":   e.ID.String(),
+		"path": e.Path,
+	}
+	if e.UserID.Valid() {
+		fields["userID"] = e.UserID.String()
+	}
+	if e.AuthorizationID.Valid() {
+		fields["authorizationID"] = e.AuthorizationID.String()
+	}
+	if e.ResourceID.Valid() {
+		fields["resourceID"] = e.ResourceID.String()
+	}
+	if e.SourceIP != "" {
+		fields["sourceIP"] = e.SourceIP
+	}
+	if len(e.Diff) > 0 {
+		diff, err := json.Marshal(e.Diff)
+		if err != nil {
+			return err
+		}
+		fields["diff"] = string(diff)
+	}
+
+	point, err := models.NewPoint("audit", tags, fields, e.Time)
+	if err != nil {
+		return err
+	}
+
+	points, err := tsdb.ExplodePoints(e.OrgID, AuditSystemBucketID, models.Points{point})
+	if err != nil {
+		return err
+	}
+	return s.pw.WritePoints(ctx, points)
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+// QueryLogSystemBucketID is the fixed system bucket ID logged queries are stored into.
+const QueryLogSystemBucketID = platform.ID(platform.BucketTypeQueryLog)
+
+var _ query.Logger = (*QueryLogger)(nil)
+
+// QueryLogger is a query.Logger that logs the queries and stores them as points
+// of the query measurement into the query log system bucket of their organization.
+type QueryLogger struct {
+	pw     PointsWriter
+	logger *zap.Logger
+}
+
+// NewQueryLogger returns a new QueryLogger writing the logged queries to pw.
+func NewQueryLogger(pw PointsWriter, logger *zap.Logger) *QueryLogger {
+	return &QueryLogger{
+		pw:     pw,
+		logger: logger,
+	}
+}
+
+// Log logs the query, then stores it into the query log system bucket.
+// Failing to store the query is logged and returned.
+func (l *QueryLogger) Log(q query.Log) error {
+	q.Redact()
+
+	text := queryText(q.ProxyRequest)
+	var authID platform.ID
+	if q.ProxyRequest != nil && q.ProxyRequest.Request.Authorization != nil {
+		authID = q.ProxyRequest.Request.Authorization.ID
+	}
+
+	fields := []zap.Field{
+		zap.Stringer("org_id", q.OrganizationID),
+		zap.String("query", text),
+		zap.Duration("total_duration", q.Statistics.TotalDuration),
+		zap.Duration("queue_duration", q.Statistics.QueueDuration),
+		zap.Duration("compile_duration", q.Statistics.CompileDuration),
+		zap.Duration("execute_duration", q.Statistics.ExecuteDuration),
+		zap.Int64("max_allocated", q.Statistics.MaxAllocated),
+		zap.Int64("response_size", q.ResponseSize),
+	}
+	if authID.Valid() {
+		fields = append(fields, zap.Stringer("authorization_id", authID))
+	}
+	if q.Error != nil {
+		fields = append(fields, zap.Error(q.Error))
+	}
+	l.logger.Info("Slow query", fields...)
+
+	if !q.OrganizationID.Valid() {
+		return nil
+	}
+	if err := l.writeQueryLog(q, text, authID); err != nil {
+		l.logger.Error("Failed to store query log", zap.Stringer("org_id", q.OrganizationID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (l *QueryLogger) writeQueryLog(q query.Log, text string, authID platform.ID) error {
+	status := "success"
+	if q.Error != nil {
+		status = "error"
+	}
+	tags := models.Tags{
+		models.NewTag([]byte("status"), []byte(status)),
+	}
+
+	s := q.Statistics
+	fields := map[string]interface{}{
+		"orgID":           q.OrganizationID.String(),
+		"query":           text,
+		"totalDuration":   int64(s.TotalDuration),
+		"queueDuration":   int64(s.QueueDuration),
+		"compileDuration": int64(s.CompileDuration),
+		"planDuration":    int64(s.PlanDuration),
+		"requeueDuration": int64(s.RequeueDuration),
+		"executeDuration": int64(s.ExecuteDuration),
+		"concurrency":     int64(s.Concurrency),
+		"maxAllocated":    s.MaxAllocated,
+		"responseSize":    q.ResponseSize,
+	}
+	if authID.Valid() {
+		fields["authorizationID"] = authID.String()
+	}
+	if q.Error != nil {
+		fields["error"] = q.Error.Error()
+	}
+
+	point, err := models.NewPoint("query", tags, fields, q.Time)
+	if err != nil {
+		return err
+	}
+
+	points, err := tsdb.ExplodePoints(q.OrganizationID, QueryLogSystemBucketID, models.Points{point})
+	if err != nil {
+		return err
+	}
+	// The query has already completed, so its context may be done.
+	return l.pw.WritePoints(context.Background(), points)
+}
+
+// queryText returns the text of the query of a request.
+// Compilers without a text representation are encoded as JSON.
+func queryText(req *query.ProxyRequest) string {
+	if req == nil || req.Request.Compiler == nil {
+		return ""
+	}
+	if text := query.CompilerText(req.Request.Compiler); text != "" {
+		return text
+	}
+	b, err := json.Marshal(req.Request.Compiler)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,90 @@
+package storage_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+func TestQueryLogger_Log(t *testing.T) {
+	pw := &mock.PointsWriter{}
+	l := storage.NewQueryLogger(pw, zap.NewNop())
+
+	orgID := influxdb.ID(1)
+	now := time.Unix(0, 10)
+	err := l.Log(query.Log{
+		Time:           now,
+		OrganizationID: orgID,
+		Error:          errors.New("out of memory"),
+		ProxyRequest: &query.ProxyRequest{
+			Request: query.Request{
+				Authorization:  &influxdb.Authorization{ID: 2, Token: "secret"},
+				OrganizationID: orgID,
+				Compiler:       lang.FluxCompiler{Query: `from(bucket: "db")`},
+			},
+		},
+		ResponseSize: 64,
+		Statistics: flux.Statistics{
+			TotalDuration:   5 * time.Second,
+			QueueDuration:   time.Second,
+			CompileDuration: time.Millisecond,
+			ExecuteDuration: 4 * time.Second,
+			Concurrency:     2,
+			MaxAllocated:    1024,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	name := tsdb.EncodeName(orgID, storage.QueryLogSystemBucketID)
+	got := make(map[string]interface{})
+	for _, p := range pw.Points {
+		if !bytes.Equal(p.Name(), name[:]) {
+			t.Errorf("unexpected point name %x", p.Name())
+		}
+		if !p.Time().Equal(now) {
+			t.Errorf("unexpected point time: got %v, want %v", p.Time(), now)
+		}
+		if status := string(p.Tags().Get([]byte("status"))); status != "error" {
+			t.Errorf("unexpected status tag: got %q, want %q", status, "error")
+		}
+		fields, err := p.Fields()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		for k, v := range fields {
+			got[k] = v
+		}
+	}
+
+	want := map[string]interface{}{
+		"orgID":           orgID.String(),
+		"authorizationID": influxdb.ID(2).String(),
+		"query":           `from(bucket: "db")`,
+		"error":           "out of memory",
+		"totalDuration":   int64(5 * time.Second),
+		"queueDuration":   int64(time.Second),
+		"compileDuration": int64(time.Millisecond),
+		"planDuration":    int64(0),
+		"requeueDuration": int64(0),
+		"executeDuration": int64(4 * time.Second),
+		"concurrency":     int64(2),
+		"maxAllocated":    int64(1024),
+		"responseSize":    int64(64),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected fields -want/+got\n%s", diff)
+	}
+}
@@ -321,6 +321,106 @@ func toComparisonOperator(o ast.OperatorKind) (datatypes.Node_Comparison, error)
 	}
 }
 
+// exprToNode transforms the influxql.Expr of a permission predicate to a predicate node.
+func exprToNode(expr influxql.Expr) (*datatypes.Node, error) {
+	switch expr := expr.(type) {
+	case *influxql.ParenExpr:
+		n, err := exprToNode(expr.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return &datatypes.Node{
+			NodeType: datatypes.NodeTypeParenExpression,
+			Children: []*datatypes.Node{n},
+		}, nil
+	case *influxql.BinaryExpr:
+		left, err := exprToNode(expr.LHS)
+		if err != nil {
+			return nil, errors.Wrap(err, "left hand side")
+		}
+		right, err := exprToNode(expr.RHS)
+		if err != nil {
+			return nil, errors.Wrap(err, "right hand side")
+		}
+		children := []*datatypes.Node{left, right}
+
+		switch expr.Op {
+		case influxql.AND:
+			return &datatypes.Node{
+				NodeType: datatypes.NodeTypeLogicalExpression,
+				Value:    &datatypes.Node_Logical_{Logical: datatypes.LogicalAnd},
+				Children: children,
+			}, nil
+		case influxql.OR:
+			return &datatypes.Node{
+				NodeType: datatypes.NodeTypeLogicalExpression,
+				Value:    &datatypes.Node_Logical_{Logical: datatypes.LogicalOr},
+				Children: children,
+			}, nil
+		}
+
+		var op datatypes.Node_Comparison
+		switch expr.Op {
+		case influxql.EQ:
+			op = datatypes.ComparisonEqual
+		case influxql.NEQ:
+			op = datatypes.ComparisonNotEqual
+		case influxql.EQREGEX:
+			op = datatypes.ComparisonRegex
+		case influxql.NEQREGEX:
+			op = datatypes.ComparisonNotRegex
+		default:
+			return nil, fmt.Errorf("unknown operator %v", expr.Op)
+		}
+
+		// The right hand side of a comparison is a string, even when it is double quoted.
+		if ref, ok := expr.RHS.(*influxql.VarRef); ok {
+			children[1] = &datatypes.Node{
+				NodeType: datatypes.NodeTypeLiteral,
+				Value: &datatypes.Node_StringValue{
+					StringValue: ref.Val,
+				},
+			}
+		}
+
+		return &datatypes.Node{
+			NodeType: datatypes.NodeTypeComparisonExpression,
+			Value:    &datatypes.Node_Comparison_{Comparison: op},
+			Children: children,
+		}, nil
+	case *influxql.VarRef:
+		key := expr.Val
+		switch key {
+		case measurementKey:
+			key = models.MeasurementTagKey
+		case fieldKey:
+			key = models.FieldKeyTagKey
+		}
+		return &datatypes.Node{
+			NodeType: datatypes.NodeTypeTagRef,
+			Value: &datatypes.Node_TagRefValue{
+				TagRefValue: key,
+			},
+		}, nil
+	case *influxql.StringLiteral:
+		return &datatypes.Node{
+			NodeType: datatypes.NodeTypeLiteral,
+			Value: &datatypes.Node_StringValue{
+				StringValue: expr.Val,
+			},
+		}, nil
+	case *influxql.RegexLiteral:
+		return &datatypes.Node{
+			NodeType: datatypes.NodeTypeLiteral,
+			Value: &datatypes.Node_RegexValue{
+				RegexValue: expr.Val.String(),
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported influxql expression type %T", expr)
+	}
+}
+
 // NodeToExpr transforms a predicate node to an influxql.Expr.
 func NodeToExpr(node *datatypes.Node, remap map[string]string) (influxql.Expr, error) {
 	v := &nodeToExprVisitor{remap: remap}
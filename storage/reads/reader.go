@@ -10,7 +10,9 @@ import (
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/values"
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/cursors"
@@ -59,6 +61,11 @@ func (r *storeReader) ReadTagKeys(ctx context.Context, spec influxdb.ReadTagKeys
 		predicate = p
 	}
 
+	predicate, err := AuthorizedPredicate(ctx, spec.OrganizationID, spec.BucketID, predicate)
+	if err != nil {
+		return nil, err
+	}
+
 	return &tagKeysIterator{
 		ctx:       ctx,
 		bounds:    spec.Bounds,
@@ -79,6 +86,11 @@ func (r *storeReader) ReadTagValues(ctx context.Context, spec influxdb.ReadTagVa
 		predicate = p
 	}
 
+	predicate, err := AuthorizedPredicate(ctx, spec.OrganizationID, spec.BucketID, predicate)
+	if err != nil {
+		return nil, err
+	}
+
 	return &tagValuesIterator{
 		ctx:       ctx,
 		bounds:    spec.Bounds,
@@ -91,6 +103,67 @@ func (r *storeReader) ReadTagValues(ctx context.Context, spec influxdb.ReadTagVa
 
 func (r *storeReader) Close() {}
 
+// AuthorizedPredicate ANDs the predicates of the permissions reading the bucket
+// of the authorization of the query request on ctx into predicate.
+func AuthorizedPredicate(ctx context.Context, orgID, bucketID platform.ID, predicate *datatypes.Predicate) (*datatypes.Predicate, error) {
+	req := query.RequestFromContext(ctx)
+	if req == nil || req.Authorization == nil {
+		return predicate, nil
+	}
+
+	perm, err := platform.NewPermissionAtID(bucketID, platform.ReadAction, platform.BucketsResourceType, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *datatypes.Node
+	for _, p := range platform.PermissionPredicates(*perm, req.Authorization.Permissions) {
+		expr, err := platform.Permission{Predicate: p}.PredicateExpr()
+		if err != nil {
+			return nil, err
+		}
+		n, err := exprToNode(expr)
+		if err != nil {
+			return nil, err
+		}
+		n = &datatypes.Node{
+			NodeType: datatypes.NodeTypeParenExpression,
+			Children: []*datatypes.Node{n},
+		}
+
+		if root == nil {
+			root = n
+			continue
+		}
+		root = &datatypes.Node{
+			NodeType: datatypes.NodeTypeLogicalExpression,
+			Value:    &datatypes.Node_Logical_{Logical: datatypes.LogicalOr},
+			Children: []*datatypes.Node{root, n},
+		}
+	}
+
+	if root == nil {
+		return predicate, nil
+	}
+	if predicate == nil {
+		return &datatypes.Predicate{Root: root}, nil
+	}
+
+	return &datatypes.Predicate{
+		Root: &datatypes.Node{
+			NodeType: datatypes.NodeTypeLogicalExpression,
+			Value:    &datatypes.Node_Logical_{Logical: datatypes.LogicalAnd},
+			Children: []*datatypes.Node{
+				{
+					NodeType: datatypes.NodeTypeParenExpression,
+					Children: []*datatypes.Node{predicate.Root},
+				},
+				root,
+			},
+		},
+	}, nil
+}
+
 type filterIterator struct {
 	ctx   context.Context
 	s     Store
@@ -122,6 +195,11 @@ func (fi *filterIterator) Do(f func(flux.Table) error) error {
 		predicate = p
 	}
 
+	predicate, err = AuthorizedPredicate(fi.ctx, fi.spec.OrganizationID, fi.spec.BucketID, predicate)
+	if err != nil {
+		return err
+	}
+
 	var req datatypes.ReadFilterRequest
 	req.ReadSource = any
 	req.Predicate = predicate
@@ -244,6 +322,11 @@ func (gi *groupIterator) Do(f func(flux.Table) error) error {
 		predicate = p
 	}
 
+	predicate, err = AuthorizedPredicate(gi.ctx, gi.spec.OrganizationID, gi.spec.BucketID, predicate)
+	if err != nil {
+		return err
+	}
+
 	var req datatypes.ReadGroupRequest
 	req.ReadSource = any
 	req.Predicate = predicate
@@ -0,0 +1,133 @@
+package reads_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/memory"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+type predicateStore struct {
+	predicate *datatypes.Predicate
+}
+
+func (s *predicateStore) ReadFilter(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+	s.predicate = req.Predicate
+	return nil, nil
+}
+
+func (s *predicateStore) ReadGroup(ctx context.Context, req *datatypes.ReadGroupRequest) (reads.GroupResultSet, error) {
+	s.predicate = req.Predicate
+	return nil, nil
+}
+
+func (s *predicateStore) TagKeys(ctx context.Context, req *datatypes.TagKeysRequest) (cursors.StringIterator, error) {
+	s.predicate = req.Predicate
+	return cursors.EmptyStringIterator, nil
+}
+
+func (s *predicateStore) TagValues(ctx context.Context, req *datatypes.TagValuesRequest) (cursors.StringIterator, error) {
+	s.predicate = req.Predicate
+	return cursors.EmptyStringIterator, nil
+}
+
+func (s *predicateStore) GetSource(orgID, bucketID uint64) proto.Message {
+	return &datatypes.Predicate{}
+}
+
+func TestReader_PermissionPredicate(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+	bucketRead := func(predicate string) platform.Permission {
+		return platform.Permission{
+			Action: platform.ReadAction,
+			Resource: platform.Resource{
+				Type:  platform.BucketsResourceType,
+				OrgID: &orgID,
+				ID:    &bucketID,
+			},
+			Predicate: predicate,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		authorization *platform.Authorization
+		want          string
+	}{
+		{
+			name: "no authorization",
+			want: "[none]",
+		},
+		{
+			name: "unrestricted permission",
+			authorization: &platform.Authorization{
+				Permissions: []platform.Permission{bucketRead(""), bucketRead(`tenant="acme"`)},
+			},
+			want: "[none]",
+		},
+		{
+			name: "predicate",
+			authorization: &platform.Authorization{
+				Permissions: []platform.Permission{bucketRead(`_measurement="cpu" AND tenant='acme'`)},
+			},
+			want: "( '\x00' = \"cpu\" AND 'tenant' = \"acme\" )",
+		},
+		{
+			name: "predicates",
+			authorization: &platform.Authorization{
+				Permissions: []platform.Permission{bucketRead(`tenant="acme"`), bucketRead(`host=~/^acme-/`)},
+			},
+			want: `( 'tenant' = "acme" ) OR ( 'host' =~ /^acme-/ )`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authorization != nil {
+				ctx = query.ContextWithRequest(ctx, &query.Request{Authorization: tt.authorization})
+			}
+
+			filter := influxdb.ReadFilterSpec{
+				OrganizationID: orgID,
+				BucketID:       bucketID,
+			}
+			readers := map[string]func(r influxdb.Reader) (influxdb.TableIterator, error){
+				"ReadFilter": func(r influxdb.Reader) (influxdb.TableIterator, error) {
+					return r.ReadFilter(ctx, filter, &memory.Allocator{})
+				},
+				"ReadGroup": func(r influxdb.Reader) (influxdb.TableIterator, error) {
+					return r.ReadGroup(ctx, influxdb.ReadGroupSpec{ReadFilterSpec: filter}, &memory.Allocator{})
+				},
+				"ReadTagKeys": func(r influxdb.Reader) (influxdb.TableIterator, error) {
+					return r.ReadTagKeys(ctx, influxdb.ReadTagKeysSpec{ReadFilterSpec: filter}, &memory.Allocator{})
+				},
+				"ReadTagValues": func(r influxdb.Reader) (influxdb.TableIterator, error) {
+					return r.ReadTagValues(ctx, influxdb.ReadTagValuesSpec{ReadFilterSpec: filter, TagKey: "host"}, &memory.Allocator{})
+				},
+			}
+			for name, read := range readers {
+				s := &predicateStore{}
+				ti, err := read(reads.NewReader(s))
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", name, err)
+				}
+				if err := ti.Do(func(flux.Table) error { return nil }); err != nil {
+					t.Fatalf("%s: unexpected error: %v", name, err)
+				}
+
+				if got := reads.PredicateToExprString(s.predicate); got != tt.want {
+					t.Errorf("%s: unexpected predicate -got/+want\n\t%s\n\t%s", name, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package reads
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
+)
+
+// SchemaMapper looks up the fields and tag keys of a measurement from the series
+// of a Store, the way InfluxDB 1.x resolves wildcards from its index.
+type SchemaMapper struct {
+	s Store
+}
+
+// NewSchemaMapper returns a SchemaMapper that reads the series from s.
+func NewSchemaMapper(s Store) *SchemaMapper {
+	return &SchemaMapper{s: s}
+}
+
+// FieldDimensions returns the fields of the measurement in the bucket with the type of their values
+// and the tag keys of its series. Only the series readable by the authorization of the query request
+// on ctx are considered.
+func (m *SchemaMapper) FieldDimensions(ctx context.Context, orgID, bucketID platform.ID, measurement string) (map[string]influxql.DataType, map[string]struct{}, error) {
+	src := m.s.GetSource(uint64(orgID), uint64(bucketID))
+	any, err := types.MarshalAny(src)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root, err := exprToNode(&influxql.BinaryExpr{
+		Op:  influxql.EQ,
+		LHS: &influxql.VarRef{Val: measurementKey},
+		RHS: &influxql.StringLiteral{Val: measurement},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	predicate, err := AuthorizedPredicate(ctx, orgID, bucketID, &datatypes.Predicate{Root: root})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var req datatypes.ReadFilterRequest
+	req.ReadSource = any
+	req.Predicate = predicate
+	req.Range.Start = models.MinNanoTime
+	req.Range.End = models.MaxNanoTime
+
+	rs, err := m.s.ReadFilter(ctx, &req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := make(map[string]influxql.DataType)
+	dimensions := make(map[string]struct{})
+	if rs == nil {
+		return fields, dimensions, nil
+	}
+	defer rs.Close()
+
+	for rs.Next() {
+		var field string
+		for _, tag := range rs.Tags() {
+			// The series of the storage engine name the measurement and field keys.
+			switch key := string(tag.Key); key {
+			case models.MeasurementTagKey, measurementKey:
+			case models.FieldKeyTagKey, fieldKey:
+				field = string(tag.Value)
+			default:
+				dimensions[key] = struct{}{}
+			}
+		}
+
+		cur := rs.Cursor()
+		if cur == nil {
+			// no data for series key + field combination
+			continue
+		}
+		typ := cursorType(cur)
+		cur.Close()
+
+		// The same field may have a different type in each shard so
+		// use the type with the highest precedence like InfluxDB 1.x.
+		if fields[field].LessThan(typ) {
+			fields[field] = typ
+		}
+	}
+	if err := rs.Err(); err != nil {
+		return nil, nil, err
+	}
+	return fields, dimensions, nil
+}
+
+// cursorType returns the InfluxQL data type of the values produced by the cursor.
+func cursorType(cur cursors.Cursor) influxql.DataType {
+	switch cur.(type) {
+	case cursors.FloatArrayCursor:
+		return influxql.Float
+	case cursors.IntegerArrayCursor:
+		return influxql.Integer
+	case cursors.UnsignedArrayCursor:
+		return influxql.Unsigned
+	case cursors.StringArrayCursor:
+		return influxql.String
+	case cursors.BooleanArrayCursor:
+		return influxql.Boolean
+	default:
+		return influxql.Unknown
+	}
+}
@@ -0,0 +1,101 @@
+package reads_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
+)
+
+type series struct {
+	tags   models.Tags
+	cursor cursors.Cursor
+}
+
+// seriesStore returns the series from ReadFilter.
+type seriesStore struct {
+	predicateStore
+	series []series
+}
+
+func (s *seriesStore) ReadFilter(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+	s.predicate = req.Predicate
+	return &seriesResultSet{series: s.series, i: -1}, nil
+}
+
+type seriesResultSet struct {
+	series []series
+	i      int
+}
+
+func (rs *seriesResultSet) Next() bool {
+	rs.i++
+	return rs.i < len(rs.series)
+}
+
+func (rs *seriesResultSet) Cursor() cursors.Cursor     { return rs.series[rs.i].cursor }
+func (rs *seriesResultSet) Tags() models.Tags          { return rs.series[rs.i].tags }
+func (rs *seriesResultSet) Close()                     {}
+func (rs *seriesResultSet) Err() error                 { return nil }
+func (rs *seriesResultSet) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+type cursor struct{}
+
+func (cursor) Close()                     {}
+func (cursor) Err() error                 { return nil }
+func (cursor) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+type floatCursor struct{ cursor }
+
+func (floatCursor) Next() *cursors.FloatArray { return cursors.NewFloatArrayLen(0) }
+
+type integerCursor struct{ cursor }
+
+func (integerCursor) Next() *cursors.IntegerArray { return cursors.NewIntegerArrayLen(0) }
+
+type stringCursor struct{ cursor }
+
+func (stringCursor) Next() *cursors.StringArray { return cursors.NewStringArrayLen(0) }
+
+func TestSchemaMapper_FieldDimensions(t *testing.T) {
+	seriesTags := func(field string, tags ...string) models.Tags {
+		m := map[string]string{
+			models.MeasurementTagKey: "cpu",
+			models.FieldKeyTagKey:    field,
+		}
+		for i := 0; i < len(tags); i += 2 {
+			m[tags[i]] = tags[i+1]
+		}
+		return models.NewTags(m)
+	}
+
+	s := &seriesStore{
+		series: []series{
+			{tags: seriesTags("usage", "host", "a"), cursor: integerCursor{}},
+			{tags: seriesTags("usage", "host", "b"), cursor: floatCursor{}},
+			{tags: seriesTags("state", "region", "west"), cursor: stringCursor{}},
+			{tags: seriesTags("idle", "host", "c")},
+			{tags: models.NewTags(map[string]string{"_measurement": "cpu", "_field": "load", "host": "d"}), cursor: floatCursor{}},
+		},
+	}
+
+	fields, dimensions, err := reads.NewSchemaMapper(s).FieldDimensions(context.Background(), 1, 2, "cpu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := reads.PredicateToExprString(s.predicate), "'\x00' = \"cpu\""; got != want {
+		t.Errorf("unexpected predicate -got/+want\n\t%s\n\t%s", got, want)
+	}
+	if want := map[string]influxql.DataType{"usage": influxql.Float, "state": influxql.String, "load": influxql.Float}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("unexpected fields -got/+want\n\t%v\n\t%v", fields, want)
+	}
+	if want := map[string]struct{}{"host": {}, "region": {}}; !reflect.DeepEqual(dimensions, want) {
+		t.Errorf("unexpected dimensions -got/+want\n\t%v\n\t%v", dimensions, want)
+	}
+}
@@ -0,0 +1,112 @@
+package readservice
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+// DefaultRetentionPolicy is the retention policy the buckets are mapped to.
+const DefaultRetentionPolicy = "autogen"
+
+var _ platform.DBRPMappingService = (*dbrpMappingService)(nil)
+
+// dbrpMappingService derives the dbrp mappings from the buckets of the organization
+// of the query request on the context.
+type dbrpMappingService struct {
+	bucketSvc platform.BucketService
+}
+
+// NewDBRPMappingService returns a DBRPMappingService that maps every bucket to the database
+// of the same name with the autogen retention policy, within the organization of the query
+// request on the context. The mappings cannot be created or deleted.
+func NewDBRPMappingService(bucketSvc platform.BucketService) platform.DBRPMappingService {
+	return &dbrpMappingService{bucketSvc: bucketSvc}
+}
+
+// FindBy returns the dbrp mapping for the cluster, db and rp.
+func (s *dbrpMappingService) FindBy(ctx context.Context, cluster, db, rp string) (*platform.DBRPMapping, error) {
+	return s.Find(ctx, platform.DBRPMappingFilter{
+		Cluster:         &cluster,
+		Database:        &db,
+		RetentionPolicy: &rp,
+	})
+}
+
+// Find returns the mapping of the bucket named after the database of the filter.
+func (s *dbrpMappingService) Find(ctx context.Context, filter platform.DBRPMappingFilter) (*platform.DBRPMapping, error) {
+	ms, _, err := s.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(ms) == 0 {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  "unable to find DBRP mapping",
+		}
+	}
+	return ms[0], nil
+}
+
+// FindMany returns the mappings of the buckets matching the filter.
+func (s *dbrpMappingService) FindMany(ctx context.Context, filter platform.DBRPMappingFilter, opt ...platform.FindOptions) ([]*platform.DBRPMapping, int, error) {
+	req := query.RequestFromContext(ctx)
+	if req == nil || !req.OrganizationID.Valid() {
+		return nil, 0, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "no organization on the query request",
+		}
+	}
+	if filter.RetentionPolicy != nil && *filter.RetentionPolicy != DefaultRetentionPolicy {
+		return nil, 0, nil
+	}
+	if filter.Default != nil && !*filter.Default && filter.RetentionPolicy == nil {
+		return nil, 0, nil
+	}
+
+	bf := platform.BucketFilter{OrganizationID: &req.OrganizationID}
+	if filter.Database != nil {
+		bf.Name = filter.Database
+	}
+	bs, _, err := s.bucketSvc.FindBuckets(ctx, bf, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ms := make([]*platform.DBRPMapping, 0, len(bs))
+	for _, b := range bs {
+		ms = append(ms, &platform.DBRPMapping{
+			Cluster:         stringValue(filter.Cluster),
+			Database:        b.Name,
+			RetentionPolicy: DefaultRetentionPolicy,
+			Default:         true,
+			OrganizationID:  b.OrgID,
+			BucketID:        b.ID,
+		})
+	}
+	return ms, len(ms), nil
+}
+
+// Create returns an error as the mappings are derived from the buckets.
+func (s *dbrpMappingService) Create(ctx context.Context, m *platform.DBRPMapping) error {
+	return &platform.Error{
+		Code: platform.EMethodNotAllowed,
+		Msg:  "DBRP mappings are derived from the buckets and cannot be created",
+	}
+}
+
+// Delete returns an error as the mappings are derived from the buckets.
+func (s *dbrpMappingService) Delete(ctx context.Context, cluster, db, rp string) error {
+	return &platform.Error{
+		Code: platform.EMethodNotAllowed,
+		Msg:  "DBRP mappings are derived from the buckets and cannot be deleted",
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -60,14 +60,33 @@ func (t *Task) EffectiveCron() string {
 
 // Run is a record created when a run of a task is scheduled.
 type Run struct {
-	ID           ID     `json:"id,omitempty"`
-	TaskID       ID     `json:"taskID"`
-	Status       string `json:"status"`
-	ScheduledFor string `json:"scheduledFor"`          // ScheduledFor is the time the task is scheduled to run at
-	StartedAt    string `json:"startedAt,omitempty"`   // StartedAt is the time the executor begins running the task
-	FinishedAt   string `json:"finishedAt,omitempty"`  // FinishedAt is the time the executor finishes running the task
-	RequestedAt  string `json:"requestedAt,omitempty"` // RequestedAt is the time the coordinator told the scheduler to schedule the task
-	Log          []Log  `json:"log,omitempty"`
+	ID           ID             `json:"id,omitempty"`
+	TaskID       ID             `json:"taskID"`
+	Status       string         `json:"status"`
+	ScheduledFor string         `json:"scheduledFor"`          // ScheduledFor is the time the task is scheduled to run at
+	StartedAt    string         `json:"startedAt,omitempty"`   // StartedAt is the time the executor begins running the task
+	FinishedAt   string         `json:"finishedAt,omitempty"`  // FinishedAt is the time the executor finishes running the task
+	RequestedAt  string         `json:"requestedAt,omitempty"` // RequestedAt is the time the coordinator told the scheduler to schedule the task
+	Log          []Log          `json:"log,omitempty"`
+	Statistics   *RunStatistics `json:"statistics,omitempty"` // Statistics of the query executed by the run, once it has finished
+}
+
+// RunStatistics describes the work done by the query of a run.
+type RunStatistics struct {
+	// CompileDuration is the time spent compiling the query.
+	CompileDuration time.Duration `json:"compileDuration"`
+	// QueueDuration is the time the query spent queued before executing.
+	QueueDuration time.Duration `json:"queueDuration"`
+	// ExecuteDuration is the time spent executing the query.
+	ExecuteDuration time.Duration `json:"executeDuration"`
+	// MaxAllocated is the maximum number of bytes the query allocated.
+	MaxAllocated int64 `json:"maxAllocated"`
+	// RowsRead is the number of values read from storage.
+	RowsRead int64 `json:"rowsRead"`
+	// RowsWritten is the number of rows written by to().
+	RowsWritten int64 `json:"rowsWritten"`
+	// BytesWritten is the size, in line protocol, of the data written by to().
+	BytesWritten int64 `json:"bytesWritten"`
 }
 
 // ScheduledForTime gives the time.Time that the run is scheduled for.
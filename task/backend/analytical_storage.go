@@ -25,6 +25,7 @@ const (
 	finishedAtField   = "finishedAt"
 	requestedAtField  = "requestedAt"
 	logField          = "logs"
+	statisticsField   = "statistics"
 
 	taskIDTag = "taskID"
 	statusTag = "status"
@@ -95,6 +96,14 @@ func (as *AnalyticalStorage) FinishRun(ctx context.Context, taskID, runID influx
 		}
 		fields[logField] = string(logBytes)
 
+		if run.Statistics != nil {
+			statsBytes, err := json.Marshal(run.Statistics)
+			if err != nil {
+				return run, err
+			}
+			fields[statisticsField] = string(statsBytes)
+		}
+
 		point, err := models.NewPoint("runs", tags, fields, startedAt)
 		if err != nil {
 			return run, err
@@ -384,6 +393,16 @@ func (re *runReader) readRuns(cr flux.ColReader) error {
 						re.logger.Info("failed to parse log data", zap.Error(err), zap.ByteString("log_bytes", logBytes))
 					}
 				}
+			case statisticsField:
+				statsBytes := bytes.TrimSpace(cr.Strings(j).Value(i))
+				if len(statsBytes) != 0 {
+					var stats influxdb.RunStatistics
+					if err := json.Unmarshal(statsBytes, &stats); err != nil {
+						re.logger.Info("failed to parse run statistics", zap.Error(err), zap.ByteString("statistics_bytes", statsBytes))
+					} else {
+						r.Statistics = &stats
+					}
+				}
 			}
 		}
 
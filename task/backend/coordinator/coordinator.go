@@ -3,10 +3,12 @@ package coordinator
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/options"
 	"go.uber.org/zap"
 )
 
@@ -18,6 +20,16 @@ type Coordinator struct {
 
 	limit         int
 	claimExisting bool
+
+	leases   backend.TaskLeaseService
+	owner    string
+	leaseTTL time.Duration
+
+	leasedMu sync.Mutex
+	leased   map[platform.ID]struct{}
+
+	stop    chan struct{}
+	stopped chan struct{}
 }
 
 type Option func(*Coordinator)
@@ -35,6 +47,18 @@ func WithoutExistingTasks() Option {
 	}
 }
 
+// WithLeases makes the coordinator share the tasks with other coordinators using the same lease service.
+// The coordinator then only claims the tasks it holds a lease on, identifying itself as owner.
+// It renews its leases every third of ttl, takes over tasks whose leases expired
+// and gives up tasks when it owns more than its fair share.
+func WithLeases(ls backend.TaskLeaseService, owner string, ttl time.Duration) Option {
+	return func(c *Coordinator) {
+		c.leases = ls
+		c.owner = owner
+		c.leaseTTL = ttl
+	}
+}
+
 func New(logger *zap.Logger, scheduler backend.Scheduler, ts platform.TaskService, opts ...Option) *Coordinator {
 	c := &Coordinator{
 		logger:        logger,
@@ -48,13 +72,38 @@ func New(logger *zap.Logger, scheduler backend.Scheduler, ts platform.TaskServic
 		opt(c)
 	}
 
-	if c.claimExisting {
+	if c.leases != nil {
+		c.leased = make(map[platform.ID]struct{})
+		c.stop = make(chan struct{})
+		c.stopped = make(chan struct{})
+		go c.maintainLeases()
+	} else if c.claimExisting {
 		go c.claimExistingTasks()
 	}
 
 	return c
 }
 
+// Stop stops maintaining leases, and releases the tasks this coordinator owns so other coordinators can take them over.
+// It is a no-op for a coordinator that does not use leases.
+func (c *Coordinator) Stop() {
+	if c.leases == nil {
+		return
+	}
+	close(c.stop)
+	<-c.stopped
+
+	ctx := context.Background()
+	c.leasedMu.Lock()
+	defer c.leasedMu.Unlock()
+	for id := range c.leased {
+		c.dropTask(ctx, id)
+	}
+	if err := c.leases.UnregisterTaskOwner(ctx, c.owner); err != nil {
+		c.logger.Error("failed to unregister task owner", zap.Error(err))
+	}
+}
+
 // claimExistingTasks is called on startup to claim all tasks in the store.
 func (c *Coordinator) claimExistingTasks() {
 	tasks, _, err := c.TaskService.FindTasks(context.Background(), platform.TaskFilter{})
@@ -64,11 +113,7 @@ func (c *Coordinator) claimExistingTasks() {
 	newLatestCompleted := time.Now().UTC().Format(time.RFC3339)
 	for len(tasks) > 0 {
 		for _, task := range tasks {
-
-			task, err := c.TaskService.UpdateTask(context.Background(), task.ID, platform.TaskUpdate{LatestCompleted: &newLatestCompleted})
-			if err != nil {
-				c.logger.Error("failed to set latestCompleted", zap.Error(err))
-			}
+			task = c.skipMissedRuns(task, newLatestCompleted)
 
 			if task.Status != string(backend.TaskActive) {
 				// Don't claim inactive tasks at startup.
@@ -91,13 +136,48 @@ func (c *Coordinator) claimExistingTasks() {
 	}
 }
 
+// skipMissedRuns moves the latest completed time of the task to newLatestCompleted, if it does not catch up on missed runs.
+// It returns the updated task, or task itself if it was not updated.
+func (c *Coordinator) skipMissedRuns(task *platform.Task, newLatestCompleted string) *platform.Task {
+	if !skipsMissedRuns(task) {
+		return task
+	}
+	t, err := c.TaskService.UpdateTask(context.Background(), task.ID, platform.TaskUpdate{LatestCompleted: &newLatestCompleted})
+	if err != nil {
+		c.logger.Error("failed to set latestCompleted", zap.Error(err))
+		return task
+	}
+	return t
+}
+
+// skipsMissedRuns reports whether the task resumes from now rather than catching up when it is claimed.
+// Tasks that do not set a catchup policy, or whose script cannot be parsed, skip missed runs.
+// Other policies are applied by the scheduler when the task is claimed.
+func skipsMissedRuns(task *platform.Task) bool {
+	opt, err := options.FromScript(task.Flux)
+	if err != nil {
+		return true
+	}
+	return opt.Catchup == "" || opt.Catchup == options.CatchupNone
+}
+
 func (c *Coordinator) CreateTask(ctx context.Context, t platform.TaskCreate) (*platform.Task, error) {
 	task, err := c.TaskService.CreateTask(ctx, t)
 	if err != nil {
 		return task, err
 	}
 
+	if c.leases != nil {
+		if !c.acquireLease(ctx, task.ID) {
+			// Another coordinator will claim the task.
+			return task, nil
+		}
+	}
+
 	if err := c.sch.ClaimTask(ctx, task); err != nil {
+		if c.leases != nil {
+			c.releaseLease(ctx, task.ID)
+		}
 		delErr := c.TaskService.DeleteTask(ctx, task.ID)
 		if delErr != nil {
 			return task, fmt.Errorf("schedule task failed: %s\n\tcleanup also failed: %s", err, delErr)
@@ -124,6 +204,9 @@ func (c *Coordinator) UpdateTask(ctx context.Context, id platform.ID, upd platfo
 		if err := c.sch.ReleaseTask(id); err != nil && err != platform.ErrTaskNotClaimed {
 			return task, err
 		}
+		if c.leases != nil {
+			c.releaseLease(ctx, id)
+		}
 	}
 
 	if err := c.sch.UpdateTask(ctx, task); err != nil && err != platform.ErrTaskNotClaimed {
@@ -132,14 +215,24 @@ func (c *Coordinator) UpdateTask(ctx context.Context, id platform.ID, upd platfo
 
 	// If enabling the task, claim it after modifying the script.
 	if task.Status != oldTask.Status && task.Status == string(backend.TaskActive) {
-		// don't catch up on all the missed task runs while disabled
-		newLatestCompleted := c.sch.Now().UTC().Format(time.RFC3339)
-		task, err := c.TaskService.UpdateTask(ctx, task.ID, platform.TaskUpdate{LatestCompleted: &newLatestCompleted})
-		if err != nil {
-			return task, err
+		// don't catch up on the missed task runs while disabled, unless the task asks for it
+		if skipsMissedRuns(task) {
+			newLatestCompleted := c.sch.Now().UTC().Format(time.RFC3339)
+			task, err = c.TaskService.UpdateTask(ctx, task.ID, platform.TaskUpdate{LatestCompleted: &newLatestCompleted})
+			if err != nil {
+				return task, err
+			}
+		}
+
+		if c.leases != nil && !c.acquireLease(ctx, task.ID) {
+			// Another coordinator will claim the task.
+			return task, nil
 		}
 
 		if err := c.sch.ClaimTask(ctx, task); err != nil && err != platform.ErrTaskAlreadyClaimed {
+			if c.leases != nil {
+				c.releaseLease(ctx, task.ID)
+			}
 			return task, err
 		}
 	}
@@ -151,6 +244,9 @@ func (c *Coordinator) DeleteTask(ctx context.Context, id platform.ID) error {
 	if err := c.sch.ReleaseTask(id); err != nil && err != platform.ErrTaskNotClaimed {
 		return err
 	}
+	if c.leases != nil {
+		c.releaseLease(ctx, id)
+	}
 
 	return c.TaskService.DeleteTask(ctx, id)
 }
@@ -177,7 +273,7 @@ func (c *Coordinator) RetryRun(ctx context.Context, taskID, runID platform.ID) (
 		return r, err
 	}
 
-	return r, c.sch.UpdateTask(ctx, task)
+	return r, c.updateClaimedTask(ctx, task)
 }
 
 func (c *Coordinator) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64) (*platform.Run, error) {
@@ -191,5 +287,15 @@ func (c *Coordinator) ForceRun(ctx context.Context, taskID platform.ID, schedule
 		return r, err
 	}
 
-	return r, c.sch.UpdateTask(ctx, task)
+	return r, c.updateClaimedTask(ctx, task)
+}
+
+// updateClaimedTask updates the task in the scheduler.
+// When leases are used, the task may be claimed by another coordinator, which picks the update up when renewing its lease.
+func (c *Coordinator) updateClaimedTask(ctx context.Context, task *platform.Task) error {
+	err := c.sch.UpdateTask(ctx, task)
+	if err == platform.ErrTaskNotClaimed && c.leases != nil {
+		return nil
+	}
+	return err
 }
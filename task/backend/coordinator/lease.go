@@ -0,0 +1,193 @@
+package coordinator
+
+import (
+	"context"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend"
+	"go.uber.org/zap"
+)
+
+// maintainLeases periodically balances the task leases until the coordinator is stopped.
+func (c *Coordinator) maintainLeases() {
+	defer close(c.stopped)
+
+	ticker := time.NewTicker(c.leaseTTL / 3)
+	defer ticker.Stop()
+
+	// On the first pass, the tasks this coordinator takes over are treated as if the process had just started,
+	// so tasks that do not catch up skip the runs missed while no coordinator owned them.
+	c.balanceLeases(context.Background(), true)
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.balanceLeases(context.Background(), false)
+		}
+	}
+}
+
+// balanceLeases renews the leases of this coordinator, releases the tasks above its fair share
+// and takes over unowned tasks up to its fair share.
+// The fair share is the number of active tasks divided by the number of live owners, rounded up.
+func (c *Coordinator) balanceLeases(ctx context.Context, startup bool) {
+	if err := c.leases.RegisterTaskOwner(ctx, c.owner, c.leaseTTL); err != nil {
+		c.logger.Error("failed to register task owner", zap.Error(err))
+		return
+	}
+
+	owners, err := c.leases.FindTaskOwners(ctx)
+	if err != nil {
+		c.logger.Error("failed to list task owners", zap.Error(err))
+		return
+	}
+	n := len(owners)
+	if !containsOwner(owners, c.owner) {
+		n++
+	}
+
+	tasks, err := c.findActiveTasks(ctx)
+	if err != nil {
+		c.logger.Error("failed to list tasks", zap.Error(err))
+		return
+	}
+	share := (len(tasks) + n - 1) / n
+
+	c.leasedMu.Lock()
+	defer c.leasedMu.Unlock()
+
+	// Drop the tasks that were deleted or disabled.
+	active := make(map[platform.ID]struct{}, len(tasks))
+	for _, task := range tasks {
+		active[task.ID] = struct{}{}
+	}
+	for id := range c.leased {
+		if _, ok := active[id]; !ok {
+			c.dropTask(ctx, id)
+		}
+	}
+
+	// Renew the leases we hold, giving up the tasks above our share.
+	for _, task := range tasks {
+		if _, ok := c.leased[task.ID]; !ok {
+			continue
+		}
+		if len(c.leased) > share {
+			c.logger.Debug("Releasing task above fair share", zap.String("taskID", task.ID.String()))
+			c.dropTask(ctx, task.ID)
+			continue
+		}
+
+		if _, err := c.leases.AcquireTaskLease(ctx, task.ID, c.owner, c.leaseTTL); err != nil {
+			// The lease expired and was taken over, or cannot be renewed:
+			// stop running the task so that it is not run twice.
+			c.logger.Info("Lost task lease", zap.String("taskID", task.ID.String()), zap.Error(err))
+			c.dropTask(ctx, task.ID)
+			continue
+		}
+
+		// Pick up updates made through other coordinators.
+		if err := c.sch.UpdateTask(ctx, task); err != nil && err != platform.ErrTaskNotClaimed {
+			c.logger.Error("failed to update task", zap.String("taskID", task.ID.String()), zap.Error(err))
+		}
+	}
+
+	// Take over the tasks nobody owns.
+	newLatestCompleted := time.Now().UTC().Format(time.RFC3339)
+	for _, task := range tasks {
+		if len(c.leased) >= share {
+			break
+		}
+		if _, ok := c.leased[task.ID]; ok {
+			continue
+		}
+
+		if _, err := c.leases.AcquireTaskLease(ctx, task.ID, c.owner, c.leaseTTL); err != nil {
+			if err != platform.ErrTaskLeaseHeld {
+				c.logger.Error("failed to acquire task lease", zap.String("taskID", task.ID.String()), zap.Error(err))
+			}
+			continue
+		}
+		c.leased[task.ID] = struct{}{}
+
+		if startup {
+			task = c.skipMissedRuns(task, newLatestCompleted)
+		}
+		if err := c.sch.ClaimTask(ctx, task); err != nil && err != platform.ErrTaskAlreadyClaimed {
+			c.logger.Error("failed claim task", zap.String("taskID", task.ID.String()), zap.Error(err))
+			c.dropTask(ctx, task.ID)
+		}
+	}
+}
+
+// findActiveTasks lists all the active tasks.
+func (c *Coordinator) findActiveTasks(ctx context.Context) ([]*platform.Task, error) {
+	var active []*platform.Task
+	tasks, _, err := c.TaskService.FindTasks(ctx, platform.TaskFilter{})
+	for len(tasks) > 0 {
+		for _, task := range tasks {
+			if task.Status == string(backend.TaskActive) {
+				active = append(active, task)
+			}
+		}
+		tasks, _, err = c.TaskService.FindTasks(ctx, platform.TaskFilter{
+			After: &tasks[len(tasks)-1].ID,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return active, nil
+}
+
+// acquireLease takes the lease on a task, and reports whether it succeeded.
+func (c *Coordinator) acquireLease(ctx context.Context, id platform.ID) bool {
+	c.leasedMu.Lock()
+	defer c.leasedMu.Unlock()
+
+	if _, err := c.leases.AcquireTaskLease(ctx, id, c.owner, c.leaseTTL); err != nil {
+		if err != platform.ErrTaskLeaseHeld {
+			c.logger.Error("failed to acquire task lease", zap.String("taskID", id.String()), zap.Error(err))
+		}
+		return false
+	}
+	c.leased[id] = struct{}{}
+	return true
+}
+
+// releaseLease gives up the lease on a task.
+func (c *Coordinator) releaseLease(ctx context.Context, id platform.ID) {
+	c.leasedMu.Lock()
+	defer c.leasedMu.Unlock()
+
+	c.releaseLeased(ctx, id)
+}
+
+// dropTask releases the task from the scheduler and gives up its lease.
+// The caller must hold leasedMu.
+func (c *Coordinator) dropTask(ctx context.Context, id platform.ID) {
+	if err := c.sch.ReleaseTask(id); err != nil && err != platform.ErrTaskNotClaimed {
+		c.logger.Error("failed to release task", zap.String("taskID", id.String()), zap.Error(err))
+	}
+	c.releaseLeased(ctx, id)
+}
+
+// releaseLeased gives up the lease on a task.
+// The caller must hold leasedMu.
+func (c *Coordinator) releaseLeased(ctx context.Context, id platform.ID) {
+	if err := c.leases.ReleaseTaskLease(ctx, id, c.owner); err != nil {
+		c.logger.Error("failed to release task lease", zap.String("taskID", id.String()), zap.Error(err))
+	}
+	delete(c.leased, id)
+}
+
+func containsOwner(owners []string, owner string) bool {
+	for _, o := range owners {
+		if o == owner {
+			return true
+		}
+	}
+	return false
+}
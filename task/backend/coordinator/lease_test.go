@@ -0,0 +1,168 @@
+package coordinator_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/task/backend/coordinator"
+	"github.com/influxdata/influxdb/task/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+const leaseTTL = 300 * time.Millisecond
+
+// leasedTaskService returns a kv task service with an organization and an authorization to create tasks with.
+func leasedTaskService(t *testing.T) (*kv.Service, context.Context, platform.TaskCreate) {
+	t.Helper()
+
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &platform.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &platform.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	auth := &platform.Authorization{OrgID: org.ID, UserID: user.ID, Permissions: platform.OperPermissions()}
+	if err := svc.CreateAuthorization(ctx, auth); err != nil {
+		t.Fatal(err)
+	}
+
+	return svc, icontext.SetAuthorizer(ctx, auth), platform.TaskCreate{OrganizationID: org.ID, Token: auth.Token, Flux: script}
+}
+
+// claimedBy returns the number of tasks claimed by each scheduler, and fails if a task is claimed by several schedulers.
+func claimedBy(t *testing.T, ids []platform.ID, scheds ...*mock.Scheduler) []int {
+	t.Helper()
+
+	counts := make([]int, len(scheds))
+	for _, id := range ids {
+		owners := 0
+		for i, sched := range scheds {
+			if sched.TaskFor(id) != nil {
+				counts[i]++
+				owners++
+			}
+		}
+		if owners > 1 {
+			t.Fatalf("task %s claimed by %d schedulers", id, owners)
+		}
+	}
+	return counts
+}
+
+// waitForClaims polls until the schedulers have claimed the expected number of tasks.
+func waitForClaims(t *testing.T, ids []platform.ID, exp []int, scheds ...*mock.Scheduler) {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		counts := claimedBy(t, ids, scheds...)
+		if fmt.Sprint(counts) == fmt.Sprint(exp) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected claims per scheduler: want %v, got %v", exp, counts)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCoordinator_Leases(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	svc, ctx, ct := leasedTaskService(t)
+
+	const numTasks = 10
+	ids := make([]platform.ID, numTasks)
+	for i := range ids {
+		task, err := svc.CreateTask(ctx, ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = task.ID
+	}
+
+	// The first coordinator takes all the tasks.
+	sched1 := mock.NewScheduler()
+	c1 := coordinator.New(zaptest.NewLogger(t), sched1, svc, coordinator.WithLeases(svc, "node1", leaseTTL))
+	defer c1.Stop()
+	waitForClaims(t, ids, []int{numTasks}, sched1)
+
+	// A second coordinator joins, and the tasks are split between both.
+	sched2 := mock.NewScheduler()
+	c2 := coordinator.New(zaptest.NewLogger(t), sched2, svc, coordinator.WithLeases(svc, "node2", leaseTTL))
+	waitForClaims(t, ids, []int{numTasks / 2, numTasks / 2}, sched1, sched2)
+
+	// A task created through one coordinator is claimed exactly once.
+	task, err := c2.CreateTask(ctx, ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids = append(ids, task.ID)
+	counts := claimedBy(t, ids, sched1, sched2)
+	if counts[0]+counts[1] != numTasks+1 {
+		t.Fatalf("expected all tasks to be claimed, got %v", counts)
+	}
+
+	// A disabled task is released by whichever coordinator owns it.
+	inactive := "inactive"
+	if _, err := c1.UpdateTask(ctx, ids[0], platform.TaskUpdate{Status: &inactive}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for sched1.TaskFor(ids[0]) != nil || sched2.TaskFor(ids[0]) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("disabled task still claimed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// When the second coordinator stops, the first one takes over its tasks.
+	c2.Stop()
+	waitForClaims(t, ids[1:], []int{numTasks, 0}, sched1, sched2)
+}
+
+func TestCoordinator_LeaseExpiry(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	svc, ctx, ct := leasedTaskService(t)
+	task, err := svc.CreateTask(ctx, ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []platform.ID{task.ID}
+
+	// A node that died without releasing its lease.
+	if err := svc.RegisterTaskOwner(ctx, "dead", leaseTTL); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AcquireTaskLease(ctx, task.ID, "dead", leaseTTL); err != nil {
+		t.Fatal(err)
+	}
+
+	sched := mock.NewScheduler()
+	c := coordinator.New(zaptest.NewLogger(t), sched, svc, coordinator.WithLeases(svc, "node", leaseTTL))
+	defer c.Stop()
+
+	if claimedBy(t, ids, sched)[0] != 0 {
+		t.Fatal("task claimed while its lease was held by another owner")
+	}
+	waitForClaims(t, ids, []int{1}, sched)
+}
@@ -0,0 +1,232 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+// DryRunResultName is the name of the result holding the points a dry run would have written.
+const DryRunResultName = "_writes"
+
+// DryRun executes script the way a run of a task scheduled for scheduledFor would,
+// except that the points written by to() are captured instead of being written.
+// It returns the captured points as a result with one table per series and field,
+// along with the statistics of the query.
+func DryRun(ctx context.Context, qs query.QueryService, auth *influxdb.Authorization, orgID influxdb.ID, script string, scheduledFor time.Time) (flux.Result, flux.Statistics, error) {
+	if _, err := options.FromScript(script); err != nil {
+		return nil, flux.Statistics{}, influxdb.ErrTaskOptionParse(err)
+	}
+
+	req, err := newTaskRequest(auth, orgID, script, scheduledFor)
+	if err != nil {
+		return nil, flux.Statistics{}, err
+	}
+
+	capture := &query.WriteCapture{}
+	ctx = query.ContextWithWriteCapture(icontext.SetAuthorizer(ctx, auth), capture)
+	it, err := qs.Query(ctx, req)
+	if err != nil {
+		return nil, flux.Statistics{}, err
+	}
+	defer it.Release()
+
+	for it.More() {
+		if err := exhaustResultIterators(it.Next()); err != nil {
+			return nil, flux.Statistics{}, err
+		}
+	}
+
+	// Must call Release to ensure Statistics are ready.
+	it.Release()
+	if err := it.Err(); err != nil {
+		return nil, it.Statistics(), err
+	}
+
+	res, err := newWritesResult(capture.Points())
+	return res, it.Statistics(), err
+}
+
+// writesResult is a flux.Result of captured points.
+type writesResult struct {
+	tables []flux.Table
+}
+
+func (r *writesResult) Name() string               { return DryRunResultName }
+func (r *writesResult) Tables() flux.TableIterator { return r }
+
+func (r *writesResult) Do(f func(flux.Table) error) error {
+	for _, tbl := range r.tables {
+		if err := f(tbl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writtenSeries are the values of a field of a series, as written by to().
+type writtenSeries struct {
+	key    flux.GroupKey
+	typ    flux.ColType
+	times  []values.Time
+	values []interface{}
+}
+
+// newWritesResult converts points, as exploded by to(), back to tables.
+// Each table has the measurement, the tags and the field in its group key, and the time and value as columns.
+func newWritesResult(points []models.Point) (*writesResult, error) {
+	bySeries := make(map[string]*writtenSeries)
+	for _, pt := range points {
+		fields, err := pt.Fields()
+		if err != nil {
+			return nil, err
+		}
+
+		var measurement, field string
+		var tags models.Tags
+		for _, tag := range pt.Tags() {
+			switch string(tag.Key) {
+			case models.MeasurementTagKey:
+				measurement = string(tag.Value)
+			case models.FieldKeyTagKey:
+				field = string(tag.Value)
+			default:
+				tags = append(tags, tag)
+			}
+		}
+
+		for name, v := range fields {
+			if field == "" {
+				field = name
+			}
+
+			typ := fluxType(v)
+			if typ == flux.TInvalid {
+				return nil, fmt.Errorf("unsupported value type %T for field %q", v, name)
+			}
+
+			var id strings.Builder
+			id.WriteString(measurement)
+			for _, tag := range tags {
+				id.WriteString("," + string(tag.Key) + "=" + string(tag.Value))
+			}
+			id.WriteString(" " + field + ":" + typ.String())
+
+			s, ok := bySeries[id.String()]
+			if !ok {
+				s = &writtenSeries{key: writtenSeriesKey(measurement, tags, field), typ: typ}
+				bySeries[id.String()] = s
+			}
+			s.times = append(s.times, values.ConvertTime(pt.Time()))
+			s.values = append(s.values, v)
+		}
+	}
+
+	ids := make([]string, 0, len(bySeries))
+	for id := range bySeries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	alloc := &memory.Allocator{}
+	res := &writesResult{tables: make([]flux.Table, 0, len(ids))}
+	for _, id := range ids {
+		tbl, err := bySeries[id].table(alloc)
+		if err != nil {
+			return nil, err
+		}
+		res.tables = append(res.tables, tbl)
+	}
+	return res, nil
+}
+
+func writtenSeriesKey(measurement string, tags models.Tags, field string) flux.GroupKey {
+	cols := []flux.ColMeta{{Label: "_measurement", Type: flux.TString}}
+	vs := []values.Value{values.NewString(measurement)}
+	for _, tag := range tags {
+		cols = append(cols, flux.ColMeta{Label: string(tag.Key), Type: flux.TString})
+		vs = append(vs, values.NewString(string(tag.Value)))
+	}
+	cols = append(cols, flux.ColMeta{Label: "_field", Type: flux.TString})
+	vs = append(vs, values.NewString(field))
+	return execute.NewGroupKey(cols, vs)
+}
+
+func (s *writtenSeries) table(alloc *memory.Allocator) (flux.Table, error) {
+	sort.Sort(s)
+
+	b := execute.NewColListTableBuilder(s.key, alloc)
+	if err := execute.AddTableKeyCols(s.key, b); err != nil {
+		return nil, err
+	}
+	timeIdx, err := b.AddCol(flux.ColMeta{Label: execute.DefaultTimeColLabel, Type: flux.TTime})
+	if err != nil {
+		return nil, err
+	}
+	valueIdx, err := b.AddCol(flux.ColMeta{Label: execute.DefaultValueColLabel, Type: s.typ})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, t := range s.times {
+		if err := execute.AppendKeyValues(s.key, b); err != nil {
+			return nil, err
+		}
+		if err := b.AppendTime(timeIdx, t); err != nil {
+			return nil, err
+		}
+
+		switch v := s.values[i].(type) {
+		case float64:
+			err = b.AppendFloat(valueIdx, v)
+		case int64:
+			err = b.AppendInt(valueIdx, v)
+		case uint64:
+			err = b.AppendUInt(valueIdx, v)
+		case string:
+			err = b.AppendString(valueIdx, v)
+		case bool:
+			err = b.AppendBool(valueIdx, v)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b.Table()
+}
+
+func (s *writtenSeries) Len() int           { return len(s.times) }
+func (s *writtenSeries) Less(i, j int) bool { return s.times[i] < s.times[j] }
+func (s *writtenSeries) Swap(i, j int) {
+	s.times[i], s.times[j] = s.times[j], s.times[i]
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+}
+
+func fluxType(v interface{}) flux.ColType {
+	switch v.(type) {
+	case float64:
+		return flux.TFloat
+	case int64:
+		return flux.TInt
+	case uint64:
+		return flux.TUInt
+	case string:
+		return flux.TString
+	case bool:
+		return flux.TBool
+	default:
+		return flux.TInvalid
+	}
+}
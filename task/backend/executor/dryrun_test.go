@@ -0,0 +1,110 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/check"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// writingQueryService is a query service whose queries write points, as to() would.
+type writingQueryService struct {
+	points []models.Point
+	req    *query.Request
+}
+
+func (s *writingQueryService) Query(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+	s.req = req
+	if c := query.WriteCaptureFromContext(ctx); c != nil {
+		if err := c.WritePoints(ctx, s.points); err != nil {
+			return nil, err
+		}
+	}
+	return flux.NewSliceResultIterator(nil), nil
+}
+
+func (s *writingQueryService) Check(ctx context.Context) check.Response {
+	return check.Response{Name: "writingQueryService", Status: check.StatusPass}
+}
+
+// writtenPoint returns a point as written by to().
+func writtenPoint(t *testing.T, measurement string, tags map[string]string, field string, v interface{}, ts time.Time) models.Point {
+	t.Helper()
+
+	pointTags := models.Tags{{Key: []byte("\x00"), Value: []byte(measurement)}}
+	pointTags = append(pointTags, models.NewTags(tags)...)
+	pointTags = append(pointTags, models.Tag{Key: []byte("\xff"), Value: []byte(field)})
+
+	pt, err := models.NewPoint(tsdb.EncodeNameString(1, 2), pointTags, models.Fields{field: v}, ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pt
+}
+
+func TestDryRun(t *testing.T) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	qs := &writingQueryService{
+		points: []models.Point{
+			writtenPoint(t, "cpu", map[string]string{"host": "b"}, "usage", 2.5, now.Add(time.Second)),
+			writtenPoint(t, "cpu", map[string]string{"host": "a"}, "usage", 1.5, now),
+			writtenPoint(t, "cpu", map[string]string{"host": "b"}, "usage", 3.5, now),
+			writtenPoint(t, "cpu", map[string]string{"host": "a"}, "count", int64(7), now),
+		},
+	}
+
+	auth := &platform.Authorization{ID: 3, OrgID: 1, UserID: 4}
+	script := `option task = {name: "dry", every: 1h}
+from(bucket: "b") |> range(start: -1h) |> to(bucket: "c", orgID: "0000000000000001")`
+	res, _, err := DryRun(context.Background(), qs, auth, 1, script, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qs.req == nil || qs.req.OrganizationID != 1 {
+		t.Fatalf("unexpected request %+v", qs.req)
+	}
+
+	var buf bytes.Buffer
+	if _, err := csv.NewResultEncoder(csv.DefaultEncoderConfig()).Encode(&buf, res); err != nil {
+		t.Fatal(err)
+	}
+	exp := strings.Join([]string{
+		"#datatype,string,long,string,string,string,dateTime:RFC3339,long",
+		"#group,false,false,true,true,true,false,false",
+		"#default,_writes,,,,,,",
+		",result,table,_measurement,host,_field,_time,_value",
+		",,0,cpu,a,count,2019-06-01T12:00:00Z,7",
+		"",
+		"#datatype,string,long,string,string,string,dateTime:RFC3339,double",
+		"#group,false,false,true,true,true,false,false",
+		"#default,_writes,,,,,,",
+		",result,table,_measurement,host,_field,_time,_value",
+		",,1,cpu,a,usage,2019-06-01T12:00:00Z,1.5",
+		",,2,cpu,b,usage,2019-06-01T12:00:00Z,3.5",
+		",,2,cpu,b,usage,2019-06-01T12:00:01Z,2.5",
+		"",
+	}, "\r\n")
+	if got := buf.String(); got != exp {
+		t.Fatalf("unexpected result:\n%s\nwant:\n%s", got, exp)
+	}
+}
+
+func TestDryRun_InvalidTaskOptions(t *testing.T) {
+	qs := &writingQueryService{}
+	auth := &platform.Authorization{ID: 3, OrgID: 1, UserID: 4}
+	if _, _, err := DryRun(context.Background(), qs, auth, 1, `from(bucket: "b") |> range(start: -1h)`, time.Now()); err == nil {
+		t.Fatal("expected an error for a script without task option")
+	}
+	if qs.req != nil {
+		t.Fatal("script without task option should not be executed")
+	}
+}
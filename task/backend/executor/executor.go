@@ -154,20 +154,11 @@ func (p *syncRunPromise) finish(res *runResult, err error) {
 func (p *syncRunPromise) doQuery(wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	pkg, err := flux.Parse(p.t.Flux)
+	req, err := newTaskRequest(p.auth, p.t.OrganizationID, p.t.Flux, time.Unix(p.qr.Now, 0))
 	if err != nil {
 		p.finish(nil, err)
 		return
 	}
-
-	req := &query.Request{
-		Authorization:  p.auth,
-		OrganizationID: p.t.OrganizationID,
-		Compiler: lang.ASTCompiler{
-			AST: pkg,
-			Now: time.Unix(p.qr.Now, 0),
-		},
-	}
 	it, err := p.qs.Query(p.ctx, req)
 	if err != nil {
 		// Assume the error should not be part of the runResult.
@@ -197,6 +188,23 @@ func (p *syncRunPromise) doQuery(wg *sync.WaitGroup) {
 	p.finish(&runResult{err: err, statistics: it.Statistics()}, nil)
 }
 
+// newTaskRequest returns the query request of a run of script, with the "now" option set to now.
+func newTaskRequest(auth *influxdb.Authorization, orgID influxdb.ID, script string, now time.Time) (*query.Request, error) {
+	pkg, err := flux.Parse(script)
+	if err != nil {
+		return nil, err
+	}
+
+	return &query.Request{
+		Authorization:  auth,
+		OrganizationID: orgID,
+		Compiler: lang.ASTCompiler{
+			AST: pkg,
+			Now: now,
+		},
+	}, nil
+}
+
 func (p *syncRunPromise) cancelOnContextDone(wg *sync.WaitGroup) {
 	defer wg.Done()
 
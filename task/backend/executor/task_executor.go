@@ -374,7 +374,8 @@ func (w *worker) executeQuery(p *Promise) {
 		},
 	}
 
-	it, err := w.te.qs.Query(ctx, req)
+	// The queries of tasks run in the background, so interactive queries are executed ahead of them.
+	it, err := w.te.qs.Query(query.ContextWithPriority(ctx, query.LowPriority), req)
 	if err != nil {
 		// Assume the error should not be part of the runResult.
 		w.finish(p, backend.RunFail, err)
@@ -399,6 +400,9 @@ func (w *worker) executeQuery(p *Promise) {
 
 	// log the statistics on the run
 	stats := it.Statistics()
+	if err := w.te.tcs.SetRunStatistics(p.ctx, p.task.ID, p.run.ID, backend.NewRunStatistics(stats)); err != nil {
+		w.te.logger.Info("Error recording run statistics", zap.Error(err), zap.String("taskID", p.task.ID.String()))
+	}
 
 	b, err := json.Marshal(stats)
 	if err == nil {
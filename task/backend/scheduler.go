@@ -14,9 +14,11 @@ import (
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/task/options"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	cron "gopkg.in/robfig/cron.v2"
 )
 
 // Executor handles execution of a run.
@@ -89,6 +91,19 @@ type RunResult interface {
 	Statistics() flux.Statistics
 }
 
+// NewRunStatistics summarizes the statistics of a run's query.
+func NewRunStatistics(stats flux.Statistics) platform.RunStatistics {
+	return platform.RunStatistics{
+		CompileDuration: stats.CompileDuration,
+		QueueDuration:   stats.QueueDuration,
+		ExecuteDuration: stats.ExecuteDuration,
+		MaxAllocated:    stats.MaxAllocated,
+		RowsRead:        query.SumMetadata(stats.Metadata, query.ScannedValuesMetadataKey),
+		RowsWritten:     query.SumMetadata(stats.Metadata, query.WrittenRowsMetadataKey),
+		BytesWritten:    query.SumMetadata(stats.Metadata, query.WrittenBytesMetadataKey),
+	}
+}
+
 // Scheduler accepts tasks and handles their scheduling.
 //
 // TODO(mr): right now the methods on Scheduler are synchronous.
@@ -156,6 +171,15 @@ func WithLogger(logger *zap.Logger) TickSchedulerOption {
 	}
 }
 
+// WithMaxCatchup limits how far behind the scheduler's current time a claimed task may resume.
+// When a task is claimed, runs scheduled earlier than d before now are skipped,
+// whatever the task's catchup policy. A zero d, the default, places no limit on catch-up.
+func WithMaxCatchup(d time.Duration) TickSchedulerOption {
+	return func(s *TickScheduler) {
+		s.maxCatchup = d
+	}
+}
+
 // NewScheduler returns a new scheduler with the given desired state and the given now UTC timestamp.
 func NewScheduler(taskControlService TaskControlService, executor Executor, now int64, opts ...TickSchedulerOption) *TickScheduler {
 	o := &TickScheduler{
@@ -182,6 +206,9 @@ type TickScheduler struct {
 	now    int64
 	logger *zap.Logger
 
+	// Furthest behind now that a claimed task may resume. Zero means unlimited.
+	maxCatchup time.Duration
+
 	metrics *schedulerMetrics
 
 	ctx    context.Context
@@ -296,17 +323,21 @@ func (s *TickScheduler) ClaimTask(authCtx context.Context, task *platform.Task)
 
 	defer func() { s.metrics.ClaimTask(err == nil) }()
 
-	ts, err := newTaskScheduler(s.ctx, authCtx, s.wg, s, task, s.metrics)
-	if err != nil {
-		return err
-	}
-
 	_, ok := s.taskSchedulers[task.ID]
 	if ok {
 		err = platform.ErrTaskAlreadyClaimed
 		return err
 	}
 
+	if err = s.applyCatchup(authCtx, task); err != nil {
+		return err
+	}
+
+	ts, err := newTaskScheduler(s.ctx, authCtx, s.wg, s, task, s.metrics)
+	if err != nil {
+		return err
+	}
+
 	// pickup any runs that are still "running from a previous failure"
 	runs, err := s.taskControlService.CurrentlyRunning(authCtx, task.ID)
 	if err != nil {
@@ -327,6 +358,91 @@ func (s *TickScheduler) ClaimTask(authCtx context.Context, task *platform.Task)
 	return nil
 }
 
+// applyCatchup skips the task's missed runs according to its catchup policy and the scheduler's maximum catch-up.
+// A task without a catchup policy is only limited by the maximum catch-up here,
+// as the coordinator already resets it to skip its missed runs before claiming it.
+func (s *TickScheduler) applyCatchup(authCtx context.Context, task *platform.Task) error {
+	opt, err := options.FromScript(task.Flux)
+	if err != nil {
+		return err
+	}
+
+	latestCompleted, err := time.Parse(time.RFC3339, task.LatestCompleted)
+	if err != nil {
+		// Without a latest completed time there is nothing to catch up on.
+		return nil
+	}
+
+	now := time.Unix(atomic.LoadInt64(&s.now), 0).UTC()
+	from := latestCompleted
+	if s.maxCatchup > 0 {
+		if limit := now.Add(-s.maxCatchup); limit.After(from) {
+			from = limit
+		}
+	}
+
+	switch opt.Catchup {
+	case options.CatchupNone:
+		from = now
+	case options.CatchupLatest:
+		from, err = beforeLatestMissed(task, from, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !from.After(latestCompleted) {
+		return nil
+	}
+
+	s.logger.Info("Skipping missed runs for claimed task",
+		zap.String("task_id", task.ID.String()),
+		zap.String("catchup", opt.Catchup),
+		zap.Time("latest_completed", latestCompleted),
+		zap.Time("resume_from", from))
+	return s.taskControlService.SetLatestCompleted(authCtx, task.ID, from)
+}
+
+// beforeLatestMissed returns the schedule time preceding the most recent run of task that was due by now,
+// so that resuming from it creates only that one run.
+// If no run after from was due by now, from is returned.
+func beforeLatestMissed(task *platform.Task, from, now time.Time) (time.Time, error) {
+	sch, err := cron.Parse(task.EffectiveCron())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var offset time.Duration
+	if task.Offset != "" {
+		o := options.Duration{}
+		if err := o.Parse(task.Offset); err != nil {
+			return time.Time{}, err
+		}
+		if offset, err = o.DurationFrom(now); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	// Mirror the alignment applied to "every" tasks when their next run is created.
+	if task.Every != "" {
+		every := options.Duration{}
+		if err := every.Parse(task.Every); err != nil {
+			return time.Time{}, err
+		}
+		d, err := every.DurationFrom(from)
+		if err != nil {
+			return time.Time{}, err
+		}
+		from = from.Truncate(d)
+	}
+
+	prev := from
+	for next := sch.Next(from); !next.Add(offset).After(now); next = sch.Next(next) {
+		from, prev = next, from
+	}
+	return prev, nil
+}
+
 func (s *TickScheduler) UpdateTask(authCtx context.Context, task *platform.Task) error {
 	opt, err := options.FromScript(task.Flux)
 	if err != nil {
@@ -764,6 +880,7 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 		r.fail(qr, runLogger, "Waiting for execution result", err)
 		return
 	}
+	r.setRunStatistics(qr, rr.Statistics(), runLogger)
 	if err := rr.Err(); err != nil {
 		runLogger.Info("Run failed to execute", zap.Error(err))
 		errMsg = "Run failed to execute, " + errMsg
@@ -790,6 +907,16 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 	r.startFromWorking(atomic.LoadInt64(r.ts.now))
 }
 
+func (r *runner) setRunStatistics(qr QueuedRun, stats flux.Statistics, runLogger *zap.Logger) {
+	// authctx can be updated mid process
+	r.ts.nextDueMu.RLock()
+	authCtx := r.ts.authCtx
+	r.ts.nextDueMu.RUnlock()
+	if err := r.taskControlService.SetRunStatistics(authCtx, r.task.ID, qr.RunID, NewRunStatistics(stats)); err != nil {
+		runLogger.Info("Error recording run statistics", zap.Error(err))
+	}
+}
+
 func (r *runner) updateRunState(qr QueuedRun, s RunStatus, runLogger *zap.Logger) {
 	switch s {
 	case RunStarted:
@@ -137,6 +137,59 @@ func TestScheduler_StartScriptOnClaim(t *testing.T) {
 	}
 }
 
+func TestScheduler_CatchupPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name       string
+		catchup    string
+		maxCatchup time.Duration
+		expNows    []int64
+	}{
+		{name: "default", expNows: []int64{4, 5, 6, 7, 8, 9, 10}},
+		{name: "all", catchup: `, catchup: "all"`, expNows: []int64{4, 5, 6, 7, 8, 9, 10}},
+		{name: "latest", catchup: `, catchup: "latest"`, expNows: []int64{10}},
+		{name: "none", catchup: `, catchup: "none"`},
+		{name: "all with max", catchup: `, catchup: "all"`, maxCatchup: 3 * time.Second, expNows: []int64{8, 9, 10}},
+		{name: "latest with max", catchup: `, catchup: "latest"`, maxCatchup: 3 * time.Second, expNows: []int64{10}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tcs := mock.NewTaskControlService()
+			e := mock.NewExecutor()
+			o := backend.NewScheduler(tcs, e, 10, backend.WithLogger(zaptest.NewLogger(t)), backend.WithMaxCatchup(tc.maxCatchup))
+			o.Start(context.Background())
+			defer o.Stop()
+
+			task := &platform.Task{
+				ID:              platform.ID(1),
+				Every:           "1s",
+				LatestCompleted: "1970-01-01T00:00:03Z",
+				Flux:            `option task = {concurrency: 99, name:"x", every:1s` + tc.catchup + `} from(bucket:"a") |> to(bucket:"b", org: "o")`,
+			}
+
+			tcs.SetTask(task)
+			if err := o.ClaimTask(context.Background(), task); err != nil {
+				t.Fatal(err)
+			}
+
+			created := tcs.CreatedFor(task.ID)
+			if len(created) != len(tc.expNows) {
+				t.Fatalf("expected %d runs queued, but got %d", len(tc.expNows), len(created))
+			}
+
+			nows := make(map[int64]bool, len(created))
+			for _, qr := range created {
+				nows[qr.Now] = true
+			}
+			for _, now := range tc.expNows {
+				if !nows[now] {
+					t.Fatalf("expected a run queued for %d, got %v", now, created)
+				}
+			}
+		})
+	}
+}
+
 func TestScheduler_DontRunInactiveTasks(t *testing.T) {
 	t.Parallel()
 
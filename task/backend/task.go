@@ -36,11 +36,55 @@ type TaskControlService interface {
 	// FinishRun removes runID from the list of running tasks and if its `ScheduledFor` is later then last completed update it.
 	FinishRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error)
 
+	// SetLatestCompleted moves the task's latest completed time forward to latestCompleted,
+	// so that CreateNextRun will not create runs scheduled at or before it.
+	// It is a no-op if the task has already completed a later run.
+	SetLatestCompleted(ctx context.Context, taskID influxdb.ID, latestCompleted time.Time) error
+
 	// UpdateRunState sets the run state at the respective time.
 	UpdateRunState(ctx context.Context, taskID, runID influxdb.ID, when time.Time, state RunStatus) error
 
 	// AddRunLog adds a log line to the run.
 	AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error
+
+	// SetRunStatistics records the statistics of the query executed by the run.
+	SetRunStatistics(ctx context.Context, taskID, runID influxdb.ID, stats influxdb.RunStatistics) error
+}
+
+// TaskLease records which scheduler owns a task when several processes share the same task store.
+type TaskLease struct {
+	TaskID    influxdb.ID `json:"taskID"`
+	Owner     string      `json:"owner"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+}
+
+// Expired reports whether the lease is no longer held at now.
+func (l *TaskLease) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// TaskLeaseService arbitrates task ownership between schedulers.
+// An owner must keep renewing its leases before they expire, or another owner may take them over.
+type TaskLeaseService interface {
+	// AcquireTaskLease takes or renews the lease on a task for owner, expiring ttl from now.
+	// It returns influxdb.ErrTaskLeaseHeld if another owner holds an unexpired lease on the task.
+	AcquireTaskLease(ctx context.Context, taskID influxdb.ID, owner string, ttl time.Duration) (*TaskLease, error)
+
+	// ReleaseTaskLease gives up owner's lease on a task.
+	// It is a no-op if owner does not hold the lease.
+	ReleaseTaskLease(ctx context.Context, taskID influxdb.ID, owner string) error
+
+	// FindTaskLeases returns all leases, including expired ones.
+	FindTaskLeases(ctx context.Context) ([]*TaskLease, error)
+
+	// RegisterTaskOwner announces that owner is alive and able to take leases, until ttl from now.
+	RegisterTaskOwner(ctx context.Context, owner string, ttl time.Duration) error
+
+	// UnregisterTaskOwner announces that owner no longer takes leases.
+	UnregisterTaskOwner(ctx context.Context, owner string) error
+
+	// FindTaskOwners returns the owners whose registration has not expired.
+	FindTaskOwners(ctx context.Context) ([]string, error)
 }
 
 type TaskStatus string
@@ -225,6 +225,27 @@ func (d *TaskControlService) FinishRun(_ context.Context, taskID, runID influxdb
 	return r, nil
 }
 
+func (d *TaskControlService) SetLatestCompleted(_ context.Context, taskID influxdb.ID, latestCompleted time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t, ok := d.tasks[taskID]
+	if !ok {
+		return influxdb.ErrTaskNotFound
+	}
+	if t.LatestCompleted != "" {
+		current, err := time.Parse(time.RFC3339, t.LatestCompleted)
+		if err != nil {
+			return err
+		}
+		if !latestCompleted.After(current) {
+			return nil
+		}
+	}
+	t.LatestCompleted = latestCompleted.UTC().Format(time.RFC3339)
+	return nil
+}
+
 func (t *TaskControlService) CurrentlyRunning(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -324,6 +345,19 @@ func (d *TaskControlService) AddRunLog(ctx context.Context, taskID, runID influx
 	return nil
 }
 
+// SetRunStatistics records the statistics of the run's query.
+func (d *TaskControlService) SetRunStatistics(ctx context.Context, taskID, runID influxdb.ID, stats influxdb.RunStatistics) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	run := d.runs[taskID][runID]
+	if run == nil {
+		panic("cannot set statistics of a non existent run")
+	}
+	run.Statistics = &stats
+	return nil
+}
+
 func (d *TaskControlService) CreatedFor(taskID influxdb.ID) []backend.QueuedRun {
 	d.mu.Lock()
 	defer d.mu.Unlock()
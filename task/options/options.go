@@ -20,6 +20,19 @@ import (
 const maxConcurrency = 100
 const maxRetry = 10
 
+// Catch-up policies control which missed runs are created when a task is claimed
+// after its schedule has fallen behind, for example after a restart or a long outage.
+const (
+	// CatchupAll creates every missed run since the task's latest completed run.
+	CatchupAll = "all"
+
+	// CatchupLatest creates only the most recent missed run.
+	CatchupLatest = "latest"
+
+	// CatchupNone skips all missed runs and resumes the schedule from now.
+	CatchupNone = "none"
+)
+
 // Options are the task-related options that can be specified in a Flux script.
 type Options struct {
 	// Name is a non optional name designator for each task.
@@ -39,6 +52,10 @@ type Options struct {
 	Concurrency *int64 `json:"concurrency,omitempty"`
 
 	Retry *int64 `json:"retry,omitempty"`
+
+	// Catchup is the policy applied to runs missed while the task was not scheduled.
+	// It must be one of CatchupAll, CatchupLatest or CatchupNone, or empty to use the scheduler's default.
+	Catchup string `json:"catchup,omitempty"`
 }
 
 // Duration is a time span that supports the same units as the flux parser's time duration, as well as negative length time spans.
@@ -128,6 +145,7 @@ func (o *Options) Clear() {
 	o.Offset = nil
 	o.Concurrency = nil
 	o.Retry = nil
+	o.Catchup = ""
 }
 
 // IsZero tells us if the options has been zeroed out.
@@ -137,7 +155,8 @@ func (o *Options) IsZero() bool {
 		o.Every.IsZero() &&
 		o.Offset == nil &&
 		o.Concurrency == nil &&
-		o.Retry == nil
+		o.Retry == nil &&
+		o.Catchup == ""
 }
 
 // All the task option names we accept.
@@ -148,6 +167,7 @@ const (
 	optOffset      = "offset"
 	optConcurrency = "concurrency"
 	optRetry       = "retry"
+	optCatchup     = "catchup"
 )
 
 // contains is a helper function to see if an array of strings contains a string
@@ -301,6 +321,13 @@ func FromScript(script string) (Options, error) {
 		opt.Retry = pointer.Int64(retryVal.Int())
 	}
 
+	if catchupVal, ok := optObject.Get(optCatchup); ok {
+		if err := checkNature(catchupVal.PolyType().Nature(), semantic.String); err != nil {
+			return opt, err
+		}
+		opt.Catchup = catchupVal.Str()
+	}
+
 	if err := opt.Validate(); err != nil {
 		return opt, err
 	}
@@ -361,6 +388,11 @@ func (o *Options) Validate() error {
 			errs = append(errs, fmt.Sprintf("retry exceeded max of %d", maxRetry))
 		}
 	}
+	switch o.Catchup {
+	case "", CatchupAll, CatchupLatest, CatchupNone:
+	default:
+		errs = append(errs, fmt.Sprintf("catchup must be one of %q, %q or %q", CatchupAll, CatchupLatest, CatchupNone))
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -402,7 +434,7 @@ func validateOptionNames(o values.Object) error {
 	var unexpected []string
 	o.Range(func(name string, _ values.Value) {
 		switch name {
-		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry:
+		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optCatchup:
 			// Known option. Nothing to do.
 		default:
 			unexpected = append(unexpected, name)
@@ -411,7 +443,7 @@ func validateOptionNames(o values.Object) error {
 
 	if len(unexpected) > 0 {
 		u := strings.Join(unexpected, ", ")
-		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry}, ", ")
+		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optCatchup}, ", ")
 		return fmt.Errorf("unknown task option(s): %s. valid options are %s", u, v)
 	}
 
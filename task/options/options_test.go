@@ -33,6 +33,9 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Retry != nil && *opt.Retry != 0 {
 		taskData = fmt.Sprintf("%s  retry: %d,\n", taskData, *opt.Retry)
 	}
+	if opt.Catchup != "" {
+		taskData = fmt.Sprintf("%s  catchup: %q,\n", taskData, opt.Catchup)
+	}
 	if body == "" {
 		body = `from(bucket: "test")
     |> range(start:-1h)`
@@ -78,6 +81,8 @@ func TestFromScript(t *testing.T) {
 		{script: "option task = {\n  name: \"name8\",\n  retry: 0,\n  every: 1m0s,\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
 		{script: scriptGenerator(options.Options{Name: "name9"}, ""), shouldErr: true},
 		{script: scriptGenerator(options.Options{}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name10", Every: *(options.MustParseDuration("1m")), Catchup: options.CatchupLatest}, ""), exp: options.Options{Name: "name10", Every: *(options.MustParseDuration("1m")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1), Catchup: options.CatchupLatest}},
+		{script: scriptGenerator(options.Options{Name: "name11", Every: *(options.MustParseDuration("1m")), Catchup: "sometimes"}, ""), shouldErr: true},
 	} {
 		o, err := options.FromScript(c.script)
 		if c.shouldErr && err == nil {
@@ -122,7 +127,7 @@ func TestFromScriptWithUnknownOptions(t *testing.T) {
 		t.Errorf("expected error to mention unrecognized options, but it said: %v", err)
 	}
 
-	validOpts := []string{"name", "cron", "every", "offset", "concurrency", "retry"}
+	validOpts := []string{"name", "cron", "every", "offset", "concurrency", "retry", "catchup"}
 	for _, o := range validOpts {
 		if !strings.Contains(msg, o) {
 			t.Errorf("expected error to mention valid option %q but it said: %v", o, err)
@@ -197,6 +202,12 @@ func TestValidate(t *testing.T) {
 	if err := bad.Validate(); err == nil {
 		t.Error("expected error for retry too large")
 	}
+
+	*bad = good
+	bad.Catchup = "some"
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for unknown catchup policy")
+	}
 }
 
 func TestEffectiveCronString(t *testing.T) {
@@ -79,6 +79,11 @@ func TestTaskService(t *testing.T, fn BackendComponentFactory, testCategory ...s
 					testUpdate(t, sys)
 				})
 
+				t.Run("Task Set Latest Completed", func(t *testing.T) {
+					t.Parallel()
+					testSetLatestCompleted(t, sys)
+				})
+
 				t.Run("Task Manual Run", func(t *testing.T) {
 					t.Parallel()
 					testManualRun(t, sys)
@@ -104,6 +109,10 @@ func TestTaskService(t *testing.T, fn BackendComponentFactory, testCategory ...s
 					t.Parallel()
 					testLogsAcrossStorage(t, sys)
 				})
+				t.Run("Task Run Statistics", func(t *testing.T) {
+					t.Parallel()
+					testRunStatistics(t, sys)
+				})
 			})
 		}
 	}
@@ -597,6 +606,55 @@ func testUpdate(t *testing.T, sys *System) {
 	}
 }
 
+func testSetLatestCompleted(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+
+	ct := influxdb.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           fmt.Sprintf(scriptFmt, 0),
+		Token:          cr.Token,
+	}
+	authorizedCtx := icontext.SetAuthorizer(sys.Ctx, cr.Authorizer())
+	task, err := sys.TaskService.CreateTask(authorizedCtx, ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lc, err := time.Parse(time.RFC3339, task.LatestCompleted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Moving latest completed backward must not replay runs.
+	if err := sys.TaskControlService.SetLatestCompleted(sys.Ctx, task.ID, lc.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	st, err := sys.TaskService.FindTaskByID(sys.Ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.LatestCompleted != task.LatestCompleted {
+		t.Fatalf("latest completed moved backward: expected %s, got %s", task.LatestCompleted, st.LatestCompleted)
+	}
+
+	forward := lc.Add(time.Hour).UTC()
+	if err := sys.TaskControlService.SetLatestCompleted(sys.Ctx, task.ID, forward); err != nil {
+		t.Fatal(err)
+	}
+	st, err = sys.TaskService.FindTaskByID(sys.Ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := forward.Format(time.RFC3339); st.LatestCompleted != exp {
+		t.Fatalf("latest completed not moved forward: expected %s, got %s", exp, st.LatestCompleted)
+	}
+
+	// No run is due before the new latest completed time.
+	if _, err := sys.TaskControlService.CreateNextRun(sys.Ctx, task.ID, forward.Unix()); err == nil {
+		t.Fatal("expected no run to be due at the new latest completed time")
+	}
+}
+
 func testTaskRuns(t *testing.T, sys *System) {
 	cr := creds(t, sys)
 
@@ -1042,6 +1100,71 @@ func testManualRun(t *testing.T, s *System) {
 	}
 }
 
+func testRunStatistics(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+
+	ct := influxdb.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           fmt.Sprintf(scriptFmt, 0),
+		Token:          cr.Token,
+	}
+	task, err := sys.TaskService.CreateTask(icontext.SetAuthorizer(sys.Ctx, cr.Authorizer()), ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestedAtUnix := time.Now().Add(5 * time.Minute).UTC().Unix()
+	rc, err := sys.TaskControlService.CreateNextRun(sys.Ctx, task.ID, requestedAtUnix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	startedAt := time.Now().UTC().Add(time.Second * -10)
+	if err := sys.TaskControlService.UpdateRunState(sys.Ctx, task.ID, rc.Created.RunID, startedAt, backend.RunStarted); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := influxdb.RunStatistics{
+		CompileDuration: time.Millisecond,
+		QueueDuration:   2 * time.Millisecond,
+		ExecuteDuration: 3 * time.Millisecond,
+		MaxAllocated:    1024,
+		RowsRead:        10,
+		RowsWritten:     5,
+		BytesWritten:    128,
+	}
+	if err := sys.TaskControlService.SetRunStatistics(sys.Ctx, task.ID, rc.Created.RunID, stats); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := sys.TaskService.FindRunByID(sys.Ctx, task.ID, rc.Created.RunID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Statistics == nil || *run.Statistics != stats {
+		t.Fatalf("unexpected statistics on running run; want %+v, got %+v", stats, run.Statistics)
+	}
+
+	if err := sys.TaskControlService.UpdateRunState(sys.Ctx, task.ID, rc.Created.RunID, startedAt.Add(time.Second), backend.RunSuccess); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sys.TaskControlService.FinishRun(sys.Ctx, task.ID, rc.Created.RunID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The statistics must survive the move of the run to analytical storage.
+	runs, _, err := sys.TaskService.FindRuns(sys.Ctx, influxdb.RunFilter{Task: task.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run, got %v", runs)
+	}
+	if runs[0].Statistics == nil || *runs[0].Statistics != stats {
+		t.Fatalf("unexpected statistics on finished run; want %+v, got %+v", stats, runs[0].Statistics)
+	}
+}
+
 func testRunStorage(t *testing.T, sys *System) {
 	cr := creds(t, sys)
 
@@ -24,6 +24,12 @@ var (
 		Msg:  "task already claimed",
 	}
 
+	// ErrTaskLeaseHeld is returned when attempting to lease a task whose lease is held by another owner.
+	ErrTaskLeaseHeld = &Error{
+		Code: EConflict,
+		Msg:  "task lease held by another owner",
+	}
+
 	// ErrNoRunsFound is returned when searching for a range of runs, but none are found.
 	ErrNoRunsFound = &Error{
 		Code: ENotFound,
@@ -41,6 +41,7 @@ type AuthorizationFields struct {
 }
 
 // AuthorizationService tests all the service functions.
+// The tokens of the authorizations are only expected when they are created or found by token.
 func AuthorizationService(
 	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()), t *testing.T,
 ) {
@@ -91,6 +92,7 @@ func CreateAuthorization(
 	}
 	type wants struct {
 		err            error
+		token          string
 		authorizations []*platform.Authorization
 	}
 
@@ -141,13 +143,13 @@ func CreateAuthorization(
 				},
 			},
 			wants: wants{
+				token: "rand",
 				authorizations: []*platform.Authorization{
 					{
 						ID:          MustIDBase16(authOneID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 						Description: "already existing auth",
 					},
@@ -155,7 +157,6 @@ func CreateAuthorization(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 						Description: "new auth",
@@ -203,20 +204,19 @@ func CreateAuthorization(
 				},
 			},
 			wants: wants{
+				token: "rand",
 				authorizations: []*platform.Authorization{
 					{
 						ID:          MustIDBase16(authOneID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 					},
 					{
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
@@ -270,7 +270,6 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 						Description: "already existing auth",
 					},
@@ -325,7 +324,6 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 						Description: "already existing auth",
 					},
@@ -346,6 +344,9 @@ func CreateAuthorization(
 			}
 
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+			if tt.wants.err == nil && tt.args.authorization.Token != tt.wants.token {
+				t.Errorf("expected the created authorization to have the token %q, got %q", tt.wants.token, tt.args.authorization.Token)
+			}
 
 			defer s.DeleteAuthorization(ctx, tt.args.authorization.ID)
 
@@ -424,7 +425,6 @@ func FindAuthorizationByID(
 					UserID:      MustIDBase16(userTwoID),
 					OrgID:       MustIDBase16(orgOneID),
 					Status:      platform.Active,
-					Token:       "rand2",
 					Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 				},
 			},
@@ -537,7 +537,6 @@ func UpdateAuthorization(
 					ID:          MustIDBase16(authTwoID),
 					UserID:      MustIDBase16(userTwoID),
 					OrgID:       MustIDBase16(orgOneID),
-					Token:       "rand2",
 					Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					Status:      platform.Inactive,
 					Description: "desc1",
@@ -872,7 +871,6 @@ func FindAuthorizations(
 						ID:          MustIDBase16(authOneID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand1",
 						Status:      platform.Active,
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 					},
@@ -880,7 +878,6 @@ func FindAuthorizations(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand2",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
@@ -941,7 +938,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand1",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 					},
 					{
@@ -949,7 +945,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand3",
 						Permissions: deleteUsersPermission(MustIDBase16(orgOneID)),
 					},
 				},
@@ -1011,7 +1006,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand1",
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
 					{
@@ -1019,7 +1013,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand2",
 						Permissions: deleteUsersPermission(MustIDBase16(orgOneID)),
 					},
 				},
@@ -1094,7 +1087,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgTwoID),
 						Status:      platform.Active,
-						Token:       "rand2",
 						Permissions: allUsersPermission(MustIDBase16(orgTwoID)),
 					},
 				},
@@ -1262,7 +1254,6 @@ func DeleteAuthorization(
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand2",
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
 				},
@@ -1317,7 +1308,6 @@ func DeleteAuthorization(
 					{
 						ID:          MustIDBase16(authOneID),
 						UserID:      MustIDBase16(userOneID),
-						Token:       "rand1",
 						Status:      platform.Active,
 						OrgID:       MustIDBase16(orgOneID),
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
@@ -1326,7 +1316,6 @@ func DeleteAuthorization(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand2",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
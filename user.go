@@ -6,11 +6,18 @@ import (
 
 // User is a user. 🎉
 type User struct {
-	ID      ID     `json:"id,omitempty"`
-	Name    string `json:"name"`
+	ID   ID     `json:"id,omitempty"`
+	Name string `json:"name"`
+	// OAuthID links the user to the identity it signs in with at an OAuth2 provider.
+	// It is unique across all users.
 	OAuthID string `json:"oauthID,omitempty"`
 }
 
+// OAuthID returns the OAuth ID of the identity of subject at the provider issuer.
+func OAuthID(issuer, subject string) string {
+	return issuer + ":" + subject
+}
+
 // Ops for user errors and op log.
 const (
 	OpFindUserByID = "FindUserByID"
@@ -48,11 +55,13 @@ type UserService interface {
 // UserUpdate represents updates to a user.
 // Only fields which are set are updated.
 type UserUpdate struct {
-	Name *string `json:"name"`
+	Name    *string `json:"name"`
+	OAuthID *string `json:"oauthID,omitempty"`
 }
 
 // UserFilter represents a set of filter that restrict the returned results.
 type UserFilter struct {
-	ID   *ID
-	Name *string
+	ID      *ID
+	Name    *string
+	OAuthID *string
 }
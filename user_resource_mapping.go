@@ -44,11 +44,14 @@ type MappingType uint8
 const (
 	UserMappingType = 0
 	OrgMappingType  = 1
+	// GroupMappingType maps a group, rather than a user, to a resource.
+	// The UserID of the mapping is then the ID of the group.
+	GroupMappingType = 2
 )
 
 func (mt MappingType) Valid() error {
 	switch mt {
-	case UserMappingType, OrgMappingType:
+	case UserMappingType, OrgMappingType, GroupMappingType:
 		return nil
 	}
 
@@ -61,6 +64,8 @@ func (mt MappingType) String() string {
 		return "user"
 	case OrgMappingType:
 		return "org"
+	case GroupMappingType:
+		return "group"
 	}
 
 	return "unknown"
@@ -83,6 +88,9 @@ func (mt *MappingType) UnmarshalJSON(b []byte) error {
 	case "org":
 		*mt = OrgMappingType
 		return nil
+	case "group":
+		*mt = GroupMappingType
+		return nil
 	}
 
 	return ErrInvalidMappingType
@@ -143,25 +151,35 @@ type UserResourceMappingFilter struct {
 }
 
 func (m *UserResourceMapping) ownerPerms() ([]Permission, error) {
-	ps := []Permission{}
-	// TODO(desa): how to grant access to specific resources.
-
 	if m.ResourceType == OrgsResourceType {
-		ps = append(ps, OwnerPermissions(m.ResourceID)...)
+		return OwnerPermissions(m.ResourceID), nil
+	}
+	if m.MappingType == GroupMappingType {
+		return m.resourcePerms(ReadAction, WriteAction), nil
 	}
 
-	return ps, nil
+	return []Permission{}, nil
 }
 
 func (m *UserResourceMapping) memberPerms() ([]Permission, error) {
-	ps := []Permission{}
-	// TODO(desa): how to grant access to specific resources.
-
 	if m.ResourceType == OrgsResourceType {
-		ps = append(ps, MemberPermissions(m.ResourceID)...)
+		return MemberPermissions(m.ResourceID), nil
 	}
+	if m.MappingType == GroupMappingType {
+		return m.resourcePerms(ReadAction), nil
+	}
+
+	return []Permission{}, nil
+}
 
-	return ps, nil
+// resourcePerms returns the permissions to the actions on the specific resource of the mapping.
+func (m *UserResourceMapping) resourcePerms(as ...Action) []Permission {
+	ps := make([]Permission, 0, len(as))
+	for _, a := range as {
+		id := m.ResourceID
+		ps = append(ps, Permission{Action: a, Resource: Resource{Type: m.ResourceType, ID: &id}})
+	}
+	return ps
 }
 
 // ToPermissions converts a user resource mapping into a set of permissions.
@@ -1,6 +1,7 @@
 package influxdb_test
 
 import (
+	"reflect"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
@@ -99,3 +100,71 @@ func TestOwnerMappingValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestUserResourceMappingToPermissions(t *testing.T) {
+	id := platformtesting.MustIDBase16("020f755c3c082000")
+	tests := []struct {
+		name  string
+		m     platform.UserResourceMapping
+		perms []platform.Permission
+	}{
+		{
+			name: "owner of a dashboard",
+			m: platform.UserResourceMapping{
+				UserID:       platformtesting.MustIDBase16("debac1e0deadbeef"),
+				UserType:     platform.Owner,
+				ResourceType: platform.DashboardsResourceType,
+				ResourceID:   id,
+			},
+			perms: []platform.Permission{},
+		},
+		{
+			name: "group owner of a dashboard",
+			m: platform.UserResourceMapping{
+				UserID:       platformtesting.MustIDBase16("debac1e0deadbeef"),
+				UserType:     platform.Owner,
+				MappingType:  platform.GroupMappingType,
+				ResourceType: platform.DashboardsResourceType,
+				ResourceID:   id,
+			},
+			perms: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.DashboardsResourceType, ID: &id}},
+				{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.DashboardsResourceType, ID: &id}},
+			},
+		},
+		{
+			name: "group member of a bucket",
+			m: platform.UserResourceMapping{
+				UserID:       platformtesting.MustIDBase16("debac1e0deadbeef"),
+				UserType:     platform.Member,
+				MappingType:  platform.GroupMappingType,
+				ResourceType: platform.BucketsResourceType,
+				ResourceID:   id,
+			},
+			perms: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType, ID: &id}},
+			},
+		},
+		{
+			name: "member of an org",
+			m: platform.UserResourceMapping{
+				UserID:       platformtesting.MustIDBase16("debac1e0deadbeef"),
+				UserType:     platform.Member,
+				ResourceType: platform.OrgsResourceType,
+				ResourceID:   id,
+			},
+			perms: platform.MemberPermissions(id),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perms, err := tt.m.ToPermissions()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(perms, tt.perms) {
+				t.Errorf("unexpected permissions:\ngot  %v\nwant %v", perms, tt.perms)
+			}
+		})
+	}
+}